@@ -20,9 +20,13 @@ import (
 	"flag"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/clock"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -31,6 +35,8 @@ import (
 	kedav1alpha1 "github.com/projectriff/system/pkg/apis/thirdparty/keda/v1alpha1"
 
 	buildv1alpha1 "github.com/projectriff/system/pkg/apis/build/v1alpha1"
+	corev1alpha1 "github.com/projectriff/system/pkg/apis/core/v1alpha1"
+	knativev1alpha1 "github.com/projectriff/system/pkg/apis/knative/v1alpha1"
 	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
 	"github.com/projectriff/system/pkg/controllers"
 	streamingcontrollers "github.com/projectriff/system/pkg/controllers/streaming"
@@ -48,6 +54,8 @@ var (
 func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = buildv1alpha1.AddToScheme(scheme)
+	_ = corev1alpha1.AddToScheme(scheme)
+	_ = knativev1alpha1.AddToScheme(scheme)
 	_ = kedav1alpha1.AddToScheme(scheme)
 
 	_ = streamingv1alpha1.AddToScheme(scheme)
@@ -57,9 +65,17 @@ func init() {
 func main() {
 	var metricsAddr string
 	var probesAddr string
+	var graphAddr string
+	var certDir string
+	var lagInterval time.Duration
+	var laggingThreshold int64
 	var enableLeaderElection bool
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probesAddr, "probes-addr", ":8081", "The address health probes bind to.")
+	flag.StringVar(&graphAddr, "graph-addr", ":8082", "The address the stream graph endpoint binds to, \"0\" disables the endpoint.")
+	flag.StringVar(&certDir, "cert-dir", filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs"), "The directory containing the tls.crt and tls.key served by the webhook and stream graph endpoints.")
+	flag.DurationVar(&lagInterval, "lag-interval", 30*time.Second, "How often the consumer lag of each processor is queried from its gateway.")
+	flag.Int64Var(&laggingThreshold, "lagging-threshold", 1000, "The number of pending messages on an input stream above which a processor is reported as lagging.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 	flag.Parse()
@@ -73,12 +89,16 @@ func main() {
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "controller-leader-election-helper-streaming",
 		SyncPeriod:             &syncPeriod,
+		CertDir:                certDir,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// a gateway that does not respond must not stall the controllers
+	provisionerHTTPClient := &http.Client{Timeout: 10 * time.Second}
+
 	streamControllerLogger := ctrl.Log.WithName("controllers").WithName("Stream")
 	if err = streamingcontrollers.StreamReconciler(
 		controllers.Config{
@@ -88,7 +108,11 @@ func main() {
 			Log:       streamControllerLogger,
 			Scheme:    mgr.GetScheme(),
 			Tracker:   tracker.New(syncPeriod, ctrl.Log.WithName("controllers").WithName("Stream").WithName("tracker")),
-		}, streamingcontrollers.NewStreamProvisionerClient(http.DefaultClient, streamControllerLogger),
+		},
+		namespace,
+		streamingcontrollers.NewStreamProvisionerClient(provisionerHTTPClient, streamControllerLogger),
+		lagInterval,
+		clock.RealClock{},
 	).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Stream")
 		os.Exit(1)
@@ -97,6 +121,34 @@ func main() {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Stream")
 		os.Exit(1)
 	}
+	mgr.GetWebhookServer().Register(streamingcontrollers.StreamQuotaWebhookPath, streamingcontrollers.StreamQuotaWebhook(mgr.GetClient(), ctrl.Log.WithName("webhooks").WithName("StreamQuota")))
+	if err = streamingcontrollers.StreamQuotaReconciler(
+		controllers.Config{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Recorder:  mgr.GetEventRecorderFor("StreamQuota"),
+			Log:       ctrl.Log.WithName("controllers").WithName("StreamQuota"),
+			Scheme:    mgr.GetScheme(),
+			Tracker:   tracker.New(syncPeriod, ctrl.Log.WithName("controllers").WithName("StreamQuota").WithName("tracker")),
+		},
+	).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StreamQuota")
+		os.Exit(1)
+	}
+	if err = ctrl.NewWebhookManagedBy(mgr).For(&streamingv1alpha1.StreamQuota{}).Complete(); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "StreamQuota")
+		os.Exit(1)
+	}
+	lagLogger := ctrl.Log.WithName("lag")
+	lagMonitor := streamingcontrollers.NewConsumerLagMonitor(
+		mgr.GetClient(),
+		streamingcontrollers.NewStreamProvisionerClient(provisionerHTTPClient, lagLogger),
+		lagInterval, laggingThreshold, lagLogger,
+	)
+	if err := mgr.Add(lagMonitor); err != nil {
+		setupLog.Error(err, "unable to create consumer lag monitor")
+		os.Exit(1)
+	}
 	if err = streamingcontrollers.ProcessorReconciler(
 		controllers.Config{
 			Client:    mgr.GetClient(),
@@ -107,6 +159,7 @@ func main() {
 			Tracker:   tracker.New(syncPeriod, ctrl.Log.WithName("controllers").WithName("Processor").WithName("tracker")),
 		},
 		namespace,
+		lagMonitor,
 	).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Processor")
 		os.Exit(1)
@@ -115,6 +168,10 @@ func main() {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Processor")
 		os.Exit(1)
 	}
+	if err = ctrl.NewWebhookManagedBy(mgr).For(&streamingv1alpha1.StreamGrant{}).Complete(); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "StreamGrant")
+		os.Exit(1)
+	}
 	if err = streamingcontrollers.GatewayReconciler(
 		controllers.Config{
 			Client:    mgr.GetClient(),
@@ -186,8 +243,164 @@ func main() {
 		setupLog.Error(err, "unable to create webhook", "webhook", "InMemoryGateway")
 		os.Exit(1)
 	}
+	if err = streamingcontrollers.NATSGatewayReconciler(
+		controllers.Config{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Recorder:  mgr.GetEventRecorderFor("NATSGateway"),
+			Log:       ctrl.Log.WithName("controllers").WithName("NATSGateway"),
+			Scheme:    mgr.GetScheme(),
+			Tracker:   tracker.New(syncPeriod, ctrl.Log.WithName("controllers").WithName("NATSGateway").WithName("tracker")),
+		},
+		namespace,
+	).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NATSGateway")
+		os.Exit(1)
+	}
+	if err = ctrl.NewWebhookManagedBy(mgr).For(&streamingv1alpha1.NATSGateway{}).Complete(); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "NATSGateway")
+		os.Exit(1)
+	}
+	if err = streamingcontrollers.RedisGatewayReconciler(
+		controllers.Config{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Recorder:  mgr.GetEventRecorderFor("RedisGateway"),
+			Log:       ctrl.Log.WithName("controllers").WithName("RedisGateway"),
+			Scheme:    mgr.GetScheme(),
+			Tracker:   tracker.New(syncPeriod, ctrl.Log.WithName("controllers").WithName("RedisGateway").WithName("tracker")),
+		},
+		namespace,
+	).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RedisGateway")
+		os.Exit(1)
+	}
+	if err = ctrl.NewWebhookManagedBy(mgr).For(&streamingv1alpha1.RedisGateway{}).Complete(); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "RedisGateway")
+		os.Exit(1)
+	}
+	if err = streamingcontrollers.PipelineReconciler(
+		controllers.Config{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Recorder:  mgr.GetEventRecorderFor("Pipeline"),
+			Log:       ctrl.Log.WithName("controllers").WithName("Pipeline"),
+			Scheme:    mgr.GetScheme(),
+			Tracker:   tracker.New(syncPeriod, ctrl.Log.WithName("controllers").WithName("Pipeline").WithName("tracker")),
+		},
+	).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Pipeline")
+		os.Exit(1)
+	}
+	if err = ctrl.NewWebhookManagedBy(mgr).For(&streamingv1alpha1.Pipeline{}).Complete(); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "Pipeline")
+		os.Exit(1)
+	}
+	if err = streamingcontrollers.HTTPSourceReconciler(
+		controllers.Config{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Recorder:  mgr.GetEventRecorderFor("HTTPSource"),
+			Log:       ctrl.Log.WithName("controllers").WithName("HTTPSource"),
+			Scheme:    mgr.GetScheme(),
+			Tracker:   tracker.New(syncPeriod, ctrl.Log.WithName("controllers").WithName("HTTPSource").WithName("tracker")),
+		},
+		namespace,
+	).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HTTPSource")
+		os.Exit(1)
+	}
+	if err = ctrl.NewWebhookManagedBy(mgr).For(&streamingv1alpha1.HTTPSource{}).Complete(); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "HTTPSource")
+		os.Exit(1)
+	}
+	if err = streamingcontrollers.CronSourceReconciler(
+		controllers.Config{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Recorder:  mgr.GetEventRecorderFor("CronSource"),
+			Log:       ctrl.Log.WithName("controllers").WithName("CronSource"),
+			Scheme:    mgr.GetScheme(),
+			Tracker:   tracker.New(syncPeriod, ctrl.Log.WithName("controllers").WithName("CronSource").WithName("tracker")),
+		},
+		namespace,
+	).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CronSource")
+		os.Exit(1)
+	}
+	if err = ctrl.NewWebhookManagedBy(mgr).For(&streamingv1alpha1.CronSource{}).Complete(); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "CronSource")
+		os.Exit(1)
+	}
+	if err = streamingcontrollers.SinkReconciler(
+		controllers.Config{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Recorder:  mgr.GetEventRecorderFor("Sink"),
+			Log:       ctrl.Log.WithName("controllers").WithName("Sink"),
+			Scheme:    mgr.GetScheme(),
+			Tracker:   tracker.New(syncPeriod, ctrl.Log.WithName("controllers").WithName("Sink").WithName("tracker")),
+		},
+		namespace,
+		streamingcontrollers.SinkTargetKinds{
+			CoreDeployer:    served(mgr, corev1alpha1.GroupVersion.WithKind("Deployer")),
+			KnativeDeployer: served(mgr, knativev1alpha1.GroupVersion.WithKind("Deployer")),
+		},
+	).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Sink")
+		os.Exit(1)
+	}
+	if err = ctrl.NewWebhookManagedBy(mgr).For(&streamingv1alpha1.Sink{}).Complete(); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "Sink")
+		os.Exit(1)
+	}
+	if err = streamingcontrollers.StreamMirrorReconciler(
+		controllers.Config{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Recorder:  mgr.GetEventRecorderFor("StreamMirror"),
+			Log:       ctrl.Log.WithName("controllers").WithName("StreamMirror"),
+			Scheme:    mgr.GetScheme(),
+			Tracker:   tracker.New(syncPeriod, ctrl.Log.WithName("controllers").WithName("StreamMirror").WithName("tracker")),
+		},
+		namespace,
+		lagMonitor,
+	).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StreamMirror")
+		os.Exit(1)
+	}
+	if err = ctrl.NewWebhookManagedBy(mgr).For(&streamingv1alpha1.StreamMirror{}).Complete(); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "StreamMirror")
+		os.Exit(1)
+	}
+	if err = streamingcontrollers.StreamTapReconciler(
+		controllers.Config{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Recorder:  mgr.GetEventRecorderFor("StreamTap"),
+			Log:       ctrl.Log.WithName("controllers").WithName("StreamTap"),
+			Scheme:    mgr.GetScheme(),
+			Tracker:   tracker.New(syncPeriod, ctrl.Log.WithName("controllers").WithName("StreamTap").WithName("tracker")),
+		},
+		namespace,
+		clock.RealClock{},
+	).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StreamTap")
+		os.Exit(1)
+	}
+	if err = ctrl.NewWebhookManagedBy(mgr).For(&streamingv1alpha1.StreamTap{}).Complete(); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "StreamTap")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
+	if graphAddr != "0" {
+		if err := mgr.Add(streamingcontrollers.GraphServer(graphAddr, certDir, mgr.GetClient(), ctrl.Log.WithName("graph"))); err != nil {
+			setupLog.Error(err, "unable to create graph server")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("default", func(_ *http.Request) error { return nil }); err != nil {
 		setupLog.Error(err, "unable to create health check")
 		os.Exit(1)
@@ -203,3 +416,17 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// served reports whether the cluster serves the kind. The core and knative
+// runtimes are optional, their CRDs are only present once installed.
+func served(mgr ctrl.Manager, gvk schema.GroupVersionKind) bool {
+	if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if !meta.IsNoMatchError(err) {
+			setupLog.Error(err, "unable to discover kind", "kind", gvk)
+			os.Exit(1)
+		}
+		setupLog.Info("kind is not served, continuing without it", "kind", gvk)
+		return false
+	}
+	return true
+}
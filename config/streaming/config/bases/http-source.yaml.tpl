@@ -0,0 +1,6 @@
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: http-source
+data:
+  receiverImage: {{ gcloud container images describe gcr.io/projectriff/http-source/receiver:0.6.0-snapshot --format="value(image_summary.fully_qualified_digest)" }}
@@ -0,0 +1,7 @@
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: redis-gateway
+data:
+  gatewayImage: {{ gcloud container images describe gcr.io/projectriff/redis-gateway/gateway:0.6.0-snapshot --format="value(image_summary.fully_qualified_digest)" }}
+  provisionerImage: {{ gcloud container images describe gcr.io/projectriff/redis-provisioner/provisioner:0.6.0-snapshot --format="value(image_summary.fully_qualified_digest)" }}
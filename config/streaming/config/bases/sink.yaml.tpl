@@ -0,0 +1,6 @@
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: sink
+data:
+  sinkImage: {{ gcloud container images describe gcr.io/projectriff/sink/sink:0.6.0-snapshot --format="value(image_summary.fully_qualified_digest)" }}
@@ -0,0 +1,6 @@
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: stream-mirror
+data:
+  mirrorImage: {{ gcloud container images describe gcr.io/projectriff/stream-mirror/stream-mirror:0.6.0-snapshot --format="value(image_summary.fully_qualified_digest)" }}
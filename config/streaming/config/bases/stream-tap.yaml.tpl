@@ -0,0 +1,6 @@
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: stream-tap
+data:
+  tapImage: {{ gcloud container images describe gcr.io/projectriff/stream-tap/stream-tap:0.6.0-snapshot --format="value(image_summary.fully_qualified_digest)" }}
@@ -6,6 +6,7 @@ require (
 	github.com/go-logr/logr v0.1.0
 	github.com/google/go-cmp v0.4.0
 	github.com/google/go-containerregistry v0.0.0-20191002200252-ff1ac7f97758
+	github.com/prometheus/client_golang v1.0.0
 	github.com/stretchr/testify v1.5.1
 	k8s.io/api v0.17.4
 	k8s.io/apimachinery v0.17.4
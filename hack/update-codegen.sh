@@ -44,5 +44,11 @@ patchClient \
 patchClient \
   'schema.GroupVersionResource{Group: "streaming.projectriff.io", Version: "v1alpha1", Resource: "pulsargateways"}' \
   'schema.GroupVersionResource{Group: "streaming.projectriff.io", Version: "v1alpha1", Resource: "pulsargatewaies"}'
+patchClient \
+  'schema.GroupVersionResource{Group: "streaming.projectriff.io", Version: "v1alpha1", Resource: "natsgateways"}' \
+  'schema.GroupVersionResource{Group: "streaming.projectriff.io", Version: "v1alpha1", Resource: "natsgatewaies"}'
+patchClient \
+  'schema.GroupVersionResource{Group: "streaming.projectriff.io", Version: "v1alpha1", Resource: "redisgateways"}' \
+  'schema.GroupVersionResource{Group: "streaming.projectriff.io", Version: "v1alpha1", Resource: "redisgatewaies"}'
 
 make fmt
@@ -0,0 +1,68 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+
+	"github.com/projectriff/system/pkg/apis"
+)
+
+const (
+	CronSourceConditionReady                           = apis.ConditionReady
+	CronSourceConditionStreamReady  apis.ConditionType = "StreamReady"
+	CronSourceConditionCronJobReady apis.ConditionType = "CronJobReady"
+)
+
+var cronSourceCondSet = apis.NewLivingConditionSet(
+	CronSourceConditionStreamReady,
+	CronSourceConditionCronJobReady,
+)
+
+func (ss *CronSourceStatus) GetObservedGeneration() int64 {
+	return ss.ObservedGeneration
+}
+
+func (ss *CronSourceStatus) IsReady() bool {
+	return cronSourceCondSet.Manage(ss).IsHappy()
+}
+
+func (*CronSourceStatus) GetReadyConditionType() apis.ConditionType {
+	return CronSourceConditionReady
+}
+
+func (ss *CronSourceStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return cronSourceCondSet.Manage(ss).GetCondition(t)
+}
+
+func (ss *CronSourceStatus) InitializeConditions() {
+	cronSourceCondSet.Manage(ss).InitializeConditions()
+}
+
+func (ss *CronSourceStatus) MarkStreamReady() {
+	cronSourceCondSet.Manage(ss).MarkTrue(CronSourceConditionStreamReady)
+}
+
+func (ss *CronSourceStatus) MarkStreamNotReady(message string) {
+	cronSourceCondSet.Manage(ss).MarkFalse(CronSourceConditionStreamReady, "StreamNotReady", message)
+}
+
+func (ss *CronSourceStatus) PropagateCronJobStatus(cjs *batchv1beta1.CronJobStatus) {
+	// cronjobs don't report readiness, the schedule is active once created
+	ss.LastScheduleTime = cjs.LastScheduleTime
+	cronSourceCondSet.Manage(ss).MarkTrue(CronSourceConditionCronJobReady)
+}
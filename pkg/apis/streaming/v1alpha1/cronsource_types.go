@@ -0,0 +1,112 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/projectriff/system/pkg/apis"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+var (
+	CronSourceLabelKey = GroupVersion.Group + "/cron-source"
+)
+
+var (
+	_ apis.Resource = (*CronSource)(nil)
+)
+
+// CronSourceSpec defines the desired state of CronSource
+type CronSourceSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// Schedule in cron format, see https://en.wikipedia.org/wiki/Cron.
+	Schedule string `json:"schedule"`
+
+	// Output is the name of the stream in this namespace each event is
+	// published to. Events are published with the content type of the stream.
+	Output string `json:"output"`
+
+	// Payload is a Go text/template rendered for each event. The template is
+	// evaluated with the fields .Time (the RFC3339 time the event fired),
+	// .Name and .Namespace of the source.
+	// +optional
+	Payload string `json:"payload,omitempty"`
+}
+
+// CronSourceStatus defines the observed state of CronSource
+type CronSourceStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	apis.Status `json:",inline"`
+
+	CronJobRef *refs.TypedLocalObjectReference `json:"cronJobRef,omitempty"`
+
+	// LastScheduleTime is the last time an event was scheduled
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// NextScheduleTime is the next time an event will be scheduled
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="riff"
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Output",type=string,JSONPath=`.spec.output`
+// +kubebuilder:printcolumn:name="Last Schedule",type=date,JSONPath=`.status.lastScheduleTime`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+
+// CronSource is the Schema for the cronsources API
+type CronSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CronSourceSpec   `json:"spec,omitempty"`
+	Status CronSourceStatus `json:"status,omitempty"`
+}
+
+func (*CronSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("CronSource")
+}
+
+func (s *CronSource) GetStatus() apis.ResourceStatus {
+	return &s.Status
+}
+
+// +kubebuilder:object:root=true
+
+// CronSourceList contains a list of CronSource
+type CronSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CronSource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CronSource{}, &CronSourceList{})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+func TestValidateCronSource(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *CronSource
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &CronSource{},
+		expected: validation.ErrMissingField("spec"),
+	}, {
+		name: "valid",
+		target: &CronSource{
+			Spec: CronSourceSpec{
+				Schedule: "*/5 * * * *",
+				Output:   "my-stream",
+			},
+		},
+		expected: validation.FieldErrors{},
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateCronSource(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateCronSourceSpec(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *CronSourceSpec
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &CronSourceSpec{},
+		expected: validation.ErrMissingField(validation.CurrentField),
+	}, {
+		name: "valid",
+		target: &CronSourceSpec{
+			Schedule: "*/5 * * * *",
+			Output:   "my-stream",
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid descriptor",
+		target: &CronSourceSpec{
+			Schedule: "@hourly",
+			Output:   "my-stream",
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid payload",
+		target: &CronSourceSpec{
+			Schedule: "*/5 * * * *",
+			Output:   "my-stream",
+			Payload:  `{"time": "{{ .Time }}"}`,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "missing schedule",
+		target: &CronSourceSpec{
+			Output: "my-stream",
+		},
+		expected: validation.ErrMissingField("schedule"),
+	}, {
+		name: "invalid schedule",
+		target: &CronSourceSpec{
+			Schedule: "* * *",
+			Output:   "my-stream",
+		},
+		expected: validation.ErrInvalidValue(`* * *: expected exactly 5 fields, found 3: "* * *"`, "schedule"),
+	}, {
+		name: "schedule out of range",
+		target: &CronSourceSpec{
+			Schedule: "0 24 * * *",
+			Output:   "my-stream",
+		},
+		expected: validation.ErrInvalidValue(`0 24 * * *: hour: "24" is out of range 0-23`, "schedule"),
+	}, {
+		name: "missing output",
+		target: &CronSourceSpec{
+			Schedule: "*/5 * * * *",
+		},
+		expected: validation.ErrMissingField("output"),
+	}, {
+		name: "invalid payload",
+		target: &CronSourceSpec{
+			Schedule: "*/5 * * * *",
+			Output:   "my-stream",
+			Payload:  "{{ .Time",
+		},
+		expected: validation.ErrInvalidValue("template: payload:1: unclosed action", "payload"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateCronSourceSpec(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
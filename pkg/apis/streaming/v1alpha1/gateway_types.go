@@ -31,6 +31,18 @@ import (
 var (
 	GatewayLabelKey     = GroupVersion.Group + "/gateway"
 	GatewayTypeLabelKey = GroupVersion.Group + "/gateway-type"
+	// GatewayTriggerAuthenticationAnnotationKey names the KEDA
+	// TriggerAuthentication consumers of the gateway authenticate with
+	GatewayTriggerAuthenticationAnnotationKey = GroupVersion.Group + "/trigger-authentication"
+	// GatewayExposureLabelKey marks the children publishing a gateway outside
+	// the cluster
+	GatewayExposureLabelKey = GroupVersion.Group + "/gateway-exposure"
+)
+
+const (
+	// GatewayAuthTokenKey is the key within the auth secret holding the token
+	// clients authenticate to the gateway with
+	GatewayAuthTokenKey = "token"
 )
 
 var (
@@ -42,9 +54,117 @@ type GatewaySpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// Replicas of the gateway deployment. The replicas of an existing
+	// deployment are retained when not set.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
 	// +optional
 	Template *corev1.PodTemplateSpec `json:"template,omitempty"`
 	Ports    []corev1.ServicePort    `json:"ports,omitempty"`
+
+	// Exposure publishes the port named `gateway` to producers outside the
+	// cluster. The gateway is only reachable within the cluster when not set.
+	// +optional
+	Exposure *GatewayExposure `json:"exposure,omitempty"`
+}
+
+// GatewayExposureType is how a gateway is reachable
+type GatewayExposureType string
+
+const (
+	// GatewayExposureClusterLocal gateways are only reachable within the
+	// cluster
+	GatewayExposureClusterLocal GatewayExposureType = "ClusterLocal"
+	// GatewayExposureLoadBalancer gateways are published by a Service of type
+	// LoadBalancer
+	GatewayExposureLoadBalancer GatewayExposureType = "LoadBalancer"
+	// GatewayExposureIngress gateways are published by an Ingress for a host
+	GatewayExposureIngress GatewayExposureType = "Ingress"
+)
+
+// GatewayExposure publishes a gateway to producers outside the cluster
+type GatewayExposure struct {
+	// Type of exposure, one of `ClusterLocal`, `LoadBalancer` or `Ingress`.
+	// Defaults to `ClusterLocal`.
+	// +optional
+	Type GatewayExposureType `json:"type,omitempty"`
+
+	// Host the Ingress routes to the gateway, required for Ingress exposure.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// TLSSecretRef references a TLS Secret in this namespace the Ingress
+	// terminates TLS for the host with.
+	// +optional
+	TLSSecretRef *corev1.LocalObjectReference `json:"tlsSecretRef,omitempty"`
+
+	// AuthSecretRef references a Secret in this namespace holding the token
+	// clients authenticate to the gateway with under the `token` key. Required
+	// when the gateway is exposed outside the cluster.
+	// +optional
+	AuthSecretRef *corev1.LocalObjectReference `json:"authSecretRef,omitempty"`
+}
+
+// External is true when the gateway is reachable from outside the cluster
+func (e *GatewayExposure) External() bool {
+	return e != nil && (e.Type == GatewayExposureLoadBalancer || e.Type == GatewayExposureIngress)
+}
+
+// GatewayDeploymentSpec tunes how the pods of a gateway type are deployed and
+// exposed
+type GatewayDeploymentSpec struct {
+	// Replicas of the gateway to run. Running more than one replica requires
+	// a durable positions store, so consumers resume from the same position
+	// regardless of the replica they connect to.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources are the compute resources requested by, and limits of, the
+	// gateway container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector constrains the nodes gateway pods are scheduled on.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations allow gateway pods to be scheduled on tainted nodes.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PositionsStore persists the positions of consumers. Positions are held
+	// in memory, and lost when the gateway restarts, when not set.
+	// +optional
+	PositionsStore *GatewayPositionsStore `json:"positionsStore,omitempty"`
+
+	// Exposure publishes the gateway to producers outside the cluster. The
+	// gateway is only reachable within the cluster when not set.
+	// +optional
+	Exposure *GatewayExposure `json:"exposure,omitempty"`
+}
+
+// GatewayPositionsStore is a durable store for the positions of consumers.
+// Exactly one store must be set.
+type GatewayPositionsStore struct {
+	// Redis stores positions in a Redis server.
+	// +optional
+	Redis *RedisPositionsStore `json:"redis,omitempty"`
+}
+
+// RedisPositionsStore stores positions in a Redis server
+type RedisPositionsStore struct {
+	// Address of the Redis server as a `host:port` pair.
+	Address string `json:"address"`
+
+	// PasswordSecretRef optionally selects a key of a Secret in this namespace
+	// holding the password to authenticate to Redis with.
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// TLS enables TLS for connections to the Redis server.
+	// +optional
+	TLS bool `json:"tls,omitempty"`
 }
 
 // GatewayStatus defines the observed state of Gateway
@@ -56,6 +176,17 @@ type GatewayStatus struct {
 	Address       *apis.Addressable               `json:"address,omitempty"`
 	DeploymentRef *refs.TypedLocalObjectReference `json:"deploymentRef,omitempty"`
 	ServiceRef    *refs.TypedLocalObjectReference `json:"serviceRef,omitempty"`
+
+	PodDisruptionBudgetRef *refs.TypedLocalObjectReference `json:"podDisruptionBudgetRef,omitempty"`
+
+	// PublicAddress is the address producers outside the cluster reach the
+	// gateway at, once assigned
+	PublicAddress *apis.Addressable `json:"publicAddress,omitempty"`
+	// PublicServiceRef references the LoadBalancer Service publishing the
+	// gateway
+	PublicServiceRef *refs.TypedLocalObjectReference `json:"publicServiceRef,omitempty"`
+	// IngressRef references the Ingress publishing the gateway
+	IngressRef *refs.TypedLocalObjectReference `json:"ingressRef,omitempty"`
 }
 
 // +kubebuilder:object:root=true
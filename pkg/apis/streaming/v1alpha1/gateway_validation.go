@@ -17,8 +17,11 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"net"
+
 	"k8s.io/apimachinery/pkg/api/equality"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/projectriff/system/pkg/validation"
@@ -62,7 +65,111 @@ func (s *GatewaySpec) Validate() validation.FieldErrors {
 
 	errs := validation.FieldErrors{}
 
-	// TODO validate
+	if s.Replicas != nil && *s.Replicas < 0 {
+		errs = errs.Also(validation.ErrInvalidValue(*s.Replicas, "replicas"))
+	}
+
+	if s.Exposure != nil {
+		errs = errs.Also(s.Exposure.Validate().ViaField("exposure"))
+	}
+
+	return errs
+}
+
+func (s *GatewayDeploymentSpec) Validate() validation.FieldErrors {
+	return s.validate(false)
+}
+
+// validate the deployment of a gateway, durablePositions is true when the
+// gateway type stores positions durably without a positions store.
+func (s *GatewayDeploymentSpec) validate(durablePositions bool) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if s.Replicas != nil {
+		if *s.Replicas < 0 {
+			errs = errs.Also(validation.ErrInvalidValue(*s.Replicas, "replicas"))
+		} else if *s.Replicas > 1 && s.PositionsStore == nil && !durablePositions {
+			// positions held in memory are not shared between replicas
+			errs = errs.Also(validation.ErrMissingField("positionsStore"))
+		}
+	}
+
+	if s.PositionsStore != nil {
+		errs = errs.Also(s.PositionsStore.Validate().ViaField("positionsStore"))
+	}
+
+	if s.Exposure != nil {
+		errs = errs.Also(s.Exposure.Validate().ViaField("exposure"))
+	}
+
+	return errs
+}
+
+func (e *GatewayExposure) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	switch e.Type {
+	case "", GatewayExposureClusterLocal, GatewayExposureLoadBalancer:
+		if e.Host != "" {
+			errs = errs.Also(validation.ErrDisallowedFields("host", "only Ingress exposure routes a host"))
+		}
+		if e.TLSSecretRef != nil {
+			errs = errs.Also(validation.ErrDisallowedFields("tlsSecretRef", "only Ingress exposure terminates TLS"))
+		}
+	case GatewayExposureIngress:
+		if e.Host == "" {
+			errs = errs.Also(validation.ErrMissingField("host"))
+		} else if msgs := k8svalidation.IsDNS1123Subdomain(e.Host); len(msgs) != 0 {
+			errs = errs.Also(validation.ErrInvalidValue(e.Host, "host"))
+		}
+		if e.TLSSecretRef != nil && e.TLSSecretRef.Name == "" {
+			errs = errs.Also(validation.ErrMissingField("tlsSecretRef.name"))
+		}
+	default:
+		errs = errs.Also(validation.ErrInvalidValue(e.Type, "type"))
+	}
+
+	if e.AuthSecretRef != nil {
+		if e.AuthSecretRef.Name == "" {
+			errs = errs.Also(validation.ErrMissingField("authSecretRef.name"))
+		}
+	} else if e.External() {
+		// producers outside the cluster must authenticate
+		errs = errs.Also(validation.ErrMissingField("authSecretRef"))
+	}
+
+	return errs
+}
+
+func (s *GatewayPositionsStore) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if s.Redis == nil {
+		errs = errs.Also(validation.ErrMissingOneOf("redis"))
+	} else {
+		errs = errs.Also(s.Redis.Validate().ViaField("redis"))
+	}
+
+	return errs
+}
+
+func (s *RedisPositionsStore) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if s.Address == "" {
+		errs = errs.Also(validation.ErrMissingField("address"))
+	} else if _, _, err := net.SplitHostPort(s.Address); err != nil {
+		errs = errs.Also(validation.ErrInvalidValue(s.Address, "address"))
+	}
+
+	if s.PasswordSecretRef != nil {
+		if s.PasswordSecretRef.Name == "" {
+			errs = errs.Also(validation.ErrMissingField("passwordSecretRef.name"))
+		}
+		if s.PasswordSecretRef.Key == "" {
+			errs = errs.Also(validation.ErrMissingField("passwordSecretRef.key"))
+		}
+	}
 
 	return errs
 }
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+
+	"github.com/projectriff/system/pkg/apis"
+)
+
+const (
+	HTTPSourceConditionReady                              = apis.ConditionReady
+	HTTPSourceConditionStreamReady     apis.ConditionType = "StreamReady"
+	HTTPSourceConditionDeploymentReady apis.ConditionType = "DeploymentReady"
+	HTTPSourceConditionServiceReady    apis.ConditionType = "ServiceReady"
+	HTTPSourceConditionIngressReady    apis.ConditionType = "IngressReady"
+)
+
+var httpSourceCondSet = apis.NewLivingConditionSet(
+	HTTPSourceConditionStreamReady,
+	HTTPSourceConditionDeploymentReady,
+	HTTPSourceConditionServiceReady,
+	HTTPSourceConditionIngressReady,
+)
+
+func (ss *HTTPSourceStatus) GetObservedGeneration() int64 {
+	return ss.ObservedGeneration
+}
+
+func (ss *HTTPSourceStatus) IsReady() bool {
+	return httpSourceCondSet.Manage(ss).IsHappy()
+}
+
+func (*HTTPSourceStatus) GetReadyConditionType() apis.ConditionType {
+	return HTTPSourceConditionReady
+}
+
+func (ss *HTTPSourceStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return httpSourceCondSet.Manage(ss).GetCondition(t)
+}
+
+func (ss *HTTPSourceStatus) InitializeConditions() {
+	httpSourceCondSet.Manage(ss).InitializeConditions()
+}
+
+func (ss *HTTPSourceStatus) MarkStreamReady() {
+	httpSourceCondSet.Manage(ss).MarkTrue(HTTPSourceConditionStreamReady)
+}
+
+func (ss *HTTPSourceStatus) MarkStreamNotReady(message string) {
+	httpSourceCondSet.Manage(ss).MarkFalse(HTTPSourceConditionStreamReady, "StreamNotReady", message)
+}
+
+func (ss *HTTPSourceStatus) PropagateDeploymentStatus(ds *appsv1.DeploymentStatus) {
+	var available, progressing *appsv1.DeploymentCondition
+	for i := range ds.Conditions {
+		switch ds.Conditions[i].Type {
+		case appsv1.DeploymentAvailable:
+			available = &ds.Conditions[i]
+		case appsv1.DeploymentProgressing:
+			progressing = &ds.Conditions[i]
+		}
+	}
+	if available == nil || progressing == nil {
+		return
+	}
+	if progressing.Status == corev1.ConditionTrue && available.Status == corev1.ConditionFalse {
+		// DeploymentAvailable is False while progressing, avoid reporting HTTPSourceConditionReady as False
+		httpSourceCondSet.Manage(ss).MarkUnknown(HTTPSourceConditionDeploymentReady, progressing.Reason, progressing.Message)
+		return
+	}
+	switch {
+	case available.Status == corev1.ConditionUnknown:
+		httpSourceCondSet.Manage(ss).MarkUnknown(HTTPSourceConditionDeploymentReady, available.Reason, available.Message)
+	case available.Status == corev1.ConditionTrue:
+		httpSourceCondSet.Manage(ss).MarkTrue(HTTPSourceConditionDeploymentReady)
+	case available.Status == corev1.ConditionFalse:
+		httpSourceCondSet.Manage(ss).MarkFalse(HTTPSourceConditionDeploymentReady, available.Reason, available.Message)
+	}
+}
+
+func (ss *HTTPSourceStatus) PropagateServiceStatus(s *corev1.ServiceStatus) {
+	// services don't have meaningful status
+	httpSourceCondSet.Manage(ss).MarkTrue(HTTPSourceConditionServiceReady)
+}
+
+func (ss *HTTPSourceStatus) MarkServiceNotOwned(name string) {
+	httpSourceCondSet.Manage(ss).MarkFalse(HTTPSourceConditionServiceReady, "NotOwned", "There is an existing Service %q that the HTTPSource does not own.", name)
+}
+
+func (ss *HTTPSourceStatus) PropagateIngressStatus(is *networkingv1beta1.IngressStatus) {
+	// ingress status is not set reliably
+	httpSourceCondSet.Manage(ss).MarkTrue(HTTPSourceConditionIngressReady)
+}
+
+func (ss *HTTPSourceStatus) MarkIngressNotRequired() {
+	httpSourceCondSet.Manage(ss).MarkTrue(HTTPSourceConditionIngressReady)
+}
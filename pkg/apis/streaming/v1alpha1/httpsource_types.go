@@ -0,0 +1,110 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/projectriff/system/pkg/apis"
+	corev1alpha1 "github.com/projectriff/system/pkg/apis/core/v1alpha1"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+var (
+	HTTPSourceLabelKey = GroupVersion.Group + "/http-source"
+)
+
+var (
+	_ apis.Resource = (*HTTPSource)(nil)
+)
+
+// HTTPSourceSpec defines the desired state of HTTPSource
+type HTTPSourceSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// Output is the name of the stream in this namespace the body of each
+	// request is published to. Requests must have a Content-Type that is
+	// compatible with the content type of the stream.
+	Output string `json:"output"`
+
+	// IngressPolicy defines whether the source should be reachable from
+	// outside the cluster
+	IngressPolicy corev1alpha1.IngressPolicy `json:"ingressPolicy,omitempty"`
+}
+
+// HTTPSourceStatus defines the observed state of HTTPSource
+type HTTPSourceStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	apis.Status `json:",inline"`
+
+	DeploymentRef *refs.TypedLocalObjectReference `json:"deploymentRef,omitempty"`
+	ServiceRef    *refs.TypedLocalObjectReference `json:"serviceRef,omitempty"`
+	IngressRef    *refs.TypedLocalObjectReference `json:"ingressRef,omitempty"`
+
+	// Address to target this source internally
+	Address *apis.Addressable `json:"address,omitempty"`
+
+	// URL to target this source publicly
+	URL string `json:"url,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="riff"
+// +kubebuilder:printcolumn:name="Output",type=string,JSONPath=`.spec.output`
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.url`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+
+// HTTPSource is the Schema for the httpsources API
+type HTTPSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HTTPSourceSpec   `json:"spec,omitempty"`
+	Status HTTPSourceStatus `json:"status,omitempty"`
+}
+
+func (*HTTPSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("HTTPSource")
+}
+
+func (s *HTTPSource) GetStatus() apis.ResourceStatus {
+	return &s.Status
+}
+
+// +kubebuilder:object:root=true
+
+// HTTPSourceList contains a list of HTTPSource
+type HTTPSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HTTPSource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HTTPSource{}, &HTTPSourceList{})
+}
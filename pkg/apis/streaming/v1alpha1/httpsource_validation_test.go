@@ -0,0 +1,98 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1alpha1 "github.com/projectriff/system/pkg/apis/core/v1alpha1"
+	"github.com/projectriff/system/pkg/validation"
+)
+
+func TestValidateHTTPSource(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *HTTPSource
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &HTTPSource{},
+		expected: validation.ErrMissingField("spec"),
+	}, {
+		name: "valid",
+		target: &HTTPSource{
+			Spec: HTTPSourceSpec{
+				Output: "my-stream",
+			},
+		},
+		expected: validation.FieldErrors{},
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateHTTPSource(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateHTTPSourceSpec(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *HTTPSourceSpec
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &HTTPSourceSpec{},
+		expected: validation.ErrMissingField(validation.CurrentField),
+	}, {
+		name: "valid",
+		target: &HTTPSourceSpec{
+			Output: "my-stream",
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "external ingress",
+		target: &HTTPSourceSpec{
+			Output:        "my-stream",
+			IngressPolicy: corev1alpha1.IngressPolicyExternal,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "missing output",
+		target: &HTTPSourceSpec{
+			IngressPolicy: corev1alpha1.IngressPolicyClusterLocal,
+		},
+		expected: validation.ErrMissingField("output"),
+	}, {
+		name: "invalid ingress policy",
+		target: &HTTPSourceSpec{
+			Output:        "my-stream",
+			IngressPolicy: "bogus",
+		},
+		expected: validation.ErrInvalidValue(corev1alpha1.IngressPolicy("bogus"), "ingressPolicy"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateHTTPSourceSpec(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
@@ -40,6 +40,9 @@ var (
 type InMemoryGatewaySpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+
+	// GatewayDeploymentSpec tunes the pods of the gateway.
+	GatewayDeploymentSpec `json:",inline"`
 }
 
 // InMemoryGatewayStatus defines the observed state of InMemoryGateway
@@ -52,6 +55,10 @@ type InMemoryGatewayStatus struct {
 	GatewayRef       *refs.TypedLocalObjectReference `json:"gatewayRef,omitempty"`
 	GatewayImage     string                          `json:"gatewayImage,omitempty"`
 	ProvisionerImage string                          `json:"provisionerImage,omitempty"`
+
+	// PublicAddress is the address producers outside the cluster reach the
+	// gateway at, once assigned
+	PublicAddress *apis.Addressable `json:"publicAddress,omitempty"`
 }
 
 // +kubebuilder:object:root=true
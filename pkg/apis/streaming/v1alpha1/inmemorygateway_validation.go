@@ -55,14 +55,14 @@ func (r *InMemoryGateway) Validate() validation.FieldErrors {
 }
 
 func (s *InMemoryGatewaySpec) Validate() validation.FieldErrors {
-	// nothing to validate, uncomment when there are fields
-	// if equality.Semantic.DeepEqual(s, &InMemoryGatewaySpec{}) {
-	// 	return validation.ErrMissingField(validation.CurrentField)
-	// }
-
 	errs := validation.FieldErrors{}
 
-	// add validation rules here
+	if s.Replicas != nil && *s.Replicas > 1 {
+		// records held in memory are not shared between replicas
+		errs = errs.Also(validation.ErrInvalidValue(*s.Replicas, "replicas"))
+	} else {
+		errs = errs.Also(s.GatewayDeploymentSpec.Validate())
+	}
 
 	return errs
 }
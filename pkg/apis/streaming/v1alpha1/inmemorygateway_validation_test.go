@@ -44,6 +44,9 @@ func TestValidateInMemoryGateway(t *testing.T) {
 }
 
 func TestValidateInMemoryGatewaySpec(t *testing.T) {
+	one := int32(1)
+	two := int32(2)
+
 	for _, c := range []struct {
 		name     string
 		target   *InMemoryGatewaySpec
@@ -52,6 +55,22 @@ func TestValidateInMemoryGatewaySpec(t *testing.T) {
 		name:     "empty",
 		target:   &InMemoryGatewaySpec{},
 		expected: validation.FieldErrors{},
+	}, {
+		name: "single replica",
+		target: &InMemoryGatewaySpec{
+			GatewayDeploymentSpec: GatewayDeploymentSpec{
+				Replicas: &one,
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "multiple replicas",
+		target: &InMemoryGatewaySpec{
+			GatewayDeploymentSpec: GatewayDeploymentSpec{
+				Replicas: &two,
+			},
+		},
+		expected: validation.ErrInvalidValue(int32(2), "replicas"),
 	}} {
 		t.Run(c.name, func(t *testing.T) {
 			actual := c.target.Validate()
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -32,6 +33,23 @@ var (
 	KafkaGatewayType     = "kafka"
 )
 
+const (
+	// KafkaGatewayCAKey is the key within the CA secret holding the CA bundle
+	KafkaGatewayCAKey = "ca.crt"
+	// KafkaGatewayClientCertKey is the key within the client cert secret
+	// holding the certificate
+	KafkaGatewayClientCertKey = corev1.TLSCertKey
+	// KafkaGatewayClientKeyKey is the key within the client cert secret
+	// holding the private key
+	KafkaGatewayClientKeyKey = corev1.TLSPrivateKeyKey
+	// KafkaGatewayUsernameKey is the key within the credentials secret holding
+	// the SASL username
+	KafkaGatewayUsernameKey = "username"
+	// KafkaGatewayPasswordKey is the key within the credentials secret holding
+	// the SASL password
+	KafkaGatewayPasswordKey = "password"
+)
+
 var (
 	_ apis.Resource = (*KafkaGateway)(nil)
 )
@@ -47,6 +65,55 @@ type KafkaGatewaySpec struct {
 	//
 	// A host and port pair uses `:` as the separator.
 	BootstrapServers string `json:"bootstrapServers"`
+
+	// TLS encrypts connections to the Kafka brokers. Connections are in
+	// plaintext when not set.
+	// +optional
+	TLS *KafkaTLS `json:"tls,omitempty"`
+
+	// SASL authenticates connections to the Kafka brokers.
+	// +optional
+	SASL *KafkaSASL `json:"sasl,omitempty"`
+
+	// GatewayDeploymentSpec tunes the pods of the gateway.
+	GatewayDeploymentSpec `json:",inline"`
+}
+
+// KafkaTLS configures TLS for connections to the Kafka brokers
+type KafkaTLS struct {
+	// CASecretRef references a Secret in this namespace holding the PEM
+	// encoded CA bundle to verify the brokers with under the `ca.crt` key.
+	// The system roots are trusted when not set.
+	// +optional
+	CASecretRef *corev1.LocalObjectReference `json:"caSecretRef,omitempty"`
+
+	// ClientCertSecretRef references a Secret in this namespace holding the
+	// PEM encoded client certificate and key to authenticate to the brokers
+	// with under the `tls.crt` and `tls.key` keys.
+	// +optional
+	ClientCertSecretRef *corev1.LocalObjectReference `json:"clientCertSecretRef,omitempty"`
+}
+
+// KafkaSASLMechanism is a SASL mechanism supported by the Kafka brokers
+type KafkaSASLMechanism string
+
+const (
+	KafkaSASLMechanismPlain       KafkaSASLMechanism = "PLAIN"
+	KafkaSASLMechanismScramSHA256 KafkaSASLMechanism = "SCRAM-SHA-256"
+	KafkaSASLMechanismScramSHA512 KafkaSASLMechanism = "SCRAM-SHA-512"
+)
+
+// KafkaSASL configures SASL authentication for connections to the Kafka
+// brokers
+type KafkaSASL struct {
+	// Mechanism to authenticate with, one of `PLAIN`, `SCRAM-SHA-256` or
+	// `SCRAM-SHA-512`.
+	Mechanism KafkaSASLMechanism `json:"mechanism"`
+
+	// CredentialsSecretRef references a Secret in this namespace holding the
+	// credentials to authenticate with under the `username` and `password`
+	// keys.
+	CredentialsSecretRef corev1.LocalObjectReference `json:"credentialsSecretRef"`
 }
 
 // KafkaGatewayStatus defines the observed state of KafkaGateway
@@ -59,6 +126,14 @@ type KafkaGatewayStatus struct {
 	GatewayRef       *refs.TypedLocalObjectReference `json:"gatewayRef,omitempty"`
 	GatewayImage     string                          `json:"gatewayImage,omitempty"`
 	ProvisionerImage string                          `json:"provisionerImage,omitempty"`
+
+	// PublicAddress is the address producers outside the cluster reach the
+	// gateway at, once assigned
+	PublicAddress *apis.Addressable `json:"publicAddress,omitempty"`
+
+	// TriggerAuthenticationRef references the KEDA TriggerAuthentication
+	// processors consuming from this gateway use to authenticate with
+	TriggerAuthenticationRef *refs.TypedLocalObjectReference `json:"triggerAuthenticationRef,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -65,6 +65,45 @@ func (s *KafkaGatewaySpec) Validate() validation.FieldErrors {
 	if s.BootstrapServers == "" {
 		errs = errs.Also(validation.ErrMissingField("bootstrapServers"))
 	}
+	if s.TLS != nil {
+		errs = errs.Also(s.TLS.Validate().ViaField("tls"))
+	}
+	if s.SASL != nil {
+		errs = errs.Also(s.SASL.Validate().ViaField("sasl"))
+	}
+
+	errs = errs.Also(s.GatewayDeploymentSpec.Validate())
+
+	return errs
+}
+
+func (s *KafkaTLS) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if s.CASecretRef != nil && s.CASecretRef.Name == "" {
+		errs = errs.Also(validation.ErrMissingField("caSecretRef.name"))
+	}
+	if s.ClientCertSecretRef != nil && s.ClientCertSecretRef.Name == "" {
+		errs = errs.Also(validation.ErrMissingField("clientCertSecretRef.name"))
+	}
+
+	return errs
+}
+
+func (s *KafkaSASL) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	switch s.Mechanism {
+	case "":
+		errs = errs.Also(validation.ErrMissingField("mechanism"))
+	case KafkaSASLMechanismPlain, KafkaSASLMechanismScramSHA256, KafkaSASLMechanismScramSHA512:
+		// valid
+	default:
+		errs = errs.Also(validation.ErrInvalidValue(s.Mechanism, "mechanism"))
+	}
+	if s.CredentialsSecretRef.Name == "" {
+		errs = errs.Also(validation.ErrMissingField("credentialsSecretRef.name"))
+	}
 
 	return errs
 }
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/projectriff/system/pkg/validation"
 )
@@ -52,6 +53,9 @@ func TestValidateKafkaGateway(t *testing.T) {
 }
 
 func TestValidateKafkaGatewaySpec(t *testing.T) {
+	negativeOne := int32(-1)
+	three := int32(3)
+
 	for _, c := range []struct {
 		name     string
 		target   *KafkaGatewaySpec
@@ -66,6 +70,142 @@ func TestValidateKafkaGatewaySpec(t *testing.T) {
 			BootstrapServers: "localhost:9092",
 		},
 		expected: validation.FieldErrors{},
+	}, {
+		name: "tls and sasl",
+		target: &KafkaGatewaySpec{
+			BootstrapServers: "localhost:9092",
+			TLS: &KafkaTLS{
+				CASecretRef:         &corev1.LocalObjectReference{Name: "kafka-ca"},
+				ClientCertSecretRef: &corev1.LocalObjectReference{Name: "kafka-client"},
+			},
+			SASL: &KafkaSASL{
+				Mechanism:            KafkaSASLMechanismScramSHA512,
+				CredentialsSecretRef: corev1.LocalObjectReference{Name: "kafka-credentials"},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "tls, system roots",
+		target: &KafkaGatewaySpec{
+			BootstrapServers: "localhost:9092",
+			TLS:              &KafkaTLS{},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "tls, empty secret refs",
+		target: &KafkaGatewaySpec{
+			BootstrapServers: "localhost:9092",
+			TLS: &KafkaTLS{
+				CASecretRef:         &corev1.LocalObjectReference{},
+				ClientCertSecretRef: &corev1.LocalObjectReference{},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMissingField("tls.caSecretRef.name"),
+			validation.ErrMissingField("tls.clientCertSecretRef.name"),
+		),
+	}, {
+		name: "sasl, empty",
+		target: &KafkaGatewaySpec{
+			BootstrapServers: "localhost:9092",
+			SASL:             &KafkaSASL{},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMissingField("sasl.mechanism"),
+			validation.ErrMissingField("sasl.credentialsSecretRef.name"),
+		),
+	}, {
+		name: "sasl, invalid mechanism",
+		target: &KafkaGatewaySpec{
+			BootstrapServers: "localhost:9092",
+			SASL: &KafkaSASL{
+				Mechanism:            "GSSAPI",
+				CredentialsSecretRef: corev1.LocalObjectReference{Name: "kafka-credentials"},
+			},
+		},
+		expected: validation.ErrInvalidValue(KafkaSASLMechanism("GSSAPI"), "sasl.mechanism"),
+	}, {
+		name: "replicas",
+		target: &KafkaGatewaySpec{
+			BootstrapServers: "localhost:9092",
+			GatewayDeploymentSpec: GatewayDeploymentSpec{
+				Replicas: &three,
+				PositionsStore: &GatewayPositionsStore{
+					Redis: &RedisPositionsStore{
+						Address: "redis:6379",
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "replicas, negative",
+		target: &KafkaGatewaySpec{
+			BootstrapServers: "localhost:9092",
+			GatewayDeploymentSpec: GatewayDeploymentSpec{
+				Replicas: &negativeOne,
+			},
+		},
+		expected: validation.ErrInvalidValue(int32(-1), "replicas"),
+	}, {
+		name: "replicas, missing positions store",
+		target: &KafkaGatewaySpec{
+			BootstrapServers: "localhost:9092",
+			GatewayDeploymentSpec: GatewayDeploymentSpec{
+				Replicas: &three,
+			},
+		},
+		expected: validation.ErrMissingField("positionsStore"),
+	}, {
+		name: "positions store, empty",
+		target: &KafkaGatewaySpec{
+			BootstrapServers: "localhost:9092",
+			GatewayDeploymentSpec: GatewayDeploymentSpec{
+				PositionsStore: &GatewayPositionsStore{},
+			},
+		},
+		expected: validation.ErrMissingOneOf("redis").ViaField("positionsStore"),
+	}, {
+		name: "positions store, invalid redis",
+		target: &KafkaGatewaySpec{
+			BootstrapServers: "localhost:9092",
+			GatewayDeploymentSpec: GatewayDeploymentSpec{
+				PositionsStore: &GatewayPositionsStore{
+					Redis: &RedisPositionsStore{
+						Address:           "redis",
+						PasswordSecretRef: &corev1.SecretKeySelector{},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidValue("redis", "positionsStore.redis.address"),
+			validation.ErrMissingField("positionsStore.redis.passwordSecretRef.name"),
+			validation.ErrMissingField("positionsStore.redis.passwordSecretRef.key"),
+		),
+	}, {
+		name: "exposure",
+		target: &KafkaGatewaySpec{
+			BootstrapServers: "localhost:9092",
+			GatewayDeploymentSpec: GatewayDeploymentSpec{
+				Exposure: &GatewayExposure{
+					Type:          GatewayExposureLoadBalancer,
+					AuthSecretRef: &corev1.LocalObjectReference{Name: "my-token"},
+				},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "exposure, unauthenticated",
+		target: &KafkaGatewaySpec{
+			BootstrapServers: "localhost:9092",
+			GatewayDeploymentSpec: GatewayDeploymentSpec{
+				Exposure: &GatewayExposure{
+					Type: GatewayExposureLoadBalancer,
+				},
+			},
+		},
+		expected: validation.ErrMissingField("exposure.authSecretRef"),
 	}} {
 		t.Run(c.name, func(t *testing.T) {
 			actual := c.target.Validate()
@@ -75,3 +215,94 @@ func TestValidateKafkaGatewaySpec(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateGatewayExposure(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *GatewayExposure
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &GatewayExposure{},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "cluster local",
+		target: &GatewayExposure{
+			Type: GatewayExposureClusterLocal,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "cluster local, authenticated",
+		target: &GatewayExposure{
+			Type:          GatewayExposureClusterLocal,
+			AuthSecretRef: &corev1.LocalObjectReference{Name: "my-token"},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "cluster local, ingress fields",
+		target: &GatewayExposure{
+			Type:         GatewayExposureClusterLocal,
+			Host:         "gateway.example.com",
+			TLSSecretRef: &corev1.LocalObjectReference{Name: "my-tls"},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrDisallowedFields("host", "only Ingress exposure routes a host"),
+			validation.ErrDisallowedFields("tlsSecretRef", "only Ingress exposure terminates TLS"),
+		),
+	}, {
+		name: "load balancer",
+		target: &GatewayExposure{
+			Type:          GatewayExposureLoadBalancer,
+			AuthSecretRef: &corev1.LocalObjectReference{Name: "my-token"},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "load balancer, unauthenticated",
+		target: &GatewayExposure{
+			Type: GatewayExposureLoadBalancer,
+		},
+		expected: validation.ErrMissingField("authSecretRef"),
+	}, {
+		name: "ingress",
+		target: &GatewayExposure{
+			Type:          GatewayExposureIngress,
+			Host:          "gateway.example.com",
+			TLSSecretRef:  &corev1.LocalObjectReference{Name: "my-tls"},
+			AuthSecretRef: &corev1.LocalObjectReference{Name: "my-token"},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "ingress, missing host",
+		target: &GatewayExposure{
+			Type:          GatewayExposureIngress,
+			AuthSecretRef: &corev1.LocalObjectReference{Name: "my-token"},
+		},
+		expected: validation.ErrMissingField("host"),
+	}, {
+		name: "ingress, invalid fields",
+		target: &GatewayExposure{
+			Type:          GatewayExposureIngress,
+			Host:          "Gateway_Example",
+			TLSSecretRef:  &corev1.LocalObjectReference{},
+			AuthSecretRef: &corev1.LocalObjectReference{},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidValue("Gateway_Example", "host"),
+			validation.ErrMissingField("tlsSecretRef.name"),
+			validation.ErrMissingField("authSecretRef.name"),
+		),
+	}, {
+		name: "invalid type",
+		target: &GatewayExposure{
+			Type: "NodePort",
+		},
+		expected: validation.ErrInvalidValue(GatewayExposureType("NodePort"), "type"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateGatewayExposure(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/projectriff/system/pkg/apis"
+)
+
+const (
+	NATSGatewayConditionReady                           = apis.ConditionReady
+	NATSGatewayConditionGatewayReady apis.ConditionType = "GatewayReady"
+)
+
+var natsGatewayCondSet = apis.NewLivingConditionSet(
+	NATSGatewayConditionGatewayReady,
+)
+
+func (s *NATSGatewayStatus) GetObservedGeneration() int64 {
+	return s.ObservedGeneration
+}
+
+func (s *NATSGatewayStatus) IsReady() bool {
+	return natsGatewayCondSet.Manage(s).IsHappy()
+}
+
+func (*NATSGatewayStatus) GetReadyConditionType() apis.ConditionType {
+	return NATSGatewayConditionReady
+}
+
+func (s *NATSGatewayStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return natsGatewayCondSet.Manage(s).GetCondition(t)
+}
+
+func (s *NATSGatewayStatus) InitializeConditions() {
+	natsGatewayCondSet.Manage(s).InitializeConditions()
+}
+
+func (s *NATSGatewayStatus) PropagateGatewayStatus(gs *GatewayStatus) {
+	sc := gs.GetCondition(GatewayConditionReady)
+	if sc == nil {
+		return
+	}
+	switch {
+	case sc.Status == corev1.ConditionUnknown:
+		natsGatewayCondSet.Manage(s).MarkUnknown(NATSGatewayConditionGatewayReady, sc.Reason, sc.Message)
+	case sc.Status == corev1.ConditionTrue:
+		natsGatewayCondSet.Manage(s).MarkTrue(NATSGatewayConditionGatewayReady)
+	case sc.Status == corev1.ConditionFalse:
+		natsGatewayCondSet.Manage(s).MarkFalse(NATSGatewayConditionGatewayReady, sc.Reason, sc.Message)
+	}
+}
+
+func (s *NATSGatewayStatus) MarkGatewayNotOwned(name string) {
+	natsGatewayCondSet.Manage(s).MarkFalse(NATSGatewayConditionGatewayReady, "NotOwned", "There is an existing Gateway %q that the NATSGateway does not own.", name)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/projectriff/system/pkg/apis"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+var (
+	NATSGatewayLabelKey = GroupVersion.Group + "/nats-gateway"
+	NATSGatewayType     = "nats"
+)
+
+const (
+	// NATSGatewayServersKey is the key within the secret holding a
+	// comma-separated list of NATS server URLs
+	NATSGatewayServersKey = "servers"
+	// NATSGatewayUsernameKey is the optional key within the secret holding
+	// the username to connect with
+	NATSGatewayUsernameKey = "username"
+	// NATSGatewayPasswordKey is the optional key within the secret holding
+	// the password to connect with
+	NATSGatewayPasswordKey = "password"
+	// NATSGatewayTokenKey is the optional key within the secret holding the
+	// token to connect with
+	NATSGatewayTokenKey = "token"
+	// NATSGatewayCredentialsKey is the optional key within the secret holding
+	// a user credentials file with the JWT and NKey seed to connect with
+	NATSGatewayCredentialsKey = "credentials"
+)
+
+var (
+	_ apis.Resource = (*NATSGateway)(nil)
+)
+
+// NATSGatewaySpec defines the desired state of NATSGateway
+type NATSGatewaySpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// SecretRef references a Secret in this namespace holding the server URLs
+	// of the NATS cluster under the `servers` key, a comma-separated list of
+	// URLs, for example `nats://nats-0:4222,nats://nats-1:4222`.
+	//
+	// The Secret may also hold credentials to connect to the cluster with,
+	// either a `username` and `password`, a `token` or a `credentials` file.
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+
+	// GatewayDeploymentSpec tunes the pods of the gateway.
+	GatewayDeploymentSpec `json:",inline"`
+}
+
+// NATSGatewayStatus defines the observed state of NATSGateway
+type NATSGatewayStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	apis.Status      `json:",inline"`
+	Address          *apis.Addressable               `json:"address,omitempty"`
+	GatewayRef       *refs.TypedLocalObjectReference `json:"gatewayRef,omitempty"`
+	GatewayImage     string                          `json:"gatewayImage,omitempty"`
+	ProvisionerImage string                          `json:"provisionerImage,omitempty"`
+
+	// PublicAddress is the address producers outside the cluster reach the
+	// gateway at, once assigned
+	PublicAddress *apis.Addressable `json:"publicAddress,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="riff"
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.spec.secretRef.name`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+
+// NATSGateway is the Schema for the gateways API
+type NATSGateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NATSGatewaySpec   `json:"spec,omitempty"`
+	Status NATSGatewayStatus `json:"status,omitempty"`
+}
+
+func (*NATSGateway) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("NATSGateway")
+}
+
+func (p *NATSGateway) GetStatus() apis.ResourceStatus {
+	return &p.Status
+}
+
+// +kubebuilder:object:root=true
+
+// NATSGatewayList contains a list of NATSGateway
+type NATSGatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NATSGateway `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NATSGateway{}, &NATSGatewayList{})
+}
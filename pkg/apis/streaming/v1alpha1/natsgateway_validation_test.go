@@ -0,0 +1,78 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+func TestValidateNATSGateway(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *NATSGateway
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &NATSGateway{},
+		expected: validation.ErrMissingField("spec"),
+	}, {
+		name: "valid",
+		target: &NATSGateway{
+			Spec: NATSGatewaySpec{
+				SecretRef: corev1.LocalObjectReference{Name: "nats-cluster"},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateNATSGateway(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateNATSGatewaySpec(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *NATSGatewaySpec
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &NATSGatewaySpec{},
+		expected: validation.ErrMissingField(validation.CurrentField),
+	}, {
+		name: "valid",
+		target: &NATSGatewaySpec{
+			SecretRef: corev1.LocalObjectReference{Name: "nats-cluster"},
+		},
+		expected: validation.FieldErrors{},
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateNATSGatewaySpec(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
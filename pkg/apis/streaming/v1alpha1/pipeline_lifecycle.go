@@ -0,0 +1,68 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/projectriff/system/pkg/apis"
+)
+
+const (
+	PipelineConditionReady                              = apis.ConditionReady
+	PipelineConditionStreamsReady    apis.ConditionType = "StreamsReady"
+	PipelineConditionProcessorsReady apis.ConditionType = "ProcessorsReady"
+)
+
+var pipelineCondSet = apis.NewLivingConditionSet(
+	PipelineConditionStreamsReady,
+	PipelineConditionProcessorsReady,
+)
+
+func (ps *PipelineStatus) GetObservedGeneration() int64 {
+	return ps.ObservedGeneration
+}
+
+func (ps *PipelineStatus) IsReady() bool {
+	return pipelineCondSet.Manage(ps).IsHappy()
+}
+
+func (*PipelineStatus) GetReadyConditionType() apis.ConditionType {
+	return PipelineConditionReady
+}
+
+func (ps *PipelineStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return pipelineCondSet.Manage(ps).GetCondition(t)
+}
+
+func (ps *PipelineStatus) InitializeConditions() {
+	pipelineCondSet.Manage(ps).InitializeConditions()
+}
+
+func (ps *PipelineStatus) MarkStreamsReady() {
+	pipelineCondSet.Manage(ps).MarkTrue(PipelineConditionStreamsReady)
+}
+
+func (ps *PipelineStatus) MarkStreamsNotReady(message string, a ...interface{}) {
+	pipelineCondSet.Manage(ps).MarkFalse(PipelineConditionStreamsReady, "StreamNotReady", message, a...)
+}
+
+func (ps *PipelineStatus) MarkProcessorsReady() {
+	pipelineCondSet.Manage(ps).MarkTrue(PipelineConditionProcessorsReady)
+}
+
+func (ps *PipelineStatus) MarkProcessorsNotReady(message string, a ...interface{}) {
+	pipelineCondSet.Manage(ps).MarkFalse(PipelineConditionProcessorsReady, "ProcessorNotReady", message, a...)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/projectriff/system/pkg/apis"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+var (
+	PipelineLabelKey     = GroupVersion.Group + "/pipeline"
+	PipelineStepLabelKey = GroupVersion.Group + "/pipeline-step"
+)
+
+var (
+	_ apis.Resource = (*Pipeline)(nil)
+)
+
+// PipelineSpec defines the desired state of Pipeline
+type PipelineSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// Gateway provisions each stream declared by the pipeline
+	Gateway corev1.LocalObjectReference `json:"gateway"`
+
+	// Streams declares the streams connecting the steps. Streams that no step
+	// outputs to are the entry points into the pipeline.
+	Streams []PipelineStream `json:"streams"`
+
+	// Steps declares the processors of the pipeline. Together the steps must
+	// form a directed acyclic graph.
+	Steps []PipelineStep `json:"steps"`
+}
+
+type PipelineStream struct {
+	// Name of the stream, unique within the pipeline
+	Name string `json:"name"`
+
+	// ContentType of messages on the stream
+	// +optional
+	ContentType string `json:"contentType,omitempty"`
+}
+
+type PipelineStep struct {
+	// Name of the step, unique within the pipeline
+	Name string `json:"name"`
+
+	// FunctionRef references a function in this namespace
+	FunctionRef string `json:"functionRef"`
+
+	// Inputs names the pipeline streams consumed by the step
+	Inputs []string `json:"inputs"`
+
+	// Outputs names the pipeline streams produced by the step
+	// +optional
+	Outputs []string `json:"outputs,omitempty"`
+}
+
+// PipelineStatus defines the observed state of Pipeline
+type PipelineStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	apis.Status `json:",inline"`
+
+	// Steps reports the processor backing each step, in the order of the spec
+	Steps []PipelineStepStatus `json:"steps,omitempty"`
+}
+
+type PipelineStepStatus struct {
+	// Name of the step
+	Name string `json:"name"`
+
+	// ProcessorRef references the Processor created for the step
+	ProcessorRef *refs.TypedLocalObjectReference `json:"processorRef,omitempty"`
+
+	// Ready is the status of the processor's Ready condition
+	Ready corev1.ConditionStatus `json:"ready,omitempty"`
+
+	// Reason is the reason of the processor's Ready condition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="riff"
+// +kubebuilder:printcolumn:name="Gateway",type=string,JSONPath=`.spec.gateway.name`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+
+// Pipeline is the Schema for the pipelines API
+type Pipeline struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PipelineSpec   `json:"spec,omitempty"`
+	Status PipelineStatus `json:"status,omitempty"`
+}
+
+func (*Pipeline) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("Pipeline")
+}
+
+func (p *Pipeline) GetStatus() apis.ResourceStatus {
+	return &p.Status
+}
+
+// +kubebuilder:object:root=true
+
+// PipelineList contains a list of Pipeline
+type PipelineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Pipeline `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Pipeline{}, &PipelineList{})
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+// +kubebuilder:webhook:path=/validate-streaming-projectriff-io-v1alpha1-pipeline,mutating=false,failurePolicy=fail,groups=streaming.projectriff.io,resources=pipelines,verbs=create;update,versions=v1alpha1,name=pipelines.streaming.projectriff.io
+
+var (
+	_ webhook.Validator         = &Pipeline{}
+	_ validation.FieldValidator = &Pipeline{}
+)
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Pipeline) ValidateCreate() error {
+	return r.Validate().ToAggregate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Pipeline) ValidateUpdate(old runtime.Object) error {
+	// TODO check for immutable fields
+	return r.Validate().ToAggregate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Pipeline) ValidateDelete() error {
+	return nil
+}
+
+func (r *Pipeline) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	errs = errs.Also(r.Spec.Validate().ViaField("spec"))
+
+	return errs
+}
+
+func (s *PipelineSpec) Validate() validation.FieldErrors {
+	if equality.Semantic.DeepEqual(s, &PipelineSpec{}) {
+		return validation.ErrMissingField(validation.CurrentField)
+	}
+
+	errs := validation.FieldErrors{}
+
+	if s.Gateway.Name == "" {
+		errs = errs.Also(validation.ErrMissingField("gateway"))
+	}
+
+	streams := map[string]bool{}
+	var streamNames []string
+	for i, stream := range s.Streams {
+		if stream.Name == "" {
+			errs = errs.Also(validation.ErrMissingField("name").ViaFieldIndex("streams", i))
+		}
+		streams[stream.Name] = true
+		streamNames = append(streamNames, stream.Name)
+	}
+	errs = errs.Also(validateNameUniqueness(streamNames, "streams"))
+
+	// at least one step is required
+	if len(s.Steps) == 0 {
+		errs = errs.Also(validation.ErrMissingField("steps"))
+	}
+	var stepNames []string
+	for i, step := range s.Steps {
+		errs = errs.Also(step.validate(streams).ViaFieldIndex("steps", i))
+		stepNames = append(stepNames, step.Name)
+	}
+	errs = errs.Also(validateNameUniqueness(stepNames, "steps"))
+
+	if len(errs) == 0 {
+		// only look for cycles within an otherwise well formed graph
+		if cycle := s.findCycle(); cycle != nil {
+			errs = errs.Also(validation.ErrInvalidValue(strings.Join(cycle, " -> "), "steps"))
+		}
+	}
+
+	return errs
+}
+
+func (s *PipelineStep) validate(streams map[string]bool) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if s.Name == "" {
+		errs = errs.Also(validation.ErrMissingField("name"))
+	}
+	if s.FunctionRef == "" {
+		errs = errs.Also(validation.ErrMissingField("functionRef"))
+	}
+
+	// at least one input is required
+	if len(s.Inputs) == 0 {
+		errs = errs.Also(validation.ErrMissingField("inputs"))
+	}
+	// each input and output must be a stream declared by the pipeline
+	for i, input := range s.Inputs {
+		if input == "" || !streams[input] {
+			errs = errs.Also(validation.ErrInvalidArrayValue(input, "inputs", i))
+		}
+	}
+	for i, output := range s.Outputs {
+		if output == "" || !streams[output] {
+			errs = errs.Also(validation.ErrInvalidArrayValue(output, "outputs", i))
+		}
+	}
+
+	return errs
+}
+
+// findCycle returns the names of the steps forming a cycle, starting and
+// ending with the same step, or nil if the steps form a directed acyclic graph
+func (s *PipelineSpec) findCycle() []string {
+	consumers := map[string][]string{}
+	for _, step := range s.Steps {
+		for _, input := range step.Inputs {
+			consumers[input] = append(consumers[input], step.Name)
+		}
+	}
+	downstream := map[string][]string{}
+	for _, step := range s.Steps {
+		for _, output := range step.Outputs {
+			downstream[step.Name] = append(downstream[step.Name], consumers[output]...)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	var path []string
+	var visit func(step string) []string
+	visit = func(step string) []string {
+		switch state[step] {
+		case visited:
+			return nil
+		case visiting:
+			for i := range path {
+				if path[i] == step {
+					return append(append([]string{}, path[i:]...), step)
+				}
+			}
+		}
+		state[step] = visiting
+		path = append(path, step)
+		for _, next := range downstream[step] {
+			if cycle := visit(next); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[step] = visited
+		return nil
+	}
+	for _, step := range s.Steps {
+		if cycle := visit(step.Name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+func validateNameUniqueness(allNames []string, nameType string) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	var dedupedNames []string
+	uses := map[string][]string{}
+	for i, name := range allNames {
+		if name == "" {
+			continue
+		}
+		if _, ok := uses[name]; !ok {
+			uses[name] = []string{}
+			dedupedNames = append(dedupedNames, name)
+		}
+		uses[name] = append(uses[name], fmt.Sprintf("%s[%d].name", nameType, i))
+	}
+
+	for _, name := range dedupedNames {
+		if len(uses[name]) > 1 {
+			errs = errs.Also(validation.ErrDuplicateValue(name, uses[name]...))
+		}
+	}
+	return errs
+}
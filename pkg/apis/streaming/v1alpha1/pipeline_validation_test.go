@@ -0,0 +1,180 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+func TestValidatePipeline(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *Pipeline
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &Pipeline{},
+		expected: validation.ErrMissingField("spec"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validatePipeline(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidatePipelineSpec(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *PipelineSpec
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &PipelineSpec{},
+		expected: validation.ErrMissingField(validation.CurrentField),
+	}, {
+		name: "valid",
+		target: &PipelineSpec{
+			Gateway: corev1.LocalObjectReference{Name: "my-gateway"},
+			Streams: []PipelineStream{{Name: "in"}, {Name: "middle"}, {Name: "out"}},
+			Steps: []PipelineStep{
+				{Name: "first", FunctionRef: "upper", Inputs: []string{"in"}, Outputs: []string{"middle"}},
+				{Name: "second", FunctionRef: "reverse", Inputs: []string{"middle"}, Outputs: []string{"out"}},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid, fan in",
+		target: &PipelineSpec{
+			Gateway: corev1.LocalObjectReference{Name: "my-gateway"},
+			Streams: []PipelineStream{{Name: "left"}, {Name: "right"}, {Name: "out"}},
+			Steps: []PipelineStep{
+				{Name: "join", FunctionRef: "join", Inputs: []string{"left", "right"}, Outputs: []string{"out"}},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "requires gateway",
+		target: &PipelineSpec{
+			Streams: []PipelineStream{{Name: "in"}},
+			Steps: []PipelineStep{
+				{Name: "first", FunctionRef: "upper", Inputs: []string{"in"}},
+			},
+		},
+		expected: validation.ErrMissingField("gateway"),
+	}, {
+		name: "requires steps",
+		target: &PipelineSpec{
+			Gateway: corev1.LocalObjectReference{Name: "my-gateway"},
+			Streams: []PipelineStream{{Name: "in"}},
+		},
+		expected: validation.ErrMissingField("steps"),
+	}, {
+		name: "requires stream name",
+		target: &PipelineSpec{
+			Gateway: corev1.LocalObjectReference{Name: "my-gateway"},
+			Streams: []PipelineStream{{Name: "in"}, {}},
+			Steps: []PipelineStep{
+				{Name: "first", FunctionRef: "upper", Inputs: []string{"in"}},
+			},
+		},
+		expected: validation.ErrMissingField("streams[1].name"),
+	}, {
+		name: "requires step fields",
+		target: &PipelineSpec{
+			Gateway: corev1.LocalObjectReference{Name: "my-gateway"},
+			Streams: []PipelineStream{{Name: "in"}},
+			Steps:   []PipelineStep{{}},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMissingField("steps[0].name"),
+			validation.ErrMissingField("steps[0].functionRef"),
+			validation.ErrMissingField("steps[0].inputs"),
+		),
+	}, {
+		name: "duplicate names",
+		target: &PipelineSpec{
+			Gateway: corev1.LocalObjectReference{Name: "my-gateway"},
+			Streams: []PipelineStream{{Name: "in"}, {Name: "in"}},
+			Steps: []PipelineStep{
+				{Name: "first", FunctionRef: "upper", Inputs: []string{"in"}},
+				{Name: "first", FunctionRef: "reverse", Inputs: []string{"in"}},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrDuplicateValue("in", "streams[0].name", "streams[1].name"),
+			validation.ErrDuplicateValue("first", "steps[0].name", "steps[1].name"),
+		),
+	}, {
+		name: "dangling input",
+		target: &PipelineSpec{
+			Gateway: corev1.LocalObjectReference{Name: "my-gateway"},
+			Streams: []PipelineStream{{Name: "in"}},
+			Steps: []PipelineStep{
+				{Name: "first", FunctionRef: "upper", Inputs: []string{"in", "missing"}},
+			},
+		},
+		expected: validation.ErrInvalidArrayValue("missing", "steps[0].inputs", 1),
+	}, {
+		name: "undeclared output",
+		target: &PipelineSpec{
+			Gateway: corev1.LocalObjectReference{Name: "my-gateway"},
+			Streams: []PipelineStream{{Name: "in"}},
+			Steps: []PipelineStep{
+				{Name: "first", FunctionRef: "upper", Inputs: []string{"in"}, Outputs: []string{"missing"}},
+			},
+		},
+		expected: validation.ErrInvalidArrayValue("missing", "steps[0].outputs", 0),
+	}, {
+		name: "self cycle",
+		target: &PipelineSpec{
+			Gateway: corev1.LocalObjectReference{Name: "my-gateway"},
+			Streams: []PipelineStream{{Name: "loop"}},
+			Steps: []PipelineStep{
+				{Name: "first", FunctionRef: "upper", Inputs: []string{"loop"}, Outputs: []string{"loop"}},
+			},
+		},
+		expected: validation.ErrInvalidValue("first -> first", "steps"),
+	}, {
+		name: "cycle",
+		target: &PipelineSpec{
+			Gateway: corev1.LocalObjectReference{Name: "my-gateway"},
+			Streams: []PipelineStream{{Name: "in"}, {Name: "a"}, {Name: "b"}, {Name: "c"}},
+			Steps: []PipelineStep{
+				{Name: "entry", FunctionRef: "upper", Inputs: []string{"in"}, Outputs: []string{"a"}},
+				{Name: "first", FunctionRef: "upper", Inputs: []string{"a"}, Outputs: []string{"b"}},
+				{Name: "second", FunctionRef: "upper", Inputs: []string{"b"}, Outputs: []string{"c"}},
+				{Name: "third", FunctionRef: "upper", Inputs: []string{"c"}, Outputs: []string{"a"}},
+			},
+		},
+		expected: validation.ErrInvalidValue("first -> second -> third -> first", "steps"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validatePipelineSpec(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
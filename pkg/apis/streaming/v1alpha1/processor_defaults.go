@@ -37,7 +37,7 @@ func (s *ProcessorSpec) Default() {
 		if s.Inputs[i].Alias == "" {
 			s.Inputs[i].Alias = s.Inputs[i].Stream
 		}
-		if s.Inputs[i].StartOffset == "" {
+		if s.Inputs[i].StartOffset == "" && s.Inputs[i].StartTime == nil && len(s.Inputs[i].PartitionOffsets) == 0 {
 			s.Inputs[i].StartOffset = Latest
 		}
 	}
@@ -51,6 +51,12 @@ func (s *ProcessorSpec) Default() {
 		}
 	}
 
+	for i := range s.Chain {
+		if s.Chain[i].Port == 0 {
+			s.Chain[i].Port = int32(8081 + i)
+		}
+	}
+
 	if s.Template == nil {
 		s.Template = &corev1.PodTemplateSpec{}
 	}
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
@@ -64,6 +65,8 @@ func TestProcessorDefault(t *testing.T) {
 }
 
 func TestProcessorSpecDefault(t *testing.T) {
+	startTime := metav1.Date(2020, time.April, 1, 12, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name string
 		in   *ProcessorSpec
@@ -145,6 +148,62 @@ func TestProcessorSpecDefault(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "preserves start position",
+		in: &ProcessorSpec{
+			Inputs: []InputStreamBinding{
+				{Stream: "my-input-1", StartTime: &startTime},
+				{Stream: "my-input-2", PartitionOffsets: []PartitionOffset{{Partition: 0, Offset: 42}}},
+			}},
+		want: &ProcessorSpec{
+			Inputs: []InputStreamBinding{
+				{Stream: "my-input-1", Alias: "my-input-1", StartTime: &startTime},
+				{Stream: "my-input-2", Alias: "my-input-2", PartitionOffsets: []PartitionOffset{{Partition: 0, Offset: 42}}},
+			},
+			Outputs: []OutputStreamBinding{},
+			Template: &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{},
+					Labels:      map[string]string{},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+					Volumes: []corev1.Volume{},
+				},
+			},
+		},
+	}, {
+		name: "add chain ports",
+		in: &ProcessorSpec{
+			Chain: []ChainedFunction{
+				{Name: "parse", Build: Build{FunctionRef: "parse"}},
+				{Name: "enrich", Build: Build{FunctionRef: "enrich"}, Port: 9000},
+				{Name: "score", Build: Build{FunctionRef: "score"}},
+			},
+		},
+		want: &ProcessorSpec{
+			Inputs:  []InputStreamBinding{},
+			Outputs: []OutputStreamBinding{},
+			Chain: []ChainedFunction{
+				{Name: "parse", Build: Build{FunctionRef: "parse"}, Port: 8081},
+				{Name: "enrich", Build: Build{FunctionRef: "enrich"}, Port: 9000},
+				{Name: "score", Build: Build{FunctionRef: "score"}, Port: 8083},
+			},
+			Template: &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{},
+					Labels:      map[string]string{},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+					Volumes: []corev1.Volume{},
+				},
+			},
+		},
 	}, {
 		name: "add container name",
 		in: &ProcessorSpec{
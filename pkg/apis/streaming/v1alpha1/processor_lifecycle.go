@@ -16,6 +16,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 
@@ -29,6 +31,9 @@ const (
 	ProcessorConditionStreamsReady      apis.ConditionType = "StreamsReady"
 	ProcessorConditionDeploymentReady   apis.ConditionType = "DeploymentReady"
 	ProcessorConditionScaledObjectReady apis.ConditionType = "ScaledObjectReady"
+	// ProcessorConditionLagging is informational, it does not affect the
+	// processor's readiness
+	ProcessorConditionLagging apis.ConditionType = "Lagging"
 )
 
 var processorCondSet = apis.NewLivingConditionSet(
@@ -66,6 +71,8 @@ func (ps *ProcessorStatus) MarkStreamsNotReady(message string) {
 }
 
 func (ps *ProcessorStatus) PropagateDeploymentStatus(ds *appsv1.DeploymentStatus) {
+	ps.Replicas = ds.Replicas
+
 	var available, progressing *appsv1.DeploymentCondition
 	for i := range ds.Conditions {
 		switch ds.Conditions[i].Type {
@@ -93,7 +100,55 @@ func (ps *ProcessorStatus) PropagateDeploymentStatus(ds *appsv1.DeploymentStatus
 	}
 }
 
+func (ps *ProcessorStatus) PropagateStatefulSetStatus(sss *appsv1.StatefulSetStatus) {
+	ps.Replicas = sss.Replicas
+
+	if sss.ObservedGeneration == 0 {
+		// not yet observed by the statefulset controller
+		return
+	}
+	if sss.CurrentRevision != sss.UpdateRevision || sss.ReadyReplicas < sss.Replicas {
+		processorCondSet.Manage(ps).MarkUnknown(ProcessorConditionDeploymentReady, "StatefulSetProgressing", "%d of %d replicas ready", sss.ReadyReplicas, sss.Replicas)
+		return
+	}
+	processorCondSet.Manage(ps).MarkTrue(ProcessorConditionDeploymentReady)
+}
+
+func (ps *ProcessorStatus) MarkScaledObjectNotRequired() {
+	// stateful processors are not autoscaled
+	processorCondSet.Manage(ps).MarkTrue(ProcessorConditionScaledObjectReady)
+}
+
 func (ps *ProcessorStatus) PropagateScaledObjectStatus(sos *kedav1alpha1.ScaledObjectStatus) {
 	// TODO: ScaledObject does not report much atm
+	ps.LastActiveTime = sos.LastActiveTime.DeepCopy()
 	processorCondSet.Manage(ps).MarkTrue(ProcessorConditionScaledObjectReady)
 }
+
+func (ps *ProcessorStatus) MarkLagging(messageFormat string, messageA ...interface{}) {
+	processorCondSet.Manage(ps).SetCondition(apis.Condition{
+		Type:     ProcessorConditionLagging,
+		Status:   corev1.ConditionTrue,
+		Reason:   "LagAboveThreshold",
+		Message:  fmt.Sprintf(messageFormat, messageA...),
+		Severity: apis.ConditionSeverityInfo,
+	})
+}
+
+func (ps *ProcessorStatus) MarkNotLagging() {
+	processorCondSet.Manage(ps).SetCondition(apis.Condition{
+		Type:     ProcessorConditionLagging,
+		Status:   corev1.ConditionFalse,
+		Severity: apis.ConditionSeverityInfo,
+	})
+}
+
+func (ps *ProcessorStatus) MarkLagUnknown(messageFormat string, messageA ...interface{}) {
+	processorCondSet.Manage(ps).SetCondition(apis.Condition{
+		Type:     ProcessorConditionLagging,
+		Status:   corev1.ConditionUnknown,
+		Reason:   "LagUnknown",
+		Message:  fmt.Sprintf(messageFormat, messageA...),
+		Severity: apis.ConditionSeverityInfo,
+	})
+}
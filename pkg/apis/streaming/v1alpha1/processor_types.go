@@ -17,9 +17,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/projectriff/system/pkg/apis"
 	"github.com/projectriff/system/pkg/refs"
@@ -43,7 +48,7 @@ type ProcessorSpec struct {
 
 	// Build resolves the image from a build resource. As the target build
 	// produces new images, they will be automatically rolled out to the
-	// processor.
+	// processor, unless the rollout is paused.
 	// +optional
 	Build *Build `json:"build,omitempty"`
 
@@ -56,6 +61,221 @@ type ProcessorSpec struct {
 	// Template pod
 	// +optional
 	Template *corev1.PodTemplateSpec `json:"template,omitempty"`
+
+	// Operation applies a built-in operation to each message in place of a
+	// function. The processor runs without a function container, build and
+	// the template's image must not be set.
+	// +optional
+	Operation *ProcessorOperation `json:"operation,omitempty"`
+
+	// Chain runs several functions in sequence within each processor pod.
+	// The result of each function is passed in-process to the next function,
+	// rather than through a stream. Each function runs in a copy of the
+	// template's function container. Mutually exclusive with build, operation
+	// and the template's image.
+	// +optional
+	Chain []ChainedFunction `json:"chain,omitempty"`
+
+	// Rollout controls how new images are rolled out to the processor
+	// +optional
+	Rollout *ProcessorRollout `json:"rollout,omitempty"`
+
+	// Replay is an opaque token, changing its value moves the processor onto a
+	// new consumer group that consumes each input again from its start
+	// position.
+	// +optional
+	Replay string `json:"replay,omitempty"`
+
+	// Window groups messages from the inputs before they are passed to the
+	// function
+	// +optional
+	Window *Window `json:"window,omitempty"`
+
+	// State backs the processor with a durable store for aggregations. Stateful
+	// processors run as a StatefulSet with a fixed number of replicas.
+	// +optional
+	State *ProcessorState `json:"state,omitempty"`
+
+	// Scale bounds and tunes the autoscaling of the processor. Unset values
+	// fall back to the controller's defaults.
+	// +optional
+	Scale Scale `json:"scale,omitempty"`
+
+	// LaggingThreshold is the number of pending messages on an input stream
+	// above which the processor is reported as lagging. Defaults to the
+	// streaming manager's threshold.
+	// +optional
+	LaggingThreshold *int64 `json:"laggingThreshold,omitempty"`
+}
+
+const (
+	TumblingWindow = "tumbling"
+	SlidingWindow  = "sliding"
+)
+
+// ProcessorPort is the port the processor container listens on within the
+// pod, functions in a chain may not listen on it
+const ProcessorPort int32 = 8080
+
+type ChainedFunction struct {
+	// Name of the function's container, unique within the chain
+	Name string `json:"name"`
+
+	// Build resolves the function's image from a build resource. As the
+	// target build produces new images, they will be rolled out to the
+	// processor.
+	Build Build `json:"build"`
+
+	// Port the function listens on within the pod, defaults to 8081 plus the
+	// function's position in the chain
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+type ProcessorRollout struct {
+	// Paused pins the processor to its deployed image. New images accumulate
+	// in the status as the latest image until promoted or resumed.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Promote is an opaque token, changing its value while paused rolls out
+	// the latest image
+	// +optional
+	Promote string `json:"promote,omitempty"`
+
+	// MaxSurge is the number or percentage of pods created above the desired
+	// number of pods while rolling out a stateless processor
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// MaxUnavailable is the number or percentage of pods that may be
+	// unavailable while rolling out a stateless processor
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// ProcessorOperation is a built-in operation, exactly one operation must be
+// set. Expressions are parsed by the expression package.
+type ProcessorOperation struct {
+	// Filter forwards the messages matching an expression to every output,
+	// other messages are dropped
+	// +optional
+	Filter *FilterOperation `json:"filter,omitempty"`
+
+	// Project forwards JSON messages to every output with the payload reduced
+	// to the selected fields
+	// +optional
+	Project *ProjectOperation `json:"project,omitempty"`
+
+	// Split forwards each message to the output routed for its key
+	// +optional
+	Split *SplitOperation `json:"split,omitempty"`
+}
+
+type FilterOperation struct {
+	// Expression evaluated for each message, like
+	// `headers["X-Tenant"] != "test"`
+	Expression string `json:"expression"`
+}
+
+type ProjectOperation struct {
+	// Fields of the payload to keep
+	Fields []ProjectedField `json:"fields"`
+}
+
+type ProjectedField struct {
+	// Path to the field in the payload, like `order.customer.id`
+	Path string `json:"path"`
+
+	// Name of the field in the projected payload, defaults to the last
+	// segment of the path
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+type SplitOperation struct {
+	// Key expression evaluated for each message, like `headers["region"]`
+	Key string `json:"key"`
+
+	// Routes from values of the key to outputs
+	Routes []SplitRoute `json:"routes"`
+
+	// Default is the alias of the output receiving messages whose key matches
+	// no route. Unmatched messages are dropped when unset.
+	// +optional
+	Default string `json:"default,omitempty"`
+}
+
+type SplitRoute struct {
+	// Value of the key
+	Value string `json:"value"`
+
+	// Output is the alias of the output receiving the messages
+	Output string `json:"output"`
+}
+
+type Window struct {
+	// Type of window, either tumbling or sliding
+	Type string `json:"type"`
+
+	// Size of each window
+	Size WindowExtent `json:"size"`
+
+	// Slide is how far a sliding window advances, in the same unit as the size
+	// +optional
+	Slide *WindowExtent `json:"slide,omitempty"`
+}
+
+type WindowExtent struct {
+	// Count of messages
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+
+	// Duration of time
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+type ProcessorState struct {
+	// Volume backs state with a PersistentVolumeClaim for each replica
+	// +optional
+	Volume *StateVolume `json:"volume,omitempty"`
+
+	// Changelog backs state with a stream provisioned on the gateway
+	// +optional
+	Changelog *StateChangelog `json:"changelog,omitempty"`
+}
+
+type StateVolume struct {
+	// Size of the volume requested for each replica
+	Size resource.Quantity `json:"size"`
+
+	// StorageClassName of the volume, the cluster default is used when unset
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+}
+
+type StateChangelog struct {
+	// Gateway to provision the changelog stream on
+	Gateway corev1.LocalObjectReference `json:"gateway"`
+}
+
+// ScaleMaxDefault is the highest number of replicas of a processor that does
+// not set a max
+const ScaleMaxDefault int32 = 30
+
+type Scale struct {
+	// Min is the lowest number of replicas, 0 allows the processor to scale
+	// to zero while there are no pending messages
+	Min *int32 `json:"min,omitempty"`
+	// Max is the highest number of replicas, defaults to ScaleMaxDefault
+	Max *int32 `json:"max,omitempty"`
+	// PollingInterval is the number of seconds between checks of the input
+	// streams for pending messages
+	PollingInterval *int32 `json:"pollingInterval,omitempty"`
+	// CooldownPeriod is the number of seconds to wait after the last pending
+	// message before scaling down to the minimum
+	CooldownPeriod *int32 `json:"cooldownPeriod,omitempty"`
 }
 
 type Build struct {
@@ -67,7 +287,10 @@ type Build struct {
 }
 
 type OutputStreamBinding struct {
-	// Stream name, from this namespace, to be bound to the processor
+	// Stream to be bound to the processor, either the name of a stream in
+	// this namespace or `namespace/name` for a stream in another namespace.
+	// Streams in another namespace must be granted to this namespace by a
+	// StreamGrant.
 	Stream string `json:"stream"`
 
 	// Alias exposes the stream under another name within the processor
@@ -75,21 +298,59 @@ type OutputStreamBinding struct {
 	Alias string `json:"alias,omitempty"`
 }
 
+// StreamNamespacedName resolves a stream reference, either `name` or
+// `namespace/name`, relative to the namespace of the processor.
+func StreamNamespacedName(namespace, stream string) types.NamespacedName {
+	if i := strings.Index(stream, "/"); i != -1 {
+		return types.NamespacedName{Namespace: stream[:i], Name: stream[i+1:]}
+	}
+	return types.NamespacedName{Namespace: namespace, Name: stream}
+}
+
 const (
 	Earliest = "earliest"
 	Latest   = "latest"
 )
 
 type InputStreamBinding struct {
-	// Stream name, from this namespace, to be bound to the processor
+	// Stream to be bound to the processor, either the name of a stream in
+	// this namespace or `namespace/name` for a stream in another namespace.
+	// Streams in another namespace must be granted to this namespace by a
+	// StreamGrant.
 	Stream string `json:"stream"`
 
 	// Alias exposes the stream under another name within the processor
 	// +optional
 	Alias string `json:"alias,omitempty"`
 
-	// Where to start consuming this stream the first time a processor runs.
-	StartOffset string `json:"startOffset"`
+	// Where to start consuming this stream the first time a processor runs,
+	// either earliest or latest. Mutually exclusive with startTime and
+	// partitionOffsets.
+	// +optional
+	StartOffset string `json:"startOffset,omitempty"`
+
+	// StartTime starts consuming this stream from the first message at or
+	// after the RFC3339 timestamp the first time a processor runs.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// PartitionOffsets starts consuming each listed partition of this stream
+	// at an explicit offset the first time a processor runs.
+	// +optional
+	PartitionOffsets []PartitionOffset `json:"partitionOffsets,omitempty"`
+
+	// LagThreshold is the number of pending messages on this stream that
+	// warrants an additional replica
+	// +optional
+	LagThreshold *int32 `json:"lagThreshold,omitempty"`
+}
+
+type PartitionOffset struct {
+	// Partition of the stream
+	Partition int32 `json:"partition"`
+
+	// Offset within the partition of the first message to consume
+	Offset int64 `json:"offset"`
 }
 
 // ProcessorStatus defines the observed state of Processor
@@ -102,11 +363,67 @@ type ProcessorStatus struct {
 	DeploymentRef   *refs.TypedLocalObjectReference `json:"deploymentRef,omitempty"`
 	ScaledObjectRef *refs.TypedLocalObjectReference `json:"scaledObjectRef,omitempty"`
 	LatestImage     string                          `json:"latestImage,omitempty"`
+
+	// DeployedImage is the image of the function rolled out to the processor's
+	// pods. It trails the latest image while the rollout is paused.
+	DeployedImage string `json:"deployedImage,omitempty"`
+	// Promoted is the promote token of the most recent rollout
+	Promoted string `json:"promoted,omitempty"`
+
+	// StatefulSetRef references the StatefulSet running a stateful processor
+	StatefulSetRef *refs.TypedLocalObjectReference `json:"statefulSetRef,omitempty"`
+	// ChangelogStreamRef references the Stream backing the processor's state
+	ChangelogStreamRef *refs.TypedLocalObjectReference `json:"changelogStreamRef,omitempty"`
+
+	// ConsumerGroup is the name of the consumer group the processor currently
+	// consumes its inputs with
+	ConsumerGroup string `json:"consumerGroup,omitempty"`
+	// ReplayHistory records the consumer groups created by replays, oldest
+	// first
+	ReplayHistory []ProcessorReplay `json:"replayHistory,omitempty"`
+
+	// Replicas is the number of processor pods most recently observed
+	Replicas int32 `json:"replicas,omitempty"`
+	// LastActiveTime is the last time the autoscaler observed pending messages
+	// on an input stream
+	LastActiveTime *metav1.Time `json:"lastActiveTime,omitempty"`
+
+	// Lag is the total number of messages pending on the input streams for
+	// the processor's consumer group
+	Lag *int64 `json:"lag,omitempty"`
+	// InputLags breaks the lag down by input stream
+	InputLags []ProcessorInputLag `json:"inputLags,omitempty"`
+
+	// Chain reports the latest image of each function in the chain, in order
+	Chain []ChainedFunctionStatus `json:"chain,omitempty"`
+}
+
+type ChainedFunctionStatus struct {
+	// Name of the function in the chain
+	Name string `json:"name"`
+	// LatestImage is the most recent image resolved from the function's build
+	LatestImage string `json:"latestImage,omitempty"`
+}
+
+type ProcessorInputLag struct {
+	// Stream is the input stream as referenced by the processor's spec
+	Stream string `json:"stream"`
+	// Lag is the number of messages pending on the stream
+	Lag int64 `json:"lag"`
+}
+
+type ProcessorReplay struct {
+	// Replay is the token from the spec that triggered the replay
+	Replay string `json:"replay"`
+	// ConsumerGroup is the name of the consumer group created for the replay
+	ConsumerGroup string `json:"consumerGroup"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:categories="riff"
+// +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=`.status.replicas`
+// +kubebuilder:printcolumn:name="Lag",type=integer,JSONPath=`.status.lag`
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 // +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
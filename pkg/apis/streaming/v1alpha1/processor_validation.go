@@ -18,13 +18,19 @@ package v1alpha1
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
-	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"github.com/projectriff/system/pkg/expression"
 	"github.com/projectriff/system/pkg/validation"
 )
 
@@ -66,20 +72,46 @@ func (s *ProcessorSpec) Validate() validation.FieldErrors {
 
 	errs := validation.FieldErrors{}
 
-	if diff := cmp.Diff(&corev1.PodSpec{
-		// add supported PodSpec fields here, otherwise their usage will be rejected
-		ServiceAccountName: s.Template.Spec.ServiceAccountName,
-		// the defaulter guarantees at least one container
-		Containers: filterInvalidContainers(s.Template.Spec.Containers[:1]),
-		Volumes:    filterInvalidVolumes(s.Template.Spec.Volumes),
-	}, &s.Template.Spec); diff != "" {
-		errs = errs.Also(validation.ErrDisallowedFields("template.spec", fmt.Sprintf("limited PodSpec fields may be set (-want, +got) = %v", diff)))
-	}
+	errs = errs.Also(validatePodSpec(&s.Template.Spec).ViaField("template.spec"))
 	if s.Template.Spec.Containers[0].Name != "function" {
 		errs = errs.Also(validation.ErrInvalidValue(s.Template.Spec.Containers[0].Name, "template.spec.containers[0].name"))
 	}
 
-	if s.Build == nil && s.Template.Spec.Containers[0].Image == "" {
+	if len(s.Chain) != 0 {
+		// each function in the chain resolves its own image
+		if s.Build != nil {
+			errs = errs.Also(validation.ErrMultipleOneOf("build", "chain"))
+		}
+		if s.Operation != nil {
+			errs = errs.Also(validation.ErrMultipleOneOf("chain", "operation"))
+		}
+		if s.Template.Spec.Containers[0].Image != "" {
+			errs = errs.Also(validation.ErrMultipleOneOf("chain", "template.spec.containers[0].image"))
+		}
+		if s.Rollout != nil && s.Rollout.Paused {
+			errs = errs.Also(validation.ErrDisallowedFields("rollout.paused", "chains roll out the latest image of each function"))
+		}
+		errs = errs.Also(s.validateChain())
+	} else if s.Operation != nil {
+		// operations run without a function
+		if s.Build != nil {
+			errs = errs.Also(validation.ErrMultipleOneOf("build", "operation"))
+		}
+		if s.Template.Spec.Containers[0].Image != "" {
+			errs = errs.Also(validation.ErrMultipleOneOf("operation", "template.spec.containers[0].image"))
+		}
+		if s.Window != nil {
+			errs = errs.Also(validation.ErrDisallowedFields("window", "operations are applied to each message"))
+		}
+		if s.State != nil {
+			errs = errs.Also(validation.ErrDisallowedFields("state", "operations are stateless"))
+		}
+		if len(s.Outputs) == 0 {
+			errs = errs.Also(validation.ErrMissingField("outputs"))
+		}
+		errs = errs.Also(s.Operation.Validate().ViaField("operation"))
+		errs = errs.Also(s.validateOperationOutputs())
+	} else if s.Build == nil && s.Template.Spec.Containers[0].Image == "" {
 		errs = errs.Also(validation.ErrMissingOneOf("build", "template.spec.containers[0].image"))
 	} else if s.Build != nil && s.Template.Spec.Containers[0].Image != "" {
 		errs = errs.Also(validation.ErrMultipleOneOf("build", "template.spec.containers[0].image"))
@@ -94,12 +126,15 @@ func (s *ProcessorSpec) Validate() validation.FieldErrors {
 	for i, input := range s.Inputs {
 		if input.Stream == "" {
 			errs = errs.Also(validation.ErrMissingField("stream").ViaFieldIndex("inputs", i))
+		} else if !validStreamReference(input.Stream) {
+			errs = errs.Also(validation.ErrInvalidValue(input.Stream, "stream").ViaFieldIndex("inputs", i))
 		}
 		if input.Alias == "" {
 			errs = errs.Also(validation.ErrMissingField("alias").ViaFieldIndex("inputs", i))
 		}
-		if input.StartOffset != "" && input.StartOffset != Earliest && input.StartOffset != Latest {
-			errs = errs.Also(validation.ErrInvalidValue(input.StartOffset, fmt.Sprintf("inputs[%d].startOffset", i)))
+		errs = errs.Also(input.validateStartPosition().ViaFieldIndex("inputs", i))
+		if input.LagThreshold != nil && *input.LagThreshold < int32(1) {
+			errs = errs.Also(validation.ErrInvalidValue(*input.LagThreshold, fmt.Sprintf("inputs[%d].lagThreshold", i)))
 		}
 	}
 
@@ -107,6 +142,8 @@ func (s *ProcessorSpec) Validate() validation.FieldErrors {
 	for i, output := range s.Outputs {
 		if output.Stream == "" {
 			errs = errs.Also(validation.ErrMissingField("stream").ViaFieldIndex("outputs", i))
+		} else if !validStreamReference(output.Stream) {
+			errs = errs.Also(validation.ErrInvalidValue(output.Stream, "stream").ViaFieldIndex("outputs", i))
 		}
 		if output.Alias == "" {
 			errs = errs.Also(validation.ErrMissingField("alias").ViaFieldIndex("outputs", i))
@@ -116,6 +153,123 @@ func (s *ProcessorSpec) Validate() validation.FieldErrors {
 	errs = errs.Also(s.validateStreamInputAliasUniqueness())
 	errs = errs.Also(s.validateStreamOutputAliasUniqueness())
 
+	if s.Window != nil {
+		errs = errs.Also(s.Window.Validate().ViaField("window"))
+	}
+	if s.State != nil {
+		errs = errs.Also(s.State.Validate().ViaField("state"))
+	}
+
+	if s.Rollout != nil {
+		errs = errs.Also(s.Rollout.Validate().ViaField("rollout"))
+		if s.State != nil && (s.Rollout.MaxSurge != nil || s.Rollout.MaxUnavailable != nil) {
+			errs = errs.Also(validation.ErrDisallowedFields("rollout", "stateful processors roll out one pod at a time"))
+		}
+	}
+
+	if s.State != nil {
+		errs = errs.Also(s.Scale.validateStateful().ViaField("scale"))
+	} else {
+		errs = errs.Also(s.Scale.Validate().ViaField("scale"))
+	}
+
+	if s.LaggingThreshold != nil && *s.LaggingThreshold < 0 {
+		errs = errs.Also(validation.ErrInvalidValue(*s.LaggingThreshold, "laggingThreshold"))
+	}
+
+	return errs
+}
+
+func (b *InputStreamBinding) validateStartPosition() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	var used []string
+	if b.StartOffset != "" {
+		used = append(used, "startOffset")
+	}
+	if b.StartTime != nil {
+		used = append(used, "startTime")
+	}
+	if len(b.PartitionOffsets) != 0 {
+		used = append(used, "partitionOffsets")
+	}
+	if len(used) > 1 {
+		// the intended starting position is ambiguous
+		return errs.Also(validation.ErrMultipleOneOf(used...))
+	}
+
+	if b.StartOffset != "" && b.StartOffset != Earliest && b.StartOffset != Latest {
+		errs = errs.Also(validation.ErrInvalidValue(b.StartOffset, "startOffset"))
+	}
+
+	var partitions []int32
+	uses := map[int32][]string{}
+	for i, po := range b.PartitionOffsets {
+		if po.Partition < 0 {
+			errs = errs.Also(validation.ErrInvalidValue(po.Partition, "partition").ViaFieldIndex("partitionOffsets", i))
+		}
+		if po.Offset < 0 {
+			errs = errs.Also(validation.ErrInvalidValue(po.Offset, "offset").ViaFieldIndex("partitionOffsets", i))
+		}
+		if _, ok := uses[po.Partition]; !ok {
+			partitions = append(partitions, po.Partition)
+		}
+		uses[po.Partition] = append(uses[po.Partition], fmt.Sprintf("partitionOffsets[%d].partition", i))
+	}
+	for _, partition := range partitions {
+		if len(uses[partition]) > 1 {
+			errs = errs.Also(validation.ErrDuplicateValue(partition, uses[partition]...))
+		}
+	}
+
+	return errs
+}
+
+func (s *ProcessorSpec) validateChain() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	var names []string
+	var ports []int32
+	nameUses := map[string][]string{}
+	portUses := map[int32][]string{}
+	for i, function := range s.Chain {
+		if function.Name == "" {
+			errs = errs.Also(validation.ErrMissingField("name").ViaFieldIndex("chain", i))
+		} else if function.Name == "processor" {
+			// reserved for the processor container
+			errs = errs.Also(validation.ErrInvalidValue(function.Name, "name").ViaFieldIndex("chain", i))
+		} else if msgs := k8svalidation.IsDNS1123Label(function.Name); len(msgs) != 0 {
+			// the name is the name of the function's container
+			errs = errs.Also(validation.ErrInvalidValue(function.Name, "name").ViaFieldIndex("chain", i))
+		}
+		errs = errs.Also(function.Build.Validate().ViaField("build").ViaFieldIndex("chain", i))
+		if function.Port < 1 || function.Port > 65535 {
+			errs = errs.Also(validation.ErrInvalidValue(function.Port, "port").ViaFieldIndex("chain", i))
+		} else if function.Port == ProcessorPort {
+			// reserved for the processor container
+			errs = errs.Also(validation.ErrInvalidValue(function.Port, "port").ViaFieldIndex("chain", i))
+		}
+
+		if _, ok := nameUses[function.Name]; !ok {
+			names = append(names, function.Name)
+		}
+		nameUses[function.Name] = append(nameUses[function.Name], fmt.Sprintf("chain[%d].name", i))
+		if _, ok := portUses[function.Port]; !ok {
+			ports = append(ports, function.Port)
+		}
+		portUses[function.Port] = append(portUses[function.Port], fmt.Sprintf("chain[%d].port", i))
+	}
+	for _, name := range names {
+		if name != "" && len(nameUses[name]) > 1 {
+			errs = errs.Also(validation.ErrDuplicateValue(name, nameUses[name]...))
+		}
+	}
+	for _, port := range ports {
+		if len(portUses[port]) > 1 {
+			errs = errs.Also(validation.ErrDuplicateValue(port, portUses[port]...))
+		}
+	}
+
 	return errs
 }
 
@@ -156,6 +310,142 @@ func validateAliasUniqueness(allAliases []string, aliasType string) validation.F
 	return errs
 }
 
+// validateOperationOutputs checks the outputs a split routes messages to are
+// bound by the processor
+func (s *ProcessorSpec) validateOperationOutputs() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	split := s.Operation.Split
+	if split == nil {
+		return errs
+	}
+	aliases := map[string]bool{}
+	for _, output := range s.Outputs {
+		aliases[output.Alias] = true
+	}
+	for i, route := range split.Routes {
+		if route.Output != "" && !aliases[route.Output] {
+			errs = errs.Also(validation.ErrInvalidValue(route.Output, "output").ViaFieldIndex("routes", i).ViaField("split").ViaField("operation"))
+		}
+	}
+	if split.Default != "" && !aliases[split.Default] {
+		errs = errs.Also(validation.ErrInvalidValue(split.Default, "operation.split.default"))
+	}
+
+	return errs
+}
+
+func (o *ProcessorOperation) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	var used, unused []string
+	for _, operation := range []struct {
+		name string
+		set  bool
+	}{
+		{name: "filter", set: o.Filter != nil},
+		{name: "project", set: o.Project != nil},
+		{name: "split", set: o.Split != nil},
+	} {
+		if operation.set {
+			used = append(used, operation.name)
+		} else {
+			unused = append(unused, operation.name)
+		}
+	}
+	if len(used) == 0 {
+		return errs.Also(validation.ErrMissingOneOf(unused...))
+	}
+	if len(used) > 1 {
+		return errs.Also(validation.ErrMultipleOneOf(used...))
+	}
+
+	if o.Filter != nil {
+		errs = errs.Also(validateExpression(o.Filter.Expression, "filter.expression"))
+	}
+	if o.Project != nil {
+		errs = errs.Also(o.Project.Validate().ViaField("project"))
+	}
+	if o.Split != nil {
+		errs = errs.Also(o.Split.Validate().ViaField("split"))
+	}
+
+	return errs
+}
+
+func (p *ProjectOperation) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if len(p.Fields) == 0 {
+		return errs.Also(validation.ErrMissingField("fields"))
+	}
+	var names []string
+	uses := map[string][]string{}
+	for i, field := range p.Fields {
+		if field.Path == "" {
+			errs = errs.Also(validation.ErrMissingField("path").ViaFieldIndex("fields", i))
+			continue
+		}
+		path, err := expression.ParsePath(field.Path)
+		if err != nil {
+			errs = errs.Also(validation.ErrInvalidValue(field.Path, "path").ViaFieldIndex("fields", i))
+			continue
+		}
+		name := field.Name
+		if name == "" {
+			name = path[len(path)-1]
+		}
+		if _, ok := uses[name]; !ok {
+			names = append(names, name)
+		}
+		uses[name] = append(uses[name], fmt.Sprintf("fields[%d]", i))
+	}
+	for _, name := range names {
+		if len(uses[name]) > 1 {
+			errs = errs.Also(validation.ErrDuplicateValue(name, uses[name]...))
+		}
+	}
+
+	return errs
+}
+
+func (s *SplitOperation) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	errs = errs.Also(validateExpression(s.Key, "key"))
+	if len(s.Routes) == 0 {
+		errs = errs.Also(validation.ErrMissingField("routes"))
+	}
+	var values []string
+	uses := map[string][]string{}
+	for i, route := range s.Routes {
+		if route.Output == "" {
+			errs = errs.Also(validation.ErrMissingField("output").ViaFieldIndex("routes", i))
+		}
+		if _, ok := uses[route.Value]; !ok {
+			values = append(values, route.Value)
+		}
+		uses[route.Value] = append(uses[route.Value], fmt.Sprintf("routes[%d].value", i))
+	}
+	for _, value := range values {
+		if len(uses[value]) > 1 {
+			errs = errs.Also(validation.ErrDuplicateValue(value, uses[value]...))
+		}
+	}
+
+	return errs
+}
+
+func validateExpression(expr, field string) validation.FieldErrors {
+	if expr == "" {
+		return validation.ErrMissingField(field)
+	}
+	if _, err := expression.Parse(expr); err != nil {
+		return validation.ErrInvalidValue(expr, field)
+	}
+	return validation.FieldErrors{}
+}
+
 func (b *Build) Validate() validation.FieldErrors {
 	if equality.Semantic.DeepEqual(b, &Build{}) {
 		return validation.ErrMissingField(validation.CurrentField)
@@ -186,12 +476,528 @@ func (b *Build) Validate() validation.FieldErrors {
 	return errs
 }
 
-func filterInvalidContainers(containers []corev1.Container) []corev1.Container {
-	// TODO remove unsupported fields
-	return containers
+func (w *Window) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	switch w.Type {
+	case TumblingWindow:
+		if w.Slide != nil {
+			errs = errs.Also(validation.ErrDisallowedFields("slide", "tumbling windows do not slide"))
+		}
+	case SlidingWindow:
+		if w.Slide == nil {
+			errs = errs.Also(validation.ErrMissingField("slide"))
+		}
+	case "":
+		errs = errs.Also(validation.ErrMissingField("type"))
+	default:
+		errs = errs.Also(validation.ErrInvalidValue(w.Type, "type"))
+	}
+
+	errs = errs.Also(w.Size.Validate().ViaField("size"))
+	if w.Slide != nil {
+		slideErrs := w.Slide.Validate()
+		errs = errs.Also(slideErrs.ViaField("slide"))
+		if len(slideErrs) == 0 {
+			switch {
+			case (w.Size.Count == nil) != (w.Slide.Count == nil):
+				errs = errs.Also(validation.ErrDisallowedFields("slide", "slide must use the same unit as size"))
+			case w.Size.Count != nil && *w.Slide.Count > *w.Size.Count:
+				errs = errs.Also(validation.ErrInvalidValue(*w.Slide.Count, "slide.count"))
+			case w.Size.Duration != nil && w.Slide.Duration.Duration > w.Size.Duration.Duration:
+				errs = errs.Also(validation.ErrInvalidValue(w.Slide.Duration.Duration.String(), "slide.duration"))
+			}
+		}
+	}
+
+	return errs
+}
+
+func (e *WindowExtent) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if e.Count == nil && e.Duration == nil {
+		return errs.Also(validation.ErrMissingOneOf("count", "duration"))
+	}
+	if e.Count != nil && e.Duration != nil {
+		return errs.Also(validation.ErrMultipleOneOf("count", "duration"))
+	}
+
+	if e.Count != nil && *e.Count < int32(1) {
+		errs = errs.Also(validation.ErrInvalidValue(*e.Count, "count"))
+	}
+	if e.Duration != nil && e.Duration.Duration <= 0 {
+		errs = errs.Also(validation.ErrInvalidValue(e.Duration.Duration.String(), "duration"))
+	}
+
+	return errs
+}
+
+func (s *ProcessorState) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if s.Volume == nil && s.Changelog == nil {
+		return errs.Also(validation.ErrMissingOneOf("volume", "changelog"))
+	}
+	if s.Volume != nil && s.Changelog != nil {
+		return errs.Also(validation.ErrMultipleOneOf("volume", "changelog"))
+	}
+
+	if s.Volume != nil && s.Volume.Size.Sign() <= 0 {
+		errs = errs.Also(validation.ErrInvalidValue(s.Volume.Size.String(), "volume.size"))
+	}
+	if s.Changelog != nil && s.Changelog.Gateway.Name == "" {
+		errs = errs.Also(validation.ErrMissingField("changelog.gateway"))
+	}
+
+	return errs
+}
+
+func (s Scale) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if s.Min != nil && *s.Min < int32(0) {
+		errs = errs.Also(validation.ErrInvalidValue(*s.Min, "min"))
+	}
+	// a max of 0 would never run the processor
+	if s.Max != nil && *s.Max < int32(1) {
+		errs = errs.Also(validation.ErrInvalidValue(*s.Max, "max"))
+	}
+	if s.Min != nil && s.Max != nil && *s.Min > *s.Max {
+		errs = errs.Also(validation.ErrInvalidValue(*s.Max, "max"))
+	}
+	// the default max must not be lower than the min
+	if s.Min != nil && s.Max == nil && *s.Min > ScaleMaxDefault {
+		errs = errs.Also(validation.ErrInvalidValue(*s.Min, "min"))
+	}
+	if s.PollingInterval != nil && *s.PollingInterval < int32(1) {
+		errs = errs.Also(validation.ErrInvalidValue(*s.PollingInterval, "pollingInterval"))
+	}
+	if s.CooldownPeriod != nil && *s.CooldownPeriod < int32(0) {
+		errs = errs.Also(validation.ErrInvalidValue(*s.CooldownPeriod, "cooldownPeriod"))
+	}
+
+	return errs
+}
+
+// validateStateful rejects the autoscaling settings, a stateful processor runs
+// a fixed number of replicas
+func (s Scale) validateStateful() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	// a stateful processor does not scale to zero
+	if s.Min != nil && *s.Min < int32(1) {
+		errs = errs.Also(validation.ErrInvalidValue(*s.Min, "min"))
+	}
+	if s.Max != nil {
+		errs = errs.Also(validation.ErrDisallowedFields("max", "stateful processors run scale.min replicas"))
+	}
+	if s.PollingInterval != nil {
+		errs = errs.Also(validation.ErrDisallowedFields("pollingInterval", "stateful processors are not autoscaled"))
+	}
+	if s.CooldownPeriod != nil {
+		errs = errs.Also(validation.ErrDisallowedFields("cooldownPeriod", "stateful processors are not autoscaled"))
+	}
+
+	return errs
+}
+
+func (r *ProcessorRollout) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	surge, surgeErrs := validateIntOrPercent(r.MaxSurge, "maxSurge")
+	unavailable, unavailableErrs := validateIntOrPercent(r.MaxUnavailable, "maxUnavailable")
+	errs = errs.Also(surgeErrs, unavailableErrs)
+	// a rollout that can neither add nor remove a pod never progresses
+	if len(errs) == 0 && r.MaxSurge != nil && r.MaxUnavailable != nil && surge == 0 && unavailable == 0 {
+		errs = errs.Also(validation.ErrInvalidValue(r.MaxUnavailable.String(), "maxUnavailable"))
+	}
+
+	return errs
+}
+
+// validateIntOrPercent checks the value is a non-negative count or a
+// percentage up to 100%, returning the value scaled to 100
+func validateIntOrPercent(value *intstr.IntOrString, name string) (int, validation.FieldErrors) {
+	if value == nil {
+		return 0, validation.FieldErrors{}
+	}
+	scaled, err := intstr.GetValueFromIntOrPercent(value, 100, true)
+	if err != nil || scaled < 0 || (value.Type == intstr.String && scaled > 100) {
+		return 0, validation.ErrInvalidValue(value.String(), name)
+	}
+	return scaled, validation.FieldErrors{}
+}
+
+// supportedPodSpecFields are the PodSpec fields, by json name, a processor's
+// template may set. Other fields are either managed by the processor or
+// unsupported.
+var supportedPodSpecFields = sets.NewString(
+	"affinity",
+	"containers",
+	"nodeSelector",
+	"securityContext",
+	"serviceAccountName",
+	"tolerations",
+	"volumes",
+)
+
+// supportedContainerFields are the Container fields, by json name, the
+// function container may set
+var supportedContainerFields = sets.NewString(
+	"args",
+	"command",
+	"env",
+	"envFrom",
+	"image",
+	"imagePullPolicy",
+	"lifecycle",
+	"livenessProbe",
+	"name",
+	"ports",
+	"readinessProbe",
+	"resources",
+	"securityContext",
+	"stdin",
+	"stdinOnce",
+	"terminationMessagePath",
+	"terminationMessagePolicy",
+	"tty",
+	"volumeMounts",
+	"workingDir",
+)
+
+// disallowedFields returns the json names of the non-zero fields of a struct
+// that are not supported
+func disallowedFields(obj interface{}, supported sets.String) []string {
+	var names []string
+	v := reflect.ValueOf(obj)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if supported.Has(name) || v.Field(i).IsZero() {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func validatePodSpec(spec *corev1.PodSpec) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	for _, name := range disallowedFields(*spec, supportedPodSpecFields) {
+		errs = errs.Also(validation.ErrDisallowedFields(name, fmt.Sprintf("unsupported field, supported PodSpec fields are %s", strings.Join(supportedPodSpecFields.List(), ", "))))
+	}
+
+	volumes := sets.NewString()
+	for _, volume := range spec.Volumes {
+		volumes.Insert(volume.Name)
+	}
+	// the defaulter guarantees at least one container
+	errs = errs.Also(validateFunctionContainer(&spec.Containers[0], volumes).ViaFieldIndex("containers", 0))
+	for i := range spec.Containers[1:] {
+		errs = errs.Also(validation.ErrDisallowedFields(fmt.Sprintf("containers[%d]", i+1), "only the function container may be set"))
+	}
+
+	keys := make([]string, 0, len(spec.NodeSelector))
+	for key := range spec.NodeSelector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if msgs := k8svalidation.IsQualifiedName(key); len(msgs) != 0 {
+			errs = errs.Also(validation.ErrInvalidValue(key, fmt.Sprintf("nodeSelector[%s]", key)))
+		} else if msgs := k8svalidation.IsValidLabelValue(spec.NodeSelector[key]); len(msgs) != 0 {
+			errs = errs.Also(validation.ErrInvalidValue(spec.NodeSelector[key], fmt.Sprintf("nodeSelector[%s]", key)))
+		}
+	}
+
+	for i, toleration := range spec.Tolerations {
+		errs = errs.Also(validateToleration(toleration).ViaFieldIndex("tolerations", i))
+	}
+	if spec.Affinity != nil {
+		errs = errs.Also(validateAffinity(spec.Affinity).ViaField("affinity"))
+	}
+	if sc := spec.SecurityContext; sc != nil {
+		errs = errs.Also(validateNonNegative(sc.RunAsUser, "securityContext.runAsUser"))
+		errs = errs.Also(validateNonNegative(sc.RunAsGroup, "securityContext.runAsGroup"))
+		errs = errs.Also(validateNonNegative(sc.FSGroup, "securityContext.fsGroup"))
+		for i, group := range sc.SupplementalGroups {
+			if group < 0 {
+				errs = errs.Also(validation.ErrInvalidArrayValue(group, "securityContext.supplementalGroups", i))
+			}
+		}
+	}
+
+	return errs
+}
+
+func validateFunctionContainer(container *corev1.Container, volumes sets.String) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	for _, name := range disallowedFields(*container, supportedContainerFields) {
+		errs = errs.Also(validation.ErrDisallowedFields(name, fmt.Sprintf("unsupported field, supported Container fields are %s", strings.Join(supportedContainerFields.List(), ", "))))
+	}
+
+	var names []string
+	uses := map[string][]string{}
+	for i, env := range container.Env {
+		if env.Name == "" {
+			errs = errs.Also(validation.ErrMissingField("name").ViaFieldIndex("env", i))
+		} else if msgs := k8svalidation.IsEnvVarName(env.Name); len(msgs) != 0 {
+			errs = errs.Also(validation.ErrInvalidValue(env.Name, "name").ViaFieldIndex("env", i))
+		}
+		if env.Value != "" && env.ValueFrom != nil {
+			errs = errs.Also(validation.ErrMultipleOneOf("value", "valueFrom").ViaFieldIndex("env", i))
+		}
+		if env.Name != "" {
+			if _, ok := uses[env.Name]; !ok {
+				names = append(names, env.Name)
+			}
+			uses[env.Name] = append(uses[env.Name], fmt.Sprintf("env[%d].name", i))
+		}
+	}
+	for _, name := range names {
+		if len(uses[name]) > 1 {
+			errs = errs.Also(validation.ErrDuplicateValue(name, uses[name]...))
+		}
+	}
+
+	for i, source := range container.EnvFrom {
+		errs = errs.Also(validateEnvFromSource(source).ViaFieldIndex("envFrom", i))
+	}
+
+	for i, port := range container.Ports {
+		if msgs := k8svalidation.IsValidPortNum(int(port.ContainerPort)); len(msgs) != 0 {
+			errs = errs.Also(validation.ErrInvalidValue(port.ContainerPort, "containerPort").ViaFieldIndex("ports", i))
+		}
+		if port.Name != "" {
+			if msgs := k8svalidation.IsValidPortName(port.Name); len(msgs) != 0 {
+				errs = errs.Also(validation.ErrInvalidValue(port.Name, "name").ViaFieldIndex("ports", i))
+			}
+		}
+	}
+
+	errs = errs.Also(validateResources(container.Resources).ViaField("resources"))
+
+	for i, mount := range container.VolumeMounts {
+		if mount.Name == "" {
+			errs = errs.Also(validation.ErrMissingField("name").ViaFieldIndex("volumeMounts", i))
+		} else if !volumes.Has(mount.Name) {
+			errs = errs.Also(validation.ErrInvalidValue(mount.Name, "name").ViaFieldIndex("volumeMounts", i))
+		}
+		if mount.MountPath == "" {
+			errs = errs.Also(validation.ErrMissingField("mountPath").ViaFieldIndex("volumeMounts", i))
+		}
+	}
+
+	if sc := container.SecurityContext; sc != nil {
+		errs = errs.Also(validateNonNegative(sc.RunAsUser, "securityContext.runAsUser"))
+		errs = errs.Also(validateNonNegative(sc.RunAsGroup, "securityContext.runAsGroup"))
+		// privileged containers always escalate privileges
+		if sc.Privileged != nil && *sc.Privileged && sc.AllowPrivilegeEscalation != nil && !*sc.AllowPrivilegeEscalation {
+			errs = errs.Also(validation.ErrInvalidValue(false, "securityContext.allowPrivilegeEscalation"))
+		}
+	}
+
+	return errs
+}
+
+func validateEnvFromSource(source corev1.EnvFromSource) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if source.Prefix != "" {
+		if msgs := k8svalidation.IsEnvVarName(source.Prefix); len(msgs) != 0 {
+			errs = errs.Also(validation.ErrInvalidValue(source.Prefix, "prefix"))
+		}
+	}
+	switch {
+	case source.ConfigMapRef == nil && source.SecretRef == nil:
+		errs = errs.Also(validation.ErrMissingOneOf("configMapRef", "secretRef"))
+	case source.ConfigMapRef != nil && source.SecretRef != nil:
+		errs = errs.Also(validation.ErrMultipleOneOf("configMapRef", "secretRef"))
+	case source.ConfigMapRef != nil && source.ConfigMapRef.Name == "":
+		errs = errs.Also(validation.ErrMissingField("configMapRef.name"))
+	case source.SecretRef != nil && source.SecretRef.Name == "":
+		errs = errs.Also(validation.ErrMissingField("secretRef.name"))
+	}
+
+	return errs
+}
+
+func validateResources(resources corev1.ResourceRequirements) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	for _, name := range sortedResourceNames(resources.Limits) {
+		if limit := resources.Limits[name]; limit.Sign() < 0 {
+			errs = errs.Also(validation.ErrInvalidValue(limit.String(), fmt.Sprintf("limits[%s]", name)))
+		}
+	}
+	for _, name := range sortedResourceNames(resources.Requests) {
+		request := resources.Requests[name]
+		if request.Sign() < 0 {
+			errs = errs.Also(validation.ErrInvalidValue(request.String(), fmt.Sprintf("requests[%s]", name)))
+		} else if limit, ok := resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			// a request may not exceed its limit
+			errs = errs.Also(validation.ErrInvalidValue(request.String(), fmt.Sprintf("requests[%s]", name)))
+		}
+	}
+
+	return errs
+}
+
+func sortedResourceNames(resources corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func validateToleration(toleration corev1.Toleration) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	switch toleration.Operator {
+	case "", corev1.TolerationOpEqual:
+		if toleration.Key == "" {
+			// an empty key matches all taints, which requires Exists
+			errs = errs.Also(validation.ErrMissingField("key"))
+		}
+	case corev1.TolerationOpExists:
+		if toleration.Value != "" {
+			errs = errs.Also(validation.ErrInvalidValue(toleration.Value, "value"))
+		}
+	default:
+		errs = errs.Also(validation.ErrInvalidValue(toleration.Operator, "operator"))
+	}
+	if toleration.Key != "" {
+		if msgs := k8svalidation.IsQualifiedName(toleration.Key); len(msgs) != 0 {
+			errs = errs.Also(validation.ErrInvalidValue(toleration.Key, "key"))
+		}
+	}
+
+	switch toleration.Effect {
+	case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		errs = errs.Also(validation.ErrInvalidValue(toleration.Effect, "effect"))
+	}
+	if toleration.TolerationSeconds != nil && toleration.Effect != corev1.TaintEffectNoExecute {
+		errs = errs.Also(validation.ErrDisallowedFields("tolerationSeconds", "only applies to the NoExecute effect"))
+	}
+
+	return errs
+}
+
+func validateAffinity(affinity *corev1.Affinity) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if na := affinity.NodeAffinity; na != nil {
+		if required := na.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			if len(required.NodeSelectorTerms) == 0 {
+				errs = errs.Also(validation.ErrMissingField("nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution.nodeSelectorTerms"))
+			}
+			for i, term := range required.NodeSelectorTerms {
+				errs = errs.Also(validateNodeSelectorTerm(term).ViaFieldIndex("nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution.nodeSelectorTerms", i))
+			}
+		}
+		for i, preferred := range na.PreferredDuringSchedulingIgnoredDuringExecution {
+			if preferred.Weight < 1 || preferred.Weight > 100 {
+				errs = errs.Also(validation.ErrInvalidValue(preferred.Weight, "weight").ViaFieldIndex("nodeAffinity.preferredDuringSchedulingIgnoredDuringExecution", i))
+			}
+			errs = errs.Also(validateNodeSelectorTerm(preferred.Preference).ViaField("preference").ViaFieldIndex("nodeAffinity.preferredDuringSchedulingIgnoredDuringExecution", i))
+		}
+	}
+	if pa := affinity.PodAffinity; pa != nil {
+		errs = errs.Also(validatePodAffinityTerms(pa.RequiredDuringSchedulingIgnoredDuringExecution, pa.PreferredDuringSchedulingIgnoredDuringExecution).ViaField("podAffinity"))
+	}
+	if paa := affinity.PodAntiAffinity; paa != nil {
+		errs = errs.Also(validatePodAffinityTerms(paa.RequiredDuringSchedulingIgnoredDuringExecution, paa.PreferredDuringSchedulingIgnoredDuringExecution).ViaField("podAntiAffinity"))
+	}
+
+	return errs
+}
+
+func validateNodeSelectorTerm(term corev1.NodeSelectorTerm) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	for i, requirement := range term.MatchExpressions {
+		errs = errs.Also(validateNodeSelectorRequirement(requirement).ViaFieldIndex("matchExpressions", i))
+	}
+	for i, requirement := range term.MatchFields {
+		errs = errs.Also(validateNodeSelectorRequirement(requirement).ViaFieldIndex("matchFields", i))
+	}
+
+	return errs
+}
+
+func validateNodeSelectorRequirement(requirement corev1.NodeSelectorRequirement) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if requirement.Key == "" {
+		errs = errs.Also(validation.ErrMissingField("key"))
+	}
+	switch requirement.Operator {
+	case corev1.NodeSelectorOpIn, corev1.NodeSelectorOpNotIn:
+		if len(requirement.Values) == 0 {
+			errs = errs.Also(validation.ErrMissingField("values"))
+		}
+	case corev1.NodeSelectorOpExists, corev1.NodeSelectorOpDoesNotExist:
+		if len(requirement.Values) != 0 {
+			errs = errs.Also(validation.ErrDisallowedFields("values", fmt.Sprintf("values may not be set for the %s operator", requirement.Operator)))
+		}
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if len(requirement.Values) != 1 {
+			errs = errs.Also(validation.ErrInvalidValue(requirement.Values, "values"))
+		}
+	default:
+		errs = errs.Also(validation.ErrInvalidValue(requirement.Operator, "operator"))
+	}
+
+	return errs
+}
+
+func validatePodAffinityTerms(required []corev1.PodAffinityTerm, preferred []corev1.WeightedPodAffinityTerm) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	for i, term := range required {
+		if term.TopologyKey == "" {
+			errs = errs.Also(validation.ErrMissingField("topologyKey").ViaFieldIndex("requiredDuringSchedulingIgnoredDuringExecution", i))
+		}
+	}
+	for i, term := range preferred {
+		if term.Weight < 1 || term.Weight > 100 {
+			errs = errs.Also(validation.ErrInvalidValue(term.Weight, "weight").ViaFieldIndex("preferredDuringSchedulingIgnoredDuringExecution", i))
+		}
+		if term.PodAffinityTerm.TopologyKey == "" {
+			errs = errs.Also(validation.ErrMissingField("podAffinityTerm.topologyKey").ViaFieldIndex("preferredDuringSchedulingIgnoredDuringExecution", i))
+		}
+	}
+
+	return errs
+}
+
+func validateNonNegative(value *int64, name string) validation.FieldErrors {
+	if value != nil && *value < 0 {
+		return validation.ErrInvalidValue(*value, name)
+	}
+	return validation.FieldErrors{}
 }
 
-func filterInvalidVolumes(volumes []corev1.Volume) []corev1.Volume {
-	// TODO remove unsupported fields
-	return volumes
+// validStreamReference checks the stream is referenced as either `name` or
+// `namespace/name`
+func validStreamReference(stream string) bool {
+	parts := strings.Split(stream, "/")
+	if len(parts) > 2 {
+		return false
+	}
+	for _, part := range parts {
+		if part == "" {
+			return false
+		}
+	}
+	return true
 }
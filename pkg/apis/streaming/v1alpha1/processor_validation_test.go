@@ -18,9 +18,13 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/projectriff/system/pkg/validation"
 )
@@ -65,6 +69,12 @@ func TestValidateProcessor(t *testing.T) {
 }
 
 func TestValidateProcessorSpec(t *testing.T) {
+	zero := int32(0)
+	negativeLag := int64(-1)
+	hundred := int32(100)
+	startTime := metav1.Date(2020, time.April, 1, 12, 0, 0, 0, time.UTC)
+	percent := intstr.FromString("25%")
+
 	for _, c := range []struct {
 		name     string
 		target   *ProcessorSpec
@@ -224,6 +234,51 @@ func TestValidateProcessorSpec(t *testing.T) {
 			},
 		},
 		expected: validation.FieldErrors{},
+	}, {
+		name: "stream in another namespace",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "platform/my-stream", Alias: "my-input"},
+			},
+			Outputs: []OutputStreamBinding{
+				{Stream: "platform/my-stream", Alias: "my-output"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "invalid stream references",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "platform/", Alias: "my-input"},
+			},
+			Outputs: []OutputStreamBinding{
+				{Stream: "a/b/c", Alias: "my-output"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidValue("platform/", "inputs[0].stream"),
+			validation.ErrInvalidValue("a/b/c", "outputs[0].stream"),
+		),
 	}, {
 		name: "valid offsets",
 		target: &ProcessorSpec{
@@ -261,6 +316,175 @@ func TestValidateProcessorSpec(t *testing.T) {
 			},
 		},
 		expected: validation.ErrInvalidValue("42", "inputs[0].startOffset"),
+	}, {
+		name: "valid start time",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "my-input", StartTime: &startTime},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid partition offsets",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "my-input", PartitionOffsets: []PartitionOffset{{Partition: 0, Offset: 42}, {Partition: 1, Offset: 0}}},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "ambiguous start position",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "my-input", StartOffset: Earliest, StartTime: &startTime, PartitionOffsets: []PartitionOffset{{Partition: 0, Offset: 42}}},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.ErrMultipleOneOf("startOffset", "startTime", "partitionOffsets").ViaFieldIndex("inputs", 0),
+	}, {
+		name: "invalid partition offsets",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "my-input", PartitionOffsets: []PartitionOffset{{Partition: -1, Offset: -1}}},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidValue(int32(-1), "inputs[0].partitionOffsets[0].partition"),
+			validation.ErrInvalidValue(int64(-1), "inputs[0].partitionOffsets[0].offset"),
+		),
+	}, {
+		name: "duplicate partition offsets",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "my-input", PartitionOffsets: []PartitionOffset{{Partition: 0, Offset: 42}, {Partition: 0, Offset: 17}}},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.ErrDuplicateValue(int32(0), "inputs[0].partitionOffsets[0].partition", "inputs[0].partitionOffsets[1].partition"),
+	}, {
+		name: "valid lag threshold",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "my-input", LagThreshold: &hundred},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "invalid lag threshold",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "my-input", LagThreshold: &zero},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.ErrInvalidValue(zero, "inputs[0].lagThreshold"),
+	}, {
+		name: "invalid scale",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "my-input"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+			Scale: Scale{
+				Max: &zero,
+			},
+		},
+		expected: validation.ErrInvalidValue(zero, "scale.max"),
+	}, {
+		name: "invalid lagging threshold",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "my-input"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+			LaggingThreshold: &negativeLag,
+		},
+		expected: validation.ErrInvalidValue(negativeLag, "laggingThreshold"),
 	}, {
 		name: "input alias collision",
 		target: &ProcessorSpec{
@@ -343,49 +567,1284 @@ func TestValidateProcessorSpec(t *testing.T) {
 			},
 		},
 		expected: validation.ErrInvalidValue("processor", "template.spec.containers[0].name"),
-	}} {
-		t.Run(c.name, func(t *testing.T) {
-			actual := c.target.Validate()
-			if diff := cmp.Diff(c.expected, actual); diff != "" {
-				t.Errorf("validateProcessorSpec(%s) (-expected, +actual) = %v", c.name, diff)
-			}
-		})
-	}
-}
-
-func TestValidateBuild(t *testing.T) {
-	for _, c := range []struct {
-		name     string
-		target   *Build
-		expected validation.FieldErrors
-	}{{
-		name:     "empty",
-		target:   &Build{},
-		expected: validation.ErrMissingField(validation.CurrentField),
 	}, {
-		name: "valid function",
-		target: &Build{
-			FunctionRef: "my-func",
+		name: "disallowed template field",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "my-input"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+					HostNetwork: true,
+				},
+			},
 		},
-		expected: validation.FieldErrors{},
+		expected: validation.ErrDisallowedFields("template.spec.hostNetwork", "unsupported field, supported PodSpec fields are affinity, containers, nodeSelector, securityContext, serviceAccountName, tolerations, volumes"),
 	}, {
-		name: "valid container",
-		target: &Build{
-			ContainerRef: "my-container",
+		name: "rollout strategy for stateful processor",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			State: &ProcessorState{
+				Volume: &StateVolume{Size: resource.MustParse("1Gi")},
+			},
+			Rollout: &ProcessorRollout{
+				Paused:   true,
+				MaxSurge: &percent,
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
 		},
-		expected: validation.FieldErrors{},
+		expected: validation.ErrDisallowedFields("rollout", "stateful processors roll out one pod at a time"),
 	}, {
-		name: "too many options",
-		target: &Build{
-			FunctionRef:  "my-func",
-			ContainerRef: "my-container",
-		},
-		expected: validation.ErrMultipleOneOf("containerRef", "functionRef"),
-	}} {
-		t.Run(c.name, func(t *testing.T) {
-			actual := c.target.Validate()
-			if diff := cmp.Diff(c.expected, actual); diff != "" {
-				t.Errorf("validateProcessor(%s) (-expected, +actual) = %v", c.name, diff)
+		name: "scale to zero for stateful processor",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			State: &ProcessorState{
+				Volume: &StateVolume{Size: resource.MustParse("1Gi")},
+			},
+			Scale: Scale{
+				Min: &zero,
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.ErrInvalidValue(zero, "scale.min"),
+	}, {
+		name: "autoscaling for stateful processor",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			State: &ProcessorState{
+				Volume: &StateVolume{Size: resource.MustParse("1Gi")},
+			},
+			Scale: Scale{
+				Max:             &hundred,
+				PollingInterval: &hundred,
+				CooldownPeriod:  &hundred,
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrDisallowedFields("scale.max", "stateful processors run scale.min replicas"),
+			validation.ErrDisallowedFields("scale.pollingInterval", "stateful processors are not autoscaled"),
+			validation.ErrDisallowedFields("scale.cooldownPeriod", "stateful processors are not autoscaled"),
+		),
+	}, {
+		name: "valid, min replicas for stateful processor",
+		target: &ProcessorSpec{
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			State: &ProcessorState{
+				Volume: &StateVolume{Size: resource.MustParse("1Gi")},
+			},
+			Scale: Scale{
+				Min: &hundred,
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid operation",
+		target: &ProcessorSpec{
+			Operation: &ProcessorOperation{
+				Filter: &FilterOperation{Expression: `headers["X-Tenant"] != "test"`},
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			Outputs: []OutputStreamBinding{
+				{Stream: "my-other-stream", Alias: "out"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "operation with function",
+		target: &ProcessorSpec{
+			Operation: &ProcessorOperation{
+				Filter: &FilterOperation{Expression: `contentType == "application/json"`},
+			},
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			Outputs: []OutputStreamBinding{
+				{Stream: "my-other-stream", Alias: "out"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function", Image: "my-image"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMultipleOneOf("build", "operation"),
+			validation.ErrMultipleOneOf("operation", "template.spec.containers[0].image"),
+		),
+	}, {
+		name: "valid chain",
+		target: &ProcessorSpec{
+			Chain: []ChainedFunction{
+				{Name: "parse", Build: Build{FunctionRef: "parse"}, Port: 8081},
+				{Name: "enrich", Build: Build{ContainerRef: "enrich"}, Port: 8082},
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "chain with function",
+		target: &ProcessorSpec{
+			Chain: []ChainedFunction{
+				{Name: "parse", Build: Build{FunctionRef: "parse"}, Port: 8081},
+			},
+			Build: &Build{
+				FunctionRef: "my-func",
+			},
+			Operation: &ProcessorOperation{
+				Filter: &FilterOperation{Expression: `contentType == "application/json"`},
+			},
+			Rollout: &ProcessorRollout{
+				Paused: true,
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function", Image: "my-image"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMultipleOneOf("build", "chain"),
+			validation.ErrMultipleOneOf("chain", "operation"),
+			validation.ErrMultipleOneOf("chain", "template.spec.containers[0].image"),
+			validation.ErrDisallowedFields("rollout.paused", "chains roll out the latest image of each function"),
+		),
+	}, {
+		name: "invalid chain",
+		target: &ProcessorSpec{
+			Chain: []ChainedFunction{
+				{Build: Build{FunctionRef: "parse"}, Port: 8081},
+				{Name: "processor", Build: Build{FunctionRef: "enrich"}, Port: 0},
+				{Name: "score", Port: 70000},
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMissingField("chain[0].name"),
+			validation.ErrInvalidValue("processor", "chain[1].name"),
+			validation.ErrInvalidValue(int32(0), "chain[1].port"),
+			validation.ErrMissingField("chain[2].build"),
+			validation.ErrInvalidValue(int32(70000), "chain[2].port"),
+		),
+	}, {
+		name: "chain names not container names",
+		target: &ProcessorSpec{
+			Chain: []ChainedFunction{
+				{Name: "Parse", Build: Build{FunctionRef: "parse"}, Port: 8081},
+				{Name: "enrich.v2", Build: Build{FunctionRef: "enrich"}, Port: 8082},
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidValue("Parse", "chain[0].name"),
+			validation.ErrInvalidValue("enrich.v2", "chain[1].name"),
+		),
+	}, {
+		name: "chain port reserved for the processor",
+		target: &ProcessorSpec{
+			Chain: []ChainedFunction{
+				{Name: "parse", Build: Build{FunctionRef: "parse"}, Port: ProcessorPort},
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.ErrInvalidValue(ProcessorPort, "chain[0].port"),
+	}, {
+		name: "duplicate chain names and ports",
+		target: &ProcessorSpec{
+			Chain: []ChainedFunction{
+				{Name: "parse", Build: Build{FunctionRef: "parse"}, Port: 8081},
+				{Name: "parse", Build: Build{FunctionRef: "enrich"}, Port: 8081},
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrDuplicateValue("parse", "chain[0].name", "chain[1].name"),
+			validation.ErrDuplicateValue(int32(8081), "chain[0].port", "chain[1].port"),
+		),
+	}, {
+		name: "operation requires outputs",
+		target: &ProcessorSpec{
+			Operation: &ProcessorOperation{
+				Filter: &FilterOperation{Expression: `contentType == "application/json"`},
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.ErrMissingField("outputs"),
+	}, {
+		name: "operation with state",
+		target: &ProcessorSpec{
+			Operation: &ProcessorOperation{
+				Filter: &FilterOperation{Expression: `contentType == "application/json"`},
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			Outputs: []OutputStreamBinding{
+				{Stream: "my-other-stream", Alias: "out"},
+			},
+			State: &ProcessorState{
+				Volume: &StateVolume{Size: resource.MustParse("1Gi")},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.ErrDisallowedFields("state", "operations are stateless"),
+	}, {
+		name: "invalid operation expression",
+		target: &ProcessorSpec{
+			Operation: &ProcessorOperation{
+				Filter: &FilterOperation{Expression: `headers["X-Tenant"] ==`},
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			Outputs: []OutputStreamBinding{
+				{Stream: "my-other-stream", Alias: "out"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.ErrInvalidValue(`headers["X-Tenant"] ==`, "operation.filter.expression"),
+	}, {
+		name: "split to unknown outputs",
+		target: &ProcessorSpec{
+			Operation: &ProcessorOperation{
+				Split: &SplitOperation{
+					Key: `headers["region"]`,
+					Routes: []SplitRoute{
+						{Value: "us", Output: "us"},
+						{Value: "eu", Output: "europe"},
+					},
+					Default: "other",
+				},
+			},
+			Inputs: []InputStreamBinding{
+				{Stream: "my-stream", Alias: "in"},
+			},
+			Outputs: []OutputStreamBinding{
+				{Stream: "my-us-stream", Alias: "us"},
+				{Stream: "my-eu-stream", Alias: "eu"},
+			},
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "function"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidValue("europe", "operation.split.routes[1].output"),
+			validation.ErrInvalidValue("other", "operation.split.default"),
+		),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateProcessorSpec(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateBuild(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *Build
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &Build{},
+		expected: validation.ErrMissingField(validation.CurrentField),
+	}, {
+		name: "valid function",
+		target: &Build{
+			FunctionRef: "my-func",
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid container",
+		target: &Build{
+			ContainerRef: "my-container",
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "too many options",
+		target: &Build{
+			FunctionRef:  "my-func",
+			ContainerRef: "my-container",
+		},
+		expected: validation.ErrMultipleOneOf("containerRef", "functionRef"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateProcessor(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateProcessorOperation(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *ProcessorOperation
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &ProcessorOperation{},
+		expected: validation.ErrMissingOneOf("filter", "project", "split"),
+	}, {
+		name: "too many operations",
+		target: &ProcessorOperation{
+			Filter: &FilterOperation{Expression: "true"},
+			Split:  &SplitOperation{Key: "contentType", Routes: []SplitRoute{{Value: "text/plain", Output: "out"}}},
+		},
+		expected: validation.ErrMultipleOneOf("filter", "split"),
+	}, {
+		name: "valid filter",
+		target: &ProcessorOperation{
+			Filter: &FilterOperation{Expression: `payload.total > 100 && !(headers["X-Test"] == "true")`},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "missing filter expression",
+		target: &ProcessorOperation{
+			Filter: &FilterOperation{},
+		},
+		expected: validation.ErrMissingField("filter.expression"),
+	}, {
+		name: "invalid filter expression",
+		target: &ProcessorOperation{
+			Filter: &FilterOperation{Expression: `"tenant"`},
+		},
+		expected: validation.ErrInvalidValue(`"tenant"`, "filter.expression"),
+	}, {
+		name: "valid project",
+		target: &ProcessorOperation{
+			Project: &ProjectOperation{
+				Fields: []ProjectedField{
+					{Path: "order.id"},
+					{Path: `order.customer["id"]`, Name: "customer"},
+				},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "missing project fields",
+		target: &ProcessorOperation{
+			Project: &ProjectOperation{},
+		},
+		expected: validation.ErrMissingField("project.fields"),
+	}, {
+		name: "invalid project fields",
+		target: &ProcessorOperation{
+			Project: &ProjectOperation{
+				Fields: []ProjectedField{
+					{},
+					{Path: "order..id"},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMissingField("project.fields[0].path"),
+			validation.ErrInvalidValue("order..id", "project.fields[1].path"),
+		),
+	}, {
+		name: "duplicate project names",
+		target: &ProcessorOperation{
+			Project: &ProjectOperation{
+				Fields: []ProjectedField{
+					{Path: "order.id"},
+					{Path: "customer.id"},
+					{Path: "customer.name", Name: "name"},
+				},
+			},
+		},
+		expected: validation.ErrDuplicateValue("id", "project.fields[0]", "project.fields[1]"),
+	}, {
+		name: "valid split",
+		target: &ProcessorOperation{
+			Split: &SplitOperation{
+				Key: `headers["region"]`,
+				Routes: []SplitRoute{
+					{Value: "us", Output: "us"},
+					{Value: "eu", Output: "eu"},
+				},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "split without routes",
+		target: &ProcessorOperation{
+			Split: &SplitOperation{Key: `headers["region"]`},
+		},
+		expected: validation.ErrMissingField("split.routes"),
+	}, {
+		name: "invalid split",
+		target: &ProcessorOperation{
+			Split: &SplitOperation{
+				Key: `headers[region]`,
+				Routes: []SplitRoute{
+					{Value: "us", Output: "us"},
+					{Value: "us", Output: "eu"},
+					{Value: "apac"},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidValue("headers[region]", "split.key"),
+			validation.ErrMissingField("split.routes[2].output"),
+			validation.ErrDuplicateValue("us", "split.routes[0].value", "split.routes[1].value"),
+		),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateProcessorOperation(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateProcessorRollout(t *testing.T) {
+	zero := intstr.FromInt(0)
+	one := intstr.FromInt(1)
+	negative := intstr.FromInt(-1)
+	zeroPercent := intstr.FromString("0%")
+	quarter := intstr.FromString("25%")
+	tooMuch := intstr.FromString("150%")
+	notPercent := intstr.FromString("one")
+
+	for _, c := range []struct {
+		name     string
+		target   *ProcessorRollout
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &ProcessorRollout{},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "paused",
+		target: &ProcessorRollout{
+			Paused:  true,
+			Promote: "v2",
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid strategy",
+		target: &ProcessorRollout{
+			MaxSurge:       &quarter,
+			MaxUnavailable: &zero,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid, zero surge",
+		target: &ProcessorRollout{
+			MaxSurge:       &zeroPercent,
+			MaxUnavailable: &one,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "invalid, no progress",
+		target: &ProcessorRollout{
+			MaxSurge:       &zeroPercent,
+			MaxUnavailable: &zero,
+		},
+		expected: validation.ErrInvalidValue("0", "maxUnavailable"),
+	}, {
+		name: "invalid values",
+		target: &ProcessorRollout{
+			MaxSurge:       &negative,
+			MaxUnavailable: &tooMuch,
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidValue("-1", "maxSurge"),
+			validation.ErrInvalidValue("150%", "maxUnavailable"),
+		),
+	}, {
+		name: "invalid percentage",
+		target: &ProcessorRollout{
+			MaxSurge: &notPercent,
+		},
+		expected: validation.ErrInvalidValue("one", "maxSurge"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateProcessorRollout(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateScale(t *testing.T) {
+	negativeOne := int32(-1)
+	zero := int32(0)
+	one := int32(1)
+	five := int32(5)
+	thirty := int32(30)
+	fifty := int32(50)
+
+	for _, c := range []struct {
+		name     string
+		target   *Scale
+		expected validation.FieldErrors
+	}{{
+		name:     "valid, empty scale",
+		target:   &Scale{},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid, scale to zero",
+		target: &Scale{
+			Min: &zero,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "invalid, negative min",
+		target: &Scale{
+			Min: &negativeOne,
+		},
+		expected: validation.ErrInvalidValue(negativeOne, "min"),
+	}, {
+		name: "valid, max",
+		target: &Scale{
+			Max: &one,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "invalid, non-positive max",
+		target: &Scale{
+			Max: &zero,
+		},
+		expected: validation.ErrInvalidValue(zero, "max"),
+	}, {
+		name: "valid, min and max",
+		target: &Scale{
+			Min: &one,
+			Max: &five,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "invalid, max lower than min",
+		target: &Scale{
+			Min: &five,
+			Max: &one,
+		},
+		expected: validation.ErrInvalidValue(one, "max"),
+	}, {
+		name: "valid, min of the default max",
+		target: &Scale{
+			Min: &thirty,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "invalid, min above the default max",
+		target: &Scale{
+			Min: &fifty,
+		},
+		expected: validation.ErrInvalidValue(fifty, "min"),
+	}, {
+		name: "valid, min above the default max with max",
+		target: &Scale{
+			Min: &fifty,
+			Max: &fifty,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid, polling interval and cooldown period",
+		target: &Scale{
+			PollingInterval: &five,
+			CooldownPeriod:  &zero,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "invalid, non-positive polling interval",
+		target: &Scale{
+			PollingInterval: &zero,
+		},
+		expected: validation.ErrInvalidValue(zero, "pollingInterval"),
+	}, {
+		name: "invalid, negative cooldown period",
+		target: &Scale{
+			CooldownPeriod: &negativeOne,
+		},
+		expected: validation.ErrInvalidValue(negativeOne, "cooldownPeriod"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateScale(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateWindow(t *testing.T) {
+	zero := int32(0)
+	ten := int32(10)
+	hundred := int32(100)
+	minute := &metav1.Duration{Duration: time.Minute}
+	second := &metav1.Duration{Duration: time.Second}
+
+	for _, c := range []struct {
+		name     string
+		target   *Window
+		expected validation.FieldErrors
+	}{{
+		name: "valid, tumbling count",
+		target: &Window{
+			Type: TumblingWindow,
+			Size: WindowExtent{Count: &hundred},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid, sliding duration",
+		target: &Window{
+			Type:  SlidingWindow,
+			Size:  WindowExtent{Duration: minute},
+			Slide: &WindowExtent{Duration: second},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "missing type",
+		target: &Window{
+			Size: WindowExtent{Count: &hundred},
+		},
+		expected: validation.ErrMissingField("type"),
+	}, {
+		name: "invalid type",
+		target: &Window{
+			Type: "hopping",
+			Size: WindowExtent{Count: &hundred},
+		},
+		expected: validation.ErrInvalidValue("hopping", "type"),
+	}, {
+		name: "tumbling with slide",
+		target: &Window{
+			Type:  TumblingWindow,
+			Size:  WindowExtent{Count: &hundred},
+			Slide: &WindowExtent{Count: &ten},
+		},
+		expected: validation.ErrDisallowedFields("slide", "tumbling windows do not slide"),
+	}, {
+		name: "sliding without slide",
+		target: &Window{
+			Type: SlidingWindow,
+			Size: WindowExtent{Count: &hundred},
+		},
+		expected: validation.ErrMissingField("slide"),
+	}, {
+		name: "missing size",
+		target: &Window{
+			Type: TumblingWindow,
+		},
+		expected: validation.ErrMissingOneOf("count", "duration").ViaField("size"),
+	}, {
+		name: "size with count and duration",
+		target: &Window{
+			Type: TumblingWindow,
+			Size: WindowExtent{Count: &hundred, Duration: minute},
+		},
+		expected: validation.ErrMultipleOneOf("count", "duration").ViaField("size"),
+	}, {
+		name: "invalid size count",
+		target: &Window{
+			Type: TumblingWindow,
+			Size: WindowExtent{Count: &zero},
+		},
+		expected: validation.ErrInvalidValue(zero, "size.count"),
+	}, {
+		name: "invalid size duration",
+		target: &Window{
+			Type: TumblingWindow,
+			Size: WindowExtent{Duration: &metav1.Duration{}},
+		},
+		expected: validation.ErrInvalidValue("0s", "size.duration"),
+	}, {
+		name: "slide unit mismatch",
+		target: &Window{
+			Type:  SlidingWindow,
+			Size:  WindowExtent{Count: &hundred},
+			Slide: &WindowExtent{Duration: second},
+		},
+		expected: validation.ErrDisallowedFields("slide", "slide must use the same unit as size"),
+	}, {
+		name: "slide count larger than size",
+		target: &Window{
+			Type:  SlidingWindow,
+			Size:  WindowExtent{Count: &ten},
+			Slide: &WindowExtent{Count: &hundred},
+		},
+		expected: validation.ErrInvalidValue(hundred, "slide.count"),
+	}, {
+		name: "slide duration larger than size",
+		target: &Window{
+			Type:  SlidingWindow,
+			Size:  WindowExtent{Duration: second},
+			Slide: &WindowExtent{Duration: minute},
+		},
+		expected: validation.ErrInvalidValue("1m0s", "slide.duration"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateWindow(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateProcessorState(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *ProcessorState
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &ProcessorState{},
+		expected: validation.ErrMissingOneOf("volume", "changelog"),
+	}, {
+		name: "valid volume",
+		target: &ProcessorState{
+			Volume: &StateVolume{Size: resource.MustParse("1Gi")},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid changelog",
+		target: &ProcessorState{
+			Changelog: &StateChangelog{Gateway: corev1.LocalObjectReference{Name: "my-gateway"}},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "volume and changelog",
+		target: &ProcessorState{
+			Volume:    &StateVolume{Size: resource.MustParse("1Gi")},
+			Changelog: &StateChangelog{Gateway: corev1.LocalObjectReference{Name: "my-gateway"}},
+		},
+		expected: validation.ErrMultipleOneOf("volume", "changelog"),
+	}, {
+		name: "missing volume size",
+		target: &ProcessorState{
+			Volume: &StateVolume{},
+		},
+		expected: validation.ErrInvalidValue("0", "volume.size"),
+	}, {
+		name: "missing changelog gateway",
+		target: &ProcessorState{
+			Changelog: &StateChangelog{},
+		},
+		expected: validation.ErrMissingField("changelog.gateway"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateProcessorState(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidatePodSpec(t *testing.T) {
+	negative := int64(-1)
+	one := int64(1)
+	yes := true
+	no := false
+
+	for _, c := range []struct {
+		name     string
+		target   *corev1.PodSpec
+		expected validation.FieldErrors
+	}{{
+		name: "minimal",
+		target: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "function"},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "supported fields",
+		target: &corev1.PodSpec{
+			ServiceAccountName: "my-service-account",
+			Containers: []corev1.Container{
+				{
+					Name:       "function",
+					Image:      "my-image",
+					Command:    []string{"/cnb/lifecycle/launcher"},
+					Args:       []string{"--verbose"},
+					WorkingDir: "/workspace",
+					Ports: []corev1.ContainerPort{
+						{Name: "grpc", ContainerPort: 8081},
+						{Name: "metrics", ContainerPort: 9090},
+					},
+					ReadinessProbe: &corev1.Probe{
+						Handler: corev1.Handler{
+							TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8081)},
+						},
+					},
+					LivenessProbe: &corev1.Probe{
+						Handler: corev1.Handler{
+							HTTPGet: &corev1.HTTPGetAction{Path: "/health", Port: intstr.FromString("metrics")},
+						},
+					},
+					Lifecycle: &corev1.Lifecycle{
+						PreStop: &corev1.Handler{
+							Exec: &corev1.ExecAction{Command: []string{"sleep", "5"}},
+						},
+					},
+					Env: []corev1.EnvVar{
+						{Name: "MY_VAR", Value: "my-value"},
+						{Name: "MY_SECRET", ValueFrom: &corev1.EnvVarSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"},
+								Key:                  "password",
+							},
+						}},
+					},
+					EnvFrom: []corev1.EnvFromSource{
+						{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "my-config"}}},
+						{Prefix: "DB_", SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "my-db"}}},
+					},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("500m"),
+							corev1.ResourceMemory: resource.MustParse("256Mi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("1"),
+							corev1.ResourceMemory: resource.MustParse("256Mi"),
+						},
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "my-volume", MountPath: "/data"},
+					},
+					SecurityContext: &corev1.SecurityContext{
+						RunAsUser:                &one,
+						AllowPrivilegeEscalation: &no,
+					},
+					ImagePullPolicy:          corev1.PullAlways,
+					TerminationMessagePath:   "/tmp/termination-log",
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Stdin:                    true,
+					StdinOnce:                true,
+					TTY:                      true,
+				},
+			},
+			Volumes: []corev1.Volume{
+				{Name: "my-volume"},
+			},
+			NodeSelector: map[string]string{
+				"kubernetes.io/os": "linux",
+			},
+			Tolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "streaming", Effect: corev1.TaintEffectNoSchedule},
+				{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute, TolerationSeconds: &one},
+			},
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "disktype", Operator: corev1.NodeSelectorOpIn, Values: []string{"ssd"}},
+							}},
+						},
+					},
+				},
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+						{Weight: 100, PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"}},
+					},
+				},
+			},
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsUser: &one,
+				FSGroup:   &one,
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "disallowed pod spec fields",
+		target: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "function"},
+			},
+			HostNetwork:   true,
+			RestartPolicy: corev1.RestartPolicyNever,
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrDisallowedFields("restartPolicy", "unsupported field, supported PodSpec fields are affinity, containers, nodeSelector, securityContext, serviceAccountName, tolerations, volumes"),
+			validation.ErrDisallowedFields("hostNetwork", "unsupported field, supported PodSpec fields are affinity, containers, nodeSelector, securityContext, serviceAccountName, tolerations, volumes"),
+		),
+	}, {
+		name: "disallowed container fields",
+		target: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "function",
+					VolumeDevices: []corev1.VolumeDevice{
+						{Name: "my-device", DevicePath: "/dev/xvda"},
+					},
+					StartupProbe: &corev1.Probe{},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrDisallowedFields("containers[0].volumeDevices", "unsupported field, supported Container fields are args, command, env, envFrom, image, imagePullPolicy, lifecycle, livenessProbe, name, ports, readinessProbe, resources, securityContext, stdin, stdinOnce, terminationMessagePath, terminationMessagePolicy, tty, volumeMounts, workingDir"),
+			validation.ErrDisallowedFields("containers[0].startupProbe", "unsupported field, supported Container fields are args, command, env, envFrom, image, imagePullPolicy, lifecycle, livenessProbe, name, ports, readinessProbe, resources, securityContext, stdin, stdinOnce, terminationMessagePath, terminationMessagePolicy, tty, volumeMounts, workingDir"),
+		),
+	}, {
+		name: "invalid ports",
+		target: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "function",
+					Ports: []corev1.ContainerPort{
+						{ContainerPort: 0},
+						{Name: "not_a_port_name", ContainerPort: 70000},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidValue(int32(0), "containers[0].ports[0].containerPort"),
+			validation.ErrInvalidValue(int32(70000), "containers[0].ports[1].containerPort"),
+			validation.ErrInvalidValue("not_a_port_name", "containers[0].ports[1].name"),
+		),
+	}, {
+		name: "additional containers",
+		target: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "function"},
+				{Name: "sidecar"},
+			},
+		},
+		expected: validation.ErrDisallowedFields("containers[1]", "only the function container may be set"),
+	}, {
+		name: "invalid env",
+		target: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "function",
+					Env: []corev1.EnvVar{
+						{Value: "my-value"},
+						{Name: "1VAR"},
+						{Name: "MY_VAR", Value: "my-value", ValueFrom: &corev1.EnvVarSource{}},
+						{Name: "MY_VAR"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMissingField("containers[0].env[0].name"),
+			validation.ErrInvalidValue("1VAR", "containers[0].env[1].name"),
+			validation.ErrMultipleOneOf("value", "valueFrom").ViaFieldIndex("env", 2).ViaFieldIndex("containers", 0),
+			validation.ErrDuplicateValue("MY_VAR", "containers[0].env[2].name", "containers[0].env[3].name"),
+		),
+	}, {
+		name: "invalid envFrom",
+		target: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "function",
+					EnvFrom: []corev1.EnvFromSource{
+						{},
+						{
+							ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "my-config"}},
+							SecretRef:    &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"}},
+						},
+						{Prefix: "1_", SecretRef: &corev1.SecretEnvSource{}},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMissingOneOf("configMapRef", "secretRef").ViaFieldIndex("envFrom", 0).ViaFieldIndex("containers", 0),
+			validation.ErrMultipleOneOf("configMapRef", "secretRef").ViaFieldIndex("envFrom", 1).ViaFieldIndex("containers", 0),
+			validation.ErrInvalidValue("1_", "containers[0].envFrom[2].prefix"),
+			validation.ErrMissingField("containers[0].envFrom[2].secretRef.name"),
+		),
+	}, {
+		name: "invalid resources",
+		target: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "function",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("2"),
+							corev1.ResourceMemory: resource.MustParse("-1Mi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("1"),
+						},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidValue("2", "containers[0].resources.requests[cpu]"),
+			validation.ErrInvalidValue("-1Mi", "containers[0].resources.requests[memory]"),
+		),
+	}, {
+		name: "invalid volume mounts",
+		target: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "function",
+					VolumeMounts: []corev1.VolumeMount{
+						{MountPath: "/data"},
+						{Name: "missing-volume"},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMissingField("containers[0].volumeMounts[0].name"),
+			validation.ErrInvalidValue("missing-volume", "containers[0].volumeMounts[1].name"),
+			validation.ErrMissingField("containers[0].volumeMounts[1].mountPath"),
+		),
+	}, {
+		name: "invalid security contexts",
+		target: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "function",
+					SecurityContext: &corev1.SecurityContext{
+						RunAsUser:                &negative,
+						Privileged:               &yes,
+						AllowPrivilegeEscalation: &no,
+					},
+				},
+			},
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsGroup:         &negative,
+				SupplementalGroups: []int64{1, -1},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidValue(negative, "containers[0].securityContext.runAsUser"),
+			validation.ErrInvalidValue(false, "containers[0].securityContext.allowPrivilegeEscalation"),
+			validation.ErrInvalidValue(negative, "securityContext.runAsGroup"),
+			validation.ErrInvalidArrayValue(negative, "securityContext.supplementalGroups", 1),
+		),
+	}, {
+		name: "invalid node selector",
+		target: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "function"},
+			},
+			NodeSelector: map[string]string{
+				"disktype":  "not a label value",
+				"not a key": "ssd",
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidValue("not a label value", "nodeSelector[disktype]"),
+			validation.ErrInvalidValue("not a key", "nodeSelector[not a key]"),
+		),
+	}, {
+		name: "invalid tolerations",
+		target: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "function"},
+			},
+			Tolerations: []corev1.Toleration{
+				{Value: "streaming"},
+				{Key: "dedicated", Operator: corev1.TolerationOpExists, Value: "streaming"},
+				{Key: "dedicated", Operator: "Matches", Effect: "Evict"},
+				{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule, TolerationSeconds: &one},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMissingField("tolerations[0].key"),
+			validation.ErrInvalidValue("streaming", "tolerations[1].value"),
+			validation.ErrInvalidValue(corev1.TolerationOperator("Matches"), "tolerations[2].operator"),
+			validation.ErrInvalidValue(corev1.TaintEffect("Evict"), "tolerations[2].effect"),
+			validation.ErrDisallowedFields("tolerations[3].tolerationSeconds", "only applies to the NoExecute effect"),
+		),
+	}, {
+		name: "invalid affinity",
+		target: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "function"},
+			},
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Operator: corev1.NodeSelectorOpIn},
+								{Key: "disktype", Operator: corev1.NodeSelectorOpExists, Values: []string{"ssd"}},
+							}},
+						},
+					},
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{
+						{Weight: 0, Preference: corev1.NodeSelectorTerm{MatchFields: []corev1.NodeSelectorRequirement{
+							{Key: "metadata.name", Operator: "Like", Values: []string{"node-1"}},
+						}}},
+					},
+				},
+				PodAffinity: &corev1.PodAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+						{},
+					},
+				},
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+						{Weight: 101, PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"}},
+					},
+				},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMissingField("affinity.nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution.nodeSelectorTerms[0].matchExpressions[0].key"),
+			validation.ErrMissingField("affinity.nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution.nodeSelectorTerms[0].matchExpressions[0].values"),
+			validation.ErrDisallowedFields("affinity.nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution.nodeSelectorTerms[0].matchExpressions[1].values", "values may not be set for the Exists operator"),
+			validation.ErrInvalidValue(int32(0), "affinity.nodeAffinity.preferredDuringSchedulingIgnoredDuringExecution[0].weight"),
+			validation.ErrInvalidValue(corev1.NodeSelectorOperator("Like"), "affinity.nodeAffinity.preferredDuringSchedulingIgnoredDuringExecution[0].preference.matchFields[0].operator"),
+			validation.ErrMissingField("affinity.podAffinity.requiredDuringSchedulingIgnoredDuringExecution[0].topologyKey"),
+			validation.ErrInvalidValue(int32(101), "affinity.podAntiAffinity.preferredDuringSchedulingIgnoredDuringExecution[0].weight"),
+		),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := validatePodSpec(c.target)
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validatePodSpec(%s) (-expected, +actual) = %v", c.name, diff)
 			}
 		})
 	}
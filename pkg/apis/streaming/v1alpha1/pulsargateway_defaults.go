@@ -27,4 +27,10 @@ func (r *PulsarGateway) Default() {
 }
 
 func (s *PulsarGatewaySpec) Default() {
+	if s.Tenant == "" {
+		s.Tenant = PulsarGatewayDefaultTenant
+	}
+	if s.Namespace == "" {
+		s.Namespace = PulsarGatewayDefaultNamespace
+	}
 }
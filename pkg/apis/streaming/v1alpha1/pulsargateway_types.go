@@ -17,6 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"bytes"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -32,6 +36,27 @@ var (
 	PulsarGatewayType     = "pulsar"
 )
 
+const (
+	// PulsarGatewayDefaultTenant is the Pulsar tenant streams are provisioned
+	// in when no tenant template is set
+	PulsarGatewayDefaultTenant = "public"
+	// PulsarGatewayDefaultNamespace is the Pulsar namespace streams are
+	// provisioned in when no namespace template is set
+	PulsarGatewayDefaultNamespace = "default"
+
+	// PulsarGatewayTokenKey is the key within the token secret holding the
+	// token to authenticate with
+	PulsarGatewayTokenKey = "token"
+	// PulsarGatewayCAKey is the key within the CA secret holding the CA bundle
+	PulsarGatewayCAKey = "ca.crt"
+	// PulsarGatewayClientCertKey is the key within the client cert secret
+	// holding the certificate
+	PulsarGatewayClientCertKey = corev1.TLSCertKey
+	// PulsarGatewayClientKeyKey is the key within the client cert secret
+	// holding the private key
+	PulsarGatewayClientKeyKey = corev1.TLSPrivateKeyKey
+)
+
 var (
 	_ apis.Resource = (*PulsarGateway)(nil)
 )
@@ -43,6 +68,89 @@ type PulsarGatewaySpec struct {
 
 	// ServiceURL is the Pulsar URL to connect to, in the form pulsar://host:port[,host2:port2].
 	ServiceURL string `json:"serviceURL"`
+
+	// Tenant is a template for the Pulsar tenant streams are provisioned in,
+	// defaults to `public`. The template is rendered with the namespace and
+	// name of this gateway, for example `{{ .Namespace }}` maps each riff
+	// namespace to a Pulsar tenant of the same name.
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
+
+	// Namespace is a template for the Pulsar namespace, within the tenant,
+	// streams are provisioned in, defaults to `default`. The template is
+	// rendered like the tenant template.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// TokenSecretRef references a Secret in this namespace holding the token
+	// to authenticate to Pulsar with under the `token` key.
+	// +optional
+	TokenSecretRef *corev1.LocalObjectReference `json:"tokenSecretRef,omitempty"`
+
+	// TLS configures TLS for connections to Pulsar. Use a `pulsar+ssl://`
+	// service URL to encrypt connections.
+	// +optional
+	TLS *PulsarTLS `json:"tls,omitempty"`
+
+	// GatewayDeploymentSpec tunes the pods of the gateway.
+	GatewayDeploymentSpec `json:",inline"`
+}
+
+// PulsarTLS configures TLS for connections to Pulsar
+type PulsarTLS struct {
+	// CASecretRef references a Secret in this namespace holding the PEM
+	// encoded CA bundle to verify Pulsar with under the `ca.crt` key.
+	// +optional
+	CASecretRef *corev1.LocalObjectReference `json:"caSecretRef,omitempty"`
+
+	// ClientCertSecretRef references a Secret in this namespace holding the
+	// PEM encoded client certificate and key to authenticate to Pulsar with
+	// under the `tls.crt` and `tls.key` keys. Client certificates may not be
+	// combined with token authentication.
+	// +optional
+	ClientCertSecretRef *corev1.LocalObjectReference `json:"clientCertSecretRef,omitempty"`
+}
+
+// PulsarNamespaceTemplateData is the data tenant and namespace templates are
+// rendered with
+type PulsarNamespaceTemplateData struct {
+	// Namespace of the gateway
+	Namespace string
+	// Name of the gateway
+	Name string
+}
+
+// PulsarNamespace resolves the Pulsar tenant and namespace streams of this
+// gateway are provisioned in
+func (r *PulsarGateway) PulsarNamespace() (string, string, error) {
+	data := PulsarNamespaceTemplateData{
+		Namespace: r.Namespace,
+		Name:      r.Name,
+	}
+	tenant, err := renderPulsarNamespaceTemplate(r.Spec.Tenant, PulsarGatewayDefaultTenant, data)
+	if err != nil {
+		return "", "", err
+	}
+	namespace, err := renderPulsarNamespaceTemplate(r.Spec.Namespace, PulsarGatewayDefaultNamespace, data)
+	if err != nil {
+		return "", "", err
+	}
+	return tenant, namespace, nil
+}
+
+func renderPulsarNamespaceTemplate(text, defaultValue string, data PulsarNamespaceTemplateData) (string, error) {
+	if text == "" {
+		return defaultValue, nil
+	}
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
 }
 
 // PulsarGatewayStatus defines the observed state of PulsarGateway
@@ -55,6 +163,10 @@ type PulsarGatewayStatus struct {
 	GatewayRef       *refs.TypedLocalObjectReference `json:"gatewayRef,omitempty"`
 	GatewayImage     string                          `json:"gatewayImage,omitempty"`
 	ProvisionerImage string                          `json:"provisionerImage,omitempty"`
+
+	// PublicAddress is the address producers outside the cluster reach the
+	// gateway at, once assigned
+	PublicAddress *apis.Addressable `json:"publicAddress,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -73,5 +73,43 @@ func (s *PulsarGatewaySpec) Validate() validation.FieldErrors {
 		})
 	}
 
+	// render templates with placeholder values to catch errors early
+	data := PulsarNamespaceTemplateData{Namespace: "namespace", Name: "name"}
+	if s.Tenant != "" {
+		if tenant, err := renderPulsarNamespaceTemplate(s.Tenant, "", data); err != nil || tenant == "" {
+			errs = errs.Also(validation.ErrInvalidValue(s.Tenant, "tenant"))
+		}
+	}
+	if s.Namespace != "" {
+		if namespace, err := renderPulsarNamespaceTemplate(s.Namespace, "", data); err != nil || namespace == "" {
+			errs = errs.Also(validation.ErrInvalidValue(s.Namespace, "namespace"))
+		}
+	}
+
+	if s.TokenSecretRef != nil && s.TokenSecretRef.Name == "" {
+		errs = errs.Also(validation.ErrMissingField("tokenSecretRef.name"))
+	}
+	if s.TLS != nil {
+		errs = errs.Also(s.TLS.Validate().ViaField("tls"))
+		if s.TokenSecretRef != nil && s.TLS.ClientCertSecretRef != nil {
+			errs = errs.Also(validation.ErrMultipleOneOf("tokenSecretRef", "tls.clientCertSecretRef"))
+		}
+	}
+
+	errs = errs.Also(s.GatewayDeploymentSpec.Validate())
+
+	return errs
+}
+
+func (s *PulsarTLS) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if s.CASecretRef != nil && s.CASecretRef.Name == "" {
+		errs = errs.Also(validation.ErrMissingField("caSecretRef.name"))
+	}
+	if s.ClientCertSecretRef != nil && s.ClientCertSecretRef.Name == "" {
+		errs = errs.Also(validation.ErrMissingField("clientCertSecretRef.name"))
+	}
+
 	return errs
 }
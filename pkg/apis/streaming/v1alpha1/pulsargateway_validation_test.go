@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/projectriff/system/pkg/validation"
 )
@@ -80,6 +81,70 @@ func TestValidatePulsarGatewaySpec(t *testing.T) {
 			ServiceURL: "localhost:6650",
 		},
 		expected: validation.FieldErrors{field.Invalid(field.NewPath("serviceURL"), "localhost:6650", "serviceURL must use 'pulsar://' or 'pulsar+ssl://' scheme")},
+	}, {
+		name: "tenant and namespace templates",
+		target: &PulsarGatewaySpec{
+			ServiceURL: "pulsar://localhost:6650",
+			Tenant:     "riff",
+			Namespace:  "{{ .Namespace }}",
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "invalid tenant template",
+		target: &PulsarGatewaySpec{
+			ServiceURL: "pulsar://localhost:6650",
+			Tenant:     "{{ .Namespace",
+		},
+		expected: validation.ErrInvalidValue("{{ .Namespace", "tenant"),
+	}, {
+		name: "invalid namespace template",
+		target: &PulsarGatewaySpec{
+			ServiceURL: "pulsar://localhost:6650",
+			Namespace:  "{{ .Stream }}",
+		},
+		expected: validation.ErrInvalidValue("{{ .Stream }}", "namespace"),
+	}, {
+		name: "token and tls",
+		target: &PulsarGatewaySpec{
+			ServiceURL:     "pulsar+ssl://localhost:6651",
+			TokenSecretRef: &corev1.LocalObjectReference{Name: "pulsar-token"},
+			TLS: &PulsarTLS{
+				CASecretRef: &corev1.LocalObjectReference{Name: "pulsar-ca"},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "client cert",
+		target: &PulsarGatewaySpec{
+			ServiceURL: "pulsar+ssl://localhost:6651",
+			TLS: &PulsarTLS{
+				ClientCertSecretRef: &corev1.LocalObjectReference{Name: "pulsar-client"},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "empty secret refs",
+		target: &PulsarGatewaySpec{
+			ServiceURL:     "pulsar+ssl://localhost:6651",
+			TokenSecretRef: &corev1.LocalObjectReference{},
+			TLS: &PulsarTLS{
+				CASecretRef: &corev1.LocalObjectReference{},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMissingField("tokenSecretRef.name"),
+			validation.ErrMissingField("tls.caSecretRef.name"),
+		),
+	}, {
+		name: "token and client cert",
+		target: &PulsarGatewaySpec{
+			ServiceURL:     "pulsar+ssl://localhost:6651",
+			TokenSecretRef: &corev1.LocalObjectReference{Name: "pulsar-token"},
+			TLS: &PulsarTLS{
+				ClientCertSecretRef: &corev1.LocalObjectReference{Name: "pulsar-client"},
+			},
+		},
+		expected: validation.ErrMultipleOneOf("tokenSecretRef", "tls.clientCertSecretRef"),
 	}} {
 		t.Run(c.name, func(t *testing.T) {
 			actual := c.target.Validate()
@@ -0,0 +1,71 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/projectriff/system/pkg/apis"
+)
+
+const (
+	RedisGatewayConditionReady                           = apis.ConditionReady
+	RedisGatewayConditionGatewayReady apis.ConditionType = "GatewayReady"
+)
+
+var redisGatewayCondSet = apis.NewLivingConditionSet(
+	RedisGatewayConditionGatewayReady,
+)
+
+func (s *RedisGatewayStatus) GetObservedGeneration() int64 {
+	return s.ObservedGeneration
+}
+
+func (s *RedisGatewayStatus) IsReady() bool {
+	return redisGatewayCondSet.Manage(s).IsHappy()
+}
+
+func (*RedisGatewayStatus) GetReadyConditionType() apis.ConditionType {
+	return RedisGatewayConditionReady
+}
+
+func (s *RedisGatewayStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return redisGatewayCondSet.Manage(s).GetCondition(t)
+}
+
+func (s *RedisGatewayStatus) InitializeConditions() {
+	redisGatewayCondSet.Manage(s).InitializeConditions()
+}
+
+func (s *RedisGatewayStatus) PropagateGatewayStatus(gs *GatewayStatus) {
+	sc := gs.GetCondition(GatewayConditionReady)
+	if sc == nil {
+		return
+	}
+	switch {
+	case sc.Status == corev1.ConditionUnknown:
+		redisGatewayCondSet.Manage(s).MarkUnknown(RedisGatewayConditionGatewayReady, sc.Reason, sc.Message)
+	case sc.Status == corev1.ConditionTrue:
+		redisGatewayCondSet.Manage(s).MarkTrue(RedisGatewayConditionGatewayReady)
+	case sc.Status == corev1.ConditionFalse:
+		redisGatewayCondSet.Manage(s).MarkFalse(RedisGatewayConditionGatewayReady, sc.Reason, sc.Message)
+	}
+}
+
+func (s *RedisGatewayStatus) MarkGatewayNotOwned(name string) {
+	redisGatewayCondSet.Manage(s).MarkFalse(RedisGatewayConditionGatewayReady, "NotOwned", "There is an existing Gateway %q that the RedisGateway does not own.", name)
+}
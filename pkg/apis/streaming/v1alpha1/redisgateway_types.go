@@ -0,0 +1,115 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/projectriff/system/pkg/apis"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+var (
+	RedisGatewayLabelKey = GroupVersion.Group + "/redis-gateway"
+	RedisGatewayType     = "redis"
+)
+
+var (
+	_ apis.Resource = (*RedisGateway)(nil)
+)
+
+// RedisGatewaySpec defines the desired state of RedisGateway
+type RedisGatewaySpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// Address of the Redis server to store records and positions in, as a
+	// `host:port` pair, for example `redis.redis.svc.cluster.local:6379`.
+	Address string `json:"address"`
+
+	// PasswordSecretRef optionally selects a key of a Secret in this namespace
+	// holding the password to authenticate to Redis with.
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// TLS enables TLS for connections to the Redis server.
+	// +optional
+	TLS bool `json:"tls,omitempty"`
+
+	// GatewayDeploymentSpec tunes the pods of the gateway.
+	GatewayDeploymentSpec `json:",inline"`
+}
+
+// RedisGatewayStatus defines the observed state of RedisGateway
+type RedisGatewayStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	apis.Status      `json:",inline"`
+	Address          *apis.Addressable               `json:"address,omitempty"`
+	GatewayRef       *refs.TypedLocalObjectReference `json:"gatewayRef,omitempty"`
+	GatewayImage     string                          `json:"gatewayImage,omitempty"`
+	ProvisionerImage string                          `json:"provisionerImage,omitempty"`
+
+	// PublicAddress is the address producers outside the cluster reach the
+	// gateway at, once assigned
+	PublicAddress *apis.Addressable `json:"publicAddress,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="riff"
+// +kubebuilder:printcolumn:name="Address",type=string,JSONPath=`.spec.address`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+
+// RedisGateway is the Schema for the gateways API
+type RedisGateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RedisGatewaySpec   `json:"spec,omitempty"`
+	Status RedisGatewayStatus `json:"status,omitempty"`
+}
+
+func (*RedisGateway) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("RedisGateway")
+}
+
+func (p *RedisGateway) GetStatus() apis.ResourceStatus {
+	return &p.Status
+}
+
+// +kubebuilder:object:root=true
+
+// RedisGatewayList contains a list of RedisGateway
+type RedisGatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisGateway `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RedisGateway{}, &RedisGatewayList{})
+}
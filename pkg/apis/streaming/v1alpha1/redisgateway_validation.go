@@ -0,0 +1,88 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"net"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+// +kubebuilder:webhook:path=/validate-streaming-projectriff-io-v1alpha1-redisgateway,mutating=false,failurePolicy=fail,groups=streaming.projectriff.io,resources=redisgateways,verbs=create;update,versions=v1alpha1,name=redisgateways.streaming.projectriff.io
+
+var (
+	_ webhook.Validator         = &RedisGateway{}
+	_ validation.FieldValidator = &RedisGateway{}
+)
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *RedisGateway) ValidateCreate() error {
+	return r.Validate().ToAggregate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *RedisGateway) ValidateUpdate(old runtime.Object) error {
+	// TODO check for immutable fields
+	return r.Validate().ToAggregate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *RedisGateway) ValidateDelete() error {
+	return nil
+}
+
+func (r *RedisGateway) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	errs = errs.Also(r.Spec.Validate().ViaField("spec"))
+
+	return errs
+}
+
+func (s *RedisGatewaySpec) Validate() validation.FieldErrors {
+	if equality.Semantic.DeepEqual(s, &RedisGatewaySpec{}) {
+		return validation.ErrMissingField(validation.CurrentField)
+	}
+
+	errs := validation.FieldErrors{}
+
+	if s.Address == "" {
+		errs = errs.Also(validation.ErrMissingField("address"))
+	} else if _, _, err := net.SplitHostPort(s.Address); err != nil {
+		errs = errs.Also(validation.ErrInvalidValue(s.Address, "address"))
+	}
+
+	if s.PasswordSecretRef != nil {
+		if s.PasswordSecretRef.Name == "" {
+			errs = errs.Also(validation.ErrMissingField("passwordSecretRef.name"))
+		}
+		if s.PasswordSecretRef.Key == "" {
+			errs = errs.Also(validation.ErrMissingField("passwordSecretRef.key"))
+		}
+	}
+
+	if s.PositionsStore != nil {
+		errs = errs.Also(validation.ErrDisallowedFields("positionsStore", "positions are stored in the Redis server of the gateway"))
+	}
+	errs = errs.Also(s.GatewayDeploymentSpec.validate(true))
+
+	return errs
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+func TestValidateRedisGateway(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *RedisGateway
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &RedisGateway{},
+		expected: validation.ErrMissingField("spec"),
+	}, {
+		name: "valid",
+		target: &RedisGateway{
+			Spec: RedisGatewaySpec{
+				Address: "redis:6379",
+			},
+		},
+		expected: validation.FieldErrors{},
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateRedisGateway(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateRedisGatewaySpec(t *testing.T) {
+	three := int32(3)
+
+	for _, c := range []struct {
+		name     string
+		target   *RedisGatewaySpec
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &RedisGatewaySpec{},
+		expected: validation.ErrMissingField(validation.CurrentField),
+	}, {
+		name: "valid",
+		target: &RedisGatewaySpec{
+			Address: "redis:6379",
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "with tls",
+		target: &RedisGatewaySpec{
+			Address: "redis:6379",
+			TLS:     true,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "missing address",
+		target: &RedisGatewaySpec{
+			TLS: true,
+		},
+		expected: validation.ErrMissingField("address"),
+	}, {
+		name: "invalid address",
+		target: &RedisGatewaySpec{
+			Address: "redis",
+		},
+		expected: validation.ErrInvalidValue("redis", "address"),
+	}, {
+		name: "with password",
+		target: &RedisGatewaySpec{
+			Address: "redis:6379",
+			PasswordSecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "redis"},
+				Key:                  "password",
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "empty password secret ref",
+		target: &RedisGatewaySpec{
+			Address:           "redis:6379",
+			PasswordSecretRef: &corev1.SecretKeySelector{},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrMissingField("passwordSecretRef.name"),
+			validation.ErrMissingField("passwordSecretRef.key"),
+		),
+	}, {
+		name: "replicas",
+		target: &RedisGatewaySpec{
+			Address: "redis:6379",
+			GatewayDeploymentSpec: GatewayDeploymentSpec{
+				Replicas: &three,
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "positions store",
+		target: &RedisGatewaySpec{
+			Address: "redis:6379",
+			GatewayDeploymentSpec: GatewayDeploymentSpec{
+				PositionsStore: &GatewayPositionsStore{
+					Redis: &RedisPositionsStore{
+						Address: "redis:6379",
+					},
+				},
+			},
+		},
+		expected: validation.ErrDisallowedFields("positionsStore", "positions are stored in the Redis server of the gateway"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateRedisGatewaySpec(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
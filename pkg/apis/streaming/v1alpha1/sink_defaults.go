@@ -0,0 +1,52 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/mutate-streaming-projectriff-io-v1alpha1-sink,mutating=true,failurePolicy=fail,groups=streaming.projectriff.io,resources=sinks,verbs=create;update,versions=v1alpha1,name=sinks.streaming.projectriff.io
+
+var _ webhook.Defaulter = &Sink{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *Sink) Default() {
+	r.Spec.Default()
+}
+
+func (s *SinkSpec) Default() {
+	if s.Retry == nil {
+		s.Retry = &SinkRetry{}
+	}
+	s.Retry.Default()
+}
+
+func (r *SinkRetry) Default() {
+	if r.MaxAttempts == 0 {
+		r.MaxAttempts = 3
+	}
+	if r.InitialBackoff == nil {
+		r.InitialBackoff = &metav1.Duration{Duration: time.Second}
+	}
+	if r.MaxBackoff == nil {
+		r.MaxBackoff = &metav1.Duration{Duration: 30 * time.Second}
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSinkDefault(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *Sink
+		want *Sink
+	}{{
+		name: "empty",
+		in:   &Sink{},
+		want: &Sink{
+			Spec: SinkSpec{
+				Retry: &SinkRetry{
+					MaxAttempts:    3,
+					InitialBackoff: &metav1.Duration{Duration: time.Second},
+					MaxBackoff:     &metav1.Duration{Duration: 30 * time.Second},
+				},
+			},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.in
+			got.Default()
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Default (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestSinkRetryDefault(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *SinkRetry
+		want *SinkRetry
+	}{{
+		name: "retry is defaulted",
+		in:   &SinkRetry{},
+		want: &SinkRetry{
+			MaxAttempts:    3,
+			InitialBackoff: &metav1.Duration{Duration: time.Second},
+			MaxBackoff:     &metav1.Duration{Duration: 30 * time.Second},
+		},
+	}, {
+		name: "retry is not overwritten",
+		in: &SinkRetry{
+			MaxAttempts:    1,
+			InitialBackoff: &metav1.Duration{Duration: 5 * time.Second},
+			MaxBackoff:     &metav1.Duration{Duration: time.Minute},
+		},
+		want: &SinkRetry{
+			MaxAttempts:    1,
+			InitialBackoff: &metav1.Duration{Duration: 5 * time.Second},
+			MaxBackoff:     &metav1.Duration{Duration: time.Minute},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.in
+			got.Default()
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Default (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/projectriff/system/pkg/apis"
+)
+
+const (
+	SinkConditionReady                              = apis.ConditionReady
+	SinkConditionStreamsReady    apis.ConditionType = "StreamsReady"
+	SinkConditionTargetReady     apis.ConditionType = "TargetReady"
+	SinkConditionDeploymentReady apis.ConditionType = "DeploymentReady"
+)
+
+var sinkCondSet = apis.NewLivingConditionSet(
+	SinkConditionStreamsReady,
+	SinkConditionTargetReady,
+	SinkConditionDeploymentReady,
+)
+
+func (ss *SinkStatus) GetObservedGeneration() int64 {
+	return ss.ObservedGeneration
+}
+
+func (ss *SinkStatus) IsReady() bool {
+	return sinkCondSet.Manage(ss).IsHappy()
+}
+
+func (*SinkStatus) GetReadyConditionType() apis.ConditionType {
+	return SinkConditionReady
+}
+
+func (ss *SinkStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return sinkCondSet.Manage(ss).GetCondition(t)
+}
+
+func (ss *SinkStatus) InitializeConditions() {
+	sinkCondSet.Manage(ss).InitializeConditions()
+}
+
+func (ss *SinkStatus) MarkStreamsReady() {
+	sinkCondSet.Manage(ss).MarkTrue(SinkConditionStreamsReady)
+}
+
+func (ss *SinkStatus) MarkStreamsNotReady(message string) {
+	sinkCondSet.Manage(ss).MarkFalse(SinkConditionStreamsReady, "StreamNotReady", message)
+}
+
+func (ss *SinkStatus) MarkTargetReady() {
+	sinkCondSet.Manage(ss).MarkTrue(SinkConditionTargetReady)
+}
+
+func (ss *SinkStatus) MarkTargetNotReady(message string) {
+	sinkCondSet.Manage(ss).MarkFalse(SinkConditionTargetReady, "TargetNotReady", message)
+}
+
+func (ss *SinkStatus) PropagateDeploymentStatus(ds *appsv1.DeploymentStatus) {
+	var available, progressing *appsv1.DeploymentCondition
+	for i := range ds.Conditions {
+		switch ds.Conditions[i].Type {
+		case appsv1.DeploymentAvailable:
+			available = &ds.Conditions[i]
+		case appsv1.DeploymentProgressing:
+			progressing = &ds.Conditions[i]
+		}
+	}
+	if available == nil || progressing == nil {
+		return
+	}
+	if progressing.Status == corev1.ConditionTrue && available.Status == corev1.ConditionFalse {
+		// DeploymentAvailable is False while progressing, avoid reporting SinkConditionReady as False
+		sinkCondSet.Manage(ss).MarkUnknown(SinkConditionDeploymentReady, progressing.Reason, progressing.Message)
+		return
+	}
+	switch {
+	case available.Status == corev1.ConditionUnknown:
+		sinkCondSet.Manage(ss).MarkUnknown(SinkConditionDeploymentReady, available.Reason, available.Message)
+	case available.Status == corev1.ConditionTrue:
+		sinkCondSet.Manage(ss).MarkTrue(SinkConditionDeploymentReady)
+	case available.Status == corev1.ConditionFalse:
+		sinkCondSet.Manage(ss).MarkFalse(SinkConditionDeploymentReady, available.Reason, available.Message)
+	}
+}
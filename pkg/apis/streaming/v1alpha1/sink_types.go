@@ -0,0 +1,140 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/projectriff/system/pkg/apis"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+var (
+	SinkLabelKey = GroupVersion.Group + "/sink"
+)
+
+var (
+	_ apis.Resource = (*Sink)(nil)
+)
+
+// SinkSpec defines the desired state of Sink
+type SinkSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// Input is the name of the stream in this namespace messages are consumed
+	// from.
+	Input string `json:"input"`
+
+	// Target each message is delivered to with an HTTP POST.
+	Target SinkTarget `json:"target"`
+
+	// Retry controls the redelivery of messages the target fails to accept.
+	// +optional
+	Retry *SinkRetry `json:"retry,omitempty"`
+
+	// DeadLetter is the name of a stream in this namespace messages are
+	// published to once delivery is no longer retried. Undeliverable messages
+	// are dropped when not set.
+	// +optional
+	DeadLetter string `json:"deadLetter,omitempty"`
+}
+
+// SinkTarget resolves the URL messages are delivered to. Only one of the
+// following targets may be specified.
+type SinkTarget struct {
+	// URL messages are delivered to.
+	URL string `json:"url,omitempty"`
+
+	// CoreDeployerRef references a core.projectriff.io Deployer in this
+	// namespace, messages are delivered to the address of the deployer.
+	CoreDeployerRef string `json:"coreDeployerRef,omitempty"`
+
+	// KnativeDeployerRef references a knative.projectriff.io Deployer in
+	// this namespace, messages are delivered to the address of the deployer.
+	KnativeDeployerRef string `json:"knativeDeployerRef,omitempty"`
+}
+
+type SinkRetry struct {
+	// MaxAttempts is the number of times delivery of a message is attempted,
+	// including the first attempt.
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+
+	// InitialBackoff is the delay before the first retry, the delay doubles
+	// for each subsequent retry.
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+
+	// MaxBackoff caps the delay between retries.
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// SinkStatus defines the observed state of Sink
+type SinkStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	apis.Status `json:",inline"`
+
+	// TargetURL is the resolved URL messages are delivered to
+	TargetURL string `json:"targetURL,omitempty"`
+
+	DeploymentRef *refs.TypedLocalObjectReference `json:"deploymentRef,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="riff"
+// +kubebuilder:printcolumn:name="Input",type=string,JSONPath=`.spec.input`
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.status.targetURL`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+
+// Sink is the Schema for the sinks API
+type Sink struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SinkSpec   `json:"spec,omitempty"`
+	Status SinkStatus `json:"status,omitempty"`
+}
+
+func (*Sink) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("Sink")
+}
+
+func (s *Sink) GetStatus() apis.ResourceStatus {
+	return &s.Status
+}
+
+// +kubebuilder:object:root=true
+
+// SinkList contains a list of Sink
+type SinkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Sink `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Sink{}, &SinkList{})
+}
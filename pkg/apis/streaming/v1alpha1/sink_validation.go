@@ -0,0 +1,135 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"net/url"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+// +kubebuilder:webhook:path=/validate-streaming-projectriff-io-v1alpha1-sink,mutating=false,failurePolicy=fail,groups=streaming.projectriff.io,resources=sinks,verbs=create;update,versions=v1alpha1,name=sinks.streaming.projectriff.io
+
+var (
+	_ webhook.Validator         = &Sink{}
+	_ validation.FieldValidator = &Sink{}
+)
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Sink) ValidateCreate() error {
+	return r.Validate().ToAggregate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Sink) ValidateUpdate(old runtime.Object) error {
+	// TODO check for immutable fields
+	return r.Validate().ToAggregate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Sink) ValidateDelete() error {
+	return nil
+}
+
+func (r *Sink) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	errs = errs.Also(r.Spec.Validate().ViaField("spec"))
+
+	return errs
+}
+
+func (s *SinkSpec) Validate() validation.FieldErrors {
+	if equality.Semantic.DeepEqual(s, &SinkSpec{}) {
+		return validation.ErrMissingField(validation.CurrentField)
+	}
+
+	errs := validation.FieldErrors{}
+
+	if s.Input == "" {
+		errs = errs.Also(validation.ErrMissingField("input"))
+	}
+
+	errs = errs.Also(s.Target.Validate().ViaField("target"))
+
+	if s.Retry != nil {
+		errs = errs.Also(s.Retry.Validate().ViaField("retry"))
+	}
+
+	if s.DeadLetter != "" && s.DeadLetter == s.Input {
+		errs = errs.Also(validation.ErrInvalidValue(s.DeadLetter, "deadLetter"))
+	}
+
+	return errs
+}
+
+func (t *SinkTarget) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+	used := []string{}
+	unused := []string{}
+
+	if t.URL != "" {
+		used = append(used, "url")
+		if u, err := url.Parse(t.URL); err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") {
+			errs = errs.Also(validation.ErrInvalidValue(t.URL, "url"))
+		}
+	} else {
+		unused = append(unused, "url")
+	}
+
+	if t.CoreDeployerRef != "" {
+		used = append(used, "coreDeployerRef")
+	} else {
+		unused = append(unused, "coreDeployerRef")
+	}
+
+	if t.KnativeDeployerRef != "" {
+		used = append(used, "knativeDeployerRef")
+	} else {
+		unused = append(unused, "knativeDeployerRef")
+	}
+
+	if len(used) == 0 {
+		errs = errs.Also(validation.ErrMissingOneOf(unused...))
+	} else if len(used) > 1 {
+		errs = errs.Also(validation.ErrMultipleOneOf(used...))
+	}
+
+	return errs
+}
+
+func (r *SinkRetry) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if r.MaxAttempts < 0 {
+		errs = errs.Also(validation.ErrInvalidValue(r.MaxAttempts, "maxAttempts"))
+	}
+	if r.InitialBackoff != nil && r.InitialBackoff.Duration <= 0 {
+		errs = errs.Also(validation.ErrInvalidValue(r.InitialBackoff.Duration.String(), "initialBackoff"))
+	}
+	if r.MaxBackoff != nil && r.MaxBackoff.Duration <= 0 {
+		errs = errs.Also(validation.ErrInvalidValue(r.MaxBackoff.Duration.String(), "maxBackoff"))
+	} else if r.MaxBackoff != nil && r.InitialBackoff != nil && r.MaxBackoff.Duration < r.InitialBackoff.Duration {
+		errs = errs.Also(validation.ErrInvalidValue(r.MaxBackoff.Duration.String(), "maxBackoff"))
+	}
+
+	return errs
+}
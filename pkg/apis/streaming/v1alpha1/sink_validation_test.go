@@ -0,0 +1,201 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+func TestValidateSink(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *Sink
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &Sink{},
+		expected: validation.ErrMissingField("spec"),
+	}, {
+		name: "valid",
+		target: &Sink{
+			Spec: SinkSpec{
+				Input: "my-stream",
+				Target: SinkTarget{
+					URL: "http://example.com/",
+				},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateSink(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateSinkSpec(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *SinkSpec
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &SinkSpec{},
+		expected: validation.ErrMissingField(validation.CurrentField),
+	}, {
+		name: "valid, url",
+		target: &SinkSpec{
+			Input: "my-stream",
+			Target: SinkTarget{
+				URL: "https://example.com/messages",
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid, core deployer",
+		target: &SinkSpec{
+			Input: "my-stream",
+			Target: SinkTarget{
+				CoreDeployerRef: "my-deployer",
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid, knative deployer",
+		target: &SinkSpec{
+			Input: "my-stream",
+			Target: SinkTarget{
+				KnativeDeployerRef: "my-deployer",
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "valid, dead letter",
+		target: &SinkSpec{
+			Input: "my-stream",
+			Target: SinkTarget{
+				URL: "http://example.com/",
+			},
+			DeadLetter: "my-dead-letters",
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "missing input",
+		target: &SinkSpec{
+			Target: SinkTarget{
+				URL: "http://example.com/",
+			},
+		},
+		expected: validation.ErrMissingField("input"),
+	}, {
+		name: "missing target",
+		target: &SinkSpec{
+			Input: "my-stream",
+		},
+		expected: validation.ErrMissingOneOf("url", "coreDeployerRef", "knativeDeployerRef").ViaField("target"),
+	}, {
+		name: "multiple targets",
+		target: &SinkSpec{
+			Input: "my-stream",
+			Target: SinkTarget{
+				URL:             "http://example.com/",
+				CoreDeployerRef: "my-deployer",
+			},
+		},
+		expected: validation.ErrMultipleOneOf("url", "coreDeployerRef").ViaField("target"),
+	}, {
+		name: "invalid url",
+		target: &SinkSpec{
+			Input: "my-stream",
+			Target: SinkTarget{
+				URL: "example.com/",
+			},
+		},
+		expected: validation.ErrInvalidValue("example.com/", "target.url"),
+	}, {
+		name: "dead letter to input",
+		target: &SinkSpec{
+			Input: "my-stream",
+			Target: SinkTarget{
+				URL: "http://example.com/",
+			},
+			DeadLetter: "my-stream",
+		},
+		expected: validation.ErrInvalidValue("my-stream", "deadLetter"),
+	}, {
+		name: "valid retry",
+		target: &SinkSpec{
+			Input: "my-stream",
+			Target: SinkTarget{
+				URL: "http://example.com/",
+			},
+			Retry: &SinkRetry{
+				MaxAttempts:    5,
+				InitialBackoff: &metav1.Duration{Duration: time.Second},
+				MaxBackoff:     &metav1.Duration{Duration: time.Minute},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "invalid retry",
+		target: &SinkSpec{
+			Input: "my-stream",
+			Target: SinkTarget{
+				URL: "http://example.com/",
+			},
+			Retry: &SinkRetry{
+				MaxAttempts:    -1,
+				InitialBackoff: &metav1.Duration{Duration: -time.Second},
+				MaxBackoff:     &metav1.Duration{Duration: 0},
+			},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidValue(int32(-1), "retry.maxAttempts"),
+			validation.ErrInvalidValue("-1s", "retry.initialBackoff"),
+			validation.ErrInvalidValue("0s", "retry.maxBackoff"),
+		),
+	}, {
+		name: "max backoff less than initial backoff",
+		target: &SinkSpec{
+			Input: "my-stream",
+			Target: SinkTarget{
+				URL: "http://example.com/",
+			},
+			Retry: &SinkRetry{
+				InitialBackoff: &metav1.Duration{Duration: time.Minute},
+				MaxBackoff:     &metav1.Duration{Duration: time.Second},
+			},
+		},
+		expected: validation.ErrInvalidValue("1s", "retry.maxBackoff"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateSinkSpec(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
@@ -33,4 +33,14 @@ func (s *StreamSpec) Default() {
 	if s.ContentType == "" {
 		s.ContentType = "application/octet-stream"
 	}
+	if s.Migration != nil {
+		s.Migration.Default()
+	}
+}
+
+func (s *StreamMigrationPolicy) Default() {
+	if s.Drain == nil {
+		drain := true
+		s.Drain = &drain
+	}
 }
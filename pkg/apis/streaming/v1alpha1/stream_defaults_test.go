@@ -49,6 +49,9 @@ func TestStreamDefault(t *testing.T) {
 }
 
 func TestStreamSpecDefault(t *testing.T) {
+	drain := true
+	noDrain := false
+
 	tests := []struct {
 		name string
 		in   *StreamSpec
@@ -67,6 +70,32 @@ func TestStreamSpecDefault(t *testing.T) {
 		want: &StreamSpec{
 			ContentType: "application/x-doom",
 		},
+	}, {
+		name: "migration drain is defaulted",
+		in: &StreamSpec{
+			Migration: &StreamMigrationPolicy{},
+		},
+		want: &StreamSpec{
+			ContentType: "application/octet-stream",
+			Migration: &StreamMigrationPolicy{
+				Drain: &drain,
+			},
+		},
+	}, {
+		name: "migration drain is not overwritten",
+		in: &StreamSpec{
+			Migration: &StreamMigrationPolicy{
+				CopyData: true,
+				Drain:    &noDrain,
+			},
+		},
+		want: &StreamSpec{
+			ContentType: "application/octet-stream",
+			Migration: &StreamMigrationPolicy{
+				CopyData: true,
+				Drain:    &noDrain,
+			},
+		},
 	}}
 
 	for _, test := range tests {
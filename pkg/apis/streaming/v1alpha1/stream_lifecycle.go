@@ -17,6 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/projectriff/system/pkg/apis"
 )
 
@@ -24,6 +28,9 @@ const (
 	StreamConditionReady                                = apis.ConditionReady
 	StreamConditionResourceAvailable apis.ConditionType = "ResourceAvailable"
 	StreamConditionBindingReady      apis.ConditionType = "BindingReady"
+	// StreamConditionPartitionsApplied is informational, it does not affect
+	// the stream's readiness
+	StreamConditionPartitionsApplied apis.ConditionType = "PartitionsApplied"
 )
 
 var streamCondSet = apis.NewLivingConditionSet(
@@ -66,3 +73,33 @@ func (ss *StreamStatus) MarkBindingReady() {
 func (ss *StreamStatus) MarkBindingNotReady(message string, a ...interface{}) {
 	streamCondSet.Manage(ss).MarkFalse(StreamConditionBindingReady, "BindingFailed", message, a...)
 }
+
+// PropagatePartitions reports the partitions of the topic reported by the
+// gateway's provisioner, zero when not reported, against the desired
+// partitions.
+func (ss *StreamStatus) PropagatePartitions(desired *int32, reported int32) {
+	ss.Partitions = nil
+	if reported != 0 {
+		ss.Partitions = &reported
+	}
+	if desired == nil {
+		_ = streamCondSet.Manage(ss).ClearCondition(StreamConditionPartitionsApplied)
+		return
+	}
+	condition := apis.Condition{
+		Type:     StreamConditionPartitionsApplied,
+		Status:   corev1.ConditionTrue,
+		Severity: apis.ConditionSeverityInfo,
+	}
+	switch {
+	case reported == 0:
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "PartitionsNotReported"
+		condition.Message = fmt.Sprintf("gateway did not report the partitions of the topic, %d partitions may not be applied", *desired)
+	case reported != *desired:
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "PartitionsNotApplied"
+		condition.Message = fmt.Sprintf("topic has %d partitions, %d requested", reported, *desired)
+	}
+	streamCondSet.Manage(ss).SetCondition(condition)
+}
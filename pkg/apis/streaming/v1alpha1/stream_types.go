@@ -22,13 +22,24 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	apis "github.com/projectriff/system/pkg/apis"
+	"github.com/projectriff/system/pkg/refs"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 var (
-	StreamLabelKey = GroupVersion.Group + "/stream"
+	StreamLabelKey              = GroupVersion.Group + "/stream"
+	StreamMigrationCopyLabelKey = GroupVersion.Group + "/migration-copy"
+)
+
+const (
+	// StreamMigrationCopyInitial labels the job copying the messages on the
+	// previous gateway when the migration starts
+	StreamMigrationCopyInitial = "initial"
+	// StreamMigrationCopyFinal labels the job copying the messages written to
+	// the previous gateway while the stream migrated
+	StreamMigrationCopyFinal = "final"
 )
 
 var (
@@ -42,8 +53,57 @@ type StreamSpec struct {
 
 	Gateway     corev1.LocalObjectReference `json:"gateway"`
 	ContentType string                      `json:"contentType"`
+
+	// Partitions of the stream's topic on gateways that partition topics,
+	// counted against the namespace's stream quota. A stream has a single
+	// partition when not set.
+	// +optional
+	Partitions *int32 `json:"partitions,omitempty"`
+
+	// Migration controls how the stream moves to a new gateway when the
+	// gateway is changed
+	// +optional
+	Migration *StreamMigrationPolicy `json:"migration,omitempty"`
 }
 
+// StreamMigrationPolicy controls the migration of a stream between gateways.
+// The stream remains bound to the previous gateway until the migration
+// completes, changing the gateway back before then cancels the migration.
+type StreamMigrationPolicy struct {
+	// CopyData copies the messages on the previous gateway to the new gateway
+	// before the stream is repointed. Messages written while the stream
+	// migrates are copied by a final copy resuming from the offset the
+	// initial copy reached. Defaults to false.
+	// +optional
+	CopyData bool `json:"copyData,omitempty"`
+
+	// Drain waits for the processors consuming the stream to catch up with
+	// the messages on the previous gateway before the stream is repointed.
+	// Set to false to repoint a stream whose consumers are unable to drain.
+	// Defaults to true.
+	// +optional
+	Drain *bool `json:"drain,omitempty"`
+}
+
+// StreamPhase is the stage of the stream's lifecycle
+type StreamPhase string
+
+const (
+	// StreamPhaseProvisioning the stream is not yet bound to a gateway
+	StreamPhaseProvisioning StreamPhase = "Provisioning"
+	// StreamPhaseActive the stream is bound to the gateway in its spec
+	StreamPhaseActive StreamPhase = "Active"
+	// StreamPhaseMigrating the stream is provisioning on a new gateway
+	StreamPhaseMigrating StreamPhase = "Migrating"
+	// StreamPhaseCopying messages are copied to the new gateway
+	StreamPhaseCopying StreamPhase = "Copying"
+	// StreamPhaseDraining consumers are catching up on the previous gateway
+	StreamPhaseDraining StreamPhase = "Draining"
+	// StreamPhaseFinalizing messages written while the stream migrated are
+	// copied to the new gateway
+	StreamPhaseFinalizing StreamPhase = "Finalizing"
+)
+
 // StreamStatus defines the observed state of Stream
 type StreamStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -52,6 +112,80 @@ type StreamStatus struct {
 	apis.Status `json:",inline"`
 
 	Binding BindingReference `json:"binding,omitempty"`
+
+	// Gateway the binding addresses. Differs from the spec while the stream
+	// migrates to a new gateway.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// Phase of the stream's lifecycle
+	// +optional
+	Phase StreamPhase `json:"phase,omitempty"`
+
+	// Migration is the progress of the stream moving to a new gateway
+	// +optional
+	Migration *StreamMigration `json:"migration,omitempty"`
+
+	// Consumers are the processors reading from this stream. Processors in
+	// another namespace are named `namespace/name`.
+	// +optional
+	Consumers []string `json:"consumers,omitempty"`
+
+	// Producers are the processors writing to this stream. Processors in
+	// another namespace are named `namespace/name`.
+	// +optional
+	Producers []string `json:"producers,omitempty"`
+
+	// Partitions of the topic as reported by the bound gateway's
+	// provisioner. Not set when the provisioner does not report partitions.
+	// +optional
+	Partitions *int32 `json:"partitions,omitempty"`
+
+	// Quotas is the use of the stream quotas in the stream's namespace
+	// +optional
+	Quotas []StreamQuotaUsage `json:"quotas,omitempty"`
+}
+
+type StreamMigration struct {
+	// From is the gateway the stream is bound to
+	From string `json:"from"`
+
+	// To is the gateway the stream is migrating to
+	To string `json:"to"`
+
+	// Generation of the stream that started the migration. The consumer
+	// group copying messages is distinct for each migration.
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+
+	// JobRef references the Job copying messages to the new gateway
+	// +optional
+	JobRef *refs.TypedLocalObjectReference `json:"jobRef,omitempty"`
+
+	// CopyCompletionTime is when the messages finished copying to the new
+	// gateway
+	// +optional
+	CopyCompletionTime *metav1.Time `json:"copyCompletionTime,omitempty"`
+
+	// DrainedTime is when every consumer was first observed to have drained
+	// the previous gateway. The drain is confirmed once the consumers remain
+	// drained for a full lag interval, as queried from the previous gateway.
+	// +optional
+	DrainedTime *metav1.Time `json:"drainedTime,omitempty"`
+
+	// DrainConfirmed is set once the consumers remained drained for a full
+	// lag interval
+	// +optional
+	DrainConfirmed bool `json:"drainConfirmed,omitempty"`
+
+	// FinalCopyCompletionTime is when the messages written to the previous
+	// gateway while the stream migrated finished copying to the new gateway
+	// +optional
+	FinalCopyCompletionTime *metav1.Time `json:"finalCopyCompletionTime,omitempty"`
+
+	// Message describes what the migration is waiting on
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 type BindingReference struct {
@@ -67,6 +201,7 @@ type BindingReference struct {
 // +kubebuilder:resource:categories="riff"
 // +kubebuilder:printcolumn:name="Gateway",type=string,JSONPath=`.spec.gateway.name`
 // +kubebuilder:printcolumn:name="Content Type",type=string,JSONPath=`.spec.contentType`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 // +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
@@ -89,6 +224,14 @@ func (s *Stream) GetStatus() apis.ResourceStatus {
 	return &s.Status
 }
 
+// GetPartitions is the number of partitions of the stream
+func (s *Stream) GetPartitions() int32 {
+	if s.Spec.Partitions == nil {
+		return 1
+	}
+	return *s.Spec.Partitions
+}
+
 // +kubebuilder:object:root=true
 
 // StreamList contains a list of Stream
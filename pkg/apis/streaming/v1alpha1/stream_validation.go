@@ -66,5 +66,9 @@ func (s *StreamSpec) Validate() validation.FieldErrors {
 		errs = errs.Also(validation.ErrMissingField("gateway"))
 	}
 
+	if s.Partitions != nil && *s.Partitions < 1 {
+		errs = errs.Also(validation.ErrInvalidValue(*s.Partitions, "partitions"))
+	}
+
 	return errs
 }
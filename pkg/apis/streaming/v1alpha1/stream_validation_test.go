@@ -54,6 +54,9 @@ func TestValidateStream(t *testing.T) {
 }
 
 func TestValidateStreamSpec(t *testing.T) {
+	zero := int32(0)
+	three := int32(3)
+
 	for _, c := range []struct {
 		name     string
 		target   *StreamSpec
@@ -81,6 +84,20 @@ func TestValidateStreamSpec(t *testing.T) {
 			ContentType: "image/*",
 		},
 		expected: validation.ErrMissingField("gateway"),
+	}, {
+		name: "valid partitions",
+		target: &StreamSpec{
+			Gateway:    corev1.LocalObjectReference{Name: "kafka"},
+			Partitions: &three,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "invalid partitions",
+		target: &StreamSpec{
+			Gateway:    corev1.LocalObjectReference{Name: "kafka"},
+			Partitions: &zero,
+		},
+		expected: validation.ErrInvalidValue(zero, "partitions"),
 	}} {
 		t.Run(c.name, func(t *testing.T) {
 			actual := c.target.Validate()
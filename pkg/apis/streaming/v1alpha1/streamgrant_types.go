@@ -0,0 +1,87 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// StreamGrantSpec defines the desired state of StreamGrant
+type StreamGrantSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// Streams in this namespace, by name, that are granted
+	Streams []string `json:"streams"`
+
+	// Namespaces that processors may bind the granted streams from. A
+	// processor references a stream in another namespace as
+	// `namespace/name`.
+	Namespaces []string `json:"namespaces"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories="riff"
+// +kubebuilder:printcolumn:name="Streams",type=string,JSONPath=`.spec.streams`
+// +kubebuilder:printcolumn:name="Namespaces",type=string,JSONPath=`.spec.namespaces`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+// +genclient:noStatus
+
+// StreamGrant allows processors in other namespaces to bind to streams in
+// this namespace
+type StreamGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec StreamGrantSpec `json:"spec,omitempty"`
+}
+
+func (*StreamGrant) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("StreamGrant")
+}
+
+// Grants checks if the stream is granted to the namespace
+func (g *StreamGrant) Grants(stream, namespace string) bool {
+	return containsString(g.Spec.Streams, stream) && containsString(g.Spec.Namespaces, namespace)
+}
+
+// +kubebuilder:object:root=true
+
+// StreamGrantList contains a list of StreamGrant
+type StreamGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StreamGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StreamGrant{}, &StreamGrantList{})
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
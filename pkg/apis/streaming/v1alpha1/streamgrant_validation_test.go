@@ -0,0 +1,101 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+func TestValidateStreamGrant(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *StreamGrant
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &StreamGrant{},
+		expected: validation.ErrMissingField("spec"),
+	}, {
+		name: "valid",
+		target: &StreamGrant{
+			Spec: StreamGrantSpec{
+				Streams:    []string{"my-stream"},
+				Namespaces: []string{"my-namespace"},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateStreamGrant(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateStreamGrantSpec(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *StreamGrantSpec
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &StreamGrantSpec{},
+		expected: validation.ErrMissingField(validation.CurrentField),
+	}, {
+		name: "valid",
+		target: &StreamGrantSpec{
+			Streams:    []string{"my-stream", "my-other-stream"},
+			Namespaces: []string{"my-namespace"},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "requires streams",
+		target: &StreamGrantSpec{
+			Namespaces: []string{"my-namespace"},
+		},
+		expected: validation.ErrMissingField("streams"),
+	}, {
+		name: "requires namespaces",
+		target: &StreamGrantSpec{
+			Streams: []string{"my-stream"},
+		},
+		expected: validation.ErrMissingField("namespaces"),
+	}, {
+		name: "empty values",
+		target: &StreamGrantSpec{
+			Streams:    []string{"my-stream", ""},
+			Namespaces: []string{""},
+		},
+		expected: validation.FieldErrors{}.Also(
+			validation.ErrInvalidArrayValue("", "streams", 1),
+			validation.ErrInvalidArrayValue("", "namespaces", 0),
+		),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateStreamGrantSpec(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
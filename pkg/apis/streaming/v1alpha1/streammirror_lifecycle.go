@@ -0,0 +1,110 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/projectriff/system/pkg/apis"
+)
+
+const (
+	StreamMirrorConditionReady                           = apis.ConditionReady
+	StreamMirrorConditionStreamsReady apis.ConditionType = "StreamsReady"
+	StreamMirrorConditionMirrorReady  apis.ConditionType = "MirrorReady"
+)
+
+var streamMirrorCondSet = apis.NewLivingConditionSet(
+	StreamMirrorConditionStreamsReady,
+	StreamMirrorConditionMirrorReady,
+)
+
+func (ms *StreamMirrorStatus) GetObservedGeneration() int64 {
+	return ms.ObservedGeneration
+}
+
+func (ms *StreamMirrorStatus) IsReady() bool {
+	return streamMirrorCondSet.Manage(ms).IsHappy()
+}
+
+func (*StreamMirrorStatus) GetReadyConditionType() apis.ConditionType {
+	return StreamMirrorConditionReady
+}
+
+func (ms *StreamMirrorStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return streamMirrorCondSet.Manage(ms).GetCondition(t)
+}
+
+func (ms *StreamMirrorStatus) InitializeConditions() {
+	streamMirrorCondSet.Manage(ms).InitializeConditions()
+}
+
+func (ms *StreamMirrorStatus) MarkStreamsReady() {
+	streamMirrorCondSet.Manage(ms).MarkTrue(StreamMirrorConditionStreamsReady)
+}
+
+func (ms *StreamMirrorStatus) MarkStreamsNotReady(message string) {
+	streamMirrorCondSet.Manage(ms).MarkFalse(StreamMirrorConditionStreamsReady, "StreamNotReady", message)
+}
+
+func (ms *StreamMirrorStatus) PropagateDeploymentStatus(ds *appsv1.DeploymentStatus) {
+	var available, progressing *appsv1.DeploymentCondition
+	for i := range ds.Conditions {
+		switch ds.Conditions[i].Type {
+		case appsv1.DeploymentAvailable:
+			available = &ds.Conditions[i]
+		case appsv1.DeploymentProgressing:
+			progressing = &ds.Conditions[i]
+		}
+	}
+	if available == nil || progressing == nil {
+		return
+	}
+	if progressing.Status == corev1.ConditionTrue && available.Status == corev1.ConditionFalse {
+		// DeploymentAvailable is False while progressing, avoid reporting StreamMirrorConditionReady as False
+		streamMirrorCondSet.Manage(ms).MarkUnknown(StreamMirrorConditionMirrorReady, progressing.Reason, progressing.Message)
+		return
+	}
+	switch {
+	case available.Status == corev1.ConditionUnknown:
+		streamMirrorCondSet.Manage(ms).MarkUnknown(StreamMirrorConditionMirrorReady, available.Reason, available.Message)
+	case available.Status == corev1.ConditionTrue:
+		streamMirrorCondSet.Manage(ms).MarkTrue(StreamMirrorConditionMirrorReady)
+	case available.Status == corev1.ConditionFalse:
+		streamMirrorCondSet.Manage(ms).MarkFalse(StreamMirrorConditionMirrorReady, available.Reason, available.Message)
+	}
+}
+
+func (ms *StreamMirrorStatus) PropagateJobStatus(js *batchv1.JobStatus) {
+	for _, c := range js.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			ms.CompletionTime = js.CompletionTime.DeepCopy()
+			streamMirrorCondSet.Manage(ms).MarkTrue(StreamMirrorConditionMirrorReady)
+			return
+		case batchv1.JobFailed:
+			streamMirrorCondSet.Manage(ms).MarkFalse(StreamMirrorConditionMirrorReady, c.Reason, c.Message)
+			return
+		}
+	}
+	streamMirrorCondSet.Manage(ms).MarkUnknown(StreamMirrorConditionMirrorReady, "Mirroring", "%d active pods", js.Active)
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/projectriff/system/pkg/apis"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+var (
+	StreamMirrorLabelKey = GroupVersion.Group + "/stream-mirror"
+)
+
+var (
+	_ apis.Resource = (*StreamMirror)(nil)
+)
+
+const (
+	ContinuousMirror = "continuous"
+	OneShotMirror    = "one-shot"
+)
+
+// StreamMirrorSpec defines the desired state of StreamMirror
+type StreamMirrorSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// Source is the name of the stream in this namespace messages are copied
+	// from.
+	Source string `json:"source"`
+
+	// Destination is the name of the stream in this namespace messages are
+	// copied to.
+	Destination string `json:"destination"`
+
+	// StartOffset is where to start copying the source stream the first time
+	// the mirror runs, either earliest or latest. Defaults to earliest.
+	// +optional
+	StartOffset string `json:"startOffset,omitempty"`
+
+	// Mode is either continuous, copying messages as they arrive, or
+	// one-shot, stopping once the head of the source stream is reached.
+	// Continuous mirrors run as a Deployment, one-shot mirrors run as a Job.
+	// Defaults to continuous.
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// Filter copies only the messages that match, all messages are copied
+	// when not set.
+	// +optional
+	Filter *StreamMirrorFilter `json:"filter,omitempty"`
+}
+
+type StreamMirrorFilter struct {
+	// ContentTypes copies only messages with one of the content types.
+	// Wildcards such as `application/*` are allowed.
+	// +optional
+	ContentTypes []string `json:"contentTypes,omitempty"`
+
+	// Headers copies only messages with each of the header values.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// StreamMirrorStatus defines the observed state of StreamMirror
+type StreamMirrorStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	apis.Status `json:",inline"`
+
+	// ConsumerGroup is the name of the consumer group the mirror consumes
+	// the source stream with
+	ConsumerGroup string `json:"consumerGroup,omitempty"`
+
+	DeploymentRef *refs.TypedLocalObjectReference `json:"deploymentRef,omitempty"`
+	JobRef        *refs.TypedLocalObjectReference `json:"jobRef,omitempty"`
+
+	// Partitions reports the position of the mirror on each partition of the
+	// source stream
+	Partitions []StreamMirrorPartition `json:"partitions,omitempty"`
+	// Lag is the total number of messages on the source stream not yet
+	// mirrored
+	Lag *int64 `json:"lag,omitempty"`
+
+	// CompletionTime is when a one-shot mirror reached the head of the source
+	// stream
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+type StreamMirrorPartition struct {
+	// Partition of the source stream
+	Partition int32 `json:"partition"`
+	// Offset of the next message to be mirrored
+	Offset int64 `json:"offset"`
+	// Lag is the number of messages on the partition not yet mirrored
+	Lag int64 `json:"lag"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="riff"
+// +kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.spec.source`
+// +kubebuilder:printcolumn:name="Destination",type=string,JSONPath=`.spec.destination`
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+// +kubebuilder:printcolumn:name="Lag",type=integer,JSONPath=`.status.lag`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+
+// StreamMirror is the Schema for the streammirrors API
+type StreamMirror struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StreamMirrorSpec   `json:"spec,omitempty"`
+	Status StreamMirrorStatus `json:"status,omitempty"`
+}
+
+func (*StreamMirror) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("StreamMirror")
+}
+
+func (m *StreamMirror) GetStatus() apis.ResourceStatus {
+	return &m.Status
+}
+
+// +kubebuilder:object:root=true
+
+// StreamMirrorList contains a list of StreamMirror
+type StreamMirrorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StreamMirror `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StreamMirror{}, &StreamMirrorList{})
+}
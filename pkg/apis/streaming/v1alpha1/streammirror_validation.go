@@ -0,0 +1,108 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+// +kubebuilder:webhook:path=/validate-streaming-projectriff-io-v1alpha1-streammirror,mutating=false,failurePolicy=fail,groups=streaming.projectriff.io,resources=streammirrors,verbs=create;update,versions=v1alpha1,name=streammirrors.streaming.projectriff.io
+
+var (
+	_ webhook.Validator         = &StreamMirror{}
+	_ validation.FieldValidator = &StreamMirror{}
+)
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *StreamMirror) ValidateCreate() error {
+	return r.Validate().ToAggregate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *StreamMirror) ValidateUpdate(old runtime.Object) error {
+	// TODO check for immutable fields
+	return r.Validate().ToAggregate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *StreamMirror) ValidateDelete() error {
+	return nil
+}
+
+func (r *StreamMirror) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	errs = errs.Also(r.Spec.Validate().ViaField("spec"))
+
+	return errs
+}
+
+func (s *StreamMirrorSpec) Validate() validation.FieldErrors {
+	if equality.Semantic.DeepEqual(s, &StreamMirrorSpec{}) {
+		return validation.ErrMissingField(validation.CurrentField)
+	}
+
+	errs := validation.FieldErrors{}
+
+	if s.Source == "" {
+		errs = errs.Also(validation.ErrMissingField("source"))
+	}
+	if s.Destination == "" {
+		errs = errs.Also(validation.ErrMissingField("destination"))
+	} else if s.Destination == s.Source {
+		errs = errs.Also(validation.ErrInvalidValue(s.Destination, "destination"))
+	}
+
+	if s.StartOffset != "" && s.StartOffset != Earliest && s.StartOffset != Latest {
+		errs = errs.Also(validation.ErrInvalidValue(s.StartOffset, "startOffset"))
+	}
+	if s.Mode != "" && s.Mode != ContinuousMirror && s.Mode != OneShotMirror {
+		errs = errs.Also(validation.ErrInvalidValue(s.Mode, "mode"))
+	}
+
+	if s.Filter != nil {
+		errs = errs.Also(s.Filter.Validate().ViaField("filter"))
+	}
+
+	return errs
+}
+
+func (f *StreamMirrorFilter) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	for i, contentType := range f.ContentTypes {
+		// allow wildcard subtypes, e.g. application/*
+		if _, _, err := mime.ParseMediaType(strings.Replace(contentType, "*", "x", -1)); err != nil {
+			errs = errs.Also(validation.ErrInvalidValue(contentType, fmt.Sprintf("contentTypes[%d]", i)))
+		}
+	}
+	for key := range f.Headers {
+		if key == "" {
+			errs = errs.Also(validation.ErrInvalidValue(key, "headers"))
+		}
+	}
+
+	return errs
+}
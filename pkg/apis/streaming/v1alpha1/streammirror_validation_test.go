@@ -0,0 +1,140 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+func TestValidateStreamMirror(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *StreamMirror
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &StreamMirror{},
+		expected: validation.ErrMissingField("spec"),
+	}, {
+		name: "valid",
+		target: &StreamMirror{
+			Spec: StreamMirrorSpec{
+				Source:      "my-source",
+				Destination: "my-destination",
+			},
+		},
+		expected: validation.FieldErrors{},
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateStreamMirror(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateStreamMirrorSpec(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *StreamMirrorSpec
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &StreamMirrorSpec{},
+		expected: validation.ErrMissingField(validation.CurrentField),
+	}, {
+		name: "valid",
+		target: &StreamMirrorSpec{
+			Source:      "my-source",
+			Destination: "my-destination",
+			StartOffset: Latest,
+			Mode:        OneShotMirror,
+			Filter: &StreamMirrorFilter{
+				ContentTypes: []string{"application/json", "text/*"},
+				Headers:      map[string]string{"tenant": "acme"},
+			},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "missing source",
+		target: &StreamMirrorSpec{
+			Destination: "my-destination",
+		},
+		expected: validation.ErrMissingField("source"),
+	}, {
+		name: "missing destination",
+		target: &StreamMirrorSpec{
+			Source: "my-source",
+		},
+		expected: validation.ErrMissingField("destination"),
+	}, {
+		name: "destination is source",
+		target: &StreamMirrorSpec{
+			Source:      "my-stream",
+			Destination: "my-stream",
+		},
+		expected: validation.ErrInvalidValue("my-stream", "destination"),
+	}, {
+		name: "invalid start offset",
+		target: &StreamMirrorSpec{
+			Source:      "my-source",
+			Destination: "my-destination",
+			StartOffset: "middle",
+		},
+		expected: validation.ErrInvalidValue("middle", "startOffset"),
+	}, {
+		name: "invalid mode",
+		target: &StreamMirrorSpec{
+			Source:      "my-source",
+			Destination: "my-destination",
+			Mode:        "sometimes",
+		},
+		expected: validation.ErrInvalidValue("sometimes", "mode"),
+	}, {
+		name: "invalid filter content type",
+		target: &StreamMirrorSpec{
+			Source:      "my-source",
+			Destination: "my-destination",
+			Filter: &StreamMirrorFilter{
+				ContentTypes: []string{"application/json", "not a content type"},
+			},
+		},
+		expected: validation.ErrInvalidValue("not a content type", "filter.contentTypes[1]"),
+	}, {
+		name: "invalid filter header",
+		target: &StreamMirrorSpec{
+			Source:      "my-source",
+			Destination: "my-destination",
+			Filter: &StreamMirrorFilter{
+				Headers: map[string]string{"": "value"},
+			},
+		},
+		expected: validation.ErrInvalidValue("", "filter.headers"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateStreamMirrorSpec(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/projectriff/system/pkg/apis"
+)
+
+const (
+	StreamQuotaConditionReady                          = apis.ConditionReady
+	StreamQuotaConditionWithinQuota apis.ConditionType = "WithinQuota"
+)
+
+var streamQuotaCondSet = apis.NewLivingConditionSet(
+	StreamQuotaConditionWithinQuota,
+)
+
+func (qs *StreamQuotaStatus) GetObservedGeneration() int64 {
+	return qs.ObservedGeneration
+}
+
+func (qs *StreamQuotaStatus) IsReady() bool {
+	return streamQuotaCondSet.Manage(qs).IsHappy()
+}
+
+func (*StreamQuotaStatus) GetReadyConditionType() apis.ConditionType {
+	return StreamQuotaConditionReady
+}
+
+func (qs *StreamQuotaStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return streamQuotaCondSet.Manage(qs).GetCondition(t)
+}
+
+func (qs *StreamQuotaStatus) InitializeConditions() {
+	streamQuotaCondSet.Manage(qs).InitializeConditions()
+}
+
+func (qs *StreamQuotaStatus) MarkWithinQuota() {
+	streamQuotaCondSet.Manage(qs).MarkTrue(StreamQuotaConditionWithinQuota)
+}
+
+func (qs *StreamQuotaStatus) MarkQuotaExceeded(message string, messageA ...interface{}) {
+	streamQuotaCondSet.Manage(qs).MarkFalse(StreamQuotaConditionWithinQuota, "QuotaExceeded", message, messageA...)
+}
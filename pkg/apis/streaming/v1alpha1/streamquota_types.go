@@ -0,0 +1,146 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/projectriff/system/pkg/apis"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+var (
+	_ apis.Resource = (*StreamQuota)(nil)
+)
+
+// StreamQuotaSpec defines the desired state of StreamQuota
+type StreamQuotaSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// MaxStreams is the number of streams that may be created in the
+	// namespace. Unlimited when not set.
+	// +optional
+	MaxStreams *int32 `json:"maxStreams,omitempty"`
+
+	// MaxPartitions is the total number of partitions across the streams in
+	// the namespace. Unlimited when not set.
+	// +optional
+	MaxPartitions *int32 `json:"maxPartitions,omitempty"`
+
+	// Gateways streams in the namespace may be created on, by name. All
+	// gateways are allowed when empty.
+	// +optional
+	Gateways []string `json:"gateways,omitempty"`
+}
+
+// StreamQuotaStatus defines the observed state of StreamQuota
+type StreamQuotaStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	apis.Status `json:",inline"`
+
+	// Streams is the number of streams in the namespace
+	Streams int32 `json:"streams"`
+
+	// Partitions is the total number of partitions across the streams in the
+	// namespace
+	Partitions int32 `json:"partitions"`
+}
+
+// StreamQuotaUsage is the use of a namespace's stream quota
+type StreamQuotaUsage struct {
+	// Name of the stream quota
+	Name string `json:"name"`
+
+	// Streams is the number of streams in the namespace
+	Streams int32 `json:"streams"`
+
+	// MaxStreams is the number of streams allowed by the quota
+	// +optional
+	MaxStreams *int32 `json:"maxStreams,omitempty"`
+
+	// Partitions is the total number of partitions across the streams in the
+	// namespace
+	Partitions int32 `json:"partitions"`
+
+	// MaxPartitions is the number of partitions allowed by the quota
+	// +optional
+	MaxPartitions *int32 `json:"maxPartitions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="riff"
+// +kubebuilder:printcolumn:name="Streams",type=integer,JSONPath=`.status.streams`
+// +kubebuilder:printcolumn:name="Max Streams",type=integer,JSONPath=`.spec.maxStreams`
+// +kubebuilder:printcolumn:name="Partitions",type=integer,JSONPath=`.status.partitions`
+// +kubebuilder:printcolumn:name="Max Partitions",type=integer,JSONPath=`.spec.maxPartitions`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+
+// StreamQuota limits the streams that may be created in a namespace
+type StreamQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StreamQuotaSpec   `json:"spec,omitempty"`
+	Status StreamQuotaStatus `json:"status,omitempty"`
+}
+
+func (*StreamQuota) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("StreamQuota")
+}
+
+func (q *StreamQuota) GetStatus() apis.ResourceStatus {
+	return &q.Status
+}
+
+// AllowsGateway checks if streams may be created on the gateway
+func (q *StreamQuota) AllowsGateway(gateway string) bool {
+	return len(q.Spec.Gateways) == 0 || containsString(q.Spec.Gateways, gateway)
+}
+
+// Usage of the quota as reported on the streams in the namespace
+func (q *StreamQuota) Usage() StreamQuotaUsage {
+	return StreamQuotaUsage{
+		Name:          q.Name,
+		Streams:       q.Status.Streams,
+		MaxStreams:    q.Spec.MaxStreams,
+		Partitions:    q.Status.Partitions,
+		MaxPartitions: q.Spec.MaxPartitions,
+	}
+}
+
+// +kubebuilder:object:root=true
+
+// StreamQuotaList contains a list of StreamQuota
+type StreamQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StreamQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StreamQuota{}, &StreamQuotaList{})
+}
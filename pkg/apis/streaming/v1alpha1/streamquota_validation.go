@@ -0,0 +1,89 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+// +kubebuilder:webhook:path=/validate-streaming-projectriff-io-v1alpha1-streamquota,mutating=false,failurePolicy=fail,groups=streaming.projectriff.io,resources=streamquotas,verbs=create;update,versions=v1alpha1,name=streamquotas.streaming.projectriff.io
+
+var (
+	_ webhook.Validator         = &StreamQuota{}
+	_ validation.FieldValidator = &StreamQuota{}
+)
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *StreamQuota) ValidateCreate() error {
+	return r.Validate().ToAggregate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *StreamQuota) ValidateUpdate(old runtime.Object) error {
+	return r.Validate().ToAggregate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *StreamQuota) ValidateDelete() error {
+	return nil
+}
+
+func (r *StreamQuota) Validate() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	errs = errs.Also(r.Spec.Validate().ViaField("spec"))
+
+	return errs
+}
+
+func (s *StreamQuotaSpec) Validate() validation.FieldErrors {
+	if equality.Semantic.DeepEqual(s, &StreamQuotaSpec{}) {
+		return validation.ErrMissingField(validation.CurrentField)
+	}
+
+	errs := validation.FieldErrors{}
+
+	if s.MaxStreams != nil && *s.MaxStreams < 0 {
+		errs = errs.Also(validation.ErrInvalidValue(*s.MaxStreams, "maxStreams"))
+	}
+	if s.MaxPartitions != nil && *s.MaxPartitions < 0 {
+		errs = errs.Also(validation.ErrInvalidValue(*s.MaxPartitions, "maxPartitions"))
+	}
+
+	gateways := map[string][]string{}
+	for i, gateway := range s.Gateways {
+		if gateway == "" {
+			errs = errs.Also(validation.ErrInvalidArrayValue(gateway, "gateways", i))
+			continue
+		}
+		gateways[gateway] = append(gateways[gateway], fmt.Sprintf("gateways[%d]", i))
+	}
+	for _, gateway := range s.Gateways {
+		if fields := gateways[gateway]; len(fields) > 1 {
+			errs = errs.Also(validation.ErrDuplicateValue(gateway, fields...))
+			delete(gateways, gateway)
+		}
+	}
+
+	return errs
+}
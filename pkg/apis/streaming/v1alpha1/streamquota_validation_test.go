@@ -0,0 +1,122 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+func TestValidateStreamQuota(t *testing.T) {
+	ten := int32(10)
+
+	for _, c := range []struct {
+		name     string
+		target   *StreamQuota
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &StreamQuota{},
+		expected: validation.ErrMissingField("spec"),
+	}, {
+		name: "valid",
+		target: &StreamQuota{
+			Spec: StreamQuotaSpec{
+				MaxStreams: &ten,
+			},
+		},
+		expected: validation.FieldErrors{},
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateStreamQuota(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateStreamQuotaSpec(t *testing.T) {
+	zero := int32(0)
+	ten := int32(10)
+	negativeOne := int32(-1)
+
+	for _, c := range []struct {
+		name     string
+		target   *StreamQuotaSpec
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &StreamQuotaSpec{},
+		expected: validation.ErrMissingField(validation.CurrentField),
+	}, {
+		name: "valid",
+		target: &StreamQuotaSpec{
+			MaxStreams:    &ten,
+			MaxPartitions: &ten,
+			Gateways:      []string{"my-gateway", "my-other-gateway"},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "zero limits",
+		target: &StreamQuotaSpec{
+			MaxStreams:    &zero,
+			MaxPartitions: &zero,
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "gateways only",
+		target: &StreamQuotaSpec{
+			Gateways: []string{"my-gateway"},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "negative max streams",
+		target: &StreamQuotaSpec{
+			MaxStreams: &negativeOne,
+		},
+		expected: validation.ErrInvalidValue(negativeOne, "maxStreams"),
+	}, {
+		name: "negative max partitions",
+		target: &StreamQuotaSpec{
+			MaxPartitions: &negativeOne,
+		},
+		expected: validation.ErrInvalidValue(negativeOne, "maxPartitions"),
+	}, {
+		name: "empty gateway",
+		target: &StreamQuotaSpec{
+			Gateways: []string{"my-gateway", ""},
+		},
+		expected: validation.ErrInvalidArrayValue("", "gateways", 1),
+	}, {
+		name: "duplicate gateway",
+		target: &StreamQuotaSpec{
+			Gateways: []string{"my-gateway", "my-other-gateway", "my-gateway"},
+		},
+		expected: validation.ErrDuplicateValue("my-gateway", "gateways[0]", "gateways[2]"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateStreamQuotaSpec(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStreamTapDefault(t *testing.T) {
+	count := int32(100)
+	defaultCount := DefaultStreamTapCount
+
+	tests := []struct {
+		name string
+		in   *StreamTap
+		want *StreamTap
+	}{{
+		name: "empty",
+		in:   &StreamTap{},
+		want: &StreamTap{
+			Spec: StreamTapSpec{
+				Count: &defaultCount,
+				TTL:   &metav1.Duration{Duration: DefaultStreamTapTTL},
+			},
+		},
+	}, {
+		name: "not overwritten",
+		in: &StreamTap{
+			Spec: StreamTapSpec{
+				Count: &count,
+				TTL:   &metav1.Duration{Duration: time.Hour},
+			},
+		},
+		want: &StreamTap{
+			Spec: StreamTapSpec{
+				Count: &count,
+				TTL:   &metav1.Duration{Duration: time.Hour},
+			},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.in
+			got.Default()
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Default (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
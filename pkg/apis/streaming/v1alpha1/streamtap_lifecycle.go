@@ -0,0 +1,95 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/projectriff/system/pkg/apis"
+)
+
+const (
+	StreamTapConditionReady                          = apis.ConditionReady
+	StreamTapConditionStreamReady apis.ConditionType = "StreamReady"
+	StreamTapConditionTapReady    apis.ConditionType = "TapReady"
+)
+
+var streamTapCondSet = apis.NewLivingConditionSet(
+	StreamTapConditionStreamReady,
+	StreamTapConditionTapReady,
+)
+
+func (ts *StreamTapStatus) GetObservedGeneration() int64 {
+	return ts.ObservedGeneration
+}
+
+func (ts *StreamTapStatus) IsReady() bool {
+	return streamTapCondSet.Manage(ts).IsHappy()
+}
+
+func (*StreamTapStatus) GetReadyConditionType() apis.ConditionType {
+	return StreamTapConditionReady
+}
+
+func (ts *StreamTapStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return streamTapCondSet.Manage(ts).GetCondition(t)
+}
+
+func (ts *StreamTapStatus) InitializeConditions() {
+	streamTapCondSet.Manage(ts).InitializeConditions()
+}
+
+func (ts *StreamTapStatus) MarkStreamReady() {
+	streamTapCondSet.Manage(ts).MarkTrue(StreamTapConditionStreamReady)
+}
+
+func (ts *StreamTapStatus) MarkStreamNotReady(message string) {
+	streamTapCondSet.Manage(ts).MarkFalse(StreamTapConditionStreamReady, "StreamNotReady", message)
+}
+
+func (ts *StreamTapStatus) MarkExpired() {
+	streamTapCondSet.Manage(ts).MarkFalse(StreamTapConditionTapReady, "Expired", "the tap expired and stopped consuming")
+}
+
+func (ts *StreamTapStatus) PropagateDeploymentStatus(ds *appsv1.DeploymentStatus) {
+	var available, progressing *appsv1.DeploymentCondition
+	for i := range ds.Conditions {
+		switch ds.Conditions[i].Type {
+		case appsv1.DeploymentAvailable:
+			available = &ds.Conditions[i]
+		case appsv1.DeploymentProgressing:
+			progressing = &ds.Conditions[i]
+		}
+	}
+	if available == nil || progressing == nil {
+		return
+	}
+	if progressing.Status == corev1.ConditionTrue && available.Status == corev1.ConditionFalse {
+		// DeploymentAvailable is False while progressing, avoid reporting StreamTapConditionReady as False
+		streamTapCondSet.Manage(ts).MarkUnknown(StreamTapConditionTapReady, progressing.Reason, progressing.Message)
+		return
+	}
+	switch {
+	case available.Status == corev1.ConditionUnknown:
+		streamTapCondSet.Manage(ts).MarkUnknown(StreamTapConditionTapReady, available.Reason, available.Message)
+	case available.Status == corev1.ConditionTrue:
+		streamTapCondSet.Manage(ts).MarkTrue(StreamTapConditionTapReady)
+	case available.Status == corev1.ConditionFalse:
+		streamTapCondSet.Manage(ts).MarkFalse(StreamTapConditionTapReady, available.Reason, available.Message)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/projectriff/system/pkg/apis"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+var (
+	StreamTapLabelKey = GroupVersion.Group + "/stream-tap"
+)
+
+var (
+	_ apis.Resource = (*StreamTap)(nil)
+)
+
+// StreamTapSpec defines the desired state of StreamTap
+type StreamTapSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// Stream is the name of the stream in this namespace to tap
+	Stream string `json:"stream"`
+
+	// Count is the number of the latest messages on the stream to print
+	// before following new messages. Defaults to 10.
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+
+	// TTL is how long the tap runs after it is created, the tap stops
+	// consuming once it expires. Defaults to 10 minutes.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// StreamTapStatus defines the observed state of StreamTap
+type StreamTapStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	apis.Status `json:",inline"`
+
+	// DeploymentRef references the Deployment running the tap's consumer,
+	// messages are printed to the logs of its pod
+	DeploymentRef *refs.TypedLocalObjectReference `json:"deploymentRef,omitempty"`
+
+	// ExpirationTime is when the tap stops consuming
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="riff"
+// +kubebuilder:printcolumn:name="Stream",type=string,JSONPath=`.spec.stream`
+// +kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.expirationTime`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+
+// StreamTap is the Schema for the streamtaps API
+type StreamTap struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StreamTapSpec   `json:"spec,omitempty"`
+	Status StreamTapStatus `json:"status,omitempty"`
+}
+
+func (*StreamTap) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("StreamTap")
+}
+
+func (t *StreamTap) GetStatus() apis.ResourceStatus {
+	return &t.Status
+}
+
+// +kubebuilder:object:root=true
+
+// StreamTapList contains a list of StreamTap
+type StreamTapList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StreamTap `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StreamTap{}, &StreamTapList{})
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectriff/system/pkg/validation"
+)
+
+func TestValidateStreamTap(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		target   *StreamTap
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &StreamTap{},
+		expected: validation.ErrMissingField("spec"),
+	}, {
+		name: "valid",
+		target: &StreamTap{
+			Spec: StreamTapSpec{
+				Stream: "my-stream",
+			},
+		},
+		expected: validation.FieldErrors{},
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateStreamTap(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
+
+func TestValidateStreamTapSpec(t *testing.T) {
+	zero := int32(0)
+	hundred := int32(100)
+	tooMany := MaxStreamTapCount + 1
+
+	for _, c := range []struct {
+		name     string
+		target   *StreamTapSpec
+		expected validation.FieldErrors
+	}{{
+		name:     "empty",
+		target:   &StreamTapSpec{},
+		expected: validation.ErrMissingField(validation.CurrentField),
+	}, {
+		name: "valid",
+		target: &StreamTapSpec{
+			Stream: "my-stream",
+			Count:  &hundred,
+			TTL:    &metav1.Duration{Duration: time.Hour},
+		},
+		expected: validation.FieldErrors{},
+	}, {
+		name: "missing stream",
+		target: &StreamTapSpec{
+			Count: &hundred,
+		},
+		expected: validation.ErrMissingField("stream"),
+	}, {
+		name: "invalid count",
+		target: &StreamTapSpec{
+			Stream: "my-stream",
+			Count:  &zero,
+		},
+		expected: validation.ErrInvalidValue(zero, "count"),
+	}, {
+		name: "count too large",
+		target: &StreamTapSpec{
+			Stream: "my-stream",
+			Count:  &tooMany,
+		},
+		expected: validation.ErrInvalidValue(tooMany, "count"),
+	}, {
+		name: "invalid ttl",
+		target: &StreamTapSpec{
+			Stream: "my-stream",
+			TTL:    &metav1.Duration{},
+		},
+		expected: validation.ErrInvalidValue("0s", "ttl"),
+	}, {
+		name: "ttl too long",
+		target: &StreamTapSpec{
+			Stream: "my-stream",
+			TTL:    &metav1.Duration{Duration: 48 * time.Hour},
+		},
+		expected: validation.ErrInvalidValue("48h0m0s", "ttl"),
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			actual := c.target.Validate()
+			if diff := cmp.Diff(c.expected, actual); diff != "" {
+				t.Errorf("validateStreamTapSpec(%s) (-expected, +actual) = %v", c.name, diff)
+			}
+		})
+	}
+}
@@ -22,7 +22,9 @@ package v1alpha1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/projectriff/system/pkg/apis"
 )
@@ -59,6 +61,154 @@ func (in *Build) DeepCopy() *Build {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChainedFunction) DeepCopyInto(out *ChainedFunction) {
+	*out = *in
+	out.Build = in.Build
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChainedFunction.
+func (in *ChainedFunction) DeepCopy() *ChainedFunction {
+	if in == nil {
+		return nil
+	}
+	out := new(ChainedFunction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChainedFunctionStatus) DeepCopyInto(out *ChainedFunctionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChainedFunctionStatus.
+func (in *ChainedFunctionStatus) DeepCopy() *ChainedFunctionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChainedFunctionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronSource) DeepCopyInto(out *CronSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronSource.
+func (in *CronSource) DeepCopy() *CronSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CronSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CronSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronSourceList) DeepCopyInto(out *CronSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CronSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronSourceList.
+func (in *CronSourceList) DeepCopy() *CronSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(CronSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CronSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronSourceSpec) DeepCopyInto(out *CronSourceSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronSourceSpec.
+func (in *CronSourceSpec) DeepCopy() *CronSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CronSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronSourceStatus) DeepCopyInto(out *CronSourceStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.CronJobRef != nil {
+		in, out := &in.CronJobRef, &out.CronJobRef
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronSourceStatus.
+func (in *CronSourceStatus) DeepCopy() *CronSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CronSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilterOperation) DeepCopyInto(out *FilterOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilterOperation.
+func (in *FilterOperation) DeepCopy() *FilterOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(FilterOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Gateway) DeepCopyInto(out *Gateway) {
 	*out = *in
@@ -86,6 +236,80 @@ func (in *Gateway) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayDeploymentSpec) DeepCopyInto(out *GatewayDeploymentSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PositionsStore != nil {
+		in, out := &in.PositionsStore, &out.PositionsStore
+		*out = new(GatewayPositionsStore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exposure != nil {
+		in, out := &in.Exposure, &out.Exposure
+		*out = new(GatewayExposure)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayDeploymentSpec.
+func (in *GatewayDeploymentSpec) DeepCopy() *GatewayDeploymentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayDeploymentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayExposure) DeepCopyInto(out *GatewayExposure) {
+	*out = *in
+	if in.TLSSecretRef != nil {
+		in, out := &in.TLSSecretRef, &out.TLSSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.AuthSecretRef != nil {
+		in, out := &in.AuthSecretRef, &out.AuthSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayExposure.
+func (in *GatewayExposure) DeepCopy() *GatewayExposure {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayExposure)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayList) DeepCopyInto(out *GatewayList) {
 	*out = *in
@@ -118,9 +342,34 @@ func (in *GatewayList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayPositionsStore) DeepCopyInto(out *GatewayPositionsStore) {
+	*out = *in
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisPositionsStore)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayPositionsStore.
+func (in *GatewayPositionsStore) DeepCopy() *GatewayPositionsStore {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayPositionsStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
 	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Template != nil {
 		in, out := &in.Template, &out.Template
 		*out = new(v1.PodTemplateSpec)
@@ -131,6 +380,11 @@ func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
 		*out = make([]v1.ServicePort, len(*in))
 		copy(*out, *in)
 	}
+	if in.Exposure != nil {
+		in, out := &in.Exposure, &out.Exposure
+		*out = new(GatewayExposure)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
@@ -160,6 +414,23 @@ func (in *GatewayStatus) DeepCopyInto(out *GatewayStatus) {
 		in, out := &in.ServiceRef, &out.ServiceRef
 		*out = (*in).DeepCopy()
 	}
+	if in.PodDisruptionBudgetRef != nil {
+		in, out := &in.PodDisruptionBudgetRef, &out.PodDisruptionBudgetRef
+		*out = (*in).DeepCopy()
+	}
+	if in.PublicAddress != nil {
+		in, out := &in.PublicAddress, &out.PublicAddress
+		*out = new(apis.Addressable)
+		**out = **in
+	}
+	if in.PublicServiceRef != nil {
+		in, out := &in.PublicServiceRef, &out.PublicServiceRef
+		*out = (*in).DeepCopy()
+	}
+	if in.IngressRef != nil {
+		in, out := &in.IngressRef, &out.IngressRef
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayStatus.
@@ -173,7 +444,7 @@ func (in *GatewayStatus) DeepCopy() *GatewayStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InMemoryGateway) DeepCopyInto(out *InMemoryGateway) {
+func (in *HTTPSource) DeepCopyInto(out *HTTPSource) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -181,18 +452,18 @@ func (in *InMemoryGateway) DeepCopyInto(out *InMemoryGateway) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryGateway.
-func (in *InMemoryGateway) DeepCopy() *InMemoryGateway {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSource.
+func (in *HTTPSource) DeepCopy() *HTTPSource {
 	if in == nil {
 		return nil
 	}
-	out := new(InMemoryGateway)
+	out := new(HTTPSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *InMemoryGateway) DeepCopyObject() runtime.Object {
+func (in *HTTPSource) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -200,31 +471,31 @@ func (in *InMemoryGateway) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InMemoryGatewayList) DeepCopyInto(out *InMemoryGatewayList) {
+func (in *HTTPSourceList) DeepCopyInto(out *HTTPSourceList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]InMemoryGateway, len(*in))
+		*out = make([]HTTPSource, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryGatewayList.
-func (in *InMemoryGatewayList) DeepCopy() *InMemoryGatewayList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSourceList.
+func (in *HTTPSourceList) DeepCopy() *HTTPSourceList {
 	if in == nil {
 		return nil
 	}
-	out := new(InMemoryGatewayList)
+	out := new(HTTPSourceList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *InMemoryGatewayList) DeepCopyObject() runtime.Object {
+func (in *HTTPSourceList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -232,81 +503,1726 @@ func (in *InMemoryGatewayList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InMemoryGatewaySpec) DeepCopyInto(out *InMemoryGatewaySpec) {
+func (in *HTTPSourceSpec) DeepCopyInto(out *HTTPSourceSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryGatewaySpec.
-func (in *InMemoryGatewaySpec) DeepCopy() *InMemoryGatewaySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSourceSpec.
+func (in *HTTPSourceSpec) DeepCopy() *HTTPSourceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(InMemoryGatewaySpec)
+	out := new(HTTPSourceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InMemoryGatewayStatus) DeepCopyInto(out *InMemoryGatewayStatus) {
+func (in *HTTPSourceStatus) DeepCopyInto(out *HTTPSourceStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
+	if in.DeploymentRef != nil {
+		in, out := &in.DeploymentRef, &out.DeploymentRef
+		*out = (*in).DeepCopy()
+	}
+	if in.ServiceRef != nil {
+		in, out := &in.ServiceRef, &out.ServiceRef
+		*out = (*in).DeepCopy()
+	}
+	if in.IngressRef != nil {
+		in, out := &in.IngressRef, &out.IngressRef
+		*out = (*in).DeepCopy()
+	}
 	if in.Address != nil {
 		in, out := &in.Address, &out.Address
 		*out = new(apis.Addressable)
 		**out = **in
 	}
-	if in.GatewayRef != nil {
-		in, out := &in.GatewayRef, &out.GatewayRef
-		*out = (*in).DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSourceStatus.
+func (in *HTTPSourceStatus) DeepCopy() *HTTPSourceStatus {
+	if in == nil {
+		return nil
 	}
+	out := new(HTTPSourceStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryGatewayStatus.
-func (in *InMemoryGatewayStatus) DeepCopy() *InMemoryGatewayStatus {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryGateway) DeepCopyInto(out *InMemoryGateway) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryGateway.
+func (in *InMemoryGateway) DeepCopy() *InMemoryGateway {
 	if in == nil {
 		return nil
 	}
-	out := new(InMemoryGatewayStatus)
+	out := new(InMemoryGateway)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InMemoryGateway) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InputStreamBinding) DeepCopyInto(out *InputStreamBinding) {
+func (in *InMemoryGatewayList) DeepCopyInto(out *InMemoryGatewayList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InMemoryGateway, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryGatewayList.
+func (in *InMemoryGatewayList) DeepCopy() *InMemoryGatewayList {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryGatewayList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InMemoryGatewayList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryGatewaySpec) DeepCopyInto(out *InMemoryGatewaySpec) {
+	*out = *in
+	in.GatewayDeploymentSpec.DeepCopyInto(&out.GatewayDeploymentSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryGatewaySpec.
+func (in *InMemoryGatewaySpec) DeepCopy() *InMemoryGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryGatewayStatus) DeepCopyInto(out *InMemoryGatewayStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.Address != nil {
+		in, out := &in.Address, &out.Address
+		*out = new(apis.Addressable)
+		**out = **in
+	}
+	if in.GatewayRef != nil {
+		in, out := &in.GatewayRef, &out.GatewayRef
+		*out = (*in).DeepCopy()
+	}
+	if in.PublicAddress != nil {
+		in, out := &in.PublicAddress, &out.PublicAddress
+		*out = new(apis.Addressable)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryGatewayStatus.
+func (in *InMemoryGatewayStatus) DeepCopy() *InMemoryGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InputStreamBinding) DeepCopyInto(out *InputStreamBinding) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PartitionOffsets != nil {
+		in, out := &in.PartitionOffsets, &out.PartitionOffsets
+		*out = make([]PartitionOffset, len(*in))
+		copy(*out, *in)
+	}
+	if in.LagThreshold != nil {
+		in, out := &in.LagThreshold, &out.LagThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InputStreamBinding.
+func (in *InputStreamBinding) DeepCopy() *InputStreamBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(InputStreamBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaGateway) DeepCopyInto(out *KafkaGateway) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaGateway.
+func (in *KafkaGateway) DeepCopy() *KafkaGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KafkaGateway) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaGatewayList) DeepCopyInto(out *KafkaGatewayList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KafkaGateway, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaGatewayList.
+func (in *KafkaGatewayList) DeepCopy() *KafkaGatewayList {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaGatewayList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KafkaGatewayList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaGatewaySpec) DeepCopyInto(out *KafkaGatewaySpec) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(KafkaTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SASL != nil {
+		in, out := &in.SASL, &out.SASL
+		*out = new(KafkaSASL)
+		**out = **in
+	}
+	in.GatewayDeploymentSpec.DeepCopyInto(&out.GatewayDeploymentSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaGatewaySpec.
+func (in *KafkaGatewaySpec) DeepCopy() *KafkaGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaGatewayStatus) DeepCopyInto(out *KafkaGatewayStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.Address != nil {
+		in, out := &in.Address, &out.Address
+		*out = new(apis.Addressable)
+		**out = **in
+	}
+	if in.GatewayRef != nil {
+		in, out := &in.GatewayRef, &out.GatewayRef
+		*out = (*in).DeepCopy()
+	}
+	if in.PublicAddress != nil {
+		in, out := &in.PublicAddress, &out.PublicAddress
+		*out = new(apis.Addressable)
+		**out = **in
+	}
+	if in.TriggerAuthenticationRef != nil {
+		in, out := &in.TriggerAuthenticationRef, &out.TriggerAuthenticationRef
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaGatewayStatus.
+func (in *KafkaGatewayStatus) DeepCopy() *KafkaGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaSASL) DeepCopyInto(out *KafkaSASL) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSASL.
+func (in *KafkaSASL) DeepCopy() *KafkaSASL {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaSASL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaTLS) DeepCopyInto(out *KafkaTLS) {
+	*out = *in
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ClientCertSecretRef != nil {
+		in, out := &in.ClientCertSecretRef, &out.ClientCertSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaTLS.
+func (in *KafkaTLS) DeepCopy() *KafkaTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATSGateway) DeepCopyInto(out *NATSGateway) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATSGateway.
+func (in *NATSGateway) DeepCopy() *NATSGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(NATSGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NATSGateway) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATSGatewayList) DeepCopyInto(out *NATSGatewayList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NATSGateway, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATSGatewayList.
+func (in *NATSGatewayList) DeepCopy() *NATSGatewayList {
+	if in == nil {
+		return nil
+	}
+	out := new(NATSGatewayList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NATSGatewayList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATSGatewaySpec) DeepCopyInto(out *NATSGatewaySpec) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	in.GatewayDeploymentSpec.DeepCopyInto(&out.GatewayDeploymentSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATSGatewaySpec.
+func (in *NATSGatewaySpec) DeepCopy() *NATSGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NATSGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATSGatewayStatus) DeepCopyInto(out *NATSGatewayStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.Address != nil {
+		in, out := &in.Address, &out.Address
+		*out = new(apis.Addressable)
+		**out = **in
+	}
+	if in.GatewayRef != nil {
+		in, out := &in.GatewayRef, &out.GatewayRef
+		*out = (*in).DeepCopy()
+	}
+	if in.PublicAddress != nil {
+		in, out := &in.PublicAddress, &out.PublicAddress
+		*out = new(apis.Addressable)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATSGatewayStatus.
+func (in *NATSGatewayStatus) DeepCopy() *NATSGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NATSGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputStreamBinding) DeepCopyInto(out *OutputStreamBinding) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputStreamBinding.
+func (in *OutputStreamBinding) DeepCopy() *OutputStreamBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputStreamBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PartitionOffset) DeepCopyInto(out *PartitionOffset) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PartitionOffset.
+func (in *PartitionOffset) DeepCopy() *PartitionOffset {
+	if in == nil {
+		return nil
+	}
+	out := new(PartitionOffset)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pipeline) DeepCopyInto(out *Pipeline) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pipeline.
+func (in *Pipeline) DeepCopy() *Pipeline {
+	if in == nil {
+		return nil
+	}
+	out := new(Pipeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Pipeline) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineList) DeepCopyInto(out *PipelineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Pipeline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineList.
+func (in *PipelineList) DeepCopy() *PipelineList {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PipelineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
+	*out = *in
+	out.Gateway = in.Gateway
+	if in.Streams != nil {
+		in, out := &in.Streams, &out.Streams
+		*out = make([]PipelineStream, len(*in))
+		copy(*out, *in)
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]PipelineStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpec.
+func (in *PipelineSpec) DeepCopy() *PipelineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineStatus) DeepCopyInto(out *PipelineStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]PipelineStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStatus.
+func (in *PipelineStatus) DeepCopy() *PipelineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineStep) DeepCopyInto(out *PipelineStep) {
+	*out = *in
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStep.
+func (in *PipelineStep) DeepCopy() *PipelineStep {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineStepStatus) DeepCopyInto(out *PipelineStepStatus) {
+	*out = *in
+	if in.ProcessorRef != nil {
+		in, out := &in.ProcessorRef, &out.ProcessorRef
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStepStatus.
+func (in *PipelineStepStatus) DeepCopy() *PipelineStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineStream) DeepCopyInto(out *PipelineStream) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStream.
+func (in *PipelineStream) DeepCopy() *PipelineStream {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineStream)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Processor) DeepCopyInto(out *Processor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Processor.
+func (in *Processor) DeepCopy() *Processor {
+	if in == nil {
+		return nil
+	}
+	out := new(Processor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Processor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessorInputLag) DeepCopyInto(out *ProcessorInputLag) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessorInputLag.
+func (in *ProcessorInputLag) DeepCopy() *ProcessorInputLag {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessorInputLag)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessorList) DeepCopyInto(out *ProcessorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Processor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessorList.
+func (in *ProcessorList) DeepCopy() *ProcessorList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProcessorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessorOperation) DeepCopyInto(out *ProcessorOperation) {
+	*out = *in
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(FilterOperation)
+		**out = **in
+	}
+	if in.Project != nil {
+		in, out := &in.Project, &out.Project
+		*out = new(ProjectOperation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Split != nil {
+		in, out := &in.Split, &out.Split
+		*out = new(SplitOperation)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessorOperation.
+func (in *ProcessorOperation) DeepCopy() *ProcessorOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessorOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessorReplay) DeepCopyInto(out *ProcessorReplay) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessorReplay.
+func (in *ProcessorReplay) DeepCopy() *ProcessorReplay {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessorReplay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessorRollout) DeepCopyInto(out *ProcessorRollout) {
+	*out = *in
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessorRollout.
+func (in *ProcessorRollout) DeepCopy() *ProcessorRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessorRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessorSpec) DeepCopyInto(out *ProcessorSpec) {
+	*out = *in
+	if in.Build != nil {
+		in, out := &in.Build, &out.Build
+		*out = new(Build)
+		**out = **in
+	}
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make([]InputStreamBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]OutputStreamBinding, len(*in))
+		copy(*out, *in)
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(v1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Operation != nil {
+		in, out := &in.Operation, &out.Operation
+		*out = new(ProcessorOperation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Chain != nil {
+		in, out := &in.Chain, &out.Chain
+		*out = make([]ChainedFunction, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ProcessorRollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(Window)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.State != nil {
+		in, out := &in.State, &out.State
+		*out = new(ProcessorState)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Scale.DeepCopyInto(&out.Scale)
+	if in.LaggingThreshold != nil {
+		in, out := &in.LaggingThreshold, &out.LaggingThreshold
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessorSpec.
+func (in *ProcessorSpec) DeepCopy() *ProcessorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessorState) DeepCopyInto(out *ProcessorState) {
+	*out = *in
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(StateVolume)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Changelog != nil {
+		in, out := &in.Changelog, &out.Changelog
+		*out = new(StateChangelog)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessorState.
+func (in *ProcessorState) DeepCopy() *ProcessorState {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessorState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessorStatus) DeepCopyInto(out *ProcessorStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.DeploymentRef != nil {
+		in, out := &in.DeploymentRef, &out.DeploymentRef
+		*out = (*in).DeepCopy()
+	}
+	if in.ScaledObjectRef != nil {
+		in, out := &in.ScaledObjectRef, &out.ScaledObjectRef
+		*out = (*in).DeepCopy()
+	}
+	if in.StatefulSetRef != nil {
+		in, out := &in.StatefulSetRef, &out.StatefulSetRef
+		*out = (*in).DeepCopy()
+	}
+	if in.ChangelogStreamRef != nil {
+		in, out := &in.ChangelogStreamRef, &out.ChangelogStreamRef
+		*out = (*in).DeepCopy()
+	}
+	if in.ReplayHistory != nil {
+		in, out := &in.ReplayHistory, &out.ReplayHistory
+		*out = make([]ProcessorReplay, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastActiveTime != nil {
+		in, out := &in.LastActiveTime, &out.LastActiveTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Lag != nil {
+		in, out := &in.Lag, &out.Lag
+		*out = new(int64)
+		**out = **in
+	}
+	if in.InputLags != nil {
+		in, out := &in.InputLags, &out.InputLags
+		*out = make([]ProcessorInputLag, len(*in))
+		copy(*out, *in)
+	}
+	if in.Chain != nil {
+		in, out := &in.Chain, &out.Chain
+		*out = make([]ChainedFunctionStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessorStatus.
+func (in *ProcessorStatus) DeepCopy() *ProcessorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectOperation) DeepCopyInto(out *ProjectOperation) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]ProjectedField, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectOperation.
+func (in *ProjectOperation) DeepCopy() *ProjectOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectedField) DeepCopyInto(out *ProjectedField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectedField.
+func (in *ProjectedField) DeepCopy() *ProjectedField {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectedField)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulsarGateway) DeepCopyInto(out *PulsarGateway) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarGateway.
+func (in *PulsarGateway) DeepCopy() *PulsarGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(PulsarGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PulsarGateway) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulsarGatewayList) DeepCopyInto(out *PulsarGatewayList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PulsarGateway, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarGatewayList.
+func (in *PulsarGatewayList) DeepCopy() *PulsarGatewayList {
+	if in == nil {
+		return nil
+	}
+	out := new(PulsarGatewayList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PulsarGatewayList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulsarGatewaySpec) DeepCopyInto(out *PulsarGatewaySpec) {
+	*out = *in
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(PulsarTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	in.GatewayDeploymentSpec.DeepCopyInto(&out.GatewayDeploymentSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarGatewaySpec.
+func (in *PulsarGatewaySpec) DeepCopy() *PulsarGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PulsarGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulsarGatewayStatus) DeepCopyInto(out *PulsarGatewayStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.Address != nil {
+		in, out := &in.Address, &out.Address
+		*out = new(apis.Addressable)
+		**out = **in
+	}
+	if in.GatewayRef != nil {
+		in, out := &in.GatewayRef, &out.GatewayRef
+		*out = (*in).DeepCopy()
+	}
+	if in.PublicAddress != nil {
+		in, out := &in.PublicAddress, &out.PublicAddress
+		*out = new(apis.Addressable)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarGatewayStatus.
+func (in *PulsarGatewayStatus) DeepCopy() *PulsarGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PulsarGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulsarNamespaceTemplateData) DeepCopyInto(out *PulsarNamespaceTemplateData) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarNamespaceTemplateData.
+func (in *PulsarNamespaceTemplateData) DeepCopy() *PulsarNamespaceTemplateData {
+	if in == nil {
+		return nil
+	}
+	out := new(PulsarNamespaceTemplateData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulsarTLS) DeepCopyInto(out *PulsarTLS) {
+	*out = *in
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ClientCertSecretRef != nil {
+		in, out := &in.ClientCertSecretRef, &out.ClientCertSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarTLS.
+func (in *PulsarTLS) DeepCopy() *PulsarTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(PulsarTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisGateway) DeepCopyInto(out *RedisGateway) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisGateway.
+func (in *RedisGateway) DeepCopy() *RedisGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RedisGateway) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisGatewayList) DeepCopyInto(out *RedisGatewayList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RedisGateway, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisGatewayList.
+func (in *RedisGatewayList) DeepCopy() *RedisGatewayList {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisGatewayList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RedisGatewayList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisGatewaySpec) DeepCopyInto(out *RedisGatewaySpec) {
+	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.GatewayDeploymentSpec.DeepCopyInto(&out.GatewayDeploymentSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisGatewaySpec.
+func (in *RedisGatewaySpec) DeepCopy() *RedisGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisGatewayStatus) DeepCopyInto(out *RedisGatewayStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.Address != nil {
+		in, out := &in.Address, &out.Address
+		*out = new(apis.Addressable)
+		**out = **in
+	}
+	if in.GatewayRef != nil {
+		in, out := &in.GatewayRef, &out.GatewayRef
+		*out = (*in).DeepCopy()
+	}
+	if in.PublicAddress != nil {
+		in, out := &in.PublicAddress, &out.PublicAddress
+		*out = new(apis.Addressable)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisGatewayStatus.
+func (in *RedisGatewayStatus) DeepCopy() *RedisGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisPositionsStore) DeepCopyInto(out *RedisPositionsStore) {
+	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisPositionsStore.
+func (in *RedisPositionsStore) DeepCopy() *RedisPositionsStore {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisPositionsStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Scale) DeepCopyInto(out *Scale) {
+	*out = *in
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PollingInterval != nil {
+		in, out := &in.PollingInterval, &out.PollingInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CooldownPeriod != nil {
+		in, out := &in.CooldownPeriod, &out.CooldownPeriod
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Scale.
+func (in *Scale) DeepCopy() *Scale {
+	if in == nil {
+		return nil
+	}
+	out := new(Scale)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Sink) DeepCopyInto(out *Sink) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sink.
+func (in *Sink) DeepCopy() *Sink {
+	if in == nil {
+		return nil
+	}
+	out := new(Sink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Sink) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SinkList) DeepCopyInto(out *SinkList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Sink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SinkList.
+func (in *SinkList) DeepCopy() *SinkList {
+	if in == nil {
+		return nil
+	}
+	out := new(SinkList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SinkList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SinkRetry) DeepCopyInto(out *SinkRetry) {
+	*out = *in
+	if in.InitialBackoff != nil {
+		in, out := &in.InitialBackoff, &out.InitialBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SinkRetry.
+func (in *SinkRetry) DeepCopy() *SinkRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(SinkRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SinkSpec) DeepCopyInto(out *SinkSpec) {
+	*out = *in
+	out.Target = in.Target
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(SinkRetry)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SinkSpec.
+func (in *SinkSpec) DeepCopy() *SinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SinkStatus) DeepCopyInto(out *SinkStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.DeploymentRef != nil {
+		in, out := &in.DeploymentRef, &out.DeploymentRef
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SinkStatus.
+func (in *SinkStatus) DeepCopy() *SinkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SinkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SinkTarget) DeepCopyInto(out *SinkTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SinkTarget.
+func (in *SinkTarget) DeepCopy() *SinkTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(SinkTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SplitOperation) DeepCopyInto(out *SplitOperation) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]SplitRoute, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplitOperation.
+func (in *SplitOperation) DeepCopy() *SplitOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(SplitOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SplitRoute) DeepCopyInto(out *SplitRoute) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplitRoute.
+func (in *SplitRoute) DeepCopy() *SplitRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(SplitRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateChangelog) DeepCopyInto(out *StateChangelog) {
+	*out = *in
+	out.Gateway = in.Gateway
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateChangelog.
+func (in *StateChangelog) DeepCopy() *StateChangelog {
+	if in == nil {
+		return nil
+	}
+	out := new(StateChangelog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateVolume) DeepCopyInto(out *StateVolume) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateVolume.
+func (in *StateVolume) DeepCopy() *StateVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(StateVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Stream) DeepCopyInto(out *Stream) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Stream.
+func (in *Stream) DeepCopy() *Stream {
+	if in == nil {
+		return nil
+	}
+	out := new(Stream)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Stream) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamGrant) DeepCopyInto(out *StreamGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamGrant.
+func (in *StreamGrant) DeepCopy() *StreamGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StreamGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamGrantList) DeepCopyInto(out *StreamGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StreamGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamGrantList.
+func (in *StreamGrantList) DeepCopy() *StreamGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StreamGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamGrantSpec) DeepCopyInto(out *StreamGrantSpec) {
+	*out = *in
+	if in.Streams != nil {
+		in, out := &in.Streams, &out.Streams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamGrantSpec.
+func (in *StreamGrantSpec) DeepCopy() *StreamGrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamList) DeepCopyInto(out *StreamList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Stream, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamList.
+func (in *StreamList) DeepCopy() *StreamList {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StreamList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamMigration) DeepCopyInto(out *StreamMigration) {
+	*out = *in
+	if in.JobRef != nil {
+		in, out := &in.JobRef, &out.JobRef
+		*out = (*in).DeepCopy()
+	}
+	if in.CopyCompletionTime != nil {
+		in, out := &in.CopyCompletionTime, &out.CopyCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DrainedTime != nil {
+		in, out := &in.DrainedTime, &out.DrainedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FinalCopyCompletionTime != nil {
+		in, out := &in.FinalCopyCompletionTime, &out.FinalCopyCompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamMigration.
+func (in *StreamMigration) DeepCopy() *StreamMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamMigrationPolicy) DeepCopyInto(out *StreamMigrationPolicy) {
+	*out = *in
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(bool)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InputStreamBinding.
-func (in *InputStreamBinding) DeepCopy() *InputStreamBinding {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamMigrationPolicy.
+func (in *StreamMigrationPolicy) DeepCopy() *StreamMigrationPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(InputStreamBinding)
+	out := new(StreamMigrationPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KafkaGateway) DeepCopyInto(out *KafkaGateway) {
+func (in *StreamMirror) DeepCopyInto(out *StreamMirror) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaGateway.
-func (in *KafkaGateway) DeepCopy() *KafkaGateway {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamMirror.
+func (in *StreamMirror) DeepCopy() *StreamMirror {
 	if in == nil {
 		return nil
 	}
-	out := new(KafkaGateway)
+	out := new(StreamMirror)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KafkaGateway) DeepCopyObject() runtime.Object {
+func (in *StreamMirror) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -314,31 +2230,58 @@ func (in *KafkaGateway) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KafkaGatewayList) DeepCopyInto(out *KafkaGatewayList) {
+func (in *StreamMirrorFilter) DeepCopyInto(out *StreamMirrorFilter) {
+	*out = *in
+	if in.ContentTypes != nil {
+		in, out := &in.ContentTypes, &out.ContentTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamMirrorFilter.
+func (in *StreamMirrorFilter) DeepCopy() *StreamMirrorFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamMirrorFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamMirrorList) DeepCopyInto(out *StreamMirrorList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]KafkaGateway, len(*in))
+		*out = make([]StreamMirror, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaGatewayList.
-func (in *KafkaGatewayList) DeepCopy() *KafkaGatewayList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamMirrorList.
+func (in *StreamMirrorList) DeepCopy() *StreamMirrorList {
 	if in == nil {
 		return nil
 	}
-	out := new(KafkaGatewayList)
+	out := new(StreamMirrorList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KafkaGatewayList) DeepCopyObject() runtime.Object {
+func (in *StreamMirrorList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -346,62 +2289,80 @@ func (in *KafkaGatewayList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KafkaGatewaySpec) DeepCopyInto(out *KafkaGatewaySpec) {
+func (in *StreamMirrorPartition) DeepCopyInto(out *StreamMirrorPartition) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaGatewaySpec.
-func (in *KafkaGatewaySpec) DeepCopy() *KafkaGatewaySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamMirrorPartition.
+func (in *StreamMirrorPartition) DeepCopy() *StreamMirrorPartition {
 	if in == nil {
 		return nil
 	}
-	out := new(KafkaGatewaySpec)
+	out := new(StreamMirrorPartition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KafkaGatewayStatus) DeepCopyInto(out *KafkaGatewayStatus) {
+func (in *StreamMirrorSpec) DeepCopyInto(out *StreamMirrorSpec) {
 	*out = *in
-	in.Status.DeepCopyInto(&out.Status)
-	if in.Address != nil {
-		in, out := &in.Address, &out.Address
-		*out = new(apis.Addressable)
-		**out = **in
-	}
-	if in.GatewayRef != nil {
-		in, out := &in.GatewayRef, &out.GatewayRef
-		*out = (*in).DeepCopy()
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(StreamMirrorFilter)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaGatewayStatus.
-func (in *KafkaGatewayStatus) DeepCopy() *KafkaGatewayStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamMirrorSpec.
+func (in *StreamMirrorSpec) DeepCopy() *StreamMirrorSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KafkaGatewayStatus)
+	out := new(StreamMirrorSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OutputStreamBinding) DeepCopyInto(out *OutputStreamBinding) {
+func (in *StreamMirrorStatus) DeepCopyInto(out *StreamMirrorStatus) {
 	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.DeploymentRef != nil {
+		in, out := &in.DeploymentRef, &out.DeploymentRef
+		*out = (*in).DeepCopy()
+	}
+	if in.JobRef != nil {
+		in, out := &in.JobRef, &out.JobRef
+		*out = (*in).DeepCopy()
+	}
+	if in.Partitions != nil {
+		in, out := &in.Partitions, &out.Partitions
+		*out = make([]StreamMirrorPartition, len(*in))
+		copy(*out, *in)
+	}
+	if in.Lag != nil {
+		in, out := &in.Lag, &out.Lag
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputStreamBinding.
-func (in *OutputStreamBinding) DeepCopy() *OutputStreamBinding {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamMirrorStatus.
+func (in *StreamMirrorStatus) DeepCopy() *StreamMirrorStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OutputStreamBinding)
+	out := new(StreamMirrorStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Processor) DeepCopyInto(out *Processor) {
+func (in *StreamQuota) DeepCopyInto(out *StreamQuota) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -409,18 +2370,18 @@ func (in *Processor) DeepCopyInto(out *Processor) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Processor.
-func (in *Processor) DeepCopy() *Processor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamQuota.
+func (in *StreamQuota) DeepCopy() *StreamQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(Processor)
+	out := new(StreamQuota)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Processor) DeepCopyObject() runtime.Object {
+func (in *StreamQuota) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -428,31 +2389,31 @@ func (in *Processor) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProcessorList) DeepCopyInto(out *ProcessorList) {
+func (in *StreamQuotaList) DeepCopyInto(out *StreamQuotaList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Processor, len(*in))
+		*out = make([]StreamQuota, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessorList.
-func (in *ProcessorList) DeepCopy() *ProcessorList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamQuotaList.
+func (in *StreamQuotaList) DeepCopy() *StreamQuotaList {
 	if in == nil {
 		return nil
 	}
-	out := new(ProcessorList)
+	out := new(StreamQuotaList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProcessorList) DeepCopyObject() runtime.Object {
+func (in *StreamQuotaList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -460,184 +2421,167 @@ func (in *ProcessorList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProcessorSpec) DeepCopyInto(out *ProcessorSpec) {
+func (in *StreamQuotaSpec) DeepCopyInto(out *StreamQuotaSpec) {
 	*out = *in
-	if in.Build != nil {
-		in, out := &in.Build, &out.Build
-		*out = new(Build)
+	if in.MaxStreams != nil {
+		in, out := &in.MaxStreams, &out.MaxStreams
+		*out = new(int32)
 		**out = **in
 	}
-	if in.Inputs != nil {
-		in, out := &in.Inputs, &out.Inputs
-		*out = make([]InputStreamBinding, len(*in))
-		copy(*out, *in)
+	if in.MaxPartitions != nil {
+		in, out := &in.MaxPartitions, &out.MaxPartitions
+		*out = new(int32)
+		**out = **in
 	}
-	if in.Outputs != nil {
-		in, out := &in.Outputs, &out.Outputs
-		*out = make([]OutputStreamBinding, len(*in))
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Template != nil {
-		in, out := &in.Template, &out.Template
-		*out = new(v1.PodTemplateSpec)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessorSpec.
-func (in *ProcessorSpec) DeepCopy() *ProcessorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamQuotaSpec.
+func (in *StreamQuotaSpec) DeepCopy() *StreamQuotaSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ProcessorSpec)
+	out := new(StreamQuotaSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProcessorStatus) DeepCopyInto(out *ProcessorStatus) {
+func (in *StreamQuotaStatus) DeepCopyInto(out *StreamQuotaStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
-	if in.DeploymentRef != nil {
-		in, out := &in.DeploymentRef, &out.DeploymentRef
-		*out = (*in).DeepCopy()
-	}
-	if in.ScaledObjectRef != nil {
-		in, out := &in.ScaledObjectRef, &out.ScaledObjectRef
-		*out = (*in).DeepCopy()
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessorStatus.
-func (in *ProcessorStatus) DeepCopy() *ProcessorStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamQuotaStatus.
+func (in *StreamQuotaStatus) DeepCopy() *StreamQuotaStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ProcessorStatus)
+	out := new(StreamQuotaStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PulsarGateway) DeepCopyInto(out *PulsarGateway) {
+func (in *StreamQuotaUsage) DeepCopyInto(out *StreamQuotaUsage) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarGateway.
-func (in *PulsarGateway) DeepCopy() *PulsarGateway {
-	if in == nil {
-		return nil
-	}
-	out := new(PulsarGateway)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PulsarGateway) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+	if in.MaxStreams != nil {
+		in, out := &in.MaxStreams, &out.MaxStreams
+		*out = new(int32)
+		**out = **in
 	}
-	return nil
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PulsarGatewayList) DeepCopyInto(out *PulsarGatewayList) {
-	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]PulsarGateway, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.MaxPartitions != nil {
+		in, out := &in.MaxPartitions, &out.MaxPartitions
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarGatewayList.
-func (in *PulsarGatewayList) DeepCopy() *PulsarGatewayList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamQuotaUsage.
+func (in *StreamQuotaUsage) DeepCopy() *StreamQuotaUsage {
 	if in == nil {
 		return nil
 	}
-	out := new(PulsarGatewayList)
+	out := new(StreamQuotaUsage)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PulsarGatewayList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PulsarGatewaySpec) DeepCopyInto(out *PulsarGatewaySpec) {
+func (in *StreamSpec) DeepCopyInto(out *StreamSpec) {
 	*out = *in
+	out.Gateway = in.Gateway
+	if in.Partitions != nil {
+		in, out := &in.Partitions, &out.Partitions
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(StreamMigrationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarGatewaySpec.
-func (in *PulsarGatewaySpec) DeepCopy() *PulsarGatewaySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamSpec.
+func (in *StreamSpec) DeepCopy() *StreamSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PulsarGatewaySpec)
+	out := new(StreamSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PulsarGatewayStatus) DeepCopyInto(out *PulsarGatewayStatus) {
+func (in *StreamStatus) DeepCopyInto(out *StreamStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
-	if in.Address != nil {
-		in, out := &in.Address, &out.Address
-		*out = new(apis.Addressable)
+	out.Binding = in.Binding
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(StreamMigration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Consumers != nil {
+		in, out := &in.Consumers, &out.Consumers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Producers != nil {
+		in, out := &in.Producers, &out.Producers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Partitions != nil {
+		in, out := &in.Partitions, &out.Partitions
+		*out = new(int32)
 		**out = **in
 	}
-	if in.GatewayRef != nil {
-		in, out := &in.GatewayRef, &out.GatewayRef
-		*out = (*in).DeepCopy()
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = make([]StreamQuotaUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarGatewayStatus.
-func (in *PulsarGatewayStatus) DeepCopy() *PulsarGatewayStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamStatus.
+func (in *StreamStatus) DeepCopy() *StreamStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PulsarGatewayStatus)
+	out := new(StreamStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Stream) DeepCopyInto(out *Stream) {
+func (in *StreamTap) DeepCopyInto(out *StreamTap) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Stream.
-func (in *Stream) DeepCopy() *Stream {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamTap.
+func (in *StreamTap) DeepCopy() *StreamTap {
 	if in == nil {
 		return nil
 	}
-	out := new(Stream)
+	out := new(StreamTap)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Stream) DeepCopyObject() runtime.Object {
+func (in *StreamTap) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -645,31 +2589,31 @@ func (in *Stream) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StreamList) DeepCopyInto(out *StreamList) {
+func (in *StreamTapList) DeepCopyInto(out *StreamTapList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Stream, len(*in))
+		*out = make([]StreamTap, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamList.
-func (in *StreamList) DeepCopy() *StreamList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamTapList.
+func (in *StreamTapList) DeepCopy() *StreamTapList {
 	if in == nil {
 		return nil
 	}
-	out := new(StreamList)
+	out := new(StreamTapList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *StreamList) DeepCopyObject() runtime.Object {
+func (in *StreamTapList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -677,34 +2621,96 @@ func (in *StreamList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StreamSpec) DeepCopyInto(out *StreamSpec) {
+func (in *StreamTapSpec) DeepCopyInto(out *StreamTapSpec) {
 	*out = *in
-	out.Gateway = in.Gateway
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamSpec.
-func (in *StreamSpec) DeepCopy() *StreamSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamTapSpec.
+func (in *StreamTapSpec) DeepCopy() *StreamTapSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(StreamSpec)
+	out := new(StreamTapSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StreamStatus) DeepCopyInto(out *StreamStatus) {
+func (in *StreamTapStatus) DeepCopyInto(out *StreamTapStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
-	out.Binding = in.Binding
+	if in.DeploymentRef != nil {
+		in, out := &in.DeploymentRef, &out.DeploymentRef
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamStatus.
-func (in *StreamStatus) DeepCopy() *StreamStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamTapStatus.
+func (in *StreamTapStatus) DeepCopy() *StreamTapStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(StreamStatus)
+	out := new(StreamTapStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Window) DeepCopyInto(out *Window) {
+	*out = *in
+	in.Size.DeepCopyInto(&out.Size)
+	if in.Slide != nil {
+		in, out := &in.Slide, &out.Slide
+		*out = new(WindowExtent)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Window.
+func (in *Window) DeepCopy() *Window {
+	if in == nil {
+		return nil
+	}
+	out := new(Window)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WindowExtent) DeepCopyInto(out *WindowExtent) {
+	*out = *in
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WindowExtent.
+func (in *WindowExtent) DeepCopy() *WindowExtent {
+	if in == nil {
+		return nil
+	}
+	out := new(WindowExtent)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -28,10 +28,18 @@ type FakeStreamingV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeStreamingV1alpha1) CronSources(namespace string) v1alpha1.CronSourceInterface {
+	return &FakeCronSources{c, namespace}
+}
+
 func (c *FakeStreamingV1alpha1) Gateways(namespace string) v1alpha1.GatewayInterface {
 	return &FakeGateways{c, namespace}
 }
 
+func (c *FakeStreamingV1alpha1) HTTPSources(namespace string) v1alpha1.HTTPSourceInterface {
+	return &FakeHTTPSources{c, namespace}
+}
+
 func (c *FakeStreamingV1alpha1) InMemoryGateways(namespace string) v1alpha1.InMemoryGatewayInterface {
 	return &FakeInMemoryGateways{c, namespace}
 }
@@ -40,6 +48,14 @@ func (c *FakeStreamingV1alpha1) KafkaGateways(namespace string) v1alpha1.KafkaGa
 	return &FakeKafkaGateways{c, namespace}
 }
 
+func (c *FakeStreamingV1alpha1) NATSGateways(namespace string) v1alpha1.NATSGatewayInterface {
+	return &FakeNATSGateways{c, namespace}
+}
+
+func (c *FakeStreamingV1alpha1) Pipelines(namespace string) v1alpha1.PipelineInterface {
+	return &FakePipelines{c, namespace}
+}
+
 func (c *FakeStreamingV1alpha1) Processors(namespace string) v1alpha1.ProcessorInterface {
 	return &FakeProcessors{c, namespace}
 }
@@ -48,6 +64,30 @@ func (c *FakeStreamingV1alpha1) PulsarGateways(namespace string) v1alpha1.Pulsar
 	return &FakePulsarGateways{c, namespace}
 }
 
+func (c *FakeStreamingV1alpha1) RedisGateways(namespace string) v1alpha1.RedisGatewayInterface {
+	return &FakeRedisGateways{c, namespace}
+}
+
+func (c *FakeStreamingV1alpha1) Sinks(namespace string) v1alpha1.SinkInterface {
+	return &FakeSinks{c, namespace}
+}
+
+func (c *FakeStreamingV1alpha1) StreamGrants(namespace string) v1alpha1.StreamGrantInterface {
+	return &FakeStreamGrants{c, namespace}
+}
+
+func (c *FakeStreamingV1alpha1) StreamMirrors(namespace string) v1alpha1.StreamMirrorInterface {
+	return &FakeStreamMirrors{c, namespace}
+}
+
+func (c *FakeStreamingV1alpha1) StreamQuotas(namespace string) v1alpha1.StreamQuotaInterface {
+	return &FakeStreamQuotas{c, namespace}
+}
+
+func (c *FakeStreamingV1alpha1) StreamTaps(namespace string) v1alpha1.StreamTapInterface {
+	return &FakeStreamTaps{c, namespace}
+}
+
 func (c *FakeStreamingV1alpha1) Streams(namespace string) v1alpha1.StreamInterface {
 	return &FakeStreams{c, namespace}
 }
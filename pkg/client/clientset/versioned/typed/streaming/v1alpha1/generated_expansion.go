@@ -17,14 +17,34 @@ limitations under the License.
 
 package v1alpha1
 
+type CronSourceExpansion interface{}
+
 type GatewayExpansion interface{}
 
+type HTTPSourceExpansion interface{}
+
 type InMemoryGatewayExpansion interface{}
 
 type KafkaGatewayExpansion interface{}
 
+type NATSGatewayExpansion interface{}
+
+type PipelineExpansion interface{}
+
 type ProcessorExpansion interface{}
 
 type PulsarGatewayExpansion interface{}
 
+type RedisGatewayExpansion interface{}
+
+type SinkExpansion interface{}
+
 type StreamExpansion interface{}
+
+type StreamGrantExpansion interface{}
+
+type StreamMirrorExpansion interface{}
+
+type StreamQuotaExpansion interface{}
+
+type StreamTapExpansion interface{}
@@ -26,11 +26,21 @@ import (
 
 type StreamingV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	CronSourcesGetter
 	GatewaysGetter
+	HTTPSourcesGetter
 	InMemoryGatewaysGetter
 	KafkaGatewaysGetter
+	NATSGatewaysGetter
+	PipelinesGetter
 	ProcessorsGetter
 	PulsarGatewaysGetter
+	RedisGatewaysGetter
+	SinksGetter
+	StreamGrantsGetter
+	StreamMirrorsGetter
+	StreamQuotasGetter
+	StreamTapsGetter
 	StreamsGetter
 }
 
@@ -39,10 +49,18 @@ type StreamingV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *StreamingV1alpha1Client) CronSources(namespace string) CronSourceInterface {
+	return newCronSources(c, namespace)
+}
+
 func (c *StreamingV1alpha1Client) Gateways(namespace string) GatewayInterface {
 	return newGateways(c, namespace)
 }
 
+func (c *StreamingV1alpha1Client) HTTPSources(namespace string) HTTPSourceInterface {
+	return newHTTPSources(c, namespace)
+}
+
 func (c *StreamingV1alpha1Client) InMemoryGateways(namespace string) InMemoryGatewayInterface {
 	return newInMemoryGateways(c, namespace)
 }
@@ -51,6 +69,14 @@ func (c *StreamingV1alpha1Client) KafkaGateways(namespace string) KafkaGatewayIn
 	return newKafkaGateways(c, namespace)
 }
 
+func (c *StreamingV1alpha1Client) NATSGateways(namespace string) NATSGatewayInterface {
+	return newNATSGateways(c, namespace)
+}
+
+func (c *StreamingV1alpha1Client) Pipelines(namespace string) PipelineInterface {
+	return newPipelines(c, namespace)
+}
+
 func (c *StreamingV1alpha1Client) Processors(namespace string) ProcessorInterface {
 	return newProcessors(c, namespace)
 }
@@ -59,6 +85,30 @@ func (c *StreamingV1alpha1Client) PulsarGateways(namespace string) PulsarGateway
 	return newPulsarGateways(c, namespace)
 }
 
+func (c *StreamingV1alpha1Client) RedisGateways(namespace string) RedisGatewayInterface {
+	return newRedisGateways(c, namespace)
+}
+
+func (c *StreamingV1alpha1Client) Sinks(namespace string) SinkInterface {
+	return newSinks(c, namespace)
+}
+
+func (c *StreamingV1alpha1Client) StreamGrants(namespace string) StreamGrantInterface {
+	return newStreamGrants(c, namespace)
+}
+
+func (c *StreamingV1alpha1Client) StreamMirrors(namespace string) StreamMirrorInterface {
+	return newStreamMirrors(c, namespace)
+}
+
+func (c *StreamingV1alpha1Client) StreamQuotas(namespace string) StreamQuotaInterface {
+	return newStreamQuotas(c, namespace)
+}
+
+func (c *StreamingV1alpha1Client) StreamTaps(namespace string) StreamTapInterface {
+	return newStreamTaps(c, namespace)
+}
+
 func (c *StreamingV1alpha1Client) Streams(namespace string) StreamInterface {
 	return newStreams(c, namespace)
 }
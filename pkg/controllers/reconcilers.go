@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 
 	"github.com/go-logr/logr"
@@ -133,15 +134,31 @@ func (r *ParentReconciler) reconcile(ctx context.Context, parent apis.Object) (c
 		return ctrl.Result{}, nil
 	}
 
+	aggregateResult := ctrl.Result{}
 	for _, reconciler := range r.SubReconcilers {
-		if _, err := reconciler.Reconcile(ctx, parent); err != nil {
+		result, err := reconciler.Reconcile(ctx, parent)
+		if err != nil {
 			return ctrl.Result{}, err
 		}
+		aggregateResult = aggregateResults(aggregateResult, result)
 	}
 
 	r.copyGeneration(parent)
 
-	return ctrl.Result{}, nil
+	return aggregateResult, nil
+}
+
+// aggregateResults combines the results of sub reconcilers, requeueing at the
+// soonest time any sub reconciler asked for
+func aggregateResults(a, b ctrl.Result) ctrl.Result {
+	result := ctrl.Result{
+		Requeue:      a.Requeue || b.Requeue,
+		RequeueAfter: a.RequeueAfter,
+	}
+	if b.RequeueAfter != 0 && (result.RequeueAfter == 0 || b.RequeueAfter < result.RequeueAfter) {
+		result.RequeueAfter = b.RequeueAfter
+	}
+	return result
 }
 
 func (r *ParentReconciler) copyGeneration(obj apis.Object) {
@@ -181,39 +198,83 @@ type SyncReconciler struct {
 	//
 	// Expected function signature:
 	//     func(ctx context.Context, parent apis.Object) error
+	//     func(ctx context.Context, parent apis.Object) (ctrl.Result, error)
 	Sync interface{}
 
 	Config
 }
 
 func (r *SyncReconciler) SetupWithManager(mgr ctrl.Manager, bldr *builder.Builder) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
 	if r.Setup == nil {
 		return nil
 	}
 	return r.Setup(mgr, bldr)
 }
 
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	resultType  = reflect.TypeOf(ctrl.Result{})
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// validate checks the Sync function signature, an unexpected signature would
+// otherwise panic when called
+func (r *SyncReconciler) validate() error {
+	fn := reflect.TypeOf(r.Sync)
+	if fn == nil || fn.Kind() != reflect.Func {
+		return fmt.Errorf("SyncReconciler Sync must be a function, found %v", fn)
+	}
+	if fn.NumIn() != 2 || fn.In(0) != contextType {
+		return fmt.Errorf("SyncReconciler Sync must accept a context and the parent, found %v", fn)
+	}
+	switch {
+	case fn.NumOut() == 1 && fn.Out(0) == errorType:
+	case fn.NumOut() == 2 && fn.Out(0) == resultType && fn.Out(1) == errorType:
+	default:
+		return fmt.Errorf("SyncReconciler Sync must return an error, or a ctrl.Result and an error, found %v", fn)
+	}
+	return nil
+}
+
 func (r *SyncReconciler) Reconcile(ctx context.Context, parent apis.Object) (ctrl.Result, error) {
-	err := r.sync(ctx, parent)
+	if err := r.validate(); err != nil {
+		r.Log.Error(err, "invalid sync", typeName(parent), parent)
+		return ctrl.Result{}, err
+	}
+	if in := reflect.TypeOf(r.Sync).In(1); !reflect.TypeOf(parent).AssignableTo(in) {
+		err := fmt.Errorf("SyncReconciler Sync accepts %v, found parent %T", in, parent)
+		r.Log.Error(err, "invalid sync", typeName(parent), parent)
+		return ctrl.Result{}, err
+	}
+	result, err := r.sync(ctx, parent)
 	if err != nil {
 		r.Log.Error(err, "unable to sync", typeName(parent), parent)
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
-func (r *SyncReconciler) sync(ctx context.Context, parent apis.Object) error {
+func (r *SyncReconciler) sync(ctx context.Context, parent apis.Object) (ctrl.Result, error) {
 	fn := reflect.ValueOf(r.Sync)
 	out := fn.Call([]reflect.Value{
 		reflect.ValueOf(ctx),
 		reflect.ValueOf(parent),
 	})
+	result := ctrl.Result{}
+	errOut := out[0]
+	if len(out) == 2 {
+		result = out[0].Interface().(ctrl.Result)
+		errOut = out[1]
+	}
 	var err error
-	if !out[0].IsNil() {
-		err = out[0].Interface().(error)
+	if !errOut.IsNil() {
+		err = errOut.Interface().(error)
 	}
-	return err
+	return result, err
 }
 
 // ChildReconciler is a sub reconciler that manages a single child resource for
@@ -292,6 +353,17 @@ type ChildReconciler struct {
 	//     func(a1, a2 apis.Object) bool
 	SemanticEquals interface{}
 
+	// OurChild filters the children of the parent to those managed by this
+	// reconciler. It is required when more than one ChildReconciler manages
+	// children of the same ChildType for a parent, otherwise each reconciler
+	// would remove the others' children as extras.
+	//
+	// Expected function signature:
+	//     func(child apis.Object) bool
+	//
+	// +optional
+	OurChild interface{}
+
 	// Sanitize is called with an object before logging the value. Any value may
 	// be returned. A meaningful subset of the resource is typically returned,
 	// like the Spec.
@@ -355,7 +427,7 @@ func (r *ChildReconciler) reconcile(ctx context.Context, parent apis.Object) (ap
 		return nil, err
 	}
 	// TODO do we need to remove resources pending deletion?
-	items := r.items(children)
+	items := r.items(parent, children)
 	if len(items) == 1 {
 		actual = items[0]
 	} else if len(items) > 1 {
@@ -521,12 +593,27 @@ func (r *ChildReconciler) sanitize(child apis.Object) interface{} {
 	return sanitized
 }
 
-func (r *ChildReconciler) items(children runtime.Object) []apis.Object {
+func (r *ChildReconciler) ourChild(child apis.Object) bool {
+	if r.OurChild == nil {
+		return true
+	}
+	fn := reflect.ValueOf(r.OurChild)
+	out := fn.Call([]reflect.Value{
+		reflect.ValueOf(child),
+	})
+	return out[0].Bool()
+}
+
+func (r *ChildReconciler) items(parent apis.Object, children runtime.Object) []apis.Object {
 	childrenValue := reflect.ValueOf(children).Elem()
 	itemsValue := childrenValue.FieldByName("Items")
-	items := make([]apis.Object, itemsValue.Len())
-	for i := range items {
-		items[i] = itemsValue.Index(i).Addr().Interface().(apis.Object)
+	items := []apis.Object{}
+	for i := 0; i < itemsValue.Len(); i++ {
+		item := itemsValue.Index(i).Addr().Interface().(apis.Object)
+		// the index is keyed by name, only consider children this parent controls
+		if metav1.IsControlledBy(item, parent) && r.ourChild(item) {
+			items = append(items, item)
+		}
 	}
 	return items
 }
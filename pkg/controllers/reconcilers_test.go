@@ -0,0 +1,524 @@
+/*
+Copyright 2020 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/controllers/testing/factories"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+const testChildLabelKey = "testing.projectriff.io/child"
+
+// testChildReconciler manages a ConfigMap for the parent Gateway, named and
+// labeled for the reconciler. The child is unwanted while the parent is
+// labeled with the reconciler's name.
+func testChildReconciler(c controllers.Config, name string) *controllers.ChildReconciler {
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.Gateway{},
+		ChildType:     &corev1.ConfigMap{},
+		ChildListType: &corev1.ConfigMapList{},
+
+		DesiredChild: func(parent *streamingv1alpha1.Gateway) (*corev1.ConfigMap, error) {
+			if parent.Labels[testChildLabelKey] == name {
+				return nil, nil
+			}
+			return &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: parent.Namespace,
+					Name:      fmt.Sprintf("%s-%s", parent.Name, name),
+					Labels: map[string]string{
+						testChildLabelKey: name,
+					},
+				},
+				Data: map[string]string{
+					"child": name,
+				},
+			}, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.Gateway, child *corev1.ConfigMap, err error) {},
+		MergeBeforeUpdate: func(current, desired *corev1.ConfigMap) {
+			current.Labels = desired.Labels
+			current.Data = desired.Data
+		},
+		SemanticEquals: func(a1, a2 *corev1.ConfigMap) bool {
+			return equality.Semantic.DeepEqual(a1.Data, a2.Data) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+		OurChild: func(child *corev1.ConfigMap) bool {
+			return child.Labels[testChildLabelKey] == name
+		},
+
+		Config:     c,
+		IndexField: fmt.Sprintf(".metadata.%sController", name),
+	}
+}
+
+func TestChildReconciler(t *testing.T) {
+	testNamespace := "test-namespace"
+	testName := "test-gateway"
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	parent := factories.Gateway().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.UID("parent-uid")
+		})
+	otherParent := factories.Gateway().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.UID("other-parent-uid")
+		})
+
+	childCreate := factories.ConfigMap().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.Name("%s-a", testName)
+			om.AddLabel(testChildLabelKey, "a")
+			om.ControlledBy(parent, scheme)
+		}).
+		AddData("child", "a")
+	childGiven := childCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+		})
+
+	table := rtesting.SubTable{{
+		Name:   "create child",
+		Parent: parent,
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(parent, scheme, corev1.EventTypeNormal, "Created",
+				`Created ConfigMap "%s-a"`, testName),
+		},
+		ExpectCreates: []rtesting.Factory{
+			childCreate,
+		},
+	}, {
+		Name:   "child unchanged",
+		Parent: parent,
+		GivenObjects: []rtesting.Factory{
+			childGiven,
+		},
+	}, {
+		Name:   "update child",
+		Parent: parent,
+		GivenObjects: []rtesting.Factory{
+			childGiven.
+				AddData("child", "stale"),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(parent, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated ConfigMap "%s-a"`, testName),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			childGiven,
+		},
+	}, {
+		Name: "delete unwanted child",
+		Parent: parent.
+			ObjectMeta(func(om factories.ObjectMeta) {
+				om.AddLabel(testChildLabelKey, "a")
+			}),
+		GivenObjects: []rtesting.Factory{
+			childGiven,
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(parent, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted ConfigMap "%s-a"`, testName),
+		},
+		ExpectDeletes: []rtesting.DeleteRef{
+			{Kind: "ConfigMap", Namespace: testNamespace, Name: fmt.Sprintf("%s-a", testName)},
+		},
+	}, {
+		Name:   "delete extra children",
+		Parent: parent,
+		GivenObjects: []rtesting.Factory{
+			childGiven,
+			childGiven.
+				NamespaceName(testNamespace, "extra"),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(parent, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted ConfigMap "%s-a"`, testName),
+			rtesting.NewEvent(parent, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted ConfigMap "extra"`),
+			rtesting.NewEvent(parent, scheme, corev1.EventTypeNormal, "Created",
+				`Created ConfigMap "%s-a"`, testName),
+		},
+		ExpectDeletes: []rtesting.DeleteRef{
+			{Kind: "ConfigMap", Namespace: testNamespace, Name: fmt.Sprintf("%s-a", testName)},
+			{Kind: "ConfigMap", Namespace: testNamespace, Name: "extra"},
+		},
+		ExpectCreates: []rtesting.Factory{
+			childCreate,
+		},
+	}, {
+		Name:   "ignore children controlled by another parent",
+		Parent: parent,
+		GivenObjects: []rtesting.Factory{
+			childGiven,
+			childGiven.
+				NamespaceName(testNamespace, "other").
+				ObjectMeta(func(om factories.ObjectMeta) {
+					om.ControlledBy(otherParent, scheme)
+				}),
+		},
+	}, {
+		Name:   "ignore children of another reconciler",
+		Parent: parent,
+		GivenObjects: []rtesting.Factory{
+			childGiven,
+			childGiven.
+				NamespaceName(testNamespace, fmt.Sprintf("%s-b", testName)).
+				ObjectMeta(func(om factories.ObjectMeta) {
+					om.AddLabel(testChildLabelKey, "b")
+				}).
+				AddData("child", "b"),
+		},
+	}}
+
+	table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
+		return testChildReconciler(
+			controllers.Config{
+				Client:    client,
+				APIReader: client,
+				Recorder:  recorder,
+				Log:       log,
+				Scheme:    scheme,
+				Tracker:   tracker,
+			},
+			"a",
+		)
+	})
+}
+
+func TestChildReconciler_Siblings(t *testing.T) {
+	testNamespace := "test-namespace"
+	testName := "test-gateway"
+	testKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
+
+	gatewayConditionDeploymentReady := factories.Condition().Type(streamingv1alpha1.GatewayConditionDeploymentReady)
+	gatewayConditionReady := factories.Condition().Type(streamingv1alpha1.GatewayConditionReady)
+	gatewayConditionServiceReady := factories.Condition().Type(streamingv1alpha1.GatewayConditionServiceReady)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	parent := factories.Gateway().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Generation(1)
+		}).
+		StatusObservedGeneration(1).
+		StatusConditions(
+			gatewayConditionDeploymentReady.Unknown(),
+			gatewayConditionReady.Unknown(),
+			gatewayConditionServiceReady.Unknown(),
+		)
+
+	childACreate := factories.ConfigMap().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.Name("%s-a", testName)
+			om.AddLabel(testChildLabelKey, "a")
+			om.ControlledBy(parent, scheme)
+		}).
+		AddData("child", "a")
+	childAGiven := childACreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+		})
+	childBCreate := factories.ConfigMap().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.Name("%s-b", testName)
+			om.AddLabel(testChildLabelKey, "b")
+			om.ControlledBy(parent, scheme)
+		}).
+		AddData("child", "b")
+	childBGiven := childBCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+		})
+
+	table := rtesting.Table{{
+		Name: "create children",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			parent,
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(parent, scheme, corev1.EventTypeNormal, "Created",
+				`Created ConfigMap "%s-a"`, testName),
+			rtesting.NewEvent(parent, scheme, corev1.EventTypeNormal, "Created",
+				`Created ConfigMap "%s-b"`, testName),
+		},
+		ExpectCreates: []rtesting.Factory{
+			childACreate,
+			childBCreate,
+		},
+	}, {
+		Name: "children unchanged",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			parent,
+			childAGiven,
+			childBGiven,
+		},
+	}, {
+		Name: "delete one unwanted child",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			parent.
+				ObjectMeta(func(om factories.ObjectMeta) {
+					om.AddLabel(testChildLabelKey, "a")
+				}),
+			childAGiven,
+			childBGiven,
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(parent, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted ConfigMap "%s-a"`, testName),
+		},
+		ExpectDeletes: []rtesting.DeleteRef{
+			{Kind: "ConfigMap", Namespace: testNamespace, Name: fmt.Sprintf("%s-a", testName)},
+		},
+	}}
+
+	table.Test(t, scheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
+		c := controllers.Config{
+			Client:    client,
+			APIReader: apiReader,
+			Recorder:  recorder,
+			Log:       log,
+			Scheme:    scheme,
+			Tracker:   tracker,
+		}
+		return &controllers.ParentReconciler{
+			Type: &streamingv1alpha1.Gateway{},
+			SubReconcilers: []controllers.SubReconciler{
+				testChildReconciler(c, "a"),
+				testChildReconciler(c, "b"),
+			},
+
+			Config: c,
+		}
+	})
+}
+
+func TestSyncReconciler(t *testing.T) {
+	testNamespace := "test-namespace"
+	testName := "test-gateway"
+
+	parent := factories.Gateway().
+		NamespaceName(testNamespace, testName)
+
+	tests := []struct {
+		name           string
+		sync           interface{}
+		expectedResult ctrl.Result
+		shouldErr      bool
+	}{{
+		name: "sync",
+		sync: func(ctx context.Context, parent *streamingv1alpha1.Gateway) error {
+			return nil
+		},
+	}, {
+		name: "sync error",
+		sync: func(ctx context.Context, parent *streamingv1alpha1.Gateway) error {
+			return fmt.Errorf("sync failed")
+		},
+		shouldErr: true,
+	}, {
+		name: "sync result",
+		sync: func(ctx context.Context, parent *streamingv1alpha1.Gateway) (ctrl.Result, error) {
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		},
+		expectedResult: ctrl.Result{RequeueAfter: time.Minute},
+	}, {
+		name: "sync result error",
+		sync: func(ctx context.Context, parent *streamingv1alpha1.Gateway) (ctrl.Result, error) {
+			return ctrl.Result{RequeueAfter: time.Minute}, fmt.Errorf("sync failed")
+		},
+		shouldErr: true,
+	}, {
+		name: "sync accepts the parent interface",
+		sync: func(ctx context.Context, parent metav1.Object) error {
+			return nil
+		},
+	}, {
+		name:      "missing sync",
+		shouldErr: true,
+	}, {
+		name: "sync missing context",
+		sync: func(parent *streamingv1alpha1.Gateway) error {
+			return nil
+		},
+		shouldErr: true,
+	}, {
+		name: "sync for another parent type",
+		sync: func(ctx context.Context, parent *streamingv1alpha1.Stream) error {
+			return nil
+		},
+		shouldErr: true,
+	}, {
+		name: "sync returns no error",
+		sync: func(ctx context.Context, parent *streamingv1alpha1.Gateway) ctrl.Result {
+			return ctrl.Result{}
+		},
+		shouldErr: true,
+	}, {
+		name: "sync returns a value other than a result",
+		sync: func(ctx context.Context, parent *streamingv1alpha1.Gateway) (time.Duration, error) {
+			return time.Minute, nil
+		},
+		shouldErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reconciler := &controllers.SyncReconciler{
+				Sync: test.sync,
+
+				Config: controllers.Config{
+					Log: rtesting.TestLogger(t),
+				},
+			}
+			result, err := reconciler.Reconcile(controllers.WithStash(context.Background()), parent.Create())
+			if (err != nil) != test.shouldErr {
+				t.Errorf("Reconcile() error = %v, ExpectErr %v", err, test.shouldErr)
+			}
+			if err == nil && result != test.expectedResult {
+				t.Errorf("Reconcile() result = %v, expected %v", result, test.expectedResult)
+			}
+		})
+	}
+}
+
+func TestParentReconciler_AggregateResults(t *testing.T) {
+	testNamespace := "test-namespace"
+	testName := "test-gateway"
+	testKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
+
+	gatewayConditionDeploymentReady := factories.Condition().Type(streamingv1alpha1.GatewayConditionDeploymentReady)
+	gatewayConditionReady := factories.Condition().Type(streamingv1alpha1.GatewayConditionReady)
+	gatewayConditionServiceReady := factories.Condition().Type(streamingv1alpha1.GatewayConditionServiceReady)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	parent := factories.Gateway().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Generation(1)
+		}).
+		StatusObservedGeneration(1).
+		StatusConditions(
+			gatewayConditionDeploymentReady.Unknown(),
+			gatewayConditionReady.Unknown(),
+			gatewayConditionServiceReady.Unknown(),
+		)
+
+	tests := []struct {
+		name           string
+		results        []ctrl.Result
+		expectedResult ctrl.Result
+	}{{
+		name:    "no results",
+		results: []ctrl.Result{{}, {}},
+	}, {
+		name:           "requeue after",
+		results:        []ctrl.Result{{}, {RequeueAfter: time.Minute}, {}},
+		expectedResult: ctrl.Result{RequeueAfter: time.Minute},
+	}, {
+		name:           "soonest requeue after wins",
+		results:        []ctrl.Result{{RequeueAfter: 2 * time.Minute}, {RequeueAfter: time.Minute}, {RequeueAfter: 3 * time.Minute}},
+		expectedResult: ctrl.Result{RequeueAfter: time.Minute},
+	}, {
+		name:           "requeue is ored",
+		results:        []ctrl.Result{{}, {Requeue: true}, {}},
+		expectedResult: ctrl.Result{Requeue: true},
+	}, {
+		name:           "requeue and requeue after",
+		results:        []ctrl.Result{{RequeueAfter: 2 * time.Minute}, {Requeue: true}, {RequeueAfter: time.Minute}},
+		expectedResult: ctrl.Result{Requeue: true, RequeueAfter: time.Minute},
+	}}
+
+	for _, test := range tests {
+		results := test.results
+		table := rtesting.Table{{
+			Name: test.name,
+			Key:  testKey,
+			GivenObjects: []rtesting.Factory{
+				parent,
+			},
+			ExpectedResult: test.expectedResult,
+		}}
+
+		table.Test(t, scheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
+			c := controllers.Config{
+				Client:    client,
+				APIReader: apiReader,
+				Recorder:  recorder,
+				Log:       log,
+				Scheme:    scheme,
+				Tracker:   tracker,
+			}
+			subReconcilers := make([]controllers.SubReconciler, len(results))
+			for i := range results {
+				result := results[i]
+				subReconcilers[i] = &controllers.SyncReconciler{
+					Sync: func(ctx context.Context, parent *streamingv1alpha1.Gateway) (ctrl.Result, error) {
+						return result, nil
+					},
+
+					Config: c,
+				}
+			}
+			return &controllers.ParentReconciler{
+				Type:           &streamingv1alpha1.Gateway{},
+				SubReconcilers: subReconcilers,
+
+				Config: c,
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/projectriff/system/pkg/apis"
+	"github.com/projectriff/system/pkg/controllers"
+)
+
+// reconcileControlledChildren converges the children of a single type that are
+// controlled by the parent onto the desired children, matching each by name.
+// Children that are no longer desired are deleted. The reconciled children are
+// returned in the order they are desired, a nil entry marks a desired child
+// whose name is taken by a resource the parent does not control.
+func reconcileControlledChildren(ctx context.Context, c controllers.Config, parent apis.Object, childType string, actual, desired []apis.Object, semanticEquals func(a1, a2 apis.Object) bool, mergeBeforeUpdate func(current, desired apis.Object)) ([]apis.Object, error) {
+	existing := map[string]apis.Object{}
+	for _, child := range actual {
+		existing[child.GetName()] = child
+	}
+	wanted := map[string]bool{}
+	for _, child := range desired {
+		wanted[child.GetName()] = true
+	}
+
+	// delete children no longer needed
+	for _, child := range actual {
+		if wanted[child.GetName()] {
+			continue
+		}
+		c.Log.Info("deleting unwanted child", childType, child.GetName())
+		if err := c.Delete(ctx, child); err != nil {
+			c.Log.Error(err, "unable to delete unwanted child", childType, child.GetName())
+			c.Recorder.Eventf(parent, corev1.EventTypeWarning, "DeleteFailed",
+				"Failed to delete %s %q: %v", childType, child.GetName(), err)
+			return nil, err
+		}
+		c.Recorder.Eventf(parent, corev1.EventTypeNormal, "Deleted",
+			"Deleted %s %q", childType, child.GetName())
+	}
+
+	children := make([]apis.Object, len(desired))
+	for i, child := range desired {
+		if err := ctrl.SetControllerReference(parent, child, c.Scheme); err != nil {
+			return nil, err
+		}
+
+		// create child if it doesn't exist
+		current, ok := existing[child.GetName()]
+		if !ok {
+			c.Log.Info("creating child", childType, child.GetName())
+			if err := c.Create(ctx, child); err != nil {
+				c.Log.Error(err, "unable to create child", childType, child.GetName())
+				c.Recorder.Eventf(parent, corev1.EventTypeWarning, "CreationFailed",
+					"Failed to create %s %q: %v", childType, child.GetName(), err)
+				if !apierrs.IsAlreadyExists(err) {
+					return nil, err
+				}
+				// the created child from a previous turn may be slow to appear
+				// in the informer cache, try again rather than reporting a
+				// conflict
+				conflicted := reflect.New(reflect.TypeOf(child).Elem()).Interface().(apis.Object)
+				_ = c.APIReader.Get(ctx, types.NamespacedName{Namespace: parent.GetNamespace(), Name: child.GetName()}, conflicted)
+				if metav1.IsControlledBy(conflicted, parent) {
+					return nil, err
+				}
+				continue
+			}
+			c.Recorder.Eventf(parent, corev1.EventTypeNormal, "Created",
+				"Created %s %q", childType, child.GetName())
+			children[i] = child
+			continue
+		}
+
+		if semanticEquals(child, current) {
+			// child is unchanged
+			children[i] = current
+			continue
+		}
+
+		// update child with desired changes
+		updated := current.DeepCopyObject().(apis.Object)
+		mergeBeforeUpdate(updated, child)
+		c.Log.Info("reconciling child", "diff", cmp.Diff(current, updated))
+		if err := c.Update(ctx, updated); err != nil {
+			c.Log.Error(err, "unable to update child", childType, updated.GetName())
+			c.Recorder.Eventf(parent, corev1.EventTypeWarning, "UpdateFailed",
+				"Failed to update %s %q: %v", childType, updated.GetName(), err)
+			return nil, err
+		}
+		c.Recorder.Eventf(parent, corev1.EventTypeNormal, "Updated",
+			"Updated %s %q", childType, updated.GetName())
+		children[i] = updated
+	}
+
+	return children, nil
+}
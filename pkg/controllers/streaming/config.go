@@ -22,9 +22,30 @@ const (
 	kafkaGatewayImages    = kustomizePrefix + "-kafka-gateway"    // contains image names for the kafka gateway
 	inmemoryGatewayImages = kustomizePrefix + "-inmemory-gateway" // contains image names for the inmemory gateway
 	pulsarGatewayImages   = kustomizePrefix + "-pulsar-gateway"   // contains image names for the pulsar gateway
+	natsGatewayImages     = kustomizePrefix + "-nats-gateway"     // contains image names for the nats gateway
+	redisGatewayImages    = kustomizePrefix + "-redis-gateway"    // contains image names for the redis gateway
 	gatewayImageKey       = "gatewayImage"
 	provisionerImageKey   = "provisionerImage"
 
 	processorImages   = kustomizePrefix + "-processor" // contains image names for the streaming processor
 	processorImageKey = "processorImage"
+
+	httpSourceImages   = kustomizePrefix + "-http-source" // contains image names for the http source
+	httpSourceImageKey = "receiverImage"
+
+	sinkImages   = kustomizePrefix + "-sink" // contains image names for the sink
+	sinkImageKey = "sinkImage"
+
+	cronSourceImages            = kustomizePrefix + "-cron-source" // contains image names for the cron source
+	cronSourcePublisherImageKey = "publisherImage"
+
+	streamMirrorImages   = kustomizePrefix + "-stream-mirror" // contains image names for the stream mirror
+	streamMirrorImageKey = "mirrorImage"
+
+	streamTapImages   = kustomizePrefix + "-stream-tap" // contains image names for the stream tap
+	streamTapImageKey = "tapImage"
+
+	settingsConfigMapName = kustomizePrefix + "-settings"
+	defaultDomainKey      = "defaultDomain"
+	defaultDomain         = "example.com"
 )
@@ -0,0 +1,371 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+)
+
+var processorConsumerLag = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "riff_streaming_processor_consumer_lag",
+		Help: "Number of messages on an input stream not yet consumed by the processor",
+	},
+	[]string{"namespace", "processor", "stream"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(processorConsumerLag)
+}
+
+// lagStaleIntervals is the number of poll intervals after which a lag that
+// could not be observed again is stale
+const lagStaleIntervals = 3
+
+// ConsumerLagMonitor periodically queries the gateway of each processor input
+// for the lag of the processor's consumer group. The most recent lag is held
+// in memory for the processor reconciler and exported as a Prometheus gauge.
+// The lag of each stream mirror on its source stream is monitored likewise.
+// A lag that has not been observed for lagStaleIntervals polls is stale.
+type ConsumerLagMonitor struct {
+	Client      client.Reader
+	Provisioner StreamProvisionerClient
+	Interval    time.Duration
+	// Threshold is the default lag above which a processor is lagging
+	Threshold int64
+	Log       logr.Logger
+	Clock     clock.Clock
+
+	events       chan event.GenericEvent
+	mirrorEvents chan event.GenericEvent
+	m            sync.RWMutex
+	lags         map[types.NamespacedName]observedInputLags
+	mirrorLags   map[types.NamespacedName]observedMirrorLag
+}
+
+type observedInputLags struct {
+	lags         []streamingv1alpha1.ProcessorInputLag
+	observedTime time.Time
+	// expired is true once the lag is known to be stale
+	expired bool
+}
+
+type observedMirrorLag struct {
+	lag          ConsumerLag
+	observedTime time.Time
+	expired      bool
+}
+
+var _ manager.Runnable = (*ConsumerLagMonitor)(nil)
+
+func NewConsumerLagMonitor(c client.Reader, provisioner StreamProvisionerClient, interval time.Duration, threshold int64, log logr.Logger) *ConsumerLagMonitor {
+	return &ConsumerLagMonitor{
+		Client:      c,
+		Provisioner: provisioner,
+		Interval:    interval,
+		Threshold:   threshold,
+		Log:         log,
+		Clock:       clock.RealClock{},
+
+		events:       make(chan event.GenericEvent),
+		mirrorEvents: make(chan event.GenericEvent),
+		lags:         map[types.NamespacedName]observedInputLags{},
+		mirrorLags:   map[types.NamespacedName]observedMirrorLag{},
+	}
+}
+
+// Events emits a processor each time its observed lag changes
+func (m *ConsumerLagMonitor) Events() <-chan event.GenericEvent {
+	return m.events
+}
+
+// MirrorEvents emits a stream mirror each time its observed lag changes
+func (m *ConsumerLagMonitor) MirrorEvents() <-chan event.GenericEvent {
+	return m.mirrorEvents
+}
+
+// MirrorLag most recently observed for the stream mirror on its source
+// stream. False is returned when the lag has not been observed, nil is
+// returned when the lag is stale.
+func (m *ConsumerLagMonitor) MirrorLag(mirror types.NamespacedName) (*ConsumerLag, bool) {
+	m.m.RLock()
+	defer m.m.RUnlock()
+	observed, ok := m.mirrorLags[mirror]
+	if !ok {
+		return nil, false
+	}
+	if m.stale(observed.observedTime) {
+		return nil, true
+	}
+	lag := observed.lag
+	return &lag, true
+}
+
+// Lag most recently observed for the processor's inputs. False is returned
+// when the lag has not been observed, nil is returned when the lag is stale.
+func (m *ConsumerLagMonitor) Lag(processor types.NamespacedName) ([]streamingv1alpha1.ProcessorInputLag, bool) {
+	m.m.RLock()
+	defer m.m.RUnlock()
+	observed, ok := m.lags[processor]
+	if !ok {
+		return nil, false
+	}
+	if m.stale(observed.observedTime) {
+		return nil, true
+	}
+	return observed.lags, true
+}
+
+// stale returns true when the observed time is more than lagStaleIntervals
+// polls ago
+func (m *ConsumerLagMonitor) stale(observedTime time.Time) bool {
+	return m.Clock.Since(observedTime) > lagStaleIntervals*m.Interval
+}
+
+func (m *ConsumerLagMonitor) Start(stop <-chan struct{}) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+	for {
+		if err := m.Poll(ctx); err != nil {
+			m.Log.Error(err, "unable to poll consumer lag")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll queries the lag of every processor and stream mirror, those that have
+// changed are emitted as events.
+func (m *ConsumerLagMonitor) Poll(ctx context.Context) error {
+	if err := m.pollProcessors(ctx); err != nil {
+		return err
+	}
+	return m.pollMirrors(ctx)
+}
+
+func (m *ConsumerLagMonitor) pollProcessors(ctx context.Context) error {
+	var processors streamingv1alpha1.ProcessorList
+	if err := m.Client.List(ctx, &processors); err != nil {
+		return err
+	}
+
+	seen := map[types.NamespacedName]bool{}
+	for i := range processors.Items {
+		processor := &processors.Items[i]
+		key := types.NamespacedName{Namespace: processor.Namespace, Name: processor.Name}
+		seen[key] = true
+		if processor.Status.ConsumerGroup == "" {
+			// nothing consumed yet
+			continue
+		}
+		var changed bool
+		if lags, err := m.processorLag(ctx, processor); err != nil {
+			// keep the last observed lag until it is stale, the gateway may be
+			// temporarily unavailable
+			m.Log.Info("unable to query consumer lag", "processor", key.String(), "error", err.Error())
+			changed = m.expire(key)
+		} else {
+			changed = m.Record(key, lags)
+		}
+		if changed {
+			select {
+			case m.events <- event.GenericEvent{Meta: processor, Object: processor}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+
+	m.m.Lock()
+	defer m.m.Unlock()
+	for key, observed := range m.lags {
+		if !seen[key] {
+			deleteLagGauges(key, observed.lags)
+			delete(m.lags, key)
+		}
+	}
+
+	return nil
+}
+
+func (m *ConsumerLagMonitor) pollMirrors(ctx context.Context) error {
+	var mirrors streamingv1alpha1.StreamMirrorList
+	if err := m.Client.List(ctx, &mirrors); err != nil {
+		return err
+	}
+
+	seen := map[types.NamespacedName]bool{}
+	for i := range mirrors.Items {
+		mirror := &mirrors.Items[i]
+		key := types.NamespacedName{Namespace: mirror.Namespace, Name: mirror.Name}
+		seen[key] = true
+		if mirror.Status.ConsumerGroup == "" || mirror.Status.CompletionTime != nil {
+			// not started or already completed
+			continue
+		}
+		var changed bool
+		if lag, err := m.streamLag(ctx, types.NamespacedName{Namespace: mirror.Namespace, Name: mirror.Spec.Source}, mirror.Status.ConsumerGroup); err != nil {
+			m.Log.Info("unable to query consumer lag", "streammirror", key.String(), "error", err.Error())
+			changed = m.expireMirror(key)
+		} else {
+			changed = m.RecordMirror(key, *lag)
+		}
+		if changed {
+			select {
+			case m.mirrorEvents <- event.GenericEvent{Meta: mirror, Object: mirror}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+
+	m.m.Lock()
+	defer m.m.Unlock()
+	for key := range m.mirrorLags {
+		if !seen[key] {
+			delete(m.mirrorLags, key)
+		}
+	}
+
+	return nil
+}
+
+func (m *ConsumerLagMonitor) processorLag(ctx context.Context, processor *streamingv1alpha1.Processor) ([]streamingv1alpha1.ProcessorInputLag, error) {
+	lags := make([]streamingv1alpha1.ProcessorInputLag, len(processor.Spec.Inputs))
+	for i, input := range processor.Spec.Inputs {
+		lag, err := m.streamLag(ctx, streamingv1alpha1.StreamNamespacedName(processor.Namespace, input.Stream), processor.Status.ConsumerGroup)
+		if err != nil {
+			return nil, err
+		}
+		lags[i] = streamingv1alpha1.ProcessorInputLag{
+			Stream: input.Stream,
+			Lag:    lag.Total(),
+		}
+	}
+	return lags, nil
+}
+
+// streamLag queries the gateway of the stream for the lag of the consumer
+// group
+func (m *ConsumerLagMonitor) streamLag(ctx context.Context, streamKey types.NamespacedName, group string) (*ConsumerLag, error) {
+	var stream streamingv1alpha1.Stream
+	if err := m.Client.Get(ctx, streamKey, &stream); err != nil {
+		return nil, err
+	}
+	// consumers read from the bound gateway while the stream migrates
+	gatewayName := stream.Status.Gateway
+	if gatewayName == "" {
+		gatewayName = stream.Spec.Gateway.Name
+	}
+	var gateway streamingv1alpha1.Gateway
+	gatewayKey := types.NamespacedName{Namespace: stream.Namespace, Name: gatewayName}
+	if err := m.Client.Get(ctx, gatewayKey, &gateway); err != nil {
+		return nil, err
+	}
+	provisionerURL, err := streamProvisionerURL(&gateway, streamKey)
+	if err != nil {
+		return nil, err
+	}
+	return m.Provisioner.ConsumerLag(ctx, provisionerURL, group)
+}
+
+// Record the observed lag for the processor's inputs, returning true if the
+// lag changed.
+func (m *ConsumerLagMonitor) Record(key types.NamespacedName, lags []streamingv1alpha1.ProcessorInputLag) bool {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	previous, ok := m.lags[key]
+	deleteLagGauges(key, previous.lags)
+	for _, lag := range lags {
+		processorConsumerLag.WithLabelValues(key.Namespace, key.Name, lag.Stream).Set(float64(lag.Lag))
+	}
+	m.lags[key] = observedInputLags{lags: lags, observedTime: m.Clock.Now()}
+	return !ok || previous.expired || !equality.Semantic.DeepEqual(previous.lags, lags)
+}
+
+// expire the processor's lag once it is stale, returning true the first time
+// the lag is found to be stale.
+func (m *ConsumerLagMonitor) expire(key types.NamespacedName) bool {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	observed, ok := m.lags[key]
+	if !ok || observed.expired || !m.stale(observed.observedTime) {
+		return false
+	}
+	deleteLagGauges(key, observed.lags)
+	observed.expired = true
+	m.lags[key] = observed
+	return true
+}
+
+func deleteLagGauges(key types.NamespacedName, lags []streamingv1alpha1.ProcessorInputLag) {
+	for _, lag := range lags {
+		processorConsumerLag.DeleteLabelValues(key.Namespace, key.Name, lag.Stream)
+	}
+}
+
+// RecordMirror records the observed lag of the stream mirror on its source
+// stream, returning true if the lag changed.
+func (m *ConsumerLagMonitor) RecordMirror(key types.NamespacedName, lag ConsumerLag) bool {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	previous, ok := m.mirrorLags[key]
+	m.mirrorLags[key] = observedMirrorLag{lag: lag, observedTime: m.Clock.Now()}
+	return !ok || previous.expired || !equality.Semantic.DeepEqual(previous.lag, lag)
+}
+
+// expireMirror expires the stream mirror's lag once it is stale, returning
+// true the first time the lag is found to be stale.
+func (m *ConsumerLagMonitor) expireMirror(key types.NamespacedName) bool {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	observed, ok := m.mirrorLags[key]
+	if !ok || observed.expired || !m.stale(observed.observedTime) {
+		return false
+	}
+	observed.expired = true
+	m.mirrorLags[key] = observed
+	return true
+}
@@ -0,0 +1,327 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers/streaming"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/controllers/testing/factories"
+)
+
+// fakeGateway serves the consumer lag of the provisioner API for groups in
+// lags, keyed by path
+func fakeGateway(t *testing.T, lags map[string]streaming.ConsumerLag) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		lag, ok := lags[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(lag)
+	}))
+}
+
+func TestStreamProvisionerClient_ConsumerLag(t *testing.T) {
+	server := fakeGateway(t, map[string]streaming.ConsumerLag{
+		"/test-namespace/my-stream/groups/my-group": {
+			Partitions: []streaming.PartitionLag{
+				{Partition: 0, Lag: 3},
+				{Partition: 1, Lag: 4},
+			},
+		},
+	})
+	defer server.Close()
+
+	client := streaming.NewStreamProvisionerClient(server.Client(), rtesting.TestLogger(t))
+
+	t.Run("lag", func(t *testing.T) {
+		lag, err := client.ConsumerLag(context.TODO(), server.URL+"/test-namespace/my-stream", "my-group")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected, actual := int64(7), lag.Total(); expected != actual {
+			t.Errorf("expected total lag %d, got %d", expected, actual)
+		}
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		if _, err := client.ConsumerLag(context.TODO(), server.URL+"/test-namespace/my-stream", "other-group"); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		if _, err := client.ConsumerLag(ctx, server.URL+"/test-namespace/my-stream", "my-group"); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
+
+func TestConsumerLagMonitor(t *testing.T) {
+	testNamespace := "test-namespace"
+	testSharedNamespace := "shared-namespace"
+
+	server := fakeGateway(t, map[string]streaming.ConsumerLag{
+		"/test-namespace/stream-1/groups/my-processor-1": {
+			Partitions: []streaming.PartitionLag{{Partition: 0, Lag: 3}, {Partition: 1, Lag: 4}},
+		},
+		"/shared-namespace/shared-stream/groups/my-processor-1": {
+			Partitions: []streaming.PartitionLag{{Partition: 0, Lag: 0}},
+		},
+	})
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	gateway := factories.Gateway().
+		NamespaceName(testNamespace, "my-gateway").
+		StatusAddress(server.URL)
+	sharedGateway := factories.Gateway().
+		NamespaceName(testSharedNamespace, "my-gateway").
+		StatusAddress(server.URL)
+	stream := factories.Stream().
+		NamespaceName(testNamespace, "stream-1").
+		Gateway("my-gateway")
+	sharedStream := factories.Stream().
+		NamespaceName(testSharedNamespace, "shared-stream").
+		Gateway("my-gateway")
+	processor := factories.Processor().
+		NamespaceName(testNamespace, "my-processor").
+		Inputs(
+			stream.CreateInputStreamBinding("", ""),
+			streamingv1alpha1.InputStreamBinding{Stream: "shared-namespace/shared-stream"},
+		).
+		StatusConsumerGroup("my-processor-1")
+	idle := factories.Processor().
+		NamespaceName(testNamespace, "idle").
+		Inputs(stream.CreateInputStreamBinding("", ""))
+
+	objects := []runtime.Object{}
+	for _, f := range []rtesting.Factory{gateway, sharedGateway, stream, sharedStream, processor, idle} {
+		objects = append(objects, f.CreateObject())
+	}
+	c := fake.NewFakeClientWithScheme(scheme, objects...)
+	monitor := streaming.NewConsumerLagMonitor(c, streaming.NewStreamProvisionerClient(server.Client(), rtesting.TestLogger(t)), time.Minute, 5, rtesting.TestLogger(t))
+	lagClock := clock.NewFakeClock(time.Date(2020, time.April, 1, 12, 0, 0, 0, time.UTC))
+	monitor.Clock = lagClock
+
+	// poll the gateways, collecting the names of processors emitted as events
+	poll := func(t *testing.T) []string {
+		events := []string{}
+		done := make(chan struct{})
+		finished := make(chan struct{})
+		go func() {
+			defer close(finished)
+			for {
+				select {
+				case e := <-monitor.Events():
+					events = append(events, e.Meta.GetName())
+				case <-done:
+					return
+				}
+			}
+		}()
+		if err := monitor.Poll(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		close(done)
+		<-finished
+		return events
+	}
+
+	key := types.NamespacedName{Namespace: testNamespace, Name: "my-processor"}
+
+	if diff := cmp.Diff([]string{"my-processor"}, poll(t)); diff != "" {
+		t.Errorf("unexpected events (-expected, +actual): %s", diff)
+	}
+	lags, ok := monitor.Lag(key)
+	if !ok {
+		t.Fatalf("expected lag for %s", key)
+	}
+	expectedLags := []streamingv1alpha1.ProcessorInputLag{
+		{Stream: "stream-1", Lag: 7},
+		{Stream: "shared-namespace/shared-stream", Lag: 0},
+	}
+	if diff := cmp.Diff(expectedLags, lags); diff != "" {
+		t.Errorf("unexpected lag (-expected, +actual): %s", diff)
+	}
+	if _, ok := monitor.Lag(types.NamespacedName{Namespace: testNamespace, Name: "idle"}); ok {
+		t.Errorf("expected no lag for a processor without a consumer group")
+	}
+	expectedMetrics := `
+# HELP riff_streaming_processor_consumer_lag Number of messages on an input stream not yet consumed by the processor
+# TYPE riff_streaming_processor_consumer_lag gauge
+riff_streaming_processor_consumer_lag{namespace="test-namespace",processor="my-processor",stream="shared-namespace/shared-stream"} 0
+riff_streaming_processor_consumer_lag{namespace="test-namespace",processor="my-processor",stream="stream-1"} 7
+`
+	if err := testutil.GatherAndCompare(metrics.Registry, strings.NewReader(expectedMetrics), "riff_streaming_processor_consumer_lag"); err != nil {
+		t.Error(err)
+	}
+
+	// unchanged lag is not emitted again
+	if diff := cmp.Diff([]string{}, poll(t)); diff != "" {
+		t.Errorf("unexpected events (-expected, +actual): %s", diff)
+	}
+
+	// the last lag is kept while a gateway is briefly unavailable
+	if err := c.Delete(context.Background(), sharedGateway.Create()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lagClock.Step(time.Minute)
+	if diff := cmp.Diff([]string{}, poll(t)); diff != "" {
+		t.Errorf("unexpected events (-expected, +actual): %s", diff)
+	}
+	if lags, _ := monitor.Lag(key); lags == nil {
+		t.Errorf("expected lag for %s", key)
+	}
+
+	// the lag is stale once not observed for three polls
+	lagClock.Step(3 * time.Minute)
+	if diff := cmp.Diff([]string{"my-processor"}, poll(t)); diff != "" {
+		t.Errorf("unexpected events (-expected, +actual): %s", diff)
+	}
+	if lags, ok := monitor.Lag(key); !ok || lags != nil {
+		t.Errorf("expected stale lag for %s, got %v", key, lags)
+	}
+	if err := testutil.GatherAndCompare(metrics.Registry, strings.NewReader(""), "riff_streaming_processor_consumer_lag"); err != nil {
+		t.Error(err)
+	}
+	if diff := cmp.Diff([]string{}, poll(t)); diff != "" {
+		t.Errorf("unexpected events (-expected, +actual): %s", diff)
+	}
+
+	// the lag is observed again once the gateway is available
+	if err := c.Create(context.Background(), sharedGateway.Create()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"my-processor"}, poll(t)); diff != "" {
+		t.Errorf("unexpected events (-expected, +actual): %s", diff)
+	}
+	if lags, _ := monitor.Lag(key); lags == nil {
+		t.Errorf("expected lag for %s", key)
+	}
+
+	// deleted processors are forgotten
+	if err := c.Delete(context.Background(), processor.Create()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	poll(t)
+	if _, ok := monitor.Lag(key); ok {
+		t.Errorf("expected lag for deleted processor to be forgotten")
+	}
+	if err := testutil.GatherAndCompare(metrics.Registry, strings.NewReader(""), "riff_streaming_processor_consumer_lag"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConsumerLagMonitor_Mirrors(t *testing.T) {
+	testNamespace := "test-namespace"
+
+	partitions := []streaming.PartitionLag{{Partition: 0, Offset: 10, Lag: 3}}
+	server := fakeGateway(t, map[string]streaming.ConsumerLag{
+		"/test-namespace/my-source/groups/streammirror-my-mirror": {Partitions: partitions},
+	})
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	gateway := factories.Gateway().
+		NamespaceName(testNamespace, "my-gateway").
+		StatusAddress(server.URL)
+	source := factories.Stream().
+		NamespaceName(testNamespace, "my-source").
+		Gateway("my-gateway")
+	mirror := factories.StreamMirror().
+		NamespaceName(testNamespace, "my-mirror").
+		Source("my-source").
+		Destination("my-destination").
+		StatusConsumerGroup("streammirror-my-mirror")
+	completed := factories.StreamMirror().
+		NamespaceName(testNamespace, "completed").
+		Source("my-source").
+		Destination("my-destination").
+		StatusConsumerGroup("streammirror-my-mirror").
+		StatusCompletionTime(1)
+
+	objects := []runtime.Object{}
+	for _, f := range []rtesting.Factory{gateway, source, mirror, completed} {
+		objects = append(objects, f.CreateObject())
+	}
+	c := fake.NewFakeClientWithScheme(scheme, objects...)
+	monitor := streaming.NewConsumerLagMonitor(c, streaming.NewStreamProvisionerClient(server.Client(), rtesting.TestLogger(t)), time.Minute, 5, rtesting.TestLogger(t))
+
+	events := []string{}
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		for {
+			select {
+			case e := <-monitor.MirrorEvents():
+				events = append(events, e.Meta.GetName())
+			case <-done:
+				return
+			}
+		}
+	}()
+	if err := monitor.Poll(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(done)
+	<-finished
+
+	if diff := cmp.Diff([]string{"my-mirror"}, events); diff != "" {
+		t.Errorf("unexpected events (-expected, +actual): %s", diff)
+	}
+	lag, ok := monitor.MirrorLag(types.NamespacedName{Namespace: testNamespace, Name: "my-mirror"})
+	if !ok {
+		t.Fatalf("expected lag for my-mirror")
+	}
+	if diff := cmp.Diff(&streaming.ConsumerLag{Partitions: partitions}, lag); diff != "" {
+		t.Errorf("unexpected lag (-expected, +actual): %s", diff)
+	}
+	if _, ok := monitor.MirrorLag(types.NamespacedName{Namespace: testNamespace, Name: "completed"}); ok {
+		t.Errorf("expected no lag for a completed mirror")
+	}
+}
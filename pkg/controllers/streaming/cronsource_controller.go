@@ -0,0 +1,263 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/projectriff/system/pkg/apis"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/cron"
+	"github.com/projectriff/system/pkg/refs"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+const (
+	CronSourceImagesStashKey controllers.StashKey = "cron-source-images"
+	CronSourceStreamStashKey controllers.StashKey = "cron-source-stream"
+)
+
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=cronsources,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=cronsources/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streams,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+func CronSourceReconciler(c controllers.Config, namespace string) *controllers.ParentReconciler {
+	c.Log = c.Log.WithName("CronSource")
+
+	return &controllers.ParentReconciler{
+		Type: &streamingv1alpha1.CronSource{},
+		SubReconcilers: []controllers.SubReconciler{
+			CronSourceSyncImages(c, namespace),
+			CronSourceResolveStreamReconciler(c),
+			CronSourceChildCronJobReconciler(c),
+		},
+
+		Config: c,
+	}
+}
+
+func CronSourceSyncImages(c controllers.Config, namespace string) controllers.SubReconciler {
+	c.Log = c.Log.WithName("SyncImages")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.CronSource) error {
+			config := corev1.ConfigMap{}
+			key := types.NamespacedName{Namespace: namespace, Name: cronSourceImages}
+			// track config for new images
+			c.Tracker.Track(
+				tracker.NewKey(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, key),
+				types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+			)
+			if err := c.Get(ctx, key, &config); err != nil {
+				return err
+			}
+			controllers.StashValue(ctx, CronSourceImagesStashKey, config.Data)
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &corev1.ConfigMap{}}, controllers.EnqueueTracked(&corev1.ConfigMap{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
+
+func CronSourceResolveStreamReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ResolveStream")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.CronSource) error {
+			var stream streamingv1alpha1.Stream
+			key := types.NamespacedName{Namespace: parent.Namespace, Name: parent.Spec.Output}
+			// track stream for new coordinates
+			c.Tracker.Track(
+				tracker.NewKey(stream.GetGroupVersionKind(), key),
+				types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+			)
+			if err := c.Get(ctx, key, &stream); err != nil {
+				if apierrs.IsNotFound(err) {
+					parent.Status.MarkStreamNotReady(fmt.Sprintf("stream %s not found", parent.Spec.Output))
+					return nil
+				}
+				return err
+			}
+			controllers.StashValue(ctx, CronSourceStreamStashKey, &stream)
+
+			ready := stream.Status.GetCondition(stream.Status.GetReadyConditionType())
+			if ready == nil {
+				ready = &apis.Condition{Message: "stream has no ready condition"}
+			}
+			if !ready.IsTrue() {
+				parent.Status.MarkStreamNotReady(fmt.Sprintf("stream %s is not ready: %s", stream.Name, ready.Message))
+				return nil
+			}
+			parent.Status.MarkStreamReady()
+
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &streamingv1alpha1.Stream{}}, controllers.EnqueueTracked(&streamingv1alpha1.Stream{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
+
+func CronSourceChildCronJobReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildCronJob")
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.CronSource{},
+		ChildType:     &batchv1beta1.CronJob{},
+		ChildListType: &batchv1beta1.CronJobList{},
+
+		DesiredChild: func(ctx context.Context, parent *streamingv1alpha1.CronSource) (*batchv1beta1.CronJob, error) {
+			stream, ok := controllers.RetrieveValue(ctx, CronSourceStreamStashKey).(*streamingv1alpha1.Stream)
+			if !ok {
+				// no stream, skip
+				return nil, nil
+			}
+			images, ok := controllers.RetrieveValue(ctx, CronSourceImagesStashKey).(map[string]string)
+			if !ok {
+				return nil, nil
+			}
+			image := images[cronSourcePublisherImageKey]
+			if image == "" {
+				return nil, nil
+			}
+
+			labels := controllers.MergeMaps(parent.Labels, map[string]string{
+				streamingv1alpha1.CronSourceLabelKey: parent.Name,
+			})
+			volumes, volumeMounts := constructBindingVolumes(nil, []streamingv1alpha1.Stream{*stream}, streamBinding)
+
+			child := &batchv1beta1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: fmt.Sprintf("%s-cron-source-", parent.Name),
+					Namespace:    parent.Namespace,
+					Labels:       labels,
+				},
+				Spec: batchv1beta1.CronJobSpec{
+					Schedule: parent.Spec.Schedule,
+					// a slow publish must not pile up events
+					ConcurrencyPolicy: batchv1beta1.ForbidConcurrent,
+					JobTemplate: batchv1beta1.JobTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: labels,
+						},
+						Spec: batchv1.JobSpec{
+							Template: corev1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: labels,
+								},
+								Spec: corev1.PodSpec{
+									Containers: []corev1.Container{
+										{
+											Name:  "publisher",
+											Image: image,
+											Env: []corev1.EnvVar{
+												{Name: "CNB_BINDINGS", Value: bindingsRootPath},
+												{Name: "OUTPUT_NAMES", Value: stream.Name},
+												{Name: "OUTPUT_CONTENT_TYPE", Value: stream.Spec.ContentType},
+												{Name: "PAYLOAD", Value: parent.Spec.Payload},
+												{Name: "SOURCE_NAME", Value: parent.Name},
+												{Name: "SOURCE_NAMESPACE", Value: parent.Namespace},
+											},
+											VolumeMounts: volumeMounts,
+										},
+									},
+									RestartPolicy: corev1.RestartPolicyOnFailure,
+									Volumes:       volumes,
+								},
+							},
+						},
+					},
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.CronSource, child *batchv1beta1.CronJob, err error) {
+			if err != nil {
+				return
+			}
+			if child == nil {
+				parent.Status.CronJobRef = nil
+				parent.Status.LastScheduleTime = nil
+				parent.Status.NextScheduleTime = nil
+				return
+			}
+			parent.Status.CronJobRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+			parent.Status.PropagateCronJobStatus(&child.Status)
+			parent.Status.NextScheduleTime = nextScheduleTime(parent, child)
+		},
+		MergeBeforeUpdate: func(current, desired *batchv1beta1.CronJob) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *batchv1beta1.CronJob) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.cronSourceCronJobController",
+		Sanitize: func(child *batchv1beta1.CronJob) interface{} {
+			return child.Spec
+		},
+	}
+}
+
+// nextScheduleTime computes the next time the cron job will fire, counting
+// from the most recent of the last schedule time and the creation of the
+// source and cron job. The result is deterministic so that status is only
+// updated when the cron job fires or is replaced.
+func nextScheduleTime(parent *streamingv1alpha1.CronSource, child *batchv1beta1.CronJob) *metav1.Time {
+	schedule, err := cron.Parse(child.Spec.Schedule)
+	if err != nil {
+		return nil
+	}
+	from := parent.CreationTimestamp.Time
+	if child.CreationTimestamp.After(from) {
+		from = child.CreationTimestamp.Time
+	}
+	if last := child.Status.LastScheduleTime; last != nil && last.After(from) {
+		from = last.Time
+	}
+	next := schedule.Next(from)
+	if next.IsZero() {
+		return nil
+	}
+	return &metav1.Time{Time: next}
+}
@@ -0,0 +1,410 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/controllers/streaming"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/controllers/testing/factories"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+func TestCronSourceReconciler(t *testing.T) {
+	testNamespace := "test-namespace"
+	testSystemNamespace := "system-namespace"
+	testName := "test-source"
+	testKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
+	testPublisherImage := "example.com/repo/publisher"
+	testSchedule := "*/5 * * * *"
+	testPayload := `{"time": "{{ .Time }}"}`
+
+	cronSourceImages := "riff-streaming-cron-source" // contains image names for the cron source
+	publisherImageKey := "publisherImage"
+
+	cronSourceConditionCronJobReady := factories.Condition().Type(streamingv1alpha1.CronSourceConditionCronJobReady)
+	cronSourceConditionReady := factories.Condition().Type(streamingv1alpha1.CronSourceConditionReady)
+	cronSourceConditionStreamReady := factories.Condition().Type(streamingv1alpha1.CronSourceConditionStreamReady)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	cronSourceImagesConfigMap := factories.ConfigMap().
+		NamespaceName(testSystemNamespace, cronSourceImages).
+		AddData(publisherImageKey, testPublisherImage)
+
+	testStream := factories.Stream().
+		NamespaceName(testNamespace, "my-stream").
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.UID("00000000-0000-0000-0000-000000000001")
+		}).
+		ContentType("application/json").
+		StatusBinding("my-stream-binding-metadata", "my-stream-binding-secret").
+		StatusReady()
+
+	cronSourceMinimal := factories.CronSource().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.Generation(1)
+		}).
+		Schedule(testSchedule).
+		Output(testStream.Create().Name).
+		Payload(testPayload)
+	cronSource := cronSourceMinimal.
+		StatusCronJobRef("%s-cron-source-000", testName).
+		StatusNextScheduleTime(300)
+
+	cronJobCreate := factories.CronJob().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.GenerateName("%s-cron-source-", testName)
+			om.AddLabel(streamingv1alpha1.CronSourceLabelKey, testName)
+			om.ControlledBy(cronSource, scheme)
+		}).
+		Schedule(testSchedule).
+		ConcurrencyPolicy(batchv1beta1.ForbidConcurrent).
+		AddJobTemplateLabel(streamingv1alpha1.CronSourceLabelKey, testName).
+		PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+			pts.ContainerNamed("publisher", func(c *corev1.Container) {
+				c.Image = testPublisherImage
+				c.Env = []corev1.EnvVar{
+					{Name: "CNB_BINDINGS", Value: "/var/riff/bindings"},
+					{Name: "OUTPUT_NAMES", Value: "my-stream"},
+					{Name: "OUTPUT_CONTENT_TYPE", Value: "application/json"},
+					{Name: "PAYLOAD", Value: testPayload},
+					{Name: "SOURCE_NAME", Value: testName},
+					{Name: "SOURCE_NAMESPACE", Value: testNamespace},
+				}
+				c.VolumeMounts = []corev1.VolumeMount{
+					{
+						Name:      "stream-00000000-0000-0000-0000-000000000001-metadata",
+						MountPath: "/var/riff/bindings/output_000/metadata",
+						ReadOnly:  true,
+					},
+					{
+						Name:      "stream-00000000-0000-0000-0000-000000000001-secret",
+						MountPath: "/var/riff/bindings/output_000/secret",
+						ReadOnly:  true,
+					},
+				}
+			})
+			pts.RestartPolicy(corev1.RestartPolicyOnFailure)
+			pts.Volumes(
+				corev1.Volume{
+					Name: "stream-00000000-0000-0000-0000-000000000001-metadata",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: "my-stream-binding-metadata",
+							},
+						},
+					},
+				},
+				corev1.Volume{
+					Name: "stream-00000000-0000-0000-0000-000000000001-secret",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "my-stream-binding-secret",
+						},
+					},
+				},
+			)
+		})
+	cronJobGiven := cronJobCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Name("%s%s", om.Create().GenerateName, "000")
+			om.Created(1)
+		})
+
+	table := rtesting.Table{{
+		Name: "cronsource does not exist",
+		Key:  testKey,
+	}, {
+		Name: "ignore deleted cronsource",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			cronSource.
+				ObjectMeta(func(om factories.ObjectMeta) {
+					om.Deleted(1)
+				}),
+		},
+	}, {
+		Name: "error fetching cronsource",
+		Key:  testKey,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("get", "CronSource"),
+		},
+		ShouldErr: true,
+	}, {
+		Name: "create cronjob",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			cronSourceMinimal,
+			cronSourceImagesConfigMap,
+			testStream,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(cronSourceImagesConfigMap, cronSourceMinimal, scheme),
+			rtesting.NewTrackRequest(testStream, cronSourceMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(cronSourceMinimal, scheme, corev1.EventTypeNormal, "Created",
+				`Created CronJob "%s-cron-source-001"`, testName),
+			rtesting.NewEvent(cronSourceMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			cronJobCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			cronSourceMinimal.
+				StatusConditions(
+					cronSourceConditionCronJobReady.True(),
+					cronSourceConditionReady.True(),
+					cronSourceConditionStreamReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusCronJobRef("%s-cron-source-001", testName).
+				StatusNextScheduleTime(300),
+		},
+	}, {
+		Name: "ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			cronSource.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					cronSourceConditionCronJobReady.True(),
+					cronSourceConditionReady.True(),
+					cronSourceConditionStreamReady.True(),
+				),
+			cronSourceImagesConfigMap,
+			testStream,
+			cronJobGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(cronSourceImagesConfigMap, cronSource, scheme),
+			rtesting.NewTrackRequest(testStream, cronSource, scheme),
+		},
+	}, {
+		Name: "reflect schedule times",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			cronSource.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					cronSourceConditionCronJobReady.True(),
+					cronSourceConditionReady.True(),
+					cronSourceConditionStreamReady.True(),
+				),
+			cronSourceImagesConfigMap,
+			testStream,
+			cronJobGiven.
+				StatusLastScheduleTime(600),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(cronSourceImagesConfigMap, cronSource, scheme),
+			rtesting.NewTrackRequest(testStream, cronSource, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(cronSource, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			cronSource.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					cronSourceConditionCronJobReady.True(),
+					cronSourceConditionReady.True(),
+					cronSourceConditionStreamReady.True(),
+				).
+				StatusLastScheduleTime(600).
+				StatusNextScheduleTime(900),
+		},
+	}, {
+		Name: "update schedule",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			cronSource.
+				Schedule("@hourly"),
+			cronSourceImagesConfigMap,
+			testStream,
+			cronJobGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(cronSourceImagesConfigMap, cronSource, scheme),
+			rtesting.NewTrackRequest(testStream, cronSource, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(cronSource, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated CronJob "%s-cron-source-000"`, testName),
+			rtesting.NewEvent(cronSource, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			cronJobGiven.
+				Schedule("@hourly"),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			cronSource.
+				Schedule("@hourly").
+				StatusConditions(
+					cronSourceConditionCronJobReady.True(),
+					cronSourceConditionReady.True(),
+					cronSourceConditionStreamReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusNextScheduleTime(3600),
+		},
+	}, {
+		Name: "images missing",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			cronSourceMinimal,
+			testStream,
+		},
+		ShouldErr: true,
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(cronSourceImagesConfigMap, cronSourceMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(cronSourceMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			cronSourceMinimal.
+				StatusConditions(
+					cronSourceConditionCronJobReady.Unknown(),
+					cronSourceConditionReady.Unknown(),
+					cronSourceConditionStreamReady.Unknown(),
+				),
+		},
+	}, {
+		Name: "stream not found",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			cronSourceMinimal,
+			cronSourceImagesConfigMap,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(cronSourceImagesConfigMap, cronSourceMinimal, scheme),
+			rtesting.NewTrackRequest(testStream, cronSourceMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(cronSourceMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			cronSourceMinimal.
+				StatusConditions(
+					cronSourceConditionCronJobReady.Unknown(),
+					cronSourceConditionReady.False().Reason("StreamNotReady", "stream my-stream not found"),
+					cronSourceConditionStreamReady.False().Reason("StreamNotReady", "stream my-stream not found"),
+				).
+				StatusObservedGeneration(1),
+		},
+	}, {
+		Name: "stream not found, delete cronjob",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			cronSource.
+				StatusLastScheduleTime(600),
+			cronSourceImagesConfigMap,
+			cronJobGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(cronSourceImagesConfigMap, cronSource, scheme),
+			rtesting.NewTrackRequest(testStream, cronSource, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(cronSource, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted CronJob "%s-cron-source-000"`, testName),
+			rtesting.NewEvent(cronSource, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectDeletes: []rtesting.DeleteRef{
+			{Group: "batch", Kind: "CronJob", Namespace: testNamespace, Name: fmt.Sprintf("%s-cron-source-000", testName)},
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			cronSourceMinimal.
+				StatusConditions(
+					cronSourceConditionCronJobReady.Unknown(),
+					cronSourceConditionReady.False().Reason("StreamNotReady", "stream my-stream not found"),
+					cronSourceConditionStreamReady.False().Reason("StreamNotReady", "stream my-stream not found"),
+				).
+				StatusObservedGeneration(1),
+		},
+	}, {
+		Name: "stream not ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			cronSource,
+			cronSourceImagesConfigMap,
+			testStream.
+				StatusConditions(),
+			cronJobGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(cronSourceImagesConfigMap, cronSource, scheme),
+			rtesting.NewTrackRequest(testStream, cronSource, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(cronSource, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			cronSource.
+				StatusConditions(
+					cronSourceConditionCronJobReady.True(),
+					cronSourceConditionReady.False().Reason("StreamNotReady", "stream my-stream is not ready: stream has no ready condition"),
+					cronSourceConditionStreamReady.False().Reason("StreamNotReady", "stream my-stream is not ready: stream has no ready condition"),
+				).
+				StatusObservedGeneration(1),
+		},
+	}}
+
+	table.Test(t, scheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
+		return streaming.CronSourceReconciler(
+			controllers.Config{
+				Client:    client,
+				APIReader: apiReader,
+				Recorder:  recorder,
+				Log:       log,
+				Scheme:    scheme,
+				Tracker:   tracker,
+			},
+			testSystemNamespace,
+		)
+	})
+}
@@ -18,11 +18,16 @@ package streaming
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/projectriff/system/pkg/apis"
 	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
@@ -34,6 +39,8 @@ import (
 // +kubebuilder:rbac:groups=streaming.projectriff.io,resources=gateways/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 
 func GatewayReconciler(c controllers.Config) *controllers.ParentReconciler {
@@ -44,6 +51,9 @@ func GatewayReconciler(c controllers.Config) *controllers.ParentReconciler {
 		SubReconcilers: []controllers.SubReconciler{
 			GatewayChildServiceReconciler(c),
 			GatewayChildDeploymentReconciler(c),
+			GatewayChildPodDisruptionBudgetReconciler(c),
+			GatewayChildPublicServiceReconciler(c),
+			GatewayChildIngressReconciler(c),
 		},
 
 		Config: c,
@@ -104,6 +114,11 @@ func GatewayChildServiceReconciler(c controllers.Config) controllers.SubReconcil
 				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
 		},
 
+		OurChild: func(child *corev1.Service) bool {
+			// the public service is managed by the public service reconciler
+			return child.Labels[streamingv1alpha1.GatewayExposureLabelKey] == ""
+		},
+
 		Config:     c,
 		IndexField: ".metadata.serviceController",
 		Sanitize: func(child *corev1.Service) interface{} {
@@ -132,6 +147,45 @@ func GatewayChildDeploymentReconciler(c controllers.Config) controllers.SubRecon
 
 			template := *parent.Spec.Template.DeepCopy()
 			template.Labels = controllers.MergeMaps(template.Labels, labels)
+			if template.Spec.Affinity == nil {
+				// prefer to spread replicas across nodes
+				template.Spec.Affinity = &corev1.Affinity{
+					PodAntiAffinity: &corev1.PodAntiAffinity{
+						PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+							{
+								Weight: 100,
+								PodAffinityTerm: corev1.PodAffinityTerm{
+									LabelSelector: &metav1.LabelSelector{
+										MatchLabels: map[string]string{
+											streamingv1alpha1.GatewayLabelKey: parent.Name,
+										},
+									},
+									TopologyKey: "kubernetes.io/hostname",
+								},
+							},
+						},
+					},
+				}
+			}
+
+			if exposure := parent.Spec.Exposure; exposure != nil && exposure.AuthSecretRef != nil {
+				// clients authenticate with the token
+				for i := range template.Spec.Containers {
+					container := &template.Spec.Containers[i]
+					if container.Name != "gateway" {
+						continue
+					}
+					container.Env = append(container.Env, corev1.EnvVar{
+						Name: "auth_token",
+						ValueFrom: &corev1.EnvVarSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: *exposure.AuthSecretRef,
+								Key:                  streamingv1alpha1.GatewayAuthTokenKey,
+							},
+						},
+					})
+				}
+			}
 
 			child := &appsv1.Deployment{
 				ObjectMeta: metav1.ObjectMeta{
@@ -141,6 +195,7 @@ func GatewayChildDeploymentReconciler(c controllers.Config) controllers.SubRecon
 					Namespace:    parent.Namespace,
 				},
 				Spec: appsv1.DeploymentSpec{
+					Replicas: parent.Spec.Replicas,
 					Selector: &metav1.LabelSelector{
 						MatchLabels: map[string]string{
 							streamingv1alpha1.GatewayLabelKey: parent.Name,
@@ -161,7 +216,10 @@ func GatewayChildDeploymentReconciler(c controllers.Config) controllers.SubRecon
 			}
 		},
 		HarmonizeImmutableFields: func(current, desired *appsv1.Deployment) {
-			desired.Spec.Replicas = current.Spec.Replicas
+			if desired.Spec.Replicas == nil {
+				// retain replicas that are managed outside of the gateway
+				desired.Spec.Replicas = current.Spec.Replicas
+			}
 		},
 		MergeBeforeUpdate: func(current, desired *appsv1.Deployment) {
 			current.Labels = desired.Labels
@@ -179,3 +237,327 @@ func GatewayChildDeploymentReconciler(c controllers.Config) controllers.SubRecon
 		},
 	}
 }
+
+func GatewayChildPodDisruptionBudgetReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildPodDisruptionBudget")
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.Gateway{},
+		ChildType:     &policyv1beta1.PodDisruptionBudget{},
+		ChildListType: &policyv1beta1.PodDisruptionBudgetList{},
+
+		DesiredChild: func(parent *streamingv1alpha1.Gateway) (*policyv1beta1.PodDisruptionBudget, error) {
+			if parent.Status.DeploymentRef == nil {
+				// no deployment, skip
+				return nil, nil
+			}
+
+			// voluntary disruptions take down at most one gateway pod at a time
+			maxUnavailable := intstr.FromInt(1)
+
+			child := &policyv1beta1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: controllers.MergeMaps(parent.Labels, map[string]string{
+						streamingv1alpha1.GatewayLabelKey: parent.Name,
+					}),
+					Annotations:  make(map[string]string),
+					GenerateName: fmt.Sprintf("%s-gateway-", parent.Name),
+					Namespace:    parent.Namespace,
+				},
+				Spec: policyv1beta1.PodDisruptionBudgetSpec{
+					MaxUnavailable: &maxUnavailable,
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							streamingv1alpha1.GatewayLabelKey: parent.Name,
+						},
+					},
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.Gateway, child *policyv1beta1.PodDisruptionBudget, err error) {
+			if child == nil {
+				parent.Status.PodDisruptionBudgetRef = nil
+			} else {
+				parent.Status.PodDisruptionBudgetRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+			}
+		},
+		MergeBeforeUpdate: func(current, desired *policyv1beta1.PodDisruptionBudget) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *policyv1beta1.PodDisruptionBudget) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.podDisruptionBudgetController",
+		Sanitize: func(child *policyv1beta1.PodDisruptionBudget) interface{} {
+			return child.Spec
+		},
+	}
+}
+
+func GatewayChildPublicServiceReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildPublicService")
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.Gateway{},
+		ChildType:     &corev1.Service{},
+		ChildListType: &corev1.ServiceList{},
+
+		DesiredChild: func(parent *streamingv1alpha1.Gateway) (*corev1.Service, error) {
+			exposure := parent.Spec.Exposure
+			if exposure == nil || exposure.Type != streamingv1alpha1.GatewayExposureLoadBalancer {
+				return nil, nil
+			}
+			port, ok := gatewayPort(parent)
+			if !ok {
+				// nothing to publish
+				return nil, nil
+			}
+
+			child := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: controllers.MergeMaps(parent.Labels, map[string]string{
+						streamingv1alpha1.GatewayLabelKey:         parent.Name,
+						streamingv1alpha1.GatewayExposureLabelKey: string(exposure.Type),
+					}),
+					Annotations:  make(map[string]string),
+					Namespace:    parent.Namespace,
+					GenerateName: fmt.Sprintf("%s-gateway-public-", parent.Name),
+				},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+					// only the gateway is published, the provisioner remains
+					// private to the cluster
+					Ports: []corev1.ServicePort{port},
+					Selector: map[string]string{
+						streamingv1alpha1.GatewayLabelKey: parent.Name,
+					},
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.Gateway, child *corev1.Service, err error) {
+			if err != nil {
+				return
+			}
+			// runs before the ingress reconciler, which may set the address
+			parent.Status.PublicAddress = nil
+			if child == nil {
+				parent.Status.PublicServiceRef = nil
+				return
+			}
+			parent.Status.PublicServiceRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+			for _, ingress := range child.Status.LoadBalancer.Ingress {
+				host := ingress.IP
+				if host == "" {
+					host = ingress.Hostname
+				}
+				if host == "" {
+					continue
+				}
+				port := strconv.Itoa(int(child.Spec.Ports[0].Port))
+				parent.Status.PublicAddress = &apis.Addressable{URL: fmt.Sprintf("http://%s", net.JoinHostPort(host, port))}
+				break
+			}
+		},
+		HarmonizeImmutableFields: func(current, desired *corev1.Service) {
+			desired.Spec.ClusterIP = current.Spec.ClusterIP
+			// retain the node ports allocated for the load balancer
+			for i := range desired.Spec.Ports {
+				for _, port := range current.Spec.Ports {
+					if port.Name == desired.Spec.Ports[i].Name {
+						desired.Spec.Ports[i].NodePort = port.NodePort
+					}
+				}
+			}
+			desired.Spec.ExternalTrafficPolicy = current.Spec.ExternalTrafficPolicy
+		},
+		MergeBeforeUpdate: func(current, desired *corev1.Service) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *corev1.Service) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+		OurChild: func(child *corev1.Service) bool {
+			return child.Labels[streamingv1alpha1.GatewayExposureLabelKey] != ""
+		},
+
+		Config:     c,
+		IndexField: ".metadata.publicServiceController",
+		Sanitize: func(child *corev1.Service) interface{} {
+			return child.Spec
+		},
+	}
+}
+
+func GatewayChildIngressReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildIngress")
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.Gateway{},
+		ChildType:     &networkingv1beta1.Ingress{},
+		ChildListType: &networkingv1beta1.IngressList{},
+
+		DesiredChild: func(parent *streamingv1alpha1.Gateway) (*networkingv1beta1.Ingress, error) {
+			exposure := parent.Spec.Exposure
+			if parent.Status.ServiceRef == nil || exposure == nil || exposure.Type != streamingv1alpha1.GatewayExposureIngress {
+				// no service or not exposed, skip
+				return nil, nil
+			}
+			port, ok := gatewayPort(parent)
+			if !ok {
+				// nothing to publish
+				return nil, nil
+			}
+
+			child := &networkingv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: controllers.MergeMaps(parent.Labels, map[string]string{
+						streamingv1alpha1.GatewayLabelKey:         parent.Name,
+						streamingv1alpha1.GatewayExposureLabelKey: string(exposure.Type),
+					}),
+					Annotations:  make(map[string]string),
+					GenerateName: fmt.Sprintf("%s-gateway-", parent.Name),
+					Namespace:    parent.Namespace,
+				},
+				Spec: networkingv1beta1.IngressSpec{
+					Rules: []networkingv1beta1.IngressRule{{
+						Host: exposure.Host,
+						IngressRuleValue: networkingv1beta1.IngressRuleValue{
+							HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+								Paths: []networkingv1beta1.HTTPIngressPath{{
+									Path: "/",
+									Backend: networkingv1beta1.IngressBackend{
+										ServiceName: parent.Status.ServiceRef.Name,
+										ServicePort: intstr.FromInt(int(port.Port)),
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}
+			if exposure.TLSSecretRef != nil {
+				child.Spec.TLS = []networkingv1beta1.IngressTLS{
+					{
+						Hosts:      []string{exposure.Host},
+						SecretName: exposure.TLSSecretRef.Name,
+					},
+				}
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.Gateway, child *networkingv1beta1.Ingress, err error) {
+			if err != nil {
+				return
+			}
+			if child == nil {
+				parent.Status.IngressRef = nil
+				return
+			}
+			parent.Status.IngressRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+			scheme := "http"
+			if len(child.Spec.TLS) != 0 {
+				scheme = "https"
+			}
+			parent.Status.PublicAddress = &apis.Addressable{URL: fmt.Sprintf("%s://%s", scheme, child.Spec.Rules[0].Host)}
+		},
+		MergeBeforeUpdate: func(current, desired *networkingv1beta1.Ingress) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *networkingv1beta1.Ingress) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.gatewayIngressController",
+		Sanitize: func(child *networkingv1beta1.Ingress) interface{} {
+			return child.Spec
+		},
+	}
+}
+
+// gatewayPort finds the port of the gateway published to producers
+func gatewayPort(gateway *streamingv1alpha1.Gateway) (corev1.ServicePort, bool) {
+	for _, port := range gateway.Spec.Ports {
+		if port.Name == "gateway" {
+			return port, true
+		}
+	}
+	return corev1.ServicePort{}, false
+}
+
+// tuneGatewayTemplate applies the deployment settings of a gateway type to the
+// template of the generated gateway, and probes the gateway and provisioner
+// containers.
+func tuneGatewayTemplate(template *corev1.PodTemplateSpec, deployment streamingv1alpha1.GatewayDeploymentSpec) {
+	for i := range template.Spec.Containers {
+		container := &template.Spec.Containers[i]
+		switch container.Name {
+		case "gateway":
+			if deployment.Resources != nil {
+				container.Resources = *deployment.Resources.DeepCopy()
+			}
+			container.ReadinessProbe = &corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{Path: "/health", Port: intstr.FromInt(8000)},
+				},
+			}
+			container.LivenessProbe = &corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{Path: "/health", Port: intstr.FromInt(8000)},
+				},
+				InitialDelaySeconds: 30,
+			}
+		case "provisioner":
+			container.ReadinessProbe = &corev1.Probe{
+				Handler: corev1.Handler{
+					TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8080)},
+				},
+			}
+			container.LivenessProbe = &corev1.Probe{
+				Handler: corev1.Handler{
+					TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8080)},
+				},
+				InitialDelaySeconds: 30,
+			}
+		}
+	}
+	template.Spec.NodeSelector = deployment.NodeSelector
+	template.Spec.Tolerations = deployment.Tolerations
+}
+
+// gatewayPositionsEnv configures the store the gateway container persists the
+// positions of consumers in, positions are held in memory by default.
+func gatewayPositionsEnv(store *streamingv1alpha1.GatewayPositionsStore) []corev1.EnvVar {
+	if store == nil || store.Redis == nil {
+		return []corev1.EnvVar{
+			{Name: "storage_positions_type", Value: "MEMORY"},
+		}
+	}
+	env := []corev1.EnvVar{
+		{Name: "storage_positions_type", Value: "REDIS"},
+		{Name: "redis_address", Value: store.Redis.Address},
+		{Name: "redis_tls", Value: strconv.FormatBool(store.Redis.TLS)},
+	}
+	if store.Redis.PasswordSecretRef != nil {
+		env = append(env, corev1.EnvVar{
+			Name: "redis_password",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: store.Redis.PasswordSecretRef.DeepCopy(),
+			},
+		})
+	}
+	return env
+}
@@ -17,10 +17,12 @@ limitations under the License.
 package streaming_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -97,6 +99,23 @@ func TestGatewayReconciler(t *testing.T) {
 			pts.ContainerNamed("test", func(c *corev1.Container) {
 				c.Image = "scratch"
 			})
+			pts.Affinity(&corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+						{
+							Weight: 100,
+							PodAffinityTerm: corev1.PodAffinityTerm{
+								LabelSelector: &metav1.LabelSelector{
+									MatchLabels: map[string]string{
+										streamingv1alpha1.GatewayLabelKey: testName,
+									},
+								},
+								TopologyKey: "kubernetes.io/hostname",
+							},
+						},
+					},
+				},
+			})
 		})
 	deploymentGiven := deploymentCreate.
 		ObjectMeta(func(om factories.ObjectMeta) {
@@ -104,6 +123,61 @@ func TestGatewayReconciler(t *testing.T) {
 			om.Created(1)
 		})
 
+	podDisruptionBudgetCreate := factories.PodDisruptionBudget().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.GenerateName("%s-gateway-", testName)
+			om.AddLabel(streamingv1alpha1.GatewayLabelKey, testName)
+			om.ControlledBy(gateway, scheme)
+		}).
+		MaxUnavailable(intstr.FromInt(1)).
+		AddSelectorLabel(streamingv1alpha1.GatewayLabelKey, testName)
+	podDisruptionBudgetGiven := podDisruptionBudgetCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Name("%s-gateway-000", testName)
+			om.Created(1)
+		})
+
+	loadBalancerExposure := &streamingv1alpha1.GatewayExposure{
+		Type:          streamingv1alpha1.GatewayExposureLoadBalancer,
+		AuthSecretRef: &corev1.LocalObjectReference{Name: "gateway-auth"},
+	}
+	ingressExposure := &streamingv1alpha1.GatewayExposure{
+		Type:          streamingv1alpha1.GatewayExposureIngress,
+		Host:          "gateway.example.com",
+		TLSSecretRef:  &corev1.LocalObjectReference{Name: "gateway-tls"},
+		AuthSecretRef: &corev1.LocalObjectReference{Name: "gateway-auth"},
+	}
+
+	publicServiceCreate := factories.Service().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.GenerateName("%s-gateway-public-", testName)
+			om.AddLabel(streamingv1alpha1.GatewayLabelKey, testName)
+			om.AddLabel(streamingv1alpha1.GatewayExposureLabelKey, string(streamingv1alpha1.GatewayExposureLoadBalancer))
+			om.ControlledBy(gateway, scheme)
+		}).
+		Type(corev1.ServiceTypeLoadBalancer).
+		AddSelectorLabel(streamingv1alpha1.GatewayLabelKey, testName).
+		Ports(ports[0])
+	publicServiceGiven := publicServiceCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Name("%s-gateway-public-000", testName)
+			om.Created(1)
+		}).
+		ClusterIP("10.10.10.11")
+
+	ingressCreate := factories.Ingress().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.GenerateName("%s-gateway-", testName)
+			om.AddLabel(streamingv1alpha1.GatewayLabelKey, testName)
+			om.AddLabel(streamingv1alpha1.GatewayExposureLabelKey, string(streamingv1alpha1.GatewayExposureIngress))
+			om.ControlledBy(gateway, scheme)
+		}).
+		HostToServicePort("gateway.example.com", fmt.Sprintf("%s-gateway-000", testName), 6565).
+		TLS("gateway-tls", "gateway.example.com")
+
 	table := rtesting.Table{{
 		Name: "gateway does not exist",
 		Key:  testKey,
@@ -188,11 +262,14 @@ func TestGatewayReconciler(t *testing.T) {
 		ExpectEvents: []rtesting.Event{
 			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "Created",
 				`Created Deployment "%s-gateway-001"`, testName),
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "Created",
+				`Created PodDisruptionBudget "%s-gateway-002"`, testName),
 			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
 				`Updated status`),
 		},
 		ExpectCreates: []rtesting.Factory{
 			deploymentCreate,
+			podDisruptionBudgetCreate,
 		},
 		ExpectStatusUpdates: []rtesting.Factory{
 			gatewayMinimal.
@@ -204,7 +281,8 @@ func TestGatewayReconciler(t *testing.T) {
 				).
 				StatusAddress("http://%s-gateway-000.%s.svc.cluster.local", testName, testNamespace).
 				StatusServiceRef("%s-gateway-000", testName).
-				StatusDeploymentRef("%s-gateway-001", testName),
+				StatusDeploymentRef("%s-gateway-001", testName).
+				StatusPodDisruptionBudgetRef("%s-gateway-002", testName),
 		},
 	}, {
 		Name: "update service and deployment",
@@ -225,6 +303,7 @@ func TestGatewayReconciler(t *testing.T) {
 						c.Image = "blah"
 					})
 				}),
+			podDisruptionBudgetGiven,
 		},
 		ExpectEvents: []rtesting.Event{
 			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "Updated",
@@ -248,7 +327,8 @@ func TestGatewayReconciler(t *testing.T) {
 				).
 				StatusAddress("http://%s-gateway-000.%s.svc.cluster.local", testName, testNamespace).
 				StatusServiceRef("%s-gateway-000", testName).
-				StatusDeploymentRef("%s-gateway-000", testName),
+				StatusDeploymentRef("%s-gateway-000", testName).
+				StatusPodDisruptionBudgetRef("%s-gateway-000", testName),
 		},
 	}, {
 		Name: "ready",
@@ -267,6 +347,7 @@ func TestGatewayReconciler(t *testing.T) {
 					deploymentConditionAvailable.True(),
 					deploymentConditionProgressing.True(),
 				),
+			podDisruptionBudgetGiven,
 		},
 		ExpectEvents: []rtesting.Event{
 			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
@@ -282,7 +363,8 @@ func TestGatewayReconciler(t *testing.T) {
 				).
 				StatusAddress("http://%s-gateway-000.%s.svc.cluster.local", testName, testNamespace).
 				StatusServiceRef("%s-gateway-000", testName).
-				StatusDeploymentRef("%s-gateway-000", testName),
+				StatusDeploymentRef("%s-gateway-000", testName).
+				StatusPodDisruptionBudgetRef("%s-gateway-000", testName),
 		},
 	}, {
 		Name: "not ready",
@@ -301,6 +383,7 @@ func TestGatewayReconciler(t *testing.T) {
 					deploymentConditionAvailable.False().Reason("TestReason", "a human readable message"),
 					deploymentConditionProgressing.Unknown(),
 				),
+			podDisruptionBudgetGiven,
 		},
 		ExpectEvents: []rtesting.Event{
 			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
@@ -316,7 +399,298 @@ func TestGatewayReconciler(t *testing.T) {
 				).
 				StatusAddress("http://%s-gateway-000.%s.svc.cluster.local", testName, testNamespace).
 				StatusServiceRef("%s-gateway-000", testName).
-				StatusDeploymentRef("%s-gateway-000", testName),
+				StatusDeploymentRef("%s-gateway-000", testName).
+				StatusPodDisruptionBudgetRef("%s-gateway-000", testName),
+		},
+	}, {
+		Name: "update deployment replicas",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			gatewayMinimal.
+				Ports(ports...).
+				Replicas(3).
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("test", func(c *corev1.Container) {
+						c.Image = "scratch"
+					})
+				}),
+			serviceGiven,
+			deploymentGiven.
+				Replicas(1),
+			podDisruptionBudgetGiven,
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Deployment "%s-gateway-000"`, testName),
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			deploymentGiven.
+				Replicas(3),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			gatewayMinimal.
+				Ports(ports...).
+				Replicas(3).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					gatewayConditionDeploymentReady.Unknown(),
+					gatewayConditionReady.Unknown(),
+					gatewayConditionServiceReady.True(),
+				).
+				StatusAddress("http://%s-gateway-000.%s.svc.cluster.local", testName, testNamespace).
+				StatusServiceRef("%s-gateway-000", testName).
+				StatusDeploymentRef("%s-gateway-000", testName).
+				StatusPodDisruptionBudgetRef("%s-gateway-000", testName),
+		},
+	}, {
+		Name: "retain deployment replicas",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			gatewayMinimal.
+				Ports(ports...).
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("test", func(c *corev1.Container) {
+						c.Image = "scratch"
+					})
+				}),
+			serviceGiven,
+			deploymentGiven.
+				Replicas(2),
+			podDisruptionBudgetGiven,
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			gatewayMinimal.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					gatewayConditionDeploymentReady.Unknown(),
+					gatewayConditionReady.Unknown(),
+					gatewayConditionServiceReady.True(),
+				).
+				StatusAddress("http://%s-gateway-000.%s.svc.cluster.local", testName, testNamespace).
+				StatusServiceRef("%s-gateway-000", testName).
+				StatusDeploymentRef("%s-gateway-000", testName).
+				StatusPodDisruptionBudgetRef("%s-gateway-000", testName),
+		},
+	}, {
+		Name: "create public service",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			gatewayMinimal.
+				Ports(ports...).
+				Exposure(loadBalancerExposure).
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("test", func(c *corev1.Container) {
+						c.Image = "scratch"
+					})
+				}),
+			serviceGiven,
+			deploymentGiven,
+			podDisruptionBudgetGiven,
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "Created",
+				`Created Service "%s-gateway-public-001"`, testName),
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			publicServiceCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			gatewayMinimal.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					gatewayConditionDeploymentReady.Unknown(),
+					gatewayConditionReady.Unknown(),
+					gatewayConditionServiceReady.True(),
+				).
+				StatusAddress("http://%s-gateway-000.%s.svc.cluster.local", testName, testNamespace).
+				StatusServiceRef("%s-gateway-000", testName).
+				StatusDeploymentRef("%s-gateway-000", testName).
+				StatusPodDisruptionBudgetRef("%s-gateway-000", testName).
+				StatusPublicServiceRef("%s-gateway-public-001", testName),
+		},
+	}, {
+		Name: "public address from load balancer",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			gatewayMinimal.
+				Ports(ports...).
+				Exposure(loadBalancerExposure).
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("test", func(c *corev1.Container) {
+						c.Image = "scratch"
+					})
+				}),
+			serviceGiven,
+			deploymentGiven,
+			podDisruptionBudgetGiven,
+			publicServiceGiven.
+				StatusLoadBalancer(corev1.LoadBalancerIngress{IP: "203.0.113.10"}),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			gatewayMinimal.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					gatewayConditionDeploymentReady.Unknown(),
+					gatewayConditionReady.Unknown(),
+					gatewayConditionServiceReady.True(),
+				).
+				StatusAddress("http://%s-gateway-000.%s.svc.cluster.local", testName, testNamespace).
+				StatusServiceRef("%s-gateway-000", testName).
+				StatusDeploymentRef("%s-gateway-000", testName).
+				StatusPodDisruptionBudgetRef("%s-gateway-000", testName).
+				StatusPublicServiceRef("%s-gateway-public-000", testName).
+				StatusPublicAddress("http://203.0.113.10:6565"),
+		},
+	}, {
+		Name: "delete public service when no longer exposed",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			gatewayMinimal.
+				Ports(ports...).
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("test", func(c *corev1.Container) {
+						c.Image = "scratch"
+					})
+				}),
+			serviceGiven,
+			deploymentGiven,
+			podDisruptionBudgetGiven,
+			publicServiceGiven,
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted Service "%s-gateway-public-000"`, testName),
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectDeletes: []rtesting.DeleteRef{
+			{Kind: "Service", Namespace: testNamespace, Name: fmt.Sprintf("%s-gateway-public-000", testName)},
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			gatewayMinimal.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					gatewayConditionDeploymentReady.Unknown(),
+					gatewayConditionReady.Unknown(),
+					gatewayConditionServiceReady.True(),
+				).
+				StatusAddress("http://%s-gateway-000.%s.svc.cluster.local", testName, testNamespace).
+				StatusServiceRef("%s-gateway-000", testName).
+				StatusDeploymentRef("%s-gateway-000", testName).
+				StatusPodDisruptionBudgetRef("%s-gateway-000", testName),
+		},
+	}, {
+		Name: "create ingress",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			gatewayMinimal.
+				Ports(ports...).
+				Exposure(ingressExposure).
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("test", func(c *corev1.Container) {
+						c.Image = "scratch"
+					})
+				}),
+			serviceGiven,
+			deploymentGiven,
+			podDisruptionBudgetGiven,
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "Created",
+				`Created Ingress "%s-gateway-001"`, testName),
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			ingressCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			gatewayMinimal.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					gatewayConditionDeploymentReady.Unknown(),
+					gatewayConditionReady.Unknown(),
+					gatewayConditionServiceReady.True(),
+				).
+				StatusAddress("http://%s-gateway-000.%s.svc.cluster.local", testName, testNamespace).
+				StatusServiceRef("%s-gateway-000", testName).
+				StatusDeploymentRef("%s-gateway-000", testName).
+				StatusPodDisruptionBudgetRef("%s-gateway-000", testName).
+				StatusIngressRef("%s-gateway-001", testName).
+				StatusPublicAddress("https://gateway.example.com"),
+		},
+	}, {
+		Name: "gateway requires auth token",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			gatewayMinimal.
+				Ports(ports...).
+				Exposure(ingressExposure).
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("test", func(c *corev1.Container) {
+						c.Image = "scratch"
+					})
+					pts.ContainerNamed("gateway", func(c *corev1.Container) {
+						c.Image = "scratch"
+					})
+				}),
+			serviceGiven,
+			podDisruptionBudgetGiven,
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "Created",
+				`Created Deployment "%s-gateway-001"`, testName),
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "Created",
+				`Created Ingress "%s-gateway-002"`, testName),
+			rtesting.NewEvent(gateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			deploymentCreate.
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("gateway", func(c *corev1.Container) {
+						c.Image = "scratch"
+						c.Env = []corev1.EnvVar{
+							{
+								Name: "auth_token",
+								ValueFrom: &corev1.EnvVarSource{
+									SecretKeyRef: &corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "gateway-auth"},
+										Key:                  streamingv1alpha1.GatewayAuthTokenKey,
+									},
+								},
+							},
+						}
+					})
+				}),
+			ingressCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			gatewayMinimal.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					gatewayConditionDeploymentReady.Unknown(),
+					gatewayConditionReady.Unknown(),
+					gatewayConditionServiceReady.True(),
+				).
+				StatusAddress("http://%s-gateway-000.%s.svc.cluster.local", testName, testNamespace).
+				StatusServiceRef("%s-gateway-000", testName).
+				StatusDeploymentRef("%s-gateway-001", testName).
+				StatusPodDisruptionBudgetRef("%s-gateway-000", testName).
+				StatusIngressRef("%s-gateway-002", testName).
+				StatusPublicAddress("https://gateway.example.com"),
 		},
 	}}
 
@@ -333,3 +707,34 @@ func TestGatewayReconciler(t *testing.T) {
 		)
 	})
 }
+
+// probeGatewayContainers adds the probes gateway types set on the containers
+// of the gateway they generate
+func probeGatewayContainers(pts factories.PodTemplateSpec) {
+	pts.ContainerNamed("gateway", func(c *corev1.Container) {
+		c.ReadinessProbe = &corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/health", Port: intstr.FromInt(8000)},
+			},
+		}
+		c.LivenessProbe = &corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/health", Port: intstr.FromInt(8000)},
+			},
+			InitialDelaySeconds: 30,
+		}
+	})
+	pts.ContainerNamed("provisioner", func(c *corev1.Container) {
+		c.ReadinessProbe = &corev1.Probe{
+			Handler: corev1.Handler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8080)},
+			},
+		}
+		c.LivenessProbe = &corev1.Probe{
+			Handler: corev1.Handler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8080)},
+			},
+			InitialDelaySeconds: 30,
+		}
+	})
+}
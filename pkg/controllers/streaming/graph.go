@@ -0,0 +1,388 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+)
+
+const (
+	GraphNodeKindStream    = "Stream"
+	GraphNodeKindProcessor = "Processor"
+)
+
+// Graph of the streams and processors in a namespace. Edges flow from a
+// stream to the processors reading from it, and from a processor to the
+// streams it writes to.
+type Graph struct {
+	Namespace string      `json:"namespace"`
+	Nodes     []GraphNode `json:"nodes"`
+	Edges     []GraphEdge `json:"edges"`
+}
+
+type GraphNode struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Ready     bool   `json:"ready"`
+}
+
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func graphNodeID(kind string, key types.NamespacedName) string {
+	return fmt.Sprintf("%s/%s", strings.ToLower(kind), key)
+}
+
+// BuildGraph collects the streams and processors in the namespace. Streams in
+// other namespaces read or written by processors in the namespace, and
+// processors in other namespaces reading or writing streams in the namespace,
+// are included so the graph is complete at its edges.
+func BuildGraph(ctx context.Context, c client.Reader, namespace string) (*Graph, error) {
+	var streams streamingv1alpha1.StreamList
+	if err := c.List(ctx, &streams, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	var processors streamingv1alpha1.ProcessorList
+	if err := c.List(ctx, &processors, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	nodes := map[string]GraphNode{}
+	edges := map[GraphEdge]bool{}
+	addNode := func(kind string, key types.NamespacedName, ready bool) string {
+		id := graphNodeID(kind, key)
+		if node, ok := nodes[id]; !ok || (!node.Ready && ready) {
+			nodes[id] = GraphNode{ID: id, Kind: kind, Namespace: key.Namespace, Name: key.Name, Ready: ready}
+		}
+		return id
+	}
+
+	for i := range streams.Items {
+		stream := &streams.Items[i]
+		key := types.NamespacedName{Namespace: stream.Namespace, Name: stream.Name}
+		id := addNode(GraphNodeKindStream, key, stream.Status.IsReady())
+		// processors in other namespaces are only known by the stream status
+		for _, consumer := range stream.Status.Consumers {
+			if !strings.Contains(consumer, "/") {
+				continue
+			}
+			edges[GraphEdge{From: id, To: addNode(GraphNodeKindProcessor, parseNamespacedName(consumer), false)}] = true
+		}
+		for _, producer := range stream.Status.Producers {
+			if !strings.Contains(producer, "/") {
+				continue
+			}
+			edges[GraphEdge{From: addNode(GraphNodeKindProcessor, parseNamespacedName(producer), false), To: id}] = true
+		}
+	}
+	for i := range processors.Items {
+		processor := &processors.Items[i]
+		key := types.NamespacedName{Namespace: processor.Namespace, Name: processor.Name}
+		id := addNode(GraphNodeKindProcessor, key, processor.Status.IsReady())
+		for _, input := range processorStreamKeys(processor, processorInputStreams) {
+			edges[GraphEdge{From: addNode(GraphNodeKindStream, parseNamespacedName(input), false), To: id}] = true
+		}
+		for _, output := range processorStreamKeys(processor, processorOutputStreams) {
+			edges[GraphEdge{From: id, To: addNode(GraphNodeKindStream, parseNamespacedName(output), false)}] = true
+		}
+	}
+
+	graph := &Graph{
+		Namespace: namespace,
+		Nodes:     []GraphNode{},
+		Edges:     []GraphEdge{},
+	}
+	for _, node := range nodes {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+	for edge := range edges {
+		graph.Edges = append(graph.Edges, edge)
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From {
+			return graph.Edges[i].From < graph.Edges[j].From
+		}
+		return graph.Edges[i].To < graph.Edges[j].To
+	})
+
+	return graph, nil
+}
+
+// omitNamespaces removes the nodes in the namespaces, and their edges, from the
+// graph.
+func (g *Graph) omitNamespaces(namespaces map[string]bool) {
+	omitted := map[string]bool{}
+	nodes := []GraphNode{}
+	for _, node := range g.Nodes {
+		if namespaces[node.Namespace] {
+			omitted[node.ID] = true
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	edges := []GraphEdge{}
+	for _, edge := range g.Edges {
+		if omitted[edge.From] || omitted[edge.To] {
+			continue
+		}
+		edges = append(edges, edge)
+	}
+	g.Nodes = nodes
+	g.Edges = edges
+}
+
+// DOT renders the graph in the Graphviz DOT language. Streams are drawn as
+// boxes and processors as ellipses, resources that are not ready are dashed.
+func (g *Graph) DOT() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "digraph %q {\n", g.Namespace)
+	for _, node := range g.Nodes {
+		label := node.Name
+		if node.Namespace != g.Namespace {
+			label = fmt.Sprintf("%s/%s", node.Namespace, node.Name)
+		}
+		shape := "box"
+		if node.Kind == GraphNodeKindProcessor {
+			shape = "ellipse"
+		}
+		style := "solid"
+		if !node.Ready {
+			style = "dashed"
+		}
+		fmt.Fprintf(&b, "  %q [label=%q, shape=%s, style=%s];\n", node.ID, label, shape, style)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q;\n", edge.From, edge.To)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// GraphAuthorizer decides if the bearer of a token may read the graph of a
+// namespace.
+type GraphAuthorizer interface {
+	// Authorize returns whether the token is authenticated and, if so, whether
+	// its user is allowed to read the graph.
+	Authorize(ctx context.Context, token, namespace string) (authenticated, allowed bool, err error)
+}
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// NewGraphAuthorizer authenticates tokens with a TokenReview and allows users
+// that may list the streams and processors in the namespace, as determined by
+// SubjectAccessReviews.
+func NewGraphAuthorizer(c client.Client) GraphAuthorizer {
+	return &reviewGraphAuthorizer{client: c}
+}
+
+type reviewGraphAuthorizer struct {
+	client client.Client
+}
+
+func (a *reviewGraphAuthorizer) Authorize(ctx context.Context, token, namespace string) (bool, bool, error) {
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}
+	if err := a.client.Create(ctx, review); err != nil {
+		return false, false, err
+	}
+	if !review.Status.Authenticated {
+		return false, false, nil
+	}
+
+	user := review.Status.User
+	extra := map[string]authorizationv1.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	for _, resource := range []string{"streams", "processors"} {
+		access := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:   user.Username,
+				UID:    user.UID,
+				Groups: user.Groups,
+				Extra:  extra,
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      "list",
+					Group:     streamingv1alpha1.GroupVersion.Group,
+					Resource:  resource,
+				},
+			},
+		}
+		if err := a.client.Create(ctx, access); err != nil {
+			return true, false, err
+		}
+		if !access.Status.Allowed {
+			return true, false, nil
+		}
+	}
+	return true, true, nil
+}
+
+// GraphHandler serves the graph for a namespace at /graph/{namespace}. The
+// graph is rendered as JSON unless `?format=dot` is requested. Requests must
+// present a bearer token the authorizer allows for the namespace. Streams and
+// processors in other namespaces are omitted unless the token is also allowed
+// for their namespace.
+func GraphHandler(c client.Reader, authorizer GraphAuthorizer, log logr.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		namespace := strings.Trim(strings.TrimPrefix(r.URL.Path, "/graph/"), "/")
+		if namespace == "" || strings.Contains(namespace, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		authorization := r.Header.Get("Authorization")
+		token := strings.TrimPrefix(authorization, "Bearer ")
+		if token == "" || token == authorization {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		authenticated, allowed, err := authorizer.Authorize(r.Context(), token, namespace)
+		if err != nil {
+			log.Error(err, "unable to authorize graph request", "namespace", namespace)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if !authenticated {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if !allowed {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		graph, err := BuildGraph(r.Context(), c, namespace)
+		if err != nil {
+			log.Error(err, "unable to build graph", "namespace", namespace)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		forbidden := map[string]bool{}
+		checked := map[string]bool{namespace: true}
+		for _, node := range graph.Nodes {
+			if checked[node.Namespace] {
+				continue
+			}
+			checked[node.Namespace] = true
+			if _, allowed, err := authorizer.Authorize(r.Context(), token, node.Namespace); err != nil {
+				log.Error(err, "unable to authorize graph request", "namespace", node.Namespace)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			} else if !allowed {
+				forbidden[node.Namespace] = true
+			}
+		}
+		graph.omitNamespaces(forbidden)
+
+		switch format := r.URL.Query().Get("format"); format {
+		case "", "json":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(graph)
+		case "dot":
+			w.Header().Set("Content-Type", "text/vnd.graphviz")
+			w.Write([]byte(graph.DOT()))
+		default:
+			http.Error(w, fmt.Sprintf("unknown format %q", format), http.StatusBadRequest)
+		}
+	})
+}
+
+// GraphServer serves the GraphHandler over TLS on addr, authorizing requests
+// with the NewGraphAuthorizer. The serving certificate and key are read from
+// tls.crt and tls.key in certDir, the webhook server's certificate is shared.
+// The server runs on every manager replica, it does not need to hold the
+// leader election lease.
+func GraphServer(addr, certDir string, c client.Client, log logr.Logger) manager.Runnable {
+	return &graphServer{addr: addr, certDir: certDir, client: c, log: log}
+}
+
+type graphServer struct {
+	addr    string
+	certDir string
+	client  client.Client
+	log     logr.Logger
+}
+
+var (
+	_ manager.Runnable               = (*graphServer)(nil)
+	_ manager.LeaderElectionRunnable = (*graphServer)(nil)
+)
+
+func (s *graphServer) Start(stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.Handle("/graph/", GraphHandler(s.client, NewGraphAuthorizer(s.client), s.log))
+	server := &http.Server{Addr: s.addr, Handler: mux}
+
+	errs := make(chan error, 1)
+	go func() {
+		s.log.Info("starting graph server", "addr", s.addr)
+		certFile := filepath.Join(s.certDir, "tls.crt")
+		keyFile := filepath.Join(s.certDir, "tls.key")
+		if err := server.ListenAndServeTLS(certFile, keyFile); err != nil && err != http.ErrServerClosed {
+			errs <- err
+		}
+		close(errs)
+	}()
+
+	select {
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return server.Shutdown(ctx)
+	case err := <-errs:
+		return err
+	}
+}
+
+func (s *graphServer) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,421 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/projectriff/system/pkg/apis"
+	corev1alpha1 "github.com/projectriff/system/pkg/apis/core/v1alpha1"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/refs"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+const (
+	HTTPSourceImagesStashKey controllers.StashKey = "http-source-images"
+	HTTPSourceStreamStashKey controllers.StashKey = "http-source-stream"
+)
+
+const (
+	httpSourcePort = 8080
+)
+
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=httpsources,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=httpsources/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streams,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+func HTTPSourceReconciler(c controllers.Config, namespace string) *controllers.ParentReconciler {
+	c.Log = c.Log.WithName("HTTPSource")
+
+	return &controllers.ParentReconciler{
+		Type: &streamingv1alpha1.HTTPSource{},
+		SubReconcilers: []controllers.SubReconciler{
+			HTTPSourceSyncImages(c, namespace),
+			HTTPSourceResolveStreamReconciler(c),
+			HTTPSourceChildDeploymentReconciler(c),
+			HTTPSourceChildServiceReconciler(c),
+			HTTPSourceChildIngressReconciler(c, namespace),
+		},
+
+		Config: c,
+	}
+}
+
+func HTTPSourceSyncImages(c controllers.Config, namespace string) controllers.SubReconciler {
+	c.Log = c.Log.WithName("SyncImages")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.HTTPSource) error {
+			config := corev1.ConfigMap{}
+			key := types.NamespacedName{Namespace: namespace, Name: httpSourceImages}
+			// track config for new images
+			c.Tracker.Track(
+				tracker.NewKey(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, key),
+				types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+			)
+			if err := c.Get(ctx, key, &config); err != nil {
+				return err
+			}
+			controllers.StashValue(ctx, HTTPSourceImagesStashKey, config.Data)
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &corev1.ConfigMap{}}, controllers.EnqueueTracked(&corev1.ConfigMap{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
+
+func HTTPSourceResolveStreamReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ResolveStream")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.HTTPSource) error {
+			var stream streamingv1alpha1.Stream
+			key := types.NamespacedName{Namespace: parent.Namespace, Name: parent.Spec.Output}
+			// track stream for new coordinates
+			c.Tracker.Track(
+				tracker.NewKey(stream.GetGroupVersionKind(), key),
+				types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+			)
+			if err := c.Get(ctx, key, &stream); err != nil {
+				if apierrs.IsNotFound(err) {
+					parent.Status.MarkStreamNotReady(fmt.Sprintf("stream %s not found", parent.Spec.Output))
+					return nil
+				}
+				return err
+			}
+			controllers.StashValue(ctx, HTTPSourceStreamStashKey, &stream)
+
+			ready := stream.Status.GetCondition(stream.Status.GetReadyConditionType())
+			if ready == nil {
+				ready = &apis.Condition{Message: "stream has no ready condition"}
+			}
+			if !ready.IsTrue() {
+				parent.Status.MarkStreamNotReady(fmt.Sprintf("stream %s is not ready: %s", stream.Name, ready.Message))
+				return nil
+			}
+			parent.Status.MarkStreamReady()
+
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &streamingv1alpha1.Stream{}}, controllers.EnqueueTracked(&streamingv1alpha1.Stream{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
+
+func HTTPSourceChildDeploymentReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildDeployment")
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.HTTPSource{},
+		ChildType:     &appsv1.Deployment{},
+		ChildListType: &appsv1.DeploymentList{},
+
+		DesiredChild: func(ctx context.Context, parent *streamingv1alpha1.HTTPSource) (*appsv1.Deployment, error) {
+			stream, ok := controllers.RetrieveValue(ctx, HTTPSourceStreamStashKey).(*streamingv1alpha1.Stream)
+			if !ok {
+				// no stream, skip
+				return nil, nil
+			}
+			images, ok := controllers.RetrieveValue(ctx, HTTPSourceImagesStashKey).(map[string]string)
+			if !ok {
+				return nil, nil
+			}
+			image := images[httpSourceImageKey]
+			if image == "" {
+				return nil, nil
+			}
+
+			labels := controllers.MergeMaps(parent.Labels, map[string]string{
+				streamingv1alpha1.HTTPSourceLabelKey: parent.Name,
+			})
+			volumes, volumeMounts := constructBindingVolumes(nil, []streamingv1alpha1.Stream{*stream}, streamBinding)
+
+			child := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: fmt.Sprintf("%s-http-source-", parent.Name),
+					Namespace:    parent.Namespace,
+					Labels:       labels,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							streamingv1alpha1.HTTPSourceLabelKey: parent.Name,
+						},
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: labels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name:  "receiver",
+									Image: image,
+									Ports: []corev1.ContainerPort{
+										{Name: "http", ContainerPort: httpSourcePort, Protocol: corev1.ProtocolTCP},
+									},
+									Env: []corev1.EnvVar{
+										{Name: "CNB_BINDINGS", Value: bindingsRootPath},
+										{Name: "OUTPUT_NAMES", Value: stream.Name},
+										{Name: "OUTPUT_CONTENT_TYPE", Value: stream.Spec.ContentType},
+										{Name: "PORT", Value: fmt.Sprintf("%d", httpSourcePort)},
+									},
+									ReadinessProbe: &corev1.Probe{
+										Handler: corev1.Handler{
+											TCPSocket: &corev1.TCPSocketAction{
+												Port: intstr.FromInt(httpSourcePort),
+											},
+										},
+									},
+									VolumeMounts: volumeMounts,
+								},
+							},
+							Volumes: volumes,
+						},
+					},
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.HTTPSource, child *appsv1.Deployment, err error) {
+			if err != nil {
+				return
+			}
+			if child == nil {
+				parent.Status.DeploymentRef = nil
+			} else {
+				parent.Status.DeploymentRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+				parent.Status.PropagateDeploymentStatus(&child.Status)
+			}
+		},
+		HarmonizeImmutableFields: func(current, desired *appsv1.Deployment) {
+			desired.Spec.Replicas = current.Spec.Replicas
+		},
+		MergeBeforeUpdate: func(current, desired *appsv1.Deployment) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *appsv1.Deployment) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.httpSourceDeploymentController",
+		Sanitize: func(child *appsv1.Deployment) interface{} {
+			return child.Spec
+		},
+	}
+}
+
+func HTTPSourceChildServiceReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildService")
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.HTTPSource{},
+		ChildType:     &corev1.Service{},
+		ChildListType: &corev1.ServiceList{},
+
+		DesiredChild: func(parent *streamingv1alpha1.HTTPSource) (*corev1.Service, error) {
+			if parent.Status.DeploymentRef == nil {
+				// no deployment, skip
+				return nil, nil
+			}
+
+			child := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: controllers.MergeMaps(parent.Labels, map[string]string{
+						streamingv1alpha1.HTTPSourceLabelKey: parent.Name,
+					}),
+					Annotations: make(map[string]string),
+					Namespace:   parent.Namespace,
+					Name:        parent.Name,
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{Name: "http", Port: 80, TargetPort: intstr.FromInt(httpSourcePort)},
+					},
+					Selector: map[string]string{
+						streamingv1alpha1.HTTPSourceLabelKey: parent.Name,
+					},
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.HTTPSource, child *corev1.Service, err error) {
+			if err != nil {
+				if apierrs.IsAlreadyExists(err) {
+					name := err.(apierrs.APIStatus).Status().Details.Name
+					parent.Status.MarkServiceNotOwned(name)
+				}
+				return
+			}
+			if child == nil {
+				parent.Status.ServiceRef = nil
+				parent.Status.Address = nil
+			} else {
+				parent.Status.ServiceRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+				parent.Status.Address = &apis.Addressable{URL: fmt.Sprintf("http://%s.%s.%s", child.Name, child.Namespace, "svc.cluster.local")}
+				parent.Status.PropagateServiceStatus(&child.Status)
+			}
+		},
+		HarmonizeImmutableFields: func(current, desired *corev1.Service) {
+			desired.Spec.ClusterIP = current.Spec.ClusterIP
+		},
+		MergeBeforeUpdate: func(current, desired *corev1.Service) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *corev1.Service) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.httpSourceServiceController",
+		Sanitize: func(child *corev1.Service) interface{} {
+			return child.Spec
+		},
+	}
+}
+
+func HTTPSourceChildIngressReconciler(c controllers.Config, namespace string) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildIngress")
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.HTTPSource{},
+		ChildType:     &networkingv1beta1.Ingress{},
+		ChildListType: &networkingv1beta1.IngressList{},
+
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &corev1.ConfigMap{}}, controllers.EnqueueTracked(&corev1.ConfigMap{}, c.Tracker, c.Scheme))
+			return nil
+		},
+		DesiredChild: func(ctx context.Context, parent *streamingv1alpha1.HTTPSource) (*networkingv1beta1.Ingress, error) {
+			if parent.Status.ServiceRef == nil || parent.Spec.IngressPolicy != corev1alpha1.IngressPolicyExternal {
+				// no service or not exposed, skip
+				return nil, nil
+			}
+
+			settings := &corev1.ConfigMap{}
+			settingsKey := types.NamespacedName{Namespace: namespace, Name: settingsConfigMapName}
+			// track config map
+			c.Tracker.Track(
+				tracker.NewKey(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, settingsKey),
+				types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+			)
+			if err := c.Get(ctx, settingsKey, settings); err != nil {
+				c.Log.Error(err, fmt.Sprintf("unable to fetch resource with reference: %s", settingsKey.String()))
+				return nil, err
+			}
+
+			domain := defaultDomain
+			if d := settings.Data[defaultDomainKey]; d != "" {
+				domain = d
+			}
+			host := fmt.Sprintf("%s.%s.%s", parent.Name, parent.Namespace, domain)
+
+			child := &networkingv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: controllers.MergeMaps(parent.Labels, map[string]string{
+						streamingv1alpha1.HTTPSourceLabelKey: parent.Name,
+					}),
+					Annotations:  make(map[string]string),
+					GenerateName: fmt.Sprintf("%s-http-source-", parent.Name),
+					Namespace:    parent.Namespace,
+				},
+				Spec: networkingv1beta1.IngressSpec{
+					Rules: []networkingv1beta1.IngressRule{{
+						Host: host,
+						IngressRuleValue: networkingv1beta1.IngressRuleValue{
+							HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+								Paths: []networkingv1beta1.HTTPIngressPath{{
+									Path: "/",
+									Backend: networkingv1beta1.IngressBackend{
+										ServiceName: parent.Status.ServiceRef.Name,
+										ServicePort: intstr.FromInt(80),
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.HTTPSource, child *networkingv1beta1.Ingress, err error) {
+			if err != nil {
+				return
+			}
+			if child == nil {
+				parent.Status.IngressRef = nil
+				parent.Status.URL = ""
+				if parent.Spec.IngressPolicy != corev1alpha1.IngressPolicyExternal {
+					parent.Status.MarkIngressNotRequired()
+				}
+			} else {
+				parent.Status.IngressRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+				parent.Status.URL = fmt.Sprintf("http://%s", child.Spec.Rules[0].Host)
+				parent.Status.PropagateIngressStatus(&child.Status)
+			}
+		},
+		MergeBeforeUpdate: func(current, desired *networkingv1beta1.Ingress) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *networkingv1beta1.Ingress) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.httpSourceIngressController",
+		Sanitize: func(child *networkingv1beta1.Ingress) interface{} {
+			return child.Spec
+		},
+	}
+}
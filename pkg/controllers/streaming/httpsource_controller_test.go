@@ -0,0 +1,486 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/projectriff/system/pkg/apis/core/v1alpha1"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/controllers/streaming"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/controllers/testing/factories"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+func TestHTTPSourceReconciler(t *testing.T) {
+	testNamespace := "test-namespace"
+	testSystemNamespace := "system-namespace"
+	testName := "test-source"
+	testKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
+	testReceiverImage := "example.com/repo/receiver"
+	testDomain := "example.com"
+	testHost := fmt.Sprintf("%s.%s.%s", testName, testNamespace, testDomain)
+	testURL := fmt.Sprintf("http://%s", testHost)
+	testAddressURL := fmt.Sprintf("http://%s.%s.svc.cluster.local", testName, testNamespace)
+
+	httpSourceImages := "riff-streaming-http-source" // contains image names for the http source
+	receiverImageKey := "receiverImage"
+
+	httpSourceConditionDeploymentReady := factories.Condition().Type(streamingv1alpha1.HTTPSourceConditionDeploymentReady)
+	httpSourceConditionIngressReady := factories.Condition().Type(streamingv1alpha1.HTTPSourceConditionIngressReady)
+	httpSourceConditionReady := factories.Condition().Type(streamingv1alpha1.HTTPSourceConditionReady)
+	httpSourceConditionServiceReady := factories.Condition().Type(streamingv1alpha1.HTTPSourceConditionServiceReady)
+	httpSourceConditionStreamReady := factories.Condition().Type(streamingv1alpha1.HTTPSourceConditionStreamReady)
+	deploymentConditionAvailable := factories.Condition().Type("Available")
+	deploymentConditionProgressing := factories.Condition().Type("Progressing")
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	httpSourceImagesConfigMap := factories.ConfigMap().
+		NamespaceName(testSystemNamespace, httpSourceImages).
+		AddData(receiverImageKey, testReceiverImage)
+	testSettings := factories.ConfigMap().
+		NamespaceName(testSystemNamespace, "riff-streaming-settings").
+		AddData("defaultDomain", testDomain)
+
+	testStream := factories.Stream().
+		NamespaceName(testNamespace, "my-stream").
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.UID("00000000-0000-0000-0000-000000000001")
+		}).
+		ContentType("application/json").
+		StatusBinding("my-stream-binding-metadata", "my-stream-binding-secret").
+		StatusReady()
+
+	httpSourceMinimal := factories.HTTPSource().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.Generation(1)
+		}).
+		Output(testStream.Create().Name).
+		IngressPolicy(corev1alpha1.IngressPolicyClusterLocal)
+	httpSource := httpSourceMinimal.
+		StatusDeploymentRef("%s-http-source-000", testName).
+		StatusServiceRef(testName).
+		StatusAddressURL(testAddressURL)
+
+	deploymentCreate := factories.Deployment().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.GenerateName("%s-http-source-", testName)
+			om.AddLabel(streamingv1alpha1.HTTPSourceLabelKey, testName)
+			om.ControlledBy(httpSource, scheme)
+		}).
+		AddSelectorLabel(streamingv1alpha1.HTTPSourceLabelKey, testName).
+		PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+			pts.ContainerNamed("receiver", func(c *corev1.Container) {
+				c.Image = testReceiverImage
+				c.Ports = []corev1.ContainerPort{
+					{Name: "http", ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+				}
+				c.Env = []corev1.EnvVar{
+					{Name: "CNB_BINDINGS", Value: "/var/riff/bindings"},
+					{Name: "OUTPUT_NAMES", Value: "my-stream"},
+					{Name: "OUTPUT_CONTENT_TYPE", Value: "application/json"},
+					{Name: "PORT", Value: "8080"},
+				}
+				c.ReadinessProbe = &corev1.Probe{
+					Handler: corev1.Handler{
+						TCPSocket: &corev1.TCPSocketAction{
+							Port: intstr.FromInt(8080),
+						},
+					},
+				}
+				c.VolumeMounts = []corev1.VolumeMount{
+					{
+						Name:      "stream-00000000-0000-0000-0000-000000000001-metadata",
+						MountPath: "/var/riff/bindings/output_000/metadata",
+						ReadOnly:  true,
+					},
+					{
+						Name:      "stream-00000000-0000-0000-0000-000000000001-secret",
+						MountPath: "/var/riff/bindings/output_000/secret",
+						ReadOnly:  true,
+					},
+				}
+			})
+			pts.Volumes(
+				corev1.Volume{
+					Name: "stream-00000000-0000-0000-0000-000000000001-metadata",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: "my-stream-binding-metadata",
+							},
+						},
+					},
+				},
+				corev1.Volume{
+					Name: "stream-00000000-0000-0000-0000-000000000001-secret",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "my-stream-binding-secret",
+						},
+					},
+				},
+			)
+		})
+	deploymentGiven := deploymentCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Name("%s%s", om.Create().GenerateName, "000")
+			om.Created(1)
+		})
+
+	serviceCreate := factories.Service().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.AddLabel(streamingv1alpha1.HTTPSourceLabelKey, testName)
+			om.ControlledBy(httpSource, scheme)
+		}).
+		AddSelectorLabel(streamingv1alpha1.HTTPSourceLabelKey, testName).
+		Ports(
+			corev1.ServicePort{
+				Name:       "http",
+				Port:       80,
+				TargetPort: intstr.FromInt(8080),
+			},
+		)
+	serviceGiven := serviceCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+		})
+
+	ingressCreate := factories.Ingress().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.GenerateName("%s-http-source-", testName)
+			om.AddLabel(streamingv1alpha1.HTTPSourceLabelKey, testName)
+			om.ControlledBy(httpSource, scheme)
+		}).
+		HostToService(testHost, testName)
+	ingressGiven := ingressCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Name("%s%s", om.Create().GenerateName, "000")
+			om.Created(1)
+		})
+
+	table := rtesting.Table{{
+		Name: "httpsource does not exist",
+		Key:  testKey,
+	}, {
+		Name: "ignore deleted httpsource",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			httpSource.
+				ObjectMeta(func(om factories.ObjectMeta) {
+					om.Deleted(1)
+				}),
+		},
+	}, {
+		Name: "error fetching httpsource",
+		Key:  testKey,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("get", "HTTPSource"),
+		},
+		ShouldErr: true,
+	}, {
+		Name: "create resources",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			httpSourceMinimal,
+			httpSourceImagesConfigMap,
+			testStream,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(httpSourceImagesConfigMap, httpSourceMinimal, scheme),
+			rtesting.NewTrackRequest(testStream, httpSourceMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(httpSourceMinimal, scheme, corev1.EventTypeNormal, "Created",
+				`Created Deployment "%s-http-source-001"`, testName),
+			rtesting.NewEvent(httpSourceMinimal, scheme, corev1.EventTypeNormal, "Created",
+				`Created Service "%s"`, testName),
+			rtesting.NewEvent(httpSourceMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			deploymentCreate,
+			serviceCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			httpSourceMinimal.
+				StatusConditions(
+					httpSourceConditionDeploymentReady.Unknown(),
+					httpSourceConditionIngressReady.True(),
+					httpSourceConditionReady.Unknown(),
+					httpSourceConditionServiceReady.True(),
+					httpSourceConditionStreamReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusDeploymentRef("%s-http-source-001", testName).
+				StatusServiceRef(testName).
+				StatusAddressURL(testAddressURL),
+		},
+	}, {
+		Name: "ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			httpSource.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					httpSourceConditionDeploymentReady.True(),
+					httpSourceConditionIngressReady.True(),
+					httpSourceConditionReady.True(),
+					httpSourceConditionServiceReady.True(),
+					httpSourceConditionStreamReady.True(),
+				),
+			httpSourceImagesConfigMap,
+			testStream,
+			deploymentGiven.
+				StatusConditions(
+					deploymentConditionAvailable.True(),
+					deploymentConditionProgressing.True(),
+				),
+			serviceGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(httpSourceImagesConfigMap, httpSource, scheme),
+			rtesting.NewTrackRequest(testStream, httpSource, scheme),
+		},
+	}, {
+		Name: "images missing",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			httpSourceMinimal,
+			testStream,
+		},
+		ShouldErr: true,
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(httpSourceImagesConfigMap, httpSourceMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(httpSourceMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			httpSourceMinimal.
+				StatusConditions(
+					httpSourceConditionDeploymentReady.Unknown(),
+					httpSourceConditionIngressReady.Unknown(),
+					httpSourceConditionReady.Unknown(),
+					httpSourceConditionServiceReady.Unknown(),
+					httpSourceConditionStreamReady.Unknown(),
+				),
+		},
+	}, {
+		Name: "stream not found",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			httpSourceMinimal,
+			httpSourceImagesConfigMap,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(httpSourceImagesConfigMap, httpSourceMinimal, scheme),
+			rtesting.NewTrackRequest(testStream, httpSourceMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(httpSourceMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			httpSourceMinimal.
+				StatusConditions(
+					httpSourceConditionDeploymentReady.Unknown(),
+					httpSourceConditionIngressReady.True(),
+					httpSourceConditionReady.False().Reason("StreamNotReady", "stream my-stream not found"),
+					httpSourceConditionServiceReady.Unknown(),
+					httpSourceConditionStreamReady.False().Reason("StreamNotReady", "stream my-stream not found"),
+				).
+				StatusObservedGeneration(1),
+		},
+	}, {
+		Name: "stream not ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			httpSource,
+			httpSourceImagesConfigMap,
+			testStream.
+				StatusConditions(),
+			deploymentGiven,
+			serviceGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(httpSourceImagesConfigMap, httpSource, scheme),
+			rtesting.NewTrackRequest(testStream, httpSource, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(httpSource, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			httpSource.
+				StatusConditions(
+					httpSourceConditionDeploymentReady.Unknown(),
+					httpSourceConditionIngressReady.True(),
+					httpSourceConditionReady.False().Reason("StreamNotReady", "stream my-stream is not ready: stream has no ready condition"),
+					httpSourceConditionServiceReady.True(),
+					httpSourceConditionStreamReady.False().Reason("StreamNotReady", "stream my-stream is not ready: stream has no ready condition"),
+				).
+				StatusObservedGeneration(1),
+		},
+	}, {
+		Name: "create ingress",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			httpSource.
+				IngressPolicy(corev1alpha1.IngressPolicyExternal),
+			httpSourceImagesConfigMap,
+			testSettings,
+			testStream,
+			deploymentGiven,
+			serviceGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(httpSourceImagesConfigMap, httpSource, scheme),
+			rtesting.NewTrackRequest(testStream, httpSource, scheme),
+			rtesting.NewTrackRequest(testSettings, httpSource, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(httpSource, scheme, corev1.EventTypeNormal, "Created",
+				`Created Ingress "%s-http-source-001"`, testName),
+			rtesting.NewEvent(httpSource, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			ingressCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			httpSource.
+				IngressPolicy(corev1alpha1.IngressPolicyExternal).
+				StatusConditions(
+					httpSourceConditionDeploymentReady.Unknown(),
+					httpSourceConditionIngressReady.True(),
+					httpSourceConditionReady.Unknown(),
+					httpSourceConditionServiceReady.True(),
+					httpSourceConditionStreamReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusIngressRef("%s-http-source-001", testName).
+				StatusURL(testURL),
+		},
+	}, {
+		Name: "create ingress, missing settings",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			httpSource.
+				IngressPolicy(corev1alpha1.IngressPolicyExternal),
+			httpSourceImagesConfigMap,
+			testStream,
+			deploymentGiven,
+			serviceGiven,
+		},
+		ShouldErr: true,
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(httpSourceImagesConfigMap, httpSource, scheme),
+			rtesting.NewTrackRequest(testStream, httpSource, scheme),
+			rtesting.NewTrackRequest(testSettings, httpSource, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(httpSource, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			httpSource.
+				IngressPolicy(corev1alpha1.IngressPolicyExternal).
+				StatusConditions(
+					httpSourceConditionDeploymentReady.Unknown(),
+					httpSourceConditionIngressReady.Unknown(),
+					httpSourceConditionReady.Unknown(),
+					httpSourceConditionServiceReady.True(),
+					httpSourceConditionStreamReady.True(),
+				),
+		},
+	}, {
+		Name: "delete ingress, cluster local",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			httpSource.
+				StatusIngressRef("%s-http-source-000", testName).
+				StatusURL(testURL),
+			httpSourceImagesConfigMap,
+			testStream,
+			deploymentGiven,
+			serviceGiven,
+			ingressGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(httpSourceImagesConfigMap, httpSource, scheme),
+			rtesting.NewTrackRequest(testStream, httpSource, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(httpSource, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted Ingress "%s-http-source-000"`, testName),
+			rtesting.NewEvent(httpSource, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectDeletes: []rtesting.DeleteRef{
+			{Group: "networking.k8s.io", Kind: "Ingress", Namespace: testNamespace, Name: fmt.Sprintf("%s-http-source-000", testName)},
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			httpSource.
+				StatusConditions(
+					httpSourceConditionDeploymentReady.Unknown(),
+					httpSourceConditionIngressReady.True(),
+					httpSourceConditionReady.Unknown(),
+					httpSourceConditionServiceReady.True(),
+					httpSourceConditionStreamReady.True(),
+				).
+				StatusObservedGeneration(1),
+		},
+	}}
+
+	table.Test(t, scheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
+		return streaming.HTTPSourceReconciler(
+			controllers.Config{
+				Client:    client,
+				APIReader: apiReader,
+				Recorder:  recorder,
+				Log:       log,
+				Scheme:    scheme,
+				Tracker:   tracker,
+			},
+			testSystemNamespace,
+		)
+	})
+}
@@ -113,11 +113,11 @@ func InMemoryGatewayChildGatewayReconciler(c controllers.Config) controllers.Sub
 							{
 								Name:  "gateway",
 								Image: parent.Status.GatewayImage,
-								Env: []corev1.EnvVar{
-									{Name: "storage_positions_type", Value: "MEMORY"},
-									{Name: "storage_records_type", Value: "MEMORY"},
-									{Name: "server_port", Value: "8000"},
-								},
+								Env: append(
+									gatewayPositionsEnv(parent.Spec.PositionsStore),
+									corev1.EnvVar{Name: "storage_records_type", Value: "MEMORY"},
+									corev1.EnvVar{Name: "server_port", Value: "8000"},
+								),
 							},
 							{
 								Name:  "provisioner",
@@ -129,6 +129,7 @@ func InMemoryGatewayChildGatewayReconciler(c controllers.Config) controllers.Sub
 						},
 					},
 				}
+				tuneGatewayTemplate(template, parent.Spec.GatewayDeploymentSpec)
 			}
 
 			child := &streamingv1alpha1.Gateway{
@@ -139,11 +140,13 @@ func InMemoryGatewayChildGatewayReconciler(c controllers.Config) controllers.Sub
 					Namespace:   parent.Namespace,
 				},
 				Spec: streamingv1alpha1.GatewaySpec{
+					Replicas: parent.Spec.Replicas,
 					Template: template,
 					Ports: []corev1.ServicePort{
 						{Name: "gateway", Port: 6565},
 						{Name: "provisioner", Port: 80, TargetPort: intstr.FromInt(8080)},
 					},
+					Exposure: parent.Spec.Exposure,
 				},
 			}
 
@@ -159,6 +162,7 @@ func InMemoryGatewayChildGatewayReconciler(c controllers.Config) controllers.Sub
 			}
 			parent.Status.GatewayRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
 			parent.Status.Address = child.Status.Address
+			parent.Status.PublicAddress = child.Status.PublicAddress
 			parent.Status.PropagateGatewayStatus(&child.Status)
 		},
 		MergeBeforeUpdate: func(current, desired *streamingv1alpha1.Gateway) {
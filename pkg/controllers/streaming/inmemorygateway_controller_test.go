@@ -119,6 +119,7 @@ func TestInMemoryGatewayReconciler(t *testing.T) {
 					{Name: "GATEWAY", Value: fmt.Sprintf("%s:6565", testProvisionerHostname)},
 				}
 			})
+			probeGatewayContainers(pts)
 		})
 
 	table := rtesting.Table{{
@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	kedav1alpha1 "github.com/projectriff/system/pkg/apis/thirdparty/keda/v1alpha1"
 	"github.com/projectriff/system/pkg/controllers"
 	"github.com/projectriff/system/pkg/refs"
 	"github.com/projectriff/system/pkg/tracker"
@@ -40,6 +41,12 @@ import (
 // +kubebuilder:rbac:groups=streaming.projectriff.io,resources=gateways,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=keda.k8s.io,resources=triggerauthentications,verbs=get;list;watch;create;update;patch;delete
+
+const (
+	kafkaCARootPath         = "/var/riff/kafka/ca"
+	kafkaClientCertRootPath = "/var/riff/kafka/client"
+)
 
 func KafkaGatewayReconciler(c controllers.Config, namespace string) *controllers.ParentReconciler {
 	c.Log = c.Log.WithName("KafkaGateway")
@@ -49,6 +56,7 @@ func KafkaGatewayReconciler(c controllers.Config, namespace string) *controllers
 		SubReconcilers: []controllers.SubReconciler{
 			KafkaGatewaySyncConfigReconciler(c, namespace),
 			KafkaGatewayChildGatewayReconciler(c),
+			KafkaGatewayChildTriggerAuthenticationReconciler(c),
 		},
 
 		Config: c,
@@ -97,12 +105,19 @@ func KafkaGatewayChildGatewayReconciler(c controllers.Config) controllers.SubRec
 				streamingv1alpha1.GatewayTypeLabelKey:  streamingv1alpha1.KafkaGatewayType,
 			})
 
+			annotations := make(map[string]string)
+			if kafkaGatewaySecured(parent) {
+				annotations[streamingv1alpha1.GatewayTriggerAuthenticationAnnotationKey] = parent.Name
+			}
+
 			var template *corev1.PodTemplateSpec
 			if parent.Status.Address != nil {
 				gatewayAddress, err := parent.Status.Address.Parse()
 				if err != nil {
 					return nil, err
 				}
+				volumes, volumeMounts, gatewayEnv, provisionerEnv := kafkaGatewaySecurity(parent)
+				gatewayEnv = append(gatewayEnv, gatewayPositionsEnv(parent.Spec.PositionsStore)...)
 
 				template = &corev1.PodTemplateSpec{
 					ObjectMeta: metav1.ObjectMeta{
@@ -113,39 +128,52 @@ func KafkaGatewayChildGatewayReconciler(c controllers.Config) controllers.SubRec
 							{
 								Name:  "gateway",
 								Image: parent.Status.GatewayImage,
-								Env: []corev1.EnvVar{
-									{Name: "kafka_bootstrapServers", Value: parent.Spec.BootstrapServers},
-									{Name: "storage_positions_type", Value: "MEMORY"},
-									{Name: "storage_records_type", Value: "KAFKA"},
-									{Name: "server_port", Value: "8000"},
-								},
+								Env: append(
+									append(
+										[]corev1.EnvVar{
+											{Name: "kafka_bootstrapServers", Value: parent.Spec.BootstrapServers},
+										},
+										gatewayEnv...,
+									),
+									corev1.EnvVar{Name: "storage_records_type", Value: "KAFKA"},
+									corev1.EnvVar{Name: "server_port", Value: "8000"},
+								),
+								VolumeMounts: volumeMounts,
 							},
 							{
 								Name:  "provisioner",
 								Image: parent.Status.ProvisionerImage,
-								Env: []corev1.EnvVar{
-									{Name: "GATEWAY", Value: fmt.Sprintf("%s:6565", gatewayAddress.Hostname())},
-									{Name: "BROKER", Value: parent.Spec.BootstrapServers},
-								},
+								Env: append(
+									[]corev1.EnvVar{
+										{Name: "GATEWAY", Value: fmt.Sprintf("%s:6565", gatewayAddress.Hostname())},
+										{Name: "BROKER", Value: parent.Spec.BootstrapServers},
+									},
+									provisionerEnv...,
+								),
+								VolumeMounts: volumeMounts,
 							},
 						},
+						Volumes: volumes,
 					},
 				}
+				tuneGatewayTemplate(template, parent.Spec.GatewayDeploymentSpec)
 			}
 
 			child := &streamingv1alpha1.Gateway{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      labels,
-					Annotations: make(map[string]string),
+					Annotations: annotations,
 					Name:        parent.Name,
 					Namespace:   parent.Namespace,
 				},
 				Spec: streamingv1alpha1.GatewaySpec{
+					Replicas: parent.Spec.Replicas,
 					Template: template,
 					Ports: []corev1.ServicePort{
 						{Name: "gateway", Port: 6565},
 						{Name: "provisioner", Port: 80, TargetPort: intstr.FromInt(8080)},
 					},
+					Exposure: parent.Spec.Exposure,
 				},
 			}
 
@@ -161,15 +189,18 @@ func KafkaGatewayChildGatewayReconciler(c controllers.Config) controllers.SubRec
 			}
 			parent.Status.GatewayRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
 			parent.Status.Address = child.Status.Address
+			parent.Status.PublicAddress = child.Status.PublicAddress
 			parent.Status.PropagateGatewayStatus(&child.Status)
 		},
 		MergeBeforeUpdate: func(current, desired *streamingv1alpha1.Gateway) {
 			current.Labels = desired.Labels
+			current.Annotations = desired.Annotations
 			current.Spec = desired.Spec
 		},
 		SemanticEquals: func(a1, a2 *streamingv1alpha1.Gateway) bool {
 			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
-				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels) &&
+				equality.Semantic.DeepEqual(a1.Annotations, a2.Annotations)
 		},
 
 		Config:     c,
@@ -179,3 +210,181 @@ func KafkaGatewayChildGatewayReconciler(c controllers.Config) controllers.SubRec
 		},
 	}
 }
+
+func KafkaGatewayChildTriggerAuthenticationReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildTriggerAuthentication")
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.KafkaGateway{},
+		ChildType:     &kedav1alpha1.TriggerAuthentication{},
+		ChildListType: &kedav1alpha1.TriggerAuthenticationList{},
+
+		DesiredChild: func(parent *streamingv1alpha1.KafkaGateway) (*kedav1alpha1.TriggerAuthentication, error) {
+			if !kafkaGatewaySecured(parent) {
+				// plaintext brokers, nothing to authenticate with
+				return nil, nil
+			}
+
+			labels := controllers.MergeMaps(parent.Labels, map[string]string{
+				streamingv1alpha1.KafkaGatewayLabelKey: parent.Name,
+			})
+
+			secretTargetRefs := []kedav1alpha1.AuthSecretTargetRef{}
+			if tls := parent.Spec.TLS; tls != nil {
+				if tls.CASecretRef != nil {
+					secretTargetRefs = append(secretTargetRefs,
+						kedav1alpha1.AuthSecretTargetRef{Parameter: "ca", Name: tls.CASecretRef.Name, Key: streamingv1alpha1.KafkaGatewayCAKey},
+					)
+				}
+				if tls.ClientCertSecretRef != nil {
+					secretTargetRefs = append(secretTargetRefs,
+						kedav1alpha1.AuthSecretTargetRef{Parameter: "cert", Name: tls.ClientCertSecretRef.Name, Key: streamingv1alpha1.KafkaGatewayClientCertKey},
+						kedav1alpha1.AuthSecretTargetRef{Parameter: "key", Name: tls.ClientCertSecretRef.Name, Key: streamingv1alpha1.KafkaGatewayClientKeyKey},
+					)
+				}
+			}
+			if sasl := parent.Spec.SASL; sasl != nil {
+				secretTargetRefs = append(secretTargetRefs,
+					kedav1alpha1.AuthSecretTargetRef{Parameter: "username", Name: sasl.CredentialsSecretRef.Name, Key: streamingv1alpha1.KafkaGatewayUsernameKey},
+					kedav1alpha1.AuthSecretTargetRef{Parameter: "password", Name: sasl.CredentialsSecretRef.Name, Key: streamingv1alpha1.KafkaGatewayPasswordKey},
+				)
+			}
+
+			child := &kedav1alpha1.TriggerAuthentication{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:    labels,
+					Name:      parent.Name,
+					Namespace: parent.Namespace,
+				},
+				Spec: kedav1alpha1.TriggerAuthenticationSpec{
+					PodIdentity: kedav1alpha1.AuthPodIdentity{
+						Provider: kedav1alpha1.PodIdentityProviderNone,
+					},
+					SecretTargetRef: secretTargetRefs,
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.KafkaGateway, child *kedav1alpha1.TriggerAuthentication, err error) {
+			if err != nil {
+				return
+			}
+			if child == nil {
+				parent.Status.TriggerAuthenticationRef = nil
+				return
+			}
+			parent.Status.TriggerAuthenticationRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+		},
+		MergeBeforeUpdate: func(current, desired *kedav1alpha1.TriggerAuthentication) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *kedav1alpha1.TriggerAuthentication) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.kafkaGatewayTriggerAuthenticationController",
+		Sanitize: func(child *kedav1alpha1.TriggerAuthentication) interface{} {
+			return child.Spec
+		},
+	}
+}
+
+// kafkaGatewaySecured is true when connections to the brokers use TLS or SASL
+func kafkaGatewaySecured(parent *streamingv1alpha1.KafkaGateway) bool {
+	return parent.Spec.TLS != nil || parent.Spec.SASL != nil
+}
+
+// kafkaGatewaySecurity resolves the volumes and environment the gateway and
+// provisioner containers need to connect to brokers secured with TLS or SASL.
+// TLS material is mounted into both containers from the referenced secrets.
+func kafkaGatewaySecurity(parent *streamingv1alpha1.KafkaGateway) ([]corev1.Volume, []corev1.VolumeMount, []corev1.EnvVar, []corev1.EnvVar) {
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+	var gatewayEnv []corev1.EnvVar
+	var provisionerEnv []corev1.EnvVar
+
+	if tls := parent.Spec.TLS; tls != nil {
+		gatewayEnv = append(gatewayEnv, corev1.EnvVar{Name: "kafka_tls_enabled", Value: "true"})
+		provisionerEnv = append(provisionerEnv, corev1.EnvVar{Name: "KAFKA_TLS", Value: "true"})
+		if tls.CASecretRef != nil {
+			volumes = append(volumes, corev1.Volume{
+				Name: "kafka-ca",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: tls.CASecretRef.Name,
+						Items: []corev1.KeyToPath{
+							{Key: streamingv1alpha1.KafkaGatewayCAKey, Path: streamingv1alpha1.KafkaGatewayCAKey},
+						},
+					},
+				},
+			})
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      "kafka-ca",
+				MountPath: kafkaCARootPath,
+				ReadOnly:  true,
+			})
+			caFile := fmt.Sprintf("%s/%s", kafkaCARootPath, streamingv1alpha1.KafkaGatewayCAKey)
+			gatewayEnv = append(gatewayEnv, corev1.EnvVar{Name: "kafka_tls_caFile", Value: caFile})
+			provisionerEnv = append(provisionerEnv, corev1.EnvVar{Name: "KAFKA_CA_FILE", Value: caFile})
+		}
+		if tls.ClientCertSecretRef != nil {
+			volumes = append(volumes, corev1.Volume{
+				Name: "kafka-client-cert",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: tls.ClientCertSecretRef.Name,
+						Items: []corev1.KeyToPath{
+							{Key: streamingv1alpha1.KafkaGatewayClientCertKey, Path: streamingv1alpha1.KafkaGatewayClientCertKey},
+							{Key: streamingv1alpha1.KafkaGatewayClientKeyKey, Path: streamingv1alpha1.KafkaGatewayClientKeyKey},
+						},
+					},
+				},
+			})
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      "kafka-client-cert",
+				MountPath: kafkaClientCertRootPath,
+				ReadOnly:  true,
+			})
+			certFile := fmt.Sprintf("%s/%s", kafkaClientCertRootPath, streamingv1alpha1.KafkaGatewayClientCertKey)
+			keyFile := fmt.Sprintf("%s/%s", kafkaClientCertRootPath, streamingv1alpha1.KafkaGatewayClientKeyKey)
+			gatewayEnv = append(gatewayEnv,
+				corev1.EnvVar{Name: "kafka_tls_certFile", Value: certFile},
+				corev1.EnvVar{Name: "kafka_tls_keyFile", Value: keyFile},
+			)
+			provisionerEnv = append(provisionerEnv,
+				corev1.EnvVar{Name: "KAFKA_CERT_FILE", Value: certFile},
+				corev1.EnvVar{Name: "KAFKA_KEY_FILE", Value: keyFile},
+			)
+		}
+	}
+
+	if sasl := parent.Spec.SASL; sasl != nil {
+		secretEnv := func(name, key string) corev1.EnvVar {
+			return corev1.EnvVar{
+				Name: name,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: sasl.CredentialsSecretRef,
+						Key:                  key,
+					},
+				},
+			}
+		}
+		gatewayEnv = append(gatewayEnv,
+			corev1.EnvVar{Name: "kafka_sasl_mechanism", Value: string(sasl.Mechanism)},
+			secretEnv("kafka_sasl_username", streamingv1alpha1.KafkaGatewayUsernameKey),
+			secretEnv("kafka_sasl_password", streamingv1alpha1.KafkaGatewayPasswordKey),
+		)
+		provisionerEnv = append(provisionerEnv,
+			corev1.EnvVar{Name: "KAFKA_SASL_MECHANISM", Value: string(sasl.Mechanism)},
+			secretEnv("KAFKA_SASL_USERNAME", streamingv1alpha1.KafkaGatewayUsernameKey),
+			secretEnv("KAFKA_SASL_PASSWORD", streamingv1alpha1.KafkaGatewayPasswordKey),
+		)
+	}
+
+	return volumes, volumeMounts, gatewayEnv, provisionerEnv
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -33,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	kedav1alpha1 "github.com/projectriff/system/pkg/apis/thirdparty/keda/v1alpha1"
 	"github.com/projectriff/system/pkg/controllers"
 	"github.com/projectriff/system/pkg/controllers/streaming"
 	rtesting "github.com/projectriff/system/pkg/controllers/testing"
@@ -63,6 +65,7 @@ func TestKafkaGatewayReconciler(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = streamingv1alpha1.AddToScheme(scheme)
+	_ = kedav1alpha1.AddToScheme(scheme)
 
 	kafkaGatewayImagesConfigMap := factories.ConfigMap().
 		NamespaceName(testSystemNamespace, kafkaGatewayImages).
@@ -123,6 +126,138 @@ func TestKafkaGatewayReconciler(t *testing.T) {
 					{Name: "BROKER", Value: testBootstrapServers},
 				}
 			})
+			probeGatewayContainers(pts)
+		})
+
+	gatewayResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+	gatewayToleration := corev1.Toleration{
+		Key:      "dedicated",
+		Operator: corev1.TolerationOpEqual,
+		Value:    "streaming",
+		Effect:   corev1.TaintEffectNoSchedule,
+	}
+	kafkaGatewayTuned := kafkaGateway.
+		Replicas(3).
+		Resources(&gatewayResources).
+		NodeSelector(map[string]string{"node-role": "streaming"}).
+		Tolerations(gatewayToleration).
+		PositionsStore(&streamingv1alpha1.GatewayPositionsStore{
+			Redis: &streamingv1alpha1.RedisPositionsStore{
+				Address: "redis:6379",
+				PasswordSecretRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "redis"},
+					Key:                  "password",
+				},
+			},
+		})
+
+	kafkaTLS := &streamingv1alpha1.KafkaTLS{
+		CASecretRef:         &corev1.LocalObjectReference{Name: "kafka-ca"},
+		ClientCertSecretRef: &corev1.LocalObjectReference{Name: "kafka-client"},
+	}
+	kafkaSASL := &streamingv1alpha1.KafkaSASL{
+		Mechanism:            streamingv1alpha1.KafkaSASLMechanismScramSHA512,
+		CredentialsSecretRef: corev1.LocalObjectReference{Name: "kafka-credentials"},
+	}
+	kafkaGatewaySecured := kafkaGateway.
+		TLS(kafkaTLS).
+		SASL(kafkaSASL)
+
+	saslEnv := func(name, key string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "kafka-credentials"},
+					Key:                  key,
+				},
+			},
+		}
+	}
+	securedVolumeMounts := []corev1.VolumeMount{
+		{Name: "kafka-ca", MountPath: "/var/riff/kafka/ca", ReadOnly: true},
+		{Name: "kafka-client-cert", MountPath: "/var/riff/kafka/client", ReadOnly: true},
+	}
+	gatewaySecured := gatewayComplete.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.AddAnnotation(streamingv1alpha1.GatewayTriggerAuthenticationAnnotationKey, testName)
+		}).
+		PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+			pts.ContainerNamed("gateway", func(c *corev1.Container) {
+				c.Env = []corev1.EnvVar{
+					{Name: "kafka_bootstrapServers", Value: testBootstrapServers},
+					{Name: "kafka_tls_enabled", Value: "true"},
+					{Name: "kafka_tls_caFile", Value: "/var/riff/kafka/ca/ca.crt"},
+					{Name: "kafka_tls_certFile", Value: "/var/riff/kafka/client/tls.crt"},
+					{Name: "kafka_tls_keyFile", Value: "/var/riff/kafka/client/tls.key"},
+					{Name: "kafka_sasl_mechanism", Value: "SCRAM-SHA-512"},
+					saslEnv("kafka_sasl_username", "username"),
+					saslEnv("kafka_sasl_password", "password"),
+					{Name: "storage_positions_type", Value: "MEMORY"},
+					{Name: "storage_records_type", Value: "KAFKA"},
+					{Name: "server_port", Value: "8000"},
+				}
+				c.VolumeMounts = securedVolumeMounts
+			})
+			pts.ContainerNamed("provisioner", func(c *corev1.Container) {
+				c.Env = []corev1.EnvVar{
+					{Name: "GATEWAY", Value: fmt.Sprintf("%s:6565", testProvisionerHostname)},
+					{Name: "BROKER", Value: testBootstrapServers},
+					{Name: "KAFKA_TLS", Value: "true"},
+					{Name: "KAFKA_CA_FILE", Value: "/var/riff/kafka/ca/ca.crt"},
+					{Name: "KAFKA_CERT_FILE", Value: "/var/riff/kafka/client/tls.crt"},
+					{Name: "KAFKA_KEY_FILE", Value: "/var/riff/kafka/client/tls.key"},
+					{Name: "KAFKA_SASL_MECHANISM", Value: "SCRAM-SHA-512"},
+					saslEnv("KAFKA_SASL_USERNAME", "username"),
+					saslEnv("KAFKA_SASL_PASSWORD", "password"),
+				}
+				c.VolumeMounts = securedVolumeMounts
+			})
+			pts.Volumes(
+				corev1.Volume{
+					Name: "kafka-ca",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "kafka-ca",
+							Items:      []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+						},
+					},
+				},
+				corev1.Volume{
+					Name: "kafka-client-cert",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "kafka-client",
+							Items:      []corev1.KeyToPath{{Key: "tls.crt", Path: "tls.crt"}, {Key: "tls.key", Path: "tls.key"}},
+						},
+					},
+				},
+			)
+		})
+	triggerAuthenticationCreate := factories.KedaTriggerAuthentication().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.AddLabel(streamingv1alpha1.KafkaGatewayLabelKey, testName)
+			om.ControlledBy(kafkaGateway, scheme)
+		}).
+		SecretTargetRefs(
+			kedav1alpha1.AuthSecretTargetRef{Parameter: "ca", Name: "kafka-ca", Key: "ca.crt"},
+			kedav1alpha1.AuthSecretTargetRef{Parameter: "cert", Name: "kafka-client", Key: "tls.crt"},
+			kedav1alpha1.AuthSecretTargetRef{Parameter: "key", Name: "kafka-client", Key: "tls.key"},
+			kedav1alpha1.AuthSecretTargetRef{Parameter: "username", Name: "kafka-credentials", Key: "username"},
+			kedav1alpha1.AuthSecretTargetRef{Parameter: "password", Name: "kafka-credentials", Key: "password"},
+		)
+	triggerAuthenticationGiven := triggerAuthenticationCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
 		})
 
 	table := rtesting.Table{{
@@ -228,6 +363,61 @@ func TestKafkaGatewayReconciler(t *testing.T) {
 					kafkaGatewayConditionReady.Unknown(),
 				),
 		},
+	}, {
+		Name: "updates gateway, tuned for high availability",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			kafkaGatewayTuned.
+				StatusAddress(testProvisionerURL),
+			kafkaGatewayImagesConfigMap,
+			gatewayGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(kafkaGatewayImagesConfigMap, kafkaGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(kafkaGateway, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Gateway "%s"`, testName),
+			rtesting.NewEvent(kafkaGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			gatewayComplete.
+				Replicas(3).
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("gateway", func(c *corev1.Container) {
+						c.Env = []corev1.EnvVar{
+							{Name: "kafka_bootstrapServers", Value: testBootstrapServers},
+							{Name: "storage_positions_type", Value: "REDIS"},
+							{Name: "redis_address", Value: "redis:6379"},
+							{Name: "redis_tls", Value: "false"},
+							{
+								Name: "redis_password",
+								ValueFrom: &corev1.EnvVarSource{
+									SecretKeyRef: &corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "redis"},
+										Key:                  "password",
+									},
+								},
+							},
+							{Name: "storage_records_type", Value: "KAFKA"},
+							{Name: "server_port", Value: "8000"},
+						}
+						c.Resources = gatewayResources
+					})
+					pts.NodeSelector(map[string]string{"node-role": "streaming"})
+					pts.Tolerations(gatewayToleration)
+				}),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			kafkaGatewayTuned.
+				StatusAddress(testProvisionerURL).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					kafkaGatewayConditionGatewayReady.Unknown(),
+					kafkaGatewayConditionReady.Unknown(),
+				),
+		},
 	}, {
 		Name: "ready",
 		Key:  testKey,
@@ -282,6 +472,77 @@ func TestKafkaGatewayReconciler(t *testing.T) {
 					kafkaGatewayConditionReady.False().Reason("TestReason", "a human readable message"),
 				),
 		},
+	}, {
+		Name: "secured with tls and sasl",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			kafkaGatewaySecured.
+				StatusAddress(testProvisionerURL),
+			kafkaGatewayImagesConfigMap,
+			gatewayComplete.
+				StatusReady(),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(kafkaGatewayImagesConfigMap, kafkaGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(kafkaGateway, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Gateway "%s"`, testName),
+			rtesting.NewEvent(kafkaGateway, scheme, corev1.EventTypeNormal, "Created",
+				`Created TriggerAuthentication "%s"`, testName),
+			rtesting.NewEvent(kafkaGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			gatewaySecured.
+				StatusReady(),
+		},
+		ExpectCreates: []rtesting.Factory{
+			triggerAuthenticationCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			kafkaGatewaySecured.
+				StatusAddress(testProvisionerURL).
+				StatusTriggerAuthenticationRef(testName).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					kafkaGatewayConditionGatewayReady.True(),
+					kafkaGatewayConditionReady.True(),
+				),
+		},
+	}, {
+		Name: "no longer secured",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			kafkaGateway.
+				StatusAddress(testProvisionerURL).
+				StatusTriggerAuthenticationRef(testName),
+			kafkaGatewayImagesConfigMap,
+			gatewayComplete.
+				StatusReady(),
+			triggerAuthenticationGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(kafkaGatewayImagesConfigMap, kafkaGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(kafkaGateway, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted TriggerAuthentication "%s"`, testName),
+			rtesting.NewEvent(kafkaGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectDeletes: []rtesting.DeleteRef{
+			{Group: "keda.k8s.io", Kind: "TriggerAuthentication", Namespace: testNamespace, Name: testName},
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			kafkaGateway.
+				StatusAddress(testProvisionerURL).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					kafkaGatewayConditionGatewayReady.True(),
+					kafkaGatewayConditionReady.True(),
+				),
+		},
 	}, {
 		Name: "missing gateway images configmap",
 		Key:  testKey,
@@ -3,6 +3,8 @@
 package streaming
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 
 	v1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
@@ -13,6 +15,29 @@ type MockStreamProvisionerClient struct {
 	mock.Mock
 }
 
+// ConsumerLag provides a mock function with given fields: ctx, provisionerURL, group
+func (_m *MockStreamProvisionerClient) ConsumerLag(ctx context.Context, provisionerURL string, group string) (*ConsumerLag, error) {
+	ret := _m.Called(ctx, provisionerURL, group)
+
+	var r0 *ConsumerLag
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *ConsumerLag); ok {
+		r0 = rf(ctx, provisionerURL, group)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ConsumerLag)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provisionerURL, group)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ProvisionStream provides a mock function with given fields: stream, provisionerURL
 func (_m *MockStreamProvisionerClient) ProvisionStream(stream *v1alpha1.Stream, provisionerURL string) (*StreamAddress, error) {
 	ret := _m.Called(stream, provisionerURL)
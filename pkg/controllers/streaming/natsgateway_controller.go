@@ -0,0 +1,214 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/refs"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=natsgateways,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=natsgateways/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=gateways,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+func NATSGatewayReconciler(c controllers.Config, namespace string) *controllers.ParentReconciler {
+	c.Log = c.Log.WithName("NATSGateway")
+
+	return &controllers.ParentReconciler{
+		Type: &streamingv1alpha1.NATSGateway{},
+		SubReconcilers: []controllers.SubReconciler{
+			NATSGatewaySyncConfigReconciler(c, namespace),
+			NATSGatewayChildGatewayReconciler(c),
+		},
+
+		Config: c,
+	}
+}
+
+func NATSGatewaySyncConfigReconciler(c controllers.Config, namespace string) controllers.SubReconciler {
+	c.Log = c.Log.WithName("SyncConfig")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.NATSGateway) error {
+			var config corev1.ConfigMap
+			key := types.NamespacedName{Namespace: namespace, Name: natsGatewayImages}
+			// track config for new images
+			c.Tracker.Track(
+				tracker.NewKey(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, key),
+				types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+			)
+			if err := c.Get(ctx, key, &config); err != nil {
+				return err
+			}
+			parent.Status.GatewayImage = config.Data[gatewayImageKey]
+			parent.Status.ProvisionerImage = config.Data[provisionerImageKey]
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &corev1.ConfigMap{}}, controllers.EnqueueTracked(&corev1.ConfigMap{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
+
+func NATSGatewayChildGatewayReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildGateway")
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.NATSGateway{},
+		ChildType:     &streamingv1alpha1.Gateway{},
+		ChildListType: &streamingv1alpha1.GatewayList{},
+
+		DesiredChild: func(parent *streamingv1alpha1.NATSGateway) (*streamingv1alpha1.Gateway, error) {
+			labels := controllers.MergeMaps(parent.Labels, map[string]string{
+				streamingv1alpha1.NATSGatewayLabelKey: parent.Name,
+				streamingv1alpha1.GatewayTypeLabelKey: streamingv1alpha1.NATSGatewayType,
+			})
+
+			var template *corev1.PodTemplateSpec
+			if parent.Status.Address != nil {
+				gatewayAddress, err := parent.Status.Address.Parse()
+				if err != nil {
+					return nil, err
+				}
+				gatewayEnv := []corev1.EnvVar{
+					natsGatewaySecretEnv(parent, "nats_servers", streamingv1alpha1.NATSGatewayServersKey, false),
+					natsGatewaySecretEnv(parent, "nats_username", streamingv1alpha1.NATSGatewayUsernameKey, true),
+					natsGatewaySecretEnv(parent, "nats_password", streamingv1alpha1.NATSGatewayPasswordKey, true),
+					natsGatewaySecretEnv(parent, "nats_token", streamingv1alpha1.NATSGatewayTokenKey, true),
+					natsGatewaySecretEnv(parent, "nats_credentials", streamingv1alpha1.NATSGatewayCredentialsKey, true),
+				}
+				gatewayEnv = append(gatewayEnv, gatewayPositionsEnv(parent.Spec.PositionsStore)...)
+				gatewayEnv = append(gatewayEnv,
+					corev1.EnvVar{Name: "storage_records_type", Value: "NATS"},
+					corev1.EnvVar{Name: "server_port", Value: "8000"},
+				)
+
+				template = &corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: labels,
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name:  "gateway",
+								Image: parent.Status.GatewayImage,
+								Env:   gatewayEnv,
+							},
+							{
+								Name:  "provisioner",
+								Image: parent.Status.ProvisionerImage,
+								Env: []corev1.EnvVar{
+									{Name: "GATEWAY", Value: fmt.Sprintf("%s:6565", gatewayAddress.Hostname())},
+									natsGatewaySecretEnv(parent, "NATS_SERVERS", streamingv1alpha1.NATSGatewayServersKey, false),
+									natsGatewaySecretEnv(parent, "NATS_USERNAME", streamingv1alpha1.NATSGatewayUsernameKey, true),
+									natsGatewaySecretEnv(parent, "NATS_PASSWORD", streamingv1alpha1.NATSGatewayPasswordKey, true),
+									natsGatewaySecretEnv(parent, "NATS_TOKEN", streamingv1alpha1.NATSGatewayTokenKey, true),
+									natsGatewaySecretEnv(parent, "NATS_CREDENTIALS", streamingv1alpha1.NATSGatewayCredentialsKey, true),
+								},
+							},
+						},
+					},
+				}
+				tuneGatewayTemplate(template, parent.Spec.GatewayDeploymentSpec)
+			}
+
+			child := &streamingv1alpha1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: make(map[string]string),
+					Name:        parent.Name,
+					Namespace:   parent.Namespace,
+				},
+				Spec: streamingv1alpha1.GatewaySpec{
+					Replicas: parent.Spec.Replicas,
+					Template: template,
+					Ports: []corev1.ServicePort{
+						{Name: "gateway", Port: 6565},
+						{Name: "provisioner", Port: 80, TargetPort: intstr.FromInt(8080)},
+					},
+					Exposure: parent.Spec.Exposure,
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.NATSGateway, child *streamingv1alpha1.Gateway, err error) {
+			if err != nil {
+				if apierrs.IsAlreadyExists(err) {
+					name := err.(apierrs.APIStatus).Status().Details.Name
+					parent.Status.MarkGatewayNotOwned(name)
+				}
+				return
+			}
+			parent.Status.GatewayRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+			parent.Status.Address = child.Status.Address
+			parent.Status.PublicAddress = child.Status.PublicAddress
+			parent.Status.PropagateGatewayStatus(&child.Status)
+		},
+		MergeBeforeUpdate: func(current, desired *streamingv1alpha1.Gateway) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *streamingv1alpha1.Gateway) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.natsGatewayController",
+		Sanitize: func(child *streamingv1alpha1.Gateway) interface{} {
+			return child.Spec
+		},
+	}
+}
+
+// natsGatewaySecretEnv exposes a key from the gateway's secret as an
+// environment variable, optional keys are omitted when not in the secret
+func natsGatewaySecretEnv(parent *streamingv1alpha1.NATSGateway, name, key string, optional bool) corev1.EnvVar {
+	selector := &corev1.SecretKeySelector{
+		LocalObjectReference: parent.Spec.SecretRef,
+		Key:                  key,
+	}
+	if optional {
+		selector.Optional = &optional
+	}
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: selector,
+		},
+	}
+}
@@ -0,0 +1,418 @@
+/*
+Copyright 2020 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/controllers/streaming"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/controllers/testing/factories"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+func TestNATSGatewayReconciler(t *testing.T) {
+	testNamespace := "test-namespace"
+	testSystemNamespace := "system-namespace"
+	testName := "test-gateway"
+	testKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
+	testImagePrefix := "example.com/repo"
+	testGatewayImage := fmt.Sprintf("%s/%s", testImagePrefix, "gateway")
+	testProvisionerImage := fmt.Sprintf("%s/%s", testImagePrefix, "provisioner")
+	testProvisionerHostname := fmt.Sprintf("%s.%s.svc.cluster.local", testName, testNamespace)
+	testProvisionerURL := fmt.Sprintf("http://%s", testProvisionerHostname)
+	testSecretName := "nats-cluster"
+
+	natsGatewayImages := "riff-streaming-nats-gateway" // contains image names for the nats gateway
+	gatewayImageKey := "gatewayImage"
+	provisionerImageKey := "provisionerImage"
+
+	natsGatewayConditionGatewayReady := factories.Condition().Type(streamingv1alpha1.NATSGatewayConditionGatewayReady)
+	natsGatewayConditionReady := factories.Condition().Type(streamingv1alpha1.NATSGatewayConditionReady)
+	gatewayConditionReady := factories.Condition().Type(streamingv1alpha1.GatewayConditionReady)
+
+	secretEnv := func(name, key string, optional bool) corev1.EnvVar {
+		selector := &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: testSecretName},
+			Key:                  key,
+		}
+		if optional {
+			selector.Optional = rtesting.BoolPtr(true)
+		}
+		return corev1.EnvVar{
+			Name:      name,
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: selector},
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	natsGatewayImagesConfigMap := factories.ConfigMap().
+		NamespaceName(testSystemNamespace, natsGatewayImages).
+		AddData(gatewayImageKey, testGatewayImage).
+		AddData(provisionerImageKey, testProvisionerImage)
+
+	natsGatewayMinimal := factories.NATSGateway().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.Generation(1)
+		}).
+		SecretRef(testSecretName)
+	natsGateway := natsGatewayMinimal.
+		StatusGatewayRef(testName).
+		StatusGatewayImage(testGatewayImage).
+		StatusProvisionerImage(testProvisionerImage)
+	natsGatewayReady := natsGateway.
+		StatusObservedGeneration(1).
+		StatusConditions(
+			natsGatewayConditionGatewayReady.True(),
+			natsGatewayConditionReady.True(),
+		)
+
+	gatewayCreate := factories.Gateway().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.AddLabel(streamingv1alpha1.NATSGatewayLabelKey, testName)
+			om.AddLabel(streamingv1alpha1.GatewayTypeLabelKey, streamingv1alpha1.NATSGatewayType)
+			om.ControlledBy(natsGateway, scheme)
+		}).
+		Ports(
+			corev1.ServicePort{Name: "gateway", Port: 6565},
+			corev1.ServicePort{Name: "provisioner", Port: 80, TargetPort: intstr.FromInt(8080)},
+		)
+	gatewayGiven := gatewayCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+		}).
+		StatusAddress(testProvisionerURL)
+	gatewayComplete := gatewayGiven.
+		PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+			pts.AddLabel(streamingv1alpha1.NATSGatewayLabelKey, testName)
+			pts.AddLabel(streamingv1alpha1.GatewayTypeLabelKey, streamingv1alpha1.NATSGatewayType)
+			pts.ContainerNamed("gateway", func(c *corev1.Container) {
+				c.Image = testGatewayImage
+				c.Env = []corev1.EnvVar{
+					secretEnv("nats_servers", streamingv1alpha1.NATSGatewayServersKey, false),
+					secretEnv("nats_username", streamingv1alpha1.NATSGatewayUsernameKey, true),
+					secretEnv("nats_password", streamingv1alpha1.NATSGatewayPasswordKey, true),
+					secretEnv("nats_token", streamingv1alpha1.NATSGatewayTokenKey, true),
+					secretEnv("nats_credentials", streamingv1alpha1.NATSGatewayCredentialsKey, true),
+					{Name: "storage_positions_type", Value: "MEMORY"},
+					{Name: "storage_records_type", Value: "NATS"},
+					{Name: "server_port", Value: "8000"},
+				}
+			})
+			pts.ContainerNamed("provisioner", func(c *corev1.Container) {
+				c.Image = testProvisionerImage
+				c.Env = []corev1.EnvVar{
+					{Name: "GATEWAY", Value: fmt.Sprintf("%s:6565", testProvisionerHostname)},
+					secretEnv("NATS_SERVERS", streamingv1alpha1.NATSGatewayServersKey, false),
+					secretEnv("NATS_USERNAME", streamingv1alpha1.NATSGatewayUsernameKey, true),
+					secretEnv("NATS_PASSWORD", streamingv1alpha1.NATSGatewayPasswordKey, true),
+					secretEnv("NATS_TOKEN", streamingv1alpha1.NATSGatewayTokenKey, true),
+					secretEnv("NATS_CREDENTIALS", streamingv1alpha1.NATSGatewayCredentialsKey, true),
+				}
+			})
+			probeGatewayContainers(pts)
+		})
+
+	table := rtesting.Table{{
+		Name: "natsgateway does not exist",
+		Key:  testKey,
+	}, {
+		Name: "ignore deleted natsgateway",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			natsGateway.
+				ObjectMeta(func(om factories.ObjectMeta) {
+					om.Deleted(1)
+				}),
+		},
+	}, {
+		Name: "error fetching natsgateway",
+		Key:  testKey,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("get", "NATSGateway"),
+		},
+		GivenObjects: []rtesting.Factory{
+			natsGateway,
+		},
+		ShouldErr: true,
+	}, {
+		Name: "creates gateway",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			natsGatewayMinimal,
+			natsGatewayImagesConfigMap,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(natsGatewayImagesConfigMap, natsGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(natsGateway, scheme, corev1.EventTypeNormal, "Created",
+				`Created Gateway "%s"`, testName),
+			rtesting.NewEvent(natsGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			gatewayCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			natsGateway.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					natsGatewayConditionGatewayReady.Unknown(),
+					natsGatewayConditionReady.Unknown(),
+				),
+		},
+	}, {
+		Name: "propagate address",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			natsGateway,
+			natsGatewayImagesConfigMap,
+			gatewayGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(natsGatewayImagesConfigMap, natsGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(natsGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			natsGateway.
+				StatusAddress(testProvisionerURL).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					natsGatewayConditionGatewayReady.Unknown(),
+					natsGatewayConditionReady.Unknown(),
+				),
+		},
+	}, {
+		Name: "updates gateway",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			natsGateway.
+				StatusAddress(testProvisionerURL),
+			natsGatewayImagesConfigMap,
+			gatewayGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(natsGatewayImagesConfigMap, natsGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(natsGateway, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Gateway "%s"`, testName),
+			rtesting.NewEvent(natsGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			gatewayComplete,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			natsGateway.
+				StatusAddress(testProvisionerURL).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					natsGatewayConditionGatewayReady.Unknown(),
+					natsGatewayConditionReady.Unknown(),
+				),
+		},
+	}, {
+		Name: "ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			natsGateway.
+				StatusAddress(testProvisionerURL),
+			natsGatewayImagesConfigMap,
+			gatewayComplete.
+				StatusReady(),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(natsGatewayImagesConfigMap, natsGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(natsGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			natsGateway.
+				StatusAddress(testProvisionerURL).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					natsGatewayConditionGatewayReady.True(),
+					natsGatewayConditionReady.True(),
+				),
+		},
+	}, {
+		Name: "not ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			natsGateway.
+				StatusAddress(testProvisionerURL),
+			natsGatewayImagesConfigMap,
+			gatewayComplete.
+				StatusConditions(
+					gatewayConditionReady.False().Reason("TestReason", "a human readable message"),
+				),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(natsGatewayImagesConfigMap, natsGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(natsGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			natsGateway.
+				StatusAddress(testProvisionerURL).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					natsGatewayConditionGatewayReady.False().Reason("TestReason", "a human readable message"),
+					natsGatewayConditionReady.False().Reason("TestReason", "a human readable message"),
+				),
+		},
+	}, {
+		Name: "missing gateway images configmap",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			natsGatewayReady,
+		},
+		ShouldErr: true,
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(natsGatewayImagesConfigMap, natsGateway, scheme),
+		},
+	}, {
+		Name: "invalid address",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			natsGatewayReady.
+				StatusAddress("\000"),
+			natsGatewayImagesConfigMap,
+			gatewayGiven.
+				StatusAddress("\000"),
+		},
+		ShouldErr: true,
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(natsGatewayImagesConfigMap, natsGateway, scheme),
+		},
+	}, {
+		Name: "conflicting gateway",
+		Key:  testKey,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("create", "Gateway", rtesting.InduceFailureOpts{
+				Error: apierrs.NewAlreadyExists(schema.GroupResource{}, testName),
+			}),
+		},
+		GivenObjects: []rtesting.Factory{
+			natsGatewayMinimal,
+			natsGatewayImagesConfigMap,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(natsGatewayImagesConfigMap, natsGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(natsGateway, scheme, corev1.EventTypeWarning, "CreationFailed",
+				`Failed to create Gateway "%s":  "%s" already exists`, testName, testName),
+			rtesting.NewEvent(natsGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			gatewayCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			natsGatewayMinimal.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					natsGatewayConditionGatewayReady.False().Reason("NotOwned", `There is an existing Gateway "test-gateway" that the NATSGateway does not own.`),
+					natsGatewayConditionReady.False().Reason("NotOwned", `There is an existing Gateway "test-gateway" that the NATSGateway does not own.`),
+				).
+				StatusGatewayImage(testGatewayImage).
+				StatusProvisionerImage(testProvisionerImage),
+		},
+	}, {
+		Name: "conflicting gateway, owned",
+		Key:  testKey,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("create", "Gateway", rtesting.InduceFailureOpts{
+				Error: apierrs.NewAlreadyExists(schema.GroupResource{}, testName),
+			}),
+		},
+		GivenObjects: []rtesting.Factory{
+			natsGatewayMinimal,
+			natsGatewayImagesConfigMap,
+		},
+		APIGivenObjects: []rtesting.Factory{
+			gatewayGiven,
+		},
+		ShouldErr: true,
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(natsGatewayImagesConfigMap, natsGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(natsGateway, scheme, corev1.EventTypeWarning, "CreationFailed",
+				`Failed to create Gateway "%s":  "%s" already exists`, testName, testName),
+			rtesting.NewEvent(natsGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			gatewayCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			natsGatewayMinimal.
+				StatusConditions(
+					natsGatewayConditionGatewayReady.Unknown(),
+					natsGatewayConditionReady.Unknown(),
+				).
+				StatusGatewayImage(testGatewayImage).
+				StatusProvisionerImage(testProvisionerImage),
+		},
+	}}
+
+	table.Test(t, scheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
+		return streaming.NATSGatewayReconciler(
+			controllers.Config{
+				Client:    client,
+				APIReader: apiReader,
+				Recorder:  recorder,
+				Log:       log,
+				Scheme:    scheme,
+				Tracker:   tracker,
+			},
+			testSystemNamespace,
+		)
+	})
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/projectriff/system/pkg/apis"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+const (
+	pipelineStreamIndexField    = ".metadata.pipelineStreamController"
+	pipelineProcessorIndexField = ".metadata.pipelineProcessorController"
+)
+
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=pipelines,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=pipelines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streams,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=processors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+func PipelineReconciler(c controllers.Config) *controllers.ParentReconciler {
+	c.Log = c.Log.WithName("Pipeline")
+
+	return &controllers.ParentReconciler{
+		Type: &streamingv1alpha1.Pipeline{},
+		SubReconcilers: []controllers.SubReconciler{
+			PipelineChildStreamsReconciler(c),
+			PipelineChildProcessorsReconciler(c),
+		},
+
+		Config: c,
+	}
+}
+
+func PipelineChildStreamsReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildStreams")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.Pipeline) error {
+			var streams streamingv1alpha1.StreamList
+			if err := c.List(ctx, &streams, client.InNamespace(parent.Namespace), client.MatchingField(pipelineStreamIndexField, parent.Name)); err != nil {
+				return err
+			}
+			actual := []apis.Object{}
+			for i := range streams.Items {
+				if metav1.IsControlledBy(&streams.Items[i], parent) {
+					actual = append(actual, &streams.Items[i])
+				}
+			}
+
+			labels := controllers.MergeMaps(parent.Labels, map[string]string{
+				streamingv1alpha1.PipelineLabelKey: parent.Name,
+			})
+			desired := make([]apis.Object, len(parent.Spec.Streams))
+			for i, stream := range parent.Spec.Streams {
+				desired[i] = &streamingv1alpha1.Stream{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      pipelineStreamName(parent, stream.Name),
+						Namespace: parent.Namespace,
+						Labels:    labels,
+					},
+					Spec: streamingv1alpha1.StreamSpec{
+						Gateway:     parent.Spec.Gateway,
+						ContentType: stream.ContentType,
+					},
+				}
+			}
+
+			children, err := reconcileControlledChildren(ctx, c, parent, "Stream", actual, desired,
+				func(a1, a2 apis.Object) bool {
+					s1, s2 := a1.(*streamingv1alpha1.Stream), a2.(*streamingv1alpha1.Stream)
+					return equality.Semantic.DeepEqual(s1.Spec, s2.Spec) &&
+						equality.Semantic.DeepEqual(s1.Labels, s2.Labels)
+				},
+				func(current, desired apis.Object) {
+					cur, des := current.(*streamingv1alpha1.Stream), desired.(*streamingv1alpha1.Stream)
+					cur.Labels = des.Labels
+					cur.Spec = des.Spec
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			for i, child := range children {
+				if child == nil {
+					parent.Status.MarkStreamsNotReady("stream %s is not owned by the pipeline", desired[i].GetName())
+					return nil
+				}
+				stream := child.(*streamingv1alpha1.Stream)
+				ready := stream.Status.GetCondition(stream.Status.GetReadyConditionType())
+				if ready == nil {
+					ready = &apis.Condition{Message: "stream has no ready condition"}
+				}
+				if !ready.IsTrue() {
+					parent.Status.MarkStreamsNotReady("stream %s is not ready: %s", stream.Name, ready.Message)
+					return nil
+				}
+			}
+			parent.Status.MarkStreamsReady()
+
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Owns(&streamingv1alpha1.Stream{})
+			return controllers.IndexControllersOfType(mgr, pipelineStreamIndexField, &streamingv1alpha1.Pipeline{}, &streamingv1alpha1.Stream{}, c.Scheme)
+		},
+	}
+}
+
+func PipelineChildProcessorsReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildProcessors")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.Pipeline) error {
+			var processors streamingv1alpha1.ProcessorList
+			if err := c.List(ctx, &processors, client.InNamespace(parent.Namespace), client.MatchingField(pipelineProcessorIndexField, parent.Name)); err != nil {
+				return err
+			}
+			actual := []apis.Object{}
+			for i := range processors.Items {
+				if metav1.IsControlledBy(&processors.Items[i], parent) {
+					actual = append(actual, &processors.Items[i])
+				}
+			}
+
+			desired := make([]apis.Object, len(parent.Spec.Steps))
+			for i, step := range parent.Spec.Steps {
+				inputs := make([]streamingv1alpha1.InputStreamBinding, len(step.Inputs))
+				for j, input := range step.Inputs {
+					inputs[j] = streamingv1alpha1.InputStreamBinding{
+						Stream: pipelineStreamName(parent, input),
+						Alias:  input,
+					}
+				}
+				outputs := make([]streamingv1alpha1.OutputStreamBinding, len(step.Outputs))
+				for j, output := range step.Outputs {
+					outputs[j] = streamingv1alpha1.OutputStreamBinding{
+						Stream: pipelineStreamName(parent, output),
+						Alias:  output,
+					}
+				}
+				processor := &streamingv1alpha1.Processor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      pipelineStepName(parent, step.Name),
+						Namespace: parent.Namespace,
+						Labels: controllers.MergeMaps(parent.Labels, map[string]string{
+							streamingv1alpha1.PipelineLabelKey:     parent.Name,
+							streamingv1alpha1.PipelineStepLabelKey: step.Name,
+						}),
+					},
+					Spec: streamingv1alpha1.ProcessorSpec{
+						Build: &streamingv1alpha1.Build{
+							FunctionRef: step.FunctionRef,
+						},
+						Inputs:  inputs,
+						Outputs: outputs,
+					},
+				}
+				// match the processor as stored, otherwise the defaulting
+				// webhook would cause an update on every reconcile
+				processor.Default()
+				desired[i] = processor
+			}
+
+			children, err := reconcileControlledChildren(ctx, c, parent, "Processor", actual, desired,
+				func(a1, a2 apis.Object) bool {
+					p1, p2 := a1.(*streamingv1alpha1.Processor), a2.(*streamingv1alpha1.Processor)
+					return equality.Semantic.DeepEqual(p1.Spec, p2.Spec) &&
+						equality.Semantic.DeepEqual(p1.Labels, p2.Labels)
+				},
+				func(current, desired apis.Object) {
+					cur, des := current.(*streamingv1alpha1.Processor), desired.(*streamingv1alpha1.Processor)
+					cur.Labels = des.Labels
+					cur.Spec = des.Spec
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			var notReady string
+			parent.Status.Steps = make([]streamingv1alpha1.PipelineStepStatus, len(parent.Spec.Steps))
+			for i, step := range parent.Spec.Steps {
+				parent.Status.Steps[i] = streamingv1alpha1.PipelineStepStatus{
+					Name:  step.Name,
+					Ready: corev1.ConditionUnknown,
+				}
+				child := children[i]
+				if child == nil {
+					if notReady == "" {
+						notReady = fmt.Sprintf("processor %s is not owned by the pipeline", desired[i].GetName())
+					}
+					continue
+				}
+				processor := child.(*streamingv1alpha1.Processor)
+				parent.Status.Steps[i].ProcessorRef = refs.NewTypedLocalObjectReferenceForObject(processor, c.Scheme)
+				ready := processor.Status.GetCondition(processor.Status.GetReadyConditionType())
+				if ready == nil {
+					ready = &apis.Condition{Status: corev1.ConditionUnknown, Message: "processor has no ready condition"}
+				}
+				parent.Status.Steps[i].Ready = ready.Status
+				parent.Status.Steps[i].Reason = ready.Reason
+				if !ready.IsTrue() && notReady == "" {
+					notReady = fmt.Sprintf("step %s is not ready: %s", step.Name, ready.Message)
+				}
+			}
+			if notReady != "" {
+				parent.Status.MarkProcessorsNotReady(notReady)
+			} else {
+				parent.Status.MarkProcessorsReady()
+			}
+
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Owns(&streamingv1alpha1.Processor{})
+			return controllers.IndexControllersOfType(mgr, pipelineProcessorIndexField, &streamingv1alpha1.Pipeline{}, &streamingv1alpha1.Processor{}, c.Scheme)
+		},
+	}
+}
+
+func pipelineStreamName(pipeline *streamingv1alpha1.Pipeline, stream string) string {
+	return fmt.Sprintf("%s-%s", pipeline.Name, stream)
+}
+
+func pipelineStepName(pipeline *streamingv1alpha1.Pipeline, step string) string {
+	return fmt.Sprintf("%s-%s", pipeline.Name, step)
+}
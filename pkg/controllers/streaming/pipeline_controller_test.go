@@ -0,0 +1,478 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	buildv1alpha1 "github.com/projectriff/system/pkg/apis/build/v1alpha1"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/controllers/streaming"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/controllers/testing/factories"
+	"github.com/projectriff/system/pkg/refs"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+func TestPipelineReconciler(t *testing.T) {
+	testNamespace := "test-namespace"
+	testName := "test-pipeline"
+	testKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
+	testGateway := "test-gateway"
+
+	pipelineConditionReady := factories.Condition().Type(streamingv1alpha1.PipelineConditionReady)
+	pipelineConditionStreamsReady := factories.Condition().Type(streamingv1alpha1.PipelineConditionStreamsReady)
+	pipelineConditionProcessorsReady := factories.Condition().Type(streamingv1alpha1.PipelineConditionProcessorsReady)
+	processorConditionReady := factories.Condition().Type(streamingv1alpha1.ProcessorConditionReady)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = buildv1alpha1.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	pipeline := factories.Pipeline().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.Generation(1)
+		}).
+		Gateway(testGateway).
+		Streams(
+			streamingv1alpha1.PipelineStream{Name: "in", ContentType: "text/plain"},
+			streamingv1alpha1.PipelineStream{Name: "out", ContentType: "text/plain"},
+		).
+		Steps(
+			streamingv1alpha1.PipelineStep{Name: "upper", FunctionRef: "uppercase", Inputs: []string{"in"}, Outputs: []string{"out"}},
+		)
+
+	inStreamCreate := factories.Stream().
+		NamespaceName(testNamespace, fmt.Sprintf("%s-in", testName)).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.AddLabel(streamingv1alpha1.PipelineLabelKey, testName)
+			om.ControlledBy(pipeline, scheme)
+		}).
+		Gateway(testGateway).
+		ContentType("text/plain")
+	inStreamGiven := inStreamCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+		})
+	outStreamCreate := factories.Stream().
+		NamespaceName(testNamespace, fmt.Sprintf("%s-out", testName)).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.AddLabel(streamingv1alpha1.PipelineLabelKey, testName)
+			om.ControlledBy(pipeline, scheme)
+		}).
+		Gateway(testGateway).
+		ContentType("text/plain")
+	outStreamGiven := outStreamCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+		})
+
+	upperProcessorCreate := factories.Processor().
+		NamespaceName(testNamespace, fmt.Sprintf("%s-upper", testName)).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.AddLabel(streamingv1alpha1.PipelineLabelKey, testName)
+			om.AddLabel(streamingv1alpha1.PipelineStepLabelKey, "upper")
+			om.ControlledBy(pipeline, scheme)
+		}).
+		BuildFunctionRef(factories.Function().NamespaceName(testNamespace, "uppercase")).
+		Inputs(inStreamCreate.CreateInputStreamBinding("in", "")).
+		Outputs(outStreamCreate.CreateOutputStreamBinding("out")).
+		Default()
+	upperProcessorGiven := upperProcessorCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+		})
+
+	upperStepStatus := streamingv1alpha1.PipelineStepStatus{
+		Name: "upper",
+		ProcessorRef: &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("streaming.projectriff.io"),
+			Kind:     "Processor",
+			Name:     fmt.Sprintf("%s-upper", testName),
+		},
+		Ready: corev1.ConditionUnknown,
+	}
+	upperStepStatusReady := upperStepStatus
+	upperStepStatusReady.Ready = corev1.ConditionTrue
+
+	table := rtesting.Table{{
+		Name: "pipeline does not exist",
+		Key:  testKey,
+	}, {
+		Name: "ignore deleted pipeline",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			pipeline.
+				ObjectMeta(func(om factories.ObjectMeta) {
+					om.Deleted(1)
+				}),
+		},
+	}, {
+		Name: "error fetching pipeline",
+		Key:  testKey,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("get", "Pipeline"),
+		},
+		GivenObjects: []rtesting.Factory{
+			pipeline,
+		},
+		ShouldErr: true,
+	}, {
+		Name: "creates streams and processors",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			pipeline,
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(pipeline, scheme, corev1.EventTypeNormal, "Created",
+				`Created Stream "%s-in"`, testName),
+			rtesting.NewEvent(pipeline, scheme, corev1.EventTypeNormal, "Created",
+				`Created Stream "%s-out"`, testName),
+			rtesting.NewEvent(pipeline, scheme, corev1.EventTypeNormal, "Created",
+				`Created Processor "%s-upper"`, testName),
+			rtesting.NewEvent(pipeline, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			inStreamCreate,
+			outStreamCreate,
+			upperProcessorCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			pipeline.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					pipelineConditionProcessorsReady.False().Reason("ProcessorNotReady", "step upper is not ready: processor has no ready condition"),
+					pipelineConditionReady.False().Reason("ProcessorNotReady", "step upper is not ready: processor has no ready condition"),
+					pipelineConditionStreamsReady.False().Reason("StreamNotReady", fmt.Sprintf("stream %s-in is not ready: stream has no ready condition", testName)),
+				).
+				StatusSteps(upperStepStatus),
+		},
+	}, {
+		Name: "ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			pipeline,
+			inStreamGiven.
+				StatusReady(),
+			outStreamGiven.
+				StatusReady(),
+			upperProcessorGiven.
+				StatusConditions(
+					processorConditionReady.True(),
+				),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(pipeline, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			pipeline.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					pipelineConditionProcessorsReady.True(),
+					pipelineConditionReady.True(),
+					pipelineConditionStreamsReady.True(),
+				).
+				StatusSteps(upperStepStatusReady),
+		},
+	}}
+
+	table.Test(t, scheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
+		return streaming.PipelineReconciler(
+			controllers.Config{
+				Client:    client,
+				APIReader: apiReader,
+				Recorder:  recorder,
+				Log:       log,
+				Scheme:    scheme,
+				Tracker:   tracker,
+			},
+		)
+	})
+
+	t.Run("PipelineChildStreamsReconciler", func(t *testing.T) {
+		table := rtesting.SubTable{
+			{
+				Name:   "create streams",
+				Parent: pipeline,
+				ExpectParent: pipeline.
+					StatusConditions(
+						pipelineConditionReady.False().Reason("StreamNotReady", fmt.Sprintf("stream %s-in is not ready: stream has no ready condition", testName)),
+						pipelineConditionStreamsReady.False().Reason("StreamNotReady", fmt.Sprintf("stream %s-in is not ready: stream has no ready condition", testName)),
+					),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(pipeline, scheme, corev1.EventTypeNormal, "Created",
+						`Created Stream "%s-in"`, testName),
+					rtesting.NewEvent(pipeline, scheme, corev1.EventTypeNormal, "Created",
+						`Created Stream "%s-out"`, testName),
+				},
+				ExpectCreates: []rtesting.Factory{
+					inStreamCreate,
+					outStreamCreate,
+				},
+			},
+			{
+				Name: "streams ready",
+				Parent: pipeline.
+					StatusConditions(
+						pipelineConditionStreamsReady.Unknown(),
+					),
+				GivenObjects: []rtesting.Factory{
+					inStreamGiven.
+						StatusReady(),
+					outStreamGiven.
+						StatusReady(),
+				},
+				ExpectParent: pipeline.
+					StatusConditions(
+						pipelineConditionStreamsReady.True(),
+					),
+			},
+			{
+				Name:   "update stream",
+				Parent: pipeline,
+				GivenObjects: []rtesting.Factory{
+					inStreamGiven.
+						ContentType("application/json").
+						StatusReady(),
+					outStreamGiven.
+						StatusReady(),
+				},
+				ExpectParent: pipeline.
+					StatusConditions(
+						pipelineConditionStreamsReady.True(),
+					),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(pipeline, scheme, corev1.EventTypeNormal, "Updated",
+						`Updated Stream "%s-in"`, testName),
+				},
+				ExpectUpdates: []rtesting.Factory{
+					inStreamGiven.
+						StatusReady(),
+				},
+			},
+			{
+				Name: "delete unwanted stream",
+				Parent: pipeline.
+					Streams(
+						streamingv1alpha1.PipelineStream{Name: "in", ContentType: "text/plain"},
+					),
+				GivenObjects: []rtesting.Factory{
+					inStreamGiven.
+						StatusReady(),
+					outStreamGiven.
+						StatusReady(),
+				},
+				ExpectParent: pipeline.
+					Streams(
+						streamingv1alpha1.PipelineStream{Name: "in", ContentType: "text/plain"},
+					).
+					StatusConditions(
+						pipelineConditionStreamsReady.True(),
+					),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(pipeline, scheme, corev1.EventTypeNormal, "Deleted",
+						`Deleted Stream "%s-out"`, testName),
+				},
+				ExpectDeletes: []rtesting.DeleteRef{
+					{Group: "streaming.projectriff.io", Kind: "Stream", Namespace: testNamespace, Name: fmt.Sprintf("%s-out", testName)},
+				},
+			},
+			{
+				Name:   "conflicting stream",
+				Parent: pipeline,
+				GivenObjects: []rtesting.Factory{
+					factories.Stream().
+						NamespaceName(testNamespace, fmt.Sprintf("%s-in", testName)).
+						ObjectMeta(func(om factories.ObjectMeta) {
+							om.Created(1)
+						}).
+						Gateway(testGateway),
+					outStreamGiven.
+						StatusReady(),
+				},
+				ExpectParent: pipeline.
+					StatusConditions(
+						pipelineConditionReady.False().Reason("StreamNotReady", fmt.Sprintf("stream %s-in is not owned by the pipeline", testName)),
+						pipelineConditionStreamsReady.False().Reason("StreamNotReady", fmt.Sprintf("stream %s-in is not owned by the pipeline", testName)),
+					),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(pipeline, scheme, corev1.EventTypeWarning, "CreationFailed",
+						`Failed to create Stream "%s-in": streams.streaming.projectriff.io "%s-in" already exists`, testName, testName),
+				},
+				ExpectCreates: []rtesting.Factory{
+					inStreamCreate,
+				},
+			},
+		}
+
+		table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
+			return streaming.PipelineChildStreamsReconciler(
+				controllers.Config{
+					Client:    client,
+					APIReader: client,
+					Recorder:  recorder,
+					Log:       log,
+					Scheme:    scheme,
+					Tracker:   tracker,
+				},
+			)
+		})
+	})
+
+	t.Run("PipelineChildProcessorsReconciler", func(t *testing.T) {
+		table := rtesting.SubTable{
+			{
+				Name:   "create processor",
+				Parent: pipeline,
+				ExpectParent: pipeline.
+					StatusConditions(
+						pipelineConditionProcessorsReady.False().Reason("ProcessorNotReady", "step upper is not ready: processor has no ready condition"),
+						pipelineConditionReady.False().Reason("ProcessorNotReady", "step upper is not ready: processor has no ready condition"),
+					).
+					StatusSteps(upperStepStatus),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(pipeline, scheme, corev1.EventTypeNormal, "Created",
+						`Created Processor "%s-upper"`, testName),
+				},
+				ExpectCreates: []rtesting.Factory{
+					upperProcessorCreate,
+				},
+			},
+			{
+				Name:   "processor not ready",
+				Parent: pipeline,
+				GivenObjects: []rtesting.Factory{
+					upperProcessorGiven.
+						StatusConditions(
+							processorConditionReady.False().Reason("DeploymentFailed", "boom"),
+						),
+				},
+				ExpectParent: pipeline.
+					StatusConditions(
+						pipelineConditionProcessorsReady.False().Reason("ProcessorNotReady", "step upper is not ready: boom"),
+						pipelineConditionReady.False().Reason("ProcessorNotReady", "step upper is not ready: boom"),
+					).
+					StatusSteps(streamingv1alpha1.PipelineStepStatus{
+						Name:         "upper",
+						ProcessorRef: upperStepStatus.ProcessorRef,
+						Ready:        corev1.ConditionFalse,
+						Reason:       "DeploymentFailed",
+					}),
+			},
+			{
+				Name: "processor ready",
+				Parent: pipeline.
+					StatusConditions(
+						pipelineConditionProcessorsReady.Unknown(),
+					),
+				GivenObjects: []rtesting.Factory{
+					upperProcessorGiven.
+						StatusConditions(
+							processorConditionReady.True(),
+						),
+				},
+				ExpectParent: pipeline.
+					StatusConditions(
+						pipelineConditionProcessorsReady.True(),
+					).
+					StatusSteps(upperStepStatusReady),
+			},
+			{
+				Name: "update processor",
+				Parent: pipeline.
+					Steps(
+						streamingv1alpha1.PipelineStep{Name: "upper", FunctionRef: "shout", Inputs: []string{"in"}, Outputs: []string{"out"}},
+					),
+				GivenObjects: []rtesting.Factory{
+					upperProcessorGiven.
+						StatusConditions(
+							processorConditionReady.True(),
+						),
+				},
+				ExpectParent: pipeline.
+					Steps(
+						streamingv1alpha1.PipelineStep{Name: "upper", FunctionRef: "shout", Inputs: []string{"in"}, Outputs: []string{"out"}},
+					).
+					StatusConditions(
+						pipelineConditionProcessorsReady.True(),
+					).
+					StatusSteps(upperStepStatusReady),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(pipeline, scheme, corev1.EventTypeNormal, "Updated",
+						`Updated Processor "%s-upper"`, testName),
+				},
+				ExpectUpdates: []rtesting.Factory{
+					upperProcessorGiven.
+						BuildFunctionRef(factories.Function().NamespaceName(testNamespace, "shout")).
+						StatusConditions(
+							processorConditionReady.True(),
+						),
+				},
+			},
+			{
+				Name: "delete unwanted processor",
+				Parent: pipeline.
+					Steps(),
+				GivenObjects: []rtesting.Factory{
+					upperProcessorGiven,
+				},
+				ExpectParent: pipeline.
+					Steps().
+					StatusConditions(
+						pipelineConditionProcessorsReady.True(),
+					).
+					StatusSteps(),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(pipeline, scheme, corev1.EventTypeNormal, "Deleted",
+						`Deleted Processor "%s-upper"`, testName),
+				},
+				ExpectDeletes: []rtesting.DeleteRef{
+					{Group: "streaming.projectriff.io", Kind: "Processor", Namespace: testNamespace, Name: fmt.Sprintf("%s-upper", testName)},
+				},
+			},
+		}
+
+		table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
+			return streaming.PipelineChildProcessorsReconciler(
+				controllers.Config{
+					Client:    client,
+					APIReader: client,
+					Recorder:  recorder,
+					Log:       log,
+					Scheme:    scheme,
+					Tracker:   tracker,
+				},
+			)
+		})
+	})
+}
@@ -18,9 +18,11 @@ package streaming
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -30,6 +32,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/projectriff/system/pkg/apis"
@@ -43,6 +48,8 @@ import (
 
 const (
 	bindingsRootPath = "/var/riff/bindings"
+	stateRootPath    = "/var/riff/state"
+	maxReplayHistory = 10
 )
 
 const (
@@ -51,17 +58,25 @@ const (
 	OutputStreamsStashKey   controllers.StashKey = "output-streams"
 )
 
+const (
+	processorBindingConfigMapIndexField = ".metadata.processorBindingConfigMapController"
+	processorBindingSecretIndexField    = ".metadata.processorBindingSecretController"
+)
+
 // +kubebuilder:rbac:groups=streaming.projectriff.io,resources=processors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=streaming.projectriff.io,resources=processors/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=keda.k8s.io,resources=scaledobjects,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streams,verbs=get;watch
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=gateways,verbs=get;list;watch
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streams,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=build.projectriff.io,resources=containers,verbs=get;watch
 // +kubebuilder:rbac:groups=build.projectriff.io,resources=functions,verbs=get;watch
-// +kubebuilder:rbac:groups=core,resources=configmaps;secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streamgrants,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps;secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 
-func ProcessorReconciler(c controllers.Config, namespace string) *controllers.ParentReconciler {
+func ProcessorReconciler(c controllers.Config, namespace string, lagMonitor *ConsumerLagMonitor) *controllers.ParentReconciler {
 	c.Log = c.Log.WithName("Processor")
 
 	return &controllers.ParentReconciler{
@@ -69,9 +84,15 @@ func ProcessorReconciler(c controllers.Config, namespace string) *controllers.Pa
 		SubReconcilers: []controllers.SubReconciler{
 			ProcessorSyncProcessorImages(c, namespace),
 			ProcessorBuildRefReconciler(c),
+			ProcessorRolloutReconciler(c),
+			ProcessorConsumerGroupReconciler(c),
 			ProcessorResolveStreamsReconciler(c),
+			ProcessorChildBindingsReconciler(c),
+			ProcessorChildChangelogStreamReconciler(c),
 			ProcessorChildDeploymentReconciler(c),
+			ProcessorChildStatefulSetReconciler(c),
 			ProcessorChildScaledObjectReconciler(c),
+			ProcessorConsumerLagReconciler(c, lagMonitor),
 		},
 
 		Config: c,
@@ -108,78 +129,205 @@ func ProcessorSyncProcessorImages(c controllers.Config, namespace string) contro
 func ProcessorBuildRefReconciler(c controllers.Config) controllers.SubReconciler {
 	c.Log = c.Log.WithName("BuildRef")
 
+	// resolveImage returns the latest image of the build, or an empty string
+	// if the build has not produced an image
+	resolveImage := func(ctx context.Context, parent *streamingv1alpha1.Processor, build streamingv1alpha1.Build) (string, error) {
+		switch {
+
+		case build.ContainerRef != "":
+			var container buildv1alpha1.Container
+			key := types.NamespacedName{Namespace: parent.Namespace, Name: build.ContainerRef}
+			// track container for new images
+			c.Tracker.Track(
+				tracker.NewKey(container.GetGroupVersionKind(), key),
+				types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+			)
+			if err := c.Get(ctx, key, &container); err != nil {
+				if apierrs.IsNotFound(err) {
+					return "", nil
+				}
+				return "", err
+			}
+			return container.Status.LatestImage, nil
+
+		case build.FunctionRef != "":
+			var function buildv1alpha1.Function
+			key := types.NamespacedName{Namespace: parent.Namespace, Name: build.FunctionRef}
+			// track function for new images
+			c.Tracker.Track(
+				tracker.NewKey(function.GetGroupVersionKind(), key),
+				types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+			)
+			if err := c.Get(ctx, key, &function); err != nil {
+				if apierrs.IsNotFound(err) {
+					return "", nil
+				}
+				return "", err
+			}
+			return function.Status.LatestImage, nil
+
+		}
+
+		panic(fmt.Errorf("invalid processor build"))
+	}
+
 	return &controllers.SyncReconciler{
 		Sync: func(ctx context.Context, parent *streamingv1alpha1.Processor) error {
+			if len(parent.Spec.Chain) != 0 {
+				// each function in the chain resolves its own image
+				chain := make([]streamingv1alpha1.ChainedFunctionStatus, len(parent.Spec.Chain))
+				for i, function := range parent.Spec.Chain {
+					chain[i].Name = function.Name
+					for _, previous := range parent.Status.Chain {
+						if previous.Name == function.Name {
+							chain[i].LatestImage = previous.LatestImage
+						}
+					}
+					image, err := resolveImage(ctx, parent, function.Build)
+					if err != nil {
+						return err
+					}
+					if image != "" {
+						chain[i].LatestImage = image
+					}
+				}
+				parent.Status.LatestImage = ""
+				parent.Status.Chain = chain
+				return nil
+			}
+			parent.Status.Chain = nil
+			if parent.Spec.Operation != nil {
+				// operations run without a function image
+				parent.Status.LatestImage = ""
+				return nil
+			}
 			build := parent.Spec.Build
 			if build == nil {
 				parent.Status.LatestImage = parent.Spec.Template.Spec.Containers[0].Image
 				return nil
 			}
 
-			switch {
+			image, err := resolveImage(ctx, parent, *build)
+			if err != nil {
+				return err
+			}
+			if image != "" {
+				parent.Status.LatestImage = image
+			}
+			return nil
+		},
 
-			case build.ContainerRef != "":
-				var container buildv1alpha1.Container
-				key := types.NamespacedName{Namespace: parent.Namespace, Name: build.ContainerRef}
-				// track container for new images
-				c.Tracker.Track(
-					tracker.NewKey(container.GetGroupVersionKind(), key),
-					types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
-				)
-				if err := c.Get(ctx, key, &container); err != nil {
-					if apierrs.IsNotFound(err) {
-						return nil
-					}
-					return err
-				}
-				if container.Status.LatestImage != "" {
-					parent.Status.LatestImage = container.Status.LatestImage
-				}
-				return nil
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &buildv1alpha1.Container{}}, controllers.EnqueueTracked(&buildv1alpha1.Container{}, c.Tracker, c.Scheme))
+			bldr.Watches(&source.Kind{Type: &buildv1alpha1.Function{}}, controllers.EnqueueTracked(&buildv1alpha1.Function{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
 
-			case build.FunctionRef != "":
-				var function buildv1alpha1.Function
-				key := types.NamespacedName{Namespace: parent.Namespace, Name: build.FunctionRef}
-				// track function for new images
-				c.Tracker.Track(
-					tracker.NewKey(function.GetGroupVersionKind(), key),
-					types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
-				)
-				if err := c.Get(ctx, key, &function); err != nil {
-					if apierrs.IsNotFound(err) {
-						return nil
-					}
-					return err
-				}
-				if function.Status.LatestImage != "" {
-					parent.Status.LatestImage = function.Status.LatestImage
-				}
-				return nil
+func ProcessorRolloutReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("Rollout")
 
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.Processor) error {
+			rollout := parent.Spec.Rollout
+			if rollout == nil {
+				rollout = &streamingv1alpha1.ProcessorRollout{}
 			}
 
-			panic(fmt.Errorf("invalid processor build"))
+			switch {
+			case parent.Spec.Operation != nil || len(parent.Spec.Chain) != 0:
+				// operations run without a function image, chains roll out
+				// the latest image of each function
+				parent.Status.DeployedImage = ""
+			case !rollout.Paused || parent.Status.DeployedImage == "":
+				// nothing deployed yet to pin while paused
+				parent.Status.DeployedImage = parent.Status.LatestImage
+				parent.Status.Promoted = rollout.Promote
+			case rollout.Promote != parent.Status.Promoted:
+				// the promote token changed, roll out the latest image
+				c.Log.Info("promoting processor image", "processor", parent.Name, "image", parent.Status.LatestImage)
+				c.Recorder.Eventf(parent, corev1.EventTypeNormal, "Promoted",
+					"Promoted image %q", parent.Status.LatestImage)
+				parent.Status.DeployedImage = parent.Status.LatestImage
+				parent.Status.Promoted = rollout.Promote
+			}
+
+			return nil
 		},
 
 		Config: c,
-		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
-			bldr.Watches(&source.Kind{Type: &buildv1alpha1.Container{}}, controllers.EnqueueTracked(&buildv1alpha1.Container{}, c.Tracker, c.Scheme))
-			bldr.Watches(&source.Kind{Type: &buildv1alpha1.Function{}}, controllers.EnqueueTracked(&buildv1alpha1.Function{}, c.Tracker, c.Scheme))
+	}
+}
+
+func ProcessorConsumerGroupReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ConsumerGroup")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.Processor) error {
+			history := parent.Status.ReplayHistory
+			if len(history) == 0 && parent.Spec.Replay == "" {
+				// never replayed, consume with the original group
+				parent.Status.ConsumerGroup = parent.Name
+				return nil
+			}
+			if len(history) != 0 && history[len(history)-1].Replay == parent.Spec.Replay {
+				parent.Status.ConsumerGroup = history[len(history)-1].ConsumerGroup
+				return nil
+			}
+
+			// the replay token changed, roll onto a new group
+			group := fmt.Sprintf("%s-%d", parent.Name, parent.Generation)
+			c.Log.Info("replaying processor", "processor", parent.Name, "consumerGroup", group)
+			history = append(history, streamingv1alpha1.ProcessorReplay{
+				Replay:        parent.Spec.Replay,
+				ConsumerGroup: group,
+			})
+			if len(history) > maxReplayHistory {
+				history = history[len(history)-maxReplayHistory:]
+			}
+			parent.Status.ReplayHistory = history
+			parent.Status.ConsumerGroup = group
 			return nil
 		},
+
+		Config: c,
 	}
 }
 
 func ProcessorResolveStreamsReconciler(c controllers.Config) controllers.SubReconciler {
 	c.Log = c.Log.WithName("ResolveStreams")
 
-	resolveStream := func(ctx context.Context, streamKey, processorKey types.NamespacedName) (*streamingv1alpha1.Stream, error) {
+	streamGranted := func(ctx context.Context, streamKey types.NamespacedName, namespace string) (bool, error) {
+		if streamKey.Namespace == namespace {
+			return true, nil
+		}
+		var grants streamingv1alpha1.StreamGrantList
+		if err := c.List(ctx, &grants, client.InNamespace(streamKey.Namespace)); err != nil {
+			return false, err
+		}
+		for _, grant := range grants.Items {
+			if grant.Grants(streamKey.Name, namespace) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	// resolveStream gets the referenced stream, a nil stream is returned when
+	// the stream is in another namespace that has not granted it to the
+	// processor's namespace
+	resolveStream := func(ctx context.Context, processor *streamingv1alpha1.Processor, ref string) (*streamingv1alpha1.Stream, error) {
 		var stream streamingv1alpha1.Stream
-		// track stream for new coordinates
+		streamKey := streamingv1alpha1.StreamNamespacedName(processor.Namespace, ref)
+		// track stream for new coordinates and grants
 		c.Tracker.Track(
 			tracker.NewKey(stream.GetGroupVersionKind(), streamKey),
-			processorKey,
+			types.NamespacedName{Namespace: processor.Namespace, Name: processor.Name},
 		)
+		if granted, err := streamGranted(ctx, streamKey, processor.Namespace); err != nil || !granted {
+			return nil, err
+		}
 		if err := c.Client.Get(ctx, streamKey, &stream); err != nil {
 			return nil, err
 		}
@@ -188,30 +336,34 @@ func ProcessorResolveStreamsReconciler(c controllers.Config) controllers.SubReco
 
 	return &controllers.SyncReconciler{
 		Sync: func(ctx context.Context, processor *streamingv1alpha1.Processor) error {
-			if processor.Status.LatestImage == "" {
+			if !processorRunnable(processor) {
 				return nil
 			}
 
-			processorKey := types.NamespacedName{Namespace: processor.Namespace, Name: processor.Name}
-
 			inputStreams := make([]streamingv1alpha1.Stream, len(processor.Spec.Inputs))
 			for i, binding := range processor.Spec.Inputs {
-				key := types.NamespacedName{Namespace: processor.Namespace, Name: binding.Stream}
-				stream, err := resolveStream(ctx, key, processorKey)
+				stream, err := resolveStream(ctx, processor, binding.Stream)
 				if err != nil {
 					return err
 				}
+				if stream == nil {
+					processor.Status.MarkStreamsNotReady(fmt.Sprintf("stream %s is not granted to namespace %s", binding.Stream, processor.Namespace))
+					return nil
+				}
 				inputStreams[i] = *stream
 			}
 			controllers.StashValue(ctx, InputStreamsStashKey, inputStreams)
 
 			outputStreams := make([]streamingv1alpha1.Stream, len(processor.Spec.Outputs))
 			for i, binding := range processor.Spec.Outputs {
-				key := types.NamespacedName{Namespace: processor.Namespace, Name: binding.Stream}
-				stream, err := resolveStream(ctx, key, processorKey)
+				stream, err := resolveStream(ctx, processor, binding.Stream)
 				if err != nil {
 					return err
 				}
+				if stream == nil {
+					processor.Status.MarkStreamsNotReady(fmt.Sprintf("stream %s is not granted to namespace %s", binding.Stream, processor.Namespace))
+					return nil
+				}
 				outputStreams[i] = *stream
 			}
 			controllers.StashValue(ctx, OutputStreamsStashKey, outputStreams)
@@ -237,151 +389,198 @@ func ProcessorResolveStreamsReconciler(c controllers.Config) controllers.SubReco
 		Config: c,
 		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
 			bldr.Watches(&source.Kind{Type: &streamingv1alpha1.Stream{}}, controllers.EnqueueTracked(&streamingv1alpha1.Stream{}, c.Tracker, c.Scheme))
+			// grants reconcile the processors tracking the granted streams
+			bldr.Watches(&source.Kind{Type: &streamingv1alpha1.StreamGrant{}}, &handler.EnqueueRequestsFromMapFunc{
+				ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+					grant, ok := a.Object.(*streamingv1alpha1.StreamGrant)
+					if !ok {
+						return nil
+					}
+					var requests []reconcile.Request
+					for _, stream := range grant.Spec.Streams {
+						key := tracker.NewKey(
+							streamingv1alpha1.SchemeGroupVersion.WithKind("Stream"),
+							types.NamespacedName{Namespace: grant.Namespace, Name: stream},
+						)
+						for _, item := range c.Tracker.Lookup(key) {
+							requests = append(requests, reconcile.Request{NamespacedName: item})
+						}
+					}
+					return requests
+				}),
+			})
 			return nil
 		},
 	}
 }
 
-func ProcessorChildDeploymentReconciler(c controllers.Config) controllers.SubReconciler {
-	c.Log = c.Log.WithName("ChildDeployment")
+func ProcessorChildBindingsReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildBindings")
 
-	one := int32(1)
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.Processor) error {
+			// streams from other namespaces are bound from copies of their
+			// binding, pods are not able to mount volumes across namespaces
+			inputStreams, _ := controllers.RetrieveValue(ctx, InputStreamsStashKey).([]streamingv1alpha1.Stream)
+			outputStreams, _ := controllers.RetrieveValue(ctx, OutputStreamsStashKey).([]streamingv1alpha1.Stream)
+			streams := []streamingv1alpha1.Stream{}
+			streams = append(streams, inputStreams...)
+			streams = append(streams, outputStreams...)
 
-	constructVolumes := func(processor *streamingv1alpha1.Processor, inputStreams, outputStreams []streamingv1alpha1.Stream) ([]corev1.Volume, []corev1.VolumeMount) {
-		volumes := []corev1.Volume{}
-		volumeMounts := []corev1.VolumeMount{}
+			parentKey := types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name}
+			labels := controllers.MergeMaps(parent.Labels, map[string]string{
+				streamingv1alpha1.ProcessorLabelKey: parent.Name,
+			})
 
-		// De-dupe streams and create one volume for each
-		streams := make(map[string]streamingv1alpha1.Stream)
-		for _, s := range inputStreams {
-			streams[s.Name] = s
-		}
-		for _, s := range outputStreams {
-			streams[s.Name] = s
-		}
-		for _, stream := range streams {
-			if stream.Status.Binding.MetadataRef.Name != "" {
-				volumes = append(volumes,
-					corev1.Volume{
-						Name: fmt.Sprintf("stream-%s-metadata", stream.UID),
-						VolumeSource: corev1.VolumeSource{
-							ConfigMap: &corev1.ConfigMapVolumeSource{
-								LocalObjectReference: corev1.LocalObjectReference{
-									Name: stream.Status.Binding.MetadataRef.Name,
-								},
+			desiredConfigMaps := []apis.Object{}
+			desiredSecrets := []apis.Object{}
+			copied := map[string]bool{}
+			for _, stream := range streams {
+				name := processorBindingName(parent, stream)
+				if stream.Namespace == parent.Namespace || copied[name] {
+					continue
+				}
+				copied[name] = true
+
+				if ref := stream.Status.Binding.MetadataRef.Name; ref != "" {
+					var metadata corev1.ConfigMap
+					key := types.NamespacedName{Namespace: stream.Namespace, Name: ref}
+					// track binding metadata for changes
+					c.Tracker.Track(
+						tracker.NewKey(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, key),
+						parentKey,
+					)
+					if err := c.Get(ctx, key, &metadata); err != nil {
+						if !apierrs.IsNotFound(err) {
+							return err
+						}
+					} else {
+						desiredConfigMaps = append(desiredConfigMaps, &corev1.ConfigMap{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      name,
+								Namespace: parent.Namespace,
+								Labels:    labels,
 							},
-						},
-					},
-				)
-			}
-			if stream.Status.Binding.SecretRef.Name != "" {
-				volumes = append(volumes,
-					corev1.Volume{
-						Name: fmt.Sprintf("stream-%s-secret", stream.UID),
-						VolumeSource: corev1.VolumeSource{
-							Secret: &corev1.SecretVolumeSource{
-								SecretName: stream.Status.Binding.SecretRef.Name,
+							Data:       metadata.Data,
+							BinaryData: metadata.BinaryData,
+						})
+					}
+				}
+				if ref := stream.Status.Binding.SecretRef.Name; ref != "" {
+					var secret corev1.Secret
+					key := types.NamespacedName{Namespace: stream.Namespace, Name: ref}
+					// track binding secret for changes
+					c.Tracker.Track(
+						tracker.NewKey(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, key),
+						parentKey,
+					)
+					if err := c.Get(ctx, key, &secret); err != nil {
+						if !apierrs.IsNotFound(err) {
+							return err
+						}
+					} else {
+						desiredSecrets = append(desiredSecrets, &corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      name,
+								Namespace: parent.Namespace,
+								Labels:    labels,
 							},
-						},
-					},
-				)
+							Type: secret.Type,
+							Data: secret.Data,
+						})
+					}
+				}
 			}
-		}
 
-		// Create one volume mount for each *binding*, split into inputs/outputs.
-		// The consumer of those will know to count from 0..Nbindings-1 thanks to the INPUT/OUTPUT_NAMES var
-		for i, binding := range processor.Spec.Inputs {
-			stream := streams[binding.Stream]
-			if stream.Status.Binding.MetadataRef.Name != "" {
-				volumeMounts = append(volumeMounts,
-					corev1.VolumeMount{
-						Name:      fmt.Sprintf("stream-%s-metadata", stream.UID),
-						MountPath: fmt.Sprintf("%s/input_%03d/metadata", bindingsRootPath, i),
-						ReadOnly:  true,
-					},
-				)
-			}
-			if stream.Status.Binding.SecretRef.Name != "" {
-				volumeMounts = append(volumeMounts,
-					corev1.VolumeMount{
-						Name:      fmt.Sprintf("stream-%s-secret", stream.UID),
-						MountPath: fmt.Sprintf("%s/input_%03d/secret", bindingsRootPath, i),
-						ReadOnly:  true,
-					},
-				)
+			var configMaps corev1.ConfigMapList
+			if err := c.List(ctx, &configMaps, client.InNamespace(parent.Namespace), client.MatchingField(processorBindingConfigMapIndexField, parent.Name)); err != nil {
+				return err
 			}
-		}
-		for i, binding := range processor.Spec.Outputs {
-			stream := streams[binding.Stream]
-			if stream.Status.Binding.MetadataRef.Name != "" {
-				volumeMounts = append(volumeMounts,
-					corev1.VolumeMount{
-						Name:      fmt.Sprintf("stream-%s-metadata", stream.UID),
-						MountPath: fmt.Sprintf("%s/output_%03d/metadata", bindingsRootPath, i),
-						ReadOnly:  true,
-					},
-				)
-			}
-			if stream.Status.Binding.SecretRef.Name != "" {
-				volumeMounts = append(volumeMounts,
-					corev1.VolumeMount{
-						Name:      fmt.Sprintf("stream-%s-secret", stream.UID),
-						MountPath: fmt.Sprintf("%s/output_%03d/secret", bindingsRootPath, i),
-						ReadOnly:  true,
-					},
-				)
+			actualConfigMaps := []apis.Object{}
+			for i := range configMaps.Items {
+				if metav1.IsControlledBy(&configMaps.Items[i], parent) {
+					actualConfigMaps = append(actualConfigMaps, &configMaps.Items[i])
+				}
+			}
+			configMapChildren, err := reconcileControlledChildren(ctx, c, parent, "ConfigMap", actualConfigMaps, desiredConfigMaps,
+				func(a1, a2 apis.Object) bool {
+					c1, c2 := a1.(*corev1.ConfigMap), a2.(*corev1.ConfigMap)
+					return equality.Semantic.DeepEqual(c1.Data, c2.Data) &&
+						equality.Semantic.DeepEqual(c1.BinaryData, c2.BinaryData) &&
+						equality.Semantic.DeepEqual(c1.Labels, c2.Labels)
+				},
+				func(current, desired apis.Object) {
+					cur, des := current.(*corev1.ConfigMap), desired.(*corev1.ConfigMap)
+					cur.Labels = des.Labels
+					cur.Data = des.Data
+					cur.BinaryData = des.BinaryData
+				},
+			)
+			if err != nil {
+				return err
 			}
-		}
 
-		// sort volumes to avoid update diffs caused by iteration order
-		sort.SliceStable(volumes, func(i, j int) bool {
-			return volumes[i].Name < volumes[j].Name
-		})
+			var secrets corev1.SecretList
+			if err := c.List(ctx, &secrets, client.InNamespace(parent.Namespace), client.MatchingField(processorBindingSecretIndexField, parent.Name)); err != nil {
+				return err
+			}
+			actualSecrets := []apis.Object{}
+			for i := range secrets.Items {
+				if metav1.IsControlledBy(&secrets.Items[i], parent) {
+					actualSecrets = append(actualSecrets, &secrets.Items[i])
+				}
+			}
+			secretChildren, err := reconcileControlledChildren(ctx, c, parent, "Secret", actualSecrets, desiredSecrets,
+				func(a1, a2 apis.Object) bool {
+					s1, s2 := a1.(*corev1.Secret), a2.(*corev1.Secret)
+					return s1.Type == s2.Type &&
+						equality.Semantic.DeepEqual(s1.Data, s2.Data) &&
+						equality.Semantic.DeepEqual(s1.Labels, s2.Labels)
+				},
+				func(current, desired apis.Object) {
+					cur, des := current.(*corev1.Secret), desired.(*corev1.Secret)
+					cur.Labels = des.Labels
+					cur.Data = des.Data
+				},
+			)
+			if err != nil {
+				return err
+			}
 
-		return volumes, volumeMounts
-	}
+			for i, child := range configMapChildren {
+				if child == nil {
+					parent.Status.MarkStreamsNotReady(fmt.Sprintf("binding metadata %s is not owned by the processor", desiredConfigMaps[i].GetName()))
+					return nil
+				}
+			}
+			for i, child := range secretChildren {
+				if child == nil {
+					parent.Status.MarkStreamsNotReady(fmt.Sprintf("binding secret %s is not owned by the processor", desiredSecrets[i].GetName()))
+					return nil
+				}
+			}
 
-	constructEnv := func(processor *streamingv1alpha1.Processor) []v1.EnvVar {
-		inputStartOffsets := make([]string, len(processor.Spec.Inputs))
-		for i, binding := range processor.Spec.Inputs {
-			inputStartOffsets[i] = binding.StartOffset
-		}
-		inputAliases := make([]string, len(processor.Spec.Inputs))
-		for i, binding := range processor.Spec.Inputs {
-			inputAliases[i] = binding.Alias
-		}
-		outputAliases := make([]string, len(processor.Spec.Outputs))
-		for i, binding := range processor.Spec.Outputs {
-			outputAliases[i] = binding.Alias
-		}
+			return nil
+		},
 
-		return []v1.EnvVar{
-			{
-				Name:  "CNB_BINDINGS",
-				Value: bindingsRootPath,
-			},
-			{
-				Name:  "INPUT_START_OFFSETS",
-				Value: strings.Join(inputStartOffsets, ","),
-			},
-			{
-				Name:  "INPUT_NAMES",
-				Value: strings.Join(inputAliases, ","),
-			},
-			{
-				Name:  "OUTPUT_NAMES",
-				Value: strings.Join(outputAliases, ","),
-			},
-			{
-				Name:  "GROUP",
-				Value: processor.Name,
-			},
-			{
-				Name:  "FUNCTION",
-				Value: "localhost:8081",
-			},
-		}
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Owns(&corev1.ConfigMap{})
+			bldr.Owns(&corev1.Secret{})
+			bldr.Watches(&source.Kind{Type: &corev1.ConfigMap{}}, controllers.EnqueueTracked(&corev1.ConfigMap{}, c.Tracker, c.Scheme))
+			bldr.Watches(&source.Kind{Type: &corev1.Secret{}}, controllers.EnqueueTracked(&corev1.Secret{}, c.Tracker, c.Scheme))
+			if err := controllers.IndexControllersOfType(mgr, processorBindingConfigMapIndexField, &streamingv1alpha1.Processor{}, &corev1.ConfigMap{}, c.Scheme); err != nil {
+				return err
+			}
+			return controllers.IndexControllersOfType(mgr, processorBindingSecretIndexField, &streamingv1alpha1.Processor{}, &corev1.Secret{}, c.Scheme)
+		},
 	}
+}
+
+func ProcessorChildDeploymentReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildDeployment")
+
+	one := int32(1)
 
 	return &controllers.ChildReconciler{
 		ParentType:    &streamingv1alpha1.Processor{},
@@ -389,7 +588,11 @@ func ProcessorChildDeploymentReconciler(c controllers.Config) controllers.SubRec
 		ChildListType: &appsv1.DeploymentList{},
 
 		DesiredChild: func(ctx context.Context, parent *streamingv1alpha1.Processor) (*appsv1.Deployment, error) {
-			if parent.Status.LatestImage == "" {
+			if parent.Spec.State != nil {
+				// stateful processors run as a StatefulSet
+				return nil, nil
+			}
+			if !processorRunnable(parent) {
 				// no image, skip
 				return nil, nil
 			}
@@ -413,25 +616,7 @@ func ProcessorChildDeploymentReconciler(c controllers.Config) controllers.SubRec
 			labels := controllers.MergeMaps(parent.Labels, map[string]string{
 				streamingv1alpha1.ProcessorLabelKey: parent.Name,
 			})
-			volumes, volumeMounts := constructVolumes(parent, inputStreams, outputStreams)
-			env := constructEnv(parent)
-
-			// merge provided template with controlled values
-			template := parent.Spec.Template.DeepCopy()
-			template.Labels = controllers.MergeMaps(template.Labels, labels)
-			template.Spec.Containers[0].Image = parent.Status.LatestImage
-			template.Spec.Containers[0].Ports = []v1.ContainerPort{
-				{
-					ContainerPort: 8081,
-				},
-			}
-			template.Spec.Containers = append(template.Spec.Containers, v1.Container{
-				Name:         "processor",
-				Image:        processorImage,
-				Env:          env,
-				VolumeMounts: volumeMounts,
-			})
-			template.Spec.Volumes = append(template.Spec.Volumes, volumes...)
+			template := constructPodTemplate(parent, labels, processorImage, inputStreams, outputStreams)
 
 			child := &appsv1.Deployment{
 				ObjectMeta: metav1.ObjectMeta{
@@ -449,12 +634,22 @@ func ProcessorChildDeploymentReconciler(c controllers.Config) controllers.SubRec
 					Template: *template,
 				},
 			}
+			if rollout := parent.Spec.Rollout; rollout != nil && (rollout.MaxSurge != nil || rollout.MaxUnavailable != nil) {
+				child.Spec.Strategy = appsv1.DeploymentStrategy{
+					Type: appsv1.RollingUpdateDeploymentStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateDeployment{
+						MaxSurge:       rollout.MaxSurge,
+						MaxUnavailable: rollout.MaxUnavailable,
+					},
+				}
+			}
 
 			return child, nil
 		},
 		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.Processor, child *appsv1.Deployment, err error) {
 			if child == nil {
 				parent.Status.DeploymentRef = nil
+				parent.Status.Replicas = 0
 			} else {
 				parent.Status.DeploymentRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
 				parent.Status.PropagateDeploymentStatus(&child.Status)
@@ -480,6 +675,259 @@ func ProcessorChildDeploymentReconciler(c controllers.Config) controllers.SubRec
 	}
 }
 
+func ProcessorChildChangelogStreamReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildChangelogStream")
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.Processor{},
+		ChildType:     &streamingv1alpha1.Stream{},
+		ChildListType: &streamingv1alpha1.StreamList{},
+
+		DesiredChild: func(ctx context.Context, parent *streamingv1alpha1.Processor) (*streamingv1alpha1.Stream, error) {
+			if parent.Spec.State == nil || parent.Spec.State.Changelog == nil {
+				return nil, nil
+			}
+
+			labels := controllers.MergeMaps(parent.Labels, map[string]string{
+				streamingv1alpha1.ProcessorLabelKey: parent.Name,
+			})
+
+			child := &streamingv1alpha1.Stream{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("%s-changelog", parent.Name),
+					Namespace: parent.Namespace,
+					Labels:    labels,
+				},
+				Spec: streamingv1alpha1.StreamSpec{
+					Gateway:     parent.Spec.State.Changelog.Gateway,
+					ContentType: "application/octet-stream",
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.Processor, child *streamingv1alpha1.Stream, err error) {
+			if err != nil {
+				if apierrs.IsAlreadyExists(err) {
+					name := err.(apierrs.APIStatus).Status().Details.Name
+					parent.Status.MarkStreamsNotReady(fmt.Sprintf("changelog stream %s is not owned by the processor", name))
+				}
+				return
+			}
+			if child == nil {
+				parent.Status.ChangelogStreamRef = nil
+				return
+			}
+			parent.Status.ChangelogStreamRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+			ready := child.Status.GetCondition(child.Status.GetReadyConditionType())
+			if ready == nil {
+				ready = &apis.Condition{Message: "stream has no ready condition"}
+			}
+			if !ready.IsTrue() {
+				parent.Status.MarkStreamsNotReady(fmt.Sprintf("changelog stream %s is not ready: %s", child.Name, ready.Message))
+			}
+		},
+		MergeBeforeUpdate: func(current, desired *streamingv1alpha1.Stream) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *streamingv1alpha1.Stream) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.processorStreamController",
+		Sanitize: func(child *streamingv1alpha1.Stream) interface{} {
+			return child.Spec
+		},
+	}
+}
+
+func ProcessorChildStatefulSetReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildStatefulSet")
+
+	one := int32(1)
+
+	// constructStateVolumes backs the processor's state store, returning nil
+	// volume mounts while a changelog stream is not yet bound
+	constructStateVolumes := func(ctx context.Context, parent *streamingv1alpha1.Processor) ([]corev1.Volume, []corev1.VolumeMount, []corev1.PersistentVolumeClaim, error) {
+		if volume := parent.Spec.State.Volume; volume != nil {
+			claims := []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "state",
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+						StorageClassName: volume.StorageClassName,
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: volume.Size,
+							},
+						},
+					},
+				},
+			}
+			volumeMounts := []corev1.VolumeMount{
+				{
+					Name:      "state",
+					MountPath: stateRootPath,
+				},
+			}
+			return []corev1.Volume{}, volumeMounts, claims, nil
+		}
+
+		if parent.Status.ChangelogStreamRef == nil {
+			return nil, nil, nil, nil
+		}
+		var stream streamingv1alpha1.Stream
+		if err := c.Get(ctx, types.NamespacedName{Namespace: parent.Namespace, Name: parent.Status.ChangelogStreamRef.Name}, &stream); err != nil {
+			if apierrs.IsNotFound(err) {
+				return nil, nil, nil, nil
+			}
+			return nil, nil, nil, err
+		}
+		if stream.Status.Binding.MetadataRef.Name == "" || stream.Status.Binding.SecretRef.Name == "" {
+			return nil, nil, nil, nil
+		}
+		volumes := []corev1.Volume{
+			{
+				Name: fmt.Sprintf("stream-%s-metadata", stream.UID),
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: stream.Status.Binding.MetadataRef.Name,
+						},
+					},
+				},
+			},
+			{
+				Name: fmt.Sprintf("stream-%s-secret", stream.UID),
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: stream.Status.Binding.SecretRef.Name,
+					},
+				},
+			},
+		}
+		volumeMounts := []corev1.VolumeMount{
+			{
+				Name:      fmt.Sprintf("stream-%s-metadata", stream.UID),
+				MountPath: fmt.Sprintf("%s/state/metadata", bindingsRootPath),
+				ReadOnly:  true,
+			},
+			{
+				Name:      fmt.Sprintf("stream-%s-secret", stream.UID),
+				MountPath: fmt.Sprintf("%s/state/secret", bindingsRootPath),
+				ReadOnly:  true,
+			},
+		}
+		return volumes, volumeMounts, []corev1.PersistentVolumeClaim{}, nil
+	}
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.Processor{},
+		ChildType:     &appsv1.StatefulSet{},
+		ChildListType: &appsv1.StatefulSetList{},
+
+		DesiredChild: func(ctx context.Context, parent *streamingv1alpha1.Processor) (*appsv1.StatefulSet, error) {
+			if parent.Spec.State == nil {
+				// stateless processors run as a Deployment
+				return nil, nil
+			}
+			if !processorRunnable(parent) {
+				// no image, skip
+				return nil, nil
+			}
+			inputStreams, ok := controllers.RetrieveValue(ctx, InputStreamsStashKey).([]streamingv1alpha1.Stream)
+			if !ok {
+				return nil, nil
+			}
+			outputStreams, ok := controllers.RetrieveValue(ctx, OutputStreamsStashKey).([]streamingv1alpha1.Stream)
+			if !ok {
+				return nil, nil
+			}
+			processorImages, ok := controllers.RetrieveValue(ctx, ProcessorImagesStashKey).(map[string]string)
+			if !ok {
+				return nil, nil
+			}
+			processorImage := processorImages[processorImageKey]
+			if processorImage == "" {
+				return nil, nil
+			}
+			stateVolumes, stateVolumeMounts, claims, err := constructStateVolumes(ctx, parent)
+			if err != nil || stateVolumeMounts == nil {
+				return nil, err
+			}
+
+			labels := controllers.MergeMaps(parent.Labels, map[string]string{
+				streamingv1alpha1.ProcessorLabelKey: parent.Name,
+			})
+			template := constructPodTemplate(parent, labels, processorImage, inputStreams, outputStreams)
+			processorContainer := &template.Spec.Containers[len(template.Spec.Containers)-1]
+			processorContainer.VolumeMounts = append(processorContainer.VolumeMounts, stateVolumeMounts...)
+			template.Spec.Volumes = append(template.Spec.Volumes, stateVolumes...)
+
+			// stateful processors run a fixed number of replicas, validation
+			// rejects the autoscaling settings
+			replicas := one
+			if min := parent.Spec.Scale.Min; min != nil && *min > 0 {
+				replicas = *min
+			}
+
+			child := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: fmt.Sprintf("%s-processor-", parent.Name),
+					Namespace:    parent.Namespace,
+					Labels:       labels,
+				},
+				Spec: appsv1.StatefulSetSpec{
+					// the governing service of the pods' network identity, the
+					// processor's pods are not addressed so no Service is created
+					ServiceName: fmt.Sprintf("%s-processor", parent.Name),
+					Replicas:    &replicas,
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							streamingv1alpha1.ProcessorLabelKey: parent.Name,
+						},
+					},
+					Template:             *template,
+					VolumeClaimTemplates: claims,
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.Processor, child *appsv1.StatefulSet, err error) {
+			if child == nil {
+				parent.Status.StatefulSetRef = nil
+			} else {
+				parent.Status.StatefulSetRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+				parent.Status.PropagateStatefulSetStatus(&child.Status)
+			}
+		},
+		HarmonizeImmutableFields: func(current, desired *appsv1.StatefulSet) {
+			desired.Spec.ServiceName = current.Spec.ServiceName
+			desired.Spec.VolumeClaimTemplates = current.Spec.VolumeClaimTemplates
+		},
+		MergeBeforeUpdate: func(current, desired *appsv1.StatefulSet) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *appsv1.StatefulSet) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.processorStatefulSetController",
+		Sanitize: func(child *appsv1.StatefulSet) interface{} {
+			return child.Spec
+		},
+	}
+}
+
 func ProcessorChildScaledObjectReconciler(c controllers.Config) controllers.SubReconciler {
 	c.Log = c.Log.WithName("ChildScaledObject")
 
@@ -512,6 +960,30 @@ func ProcessorChildScaledObjectReconciler(c controllers.Config) controllers.SubR
 		}
 		return addresses, nil
 	}
+	collectTriggerAuthentications := func(ctx context.Context, parent *streamingv1alpha1.Processor, streams []streamingv1alpha1.Stream) ([]string, error) {
+		names := make([]string, len(streams))
+		for i, stream := range streams {
+			if stream.Namespace != parent.Namespace {
+				// a TriggerAuthentication is only referenced from its own namespace
+				continue
+			}
+			var gateway streamingv1alpha1.Gateway
+			key := types.NamespacedName{Namespace: stream.Namespace, Name: stream.Spec.Gateway.Name}
+			// track gateway for authentication changes
+			c.Tracker.Track(
+				tracker.NewKey(gateway.GetGroupVersionKind(), key),
+				types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+			)
+			if err := c.Get(ctx, key, &gateway); err != nil {
+				if apierrs.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			names[i] = gateway.Annotations[streamingv1alpha1.GatewayTriggerAuthenticationAnnotationKey]
+		}
+		return names, nil
+	}
 
 	return &controllers.ChildReconciler{
 		ParentType:    &streamingv1alpha1.Processor{},
@@ -519,6 +991,10 @@ func ProcessorChildScaledObjectReconciler(c controllers.Config) controllers.SubR
 		ChildListType: &kedav1alpha1.ScaledObjectList{},
 
 		DesiredChild: func(ctx context.Context, parent *streamingv1alpha1.Processor) (*kedav1alpha1.ScaledObject, error) {
+			if parent.Spec.State != nil {
+				// stateful processors are not autoscaled
+				return nil, nil
+			}
 			if parent.Status.DeploymentRef == nil {
 				// no deployment, skip
 				return nil, nil
@@ -532,9 +1008,28 @@ func ProcessorChildScaledObjectReconciler(c controllers.Config) controllers.SubR
 				streamingv1alpha1.ProcessorLabelKey: parent.Name,
 			})
 
-			maxReplicas := thirty
+			minReplicas := one
+			if parent.Spec.Scale.Min != nil {
+				minReplicas = *parent.Spec.Scale.Min
+			}
+			maxReplicas := streamingv1alpha1.ScaleMaxDefault
+			if parent.Spec.Scale.Max != nil {
+				maxReplicas = *parent.Spec.Scale.Max
+			} else if minReplicas > maxReplicas {
+				// the default max never caps the min
+				maxReplicas = minReplicas
+			}
+			pollingInterval := one
+			if parent.Spec.Scale.PollingInterval != nil {
+				pollingInterval = *parent.Spec.Scale.PollingInterval
+			}
+			cooldownPeriod := thirty
+			if parent.Spec.Scale.CooldownPeriod != nil {
+				cooldownPeriod = *parent.Spec.Scale.CooldownPeriod
+			}
 			if parent.Status.GetCondition(streamingv1alpha1.ProcessorConditionStreamsReady).IsFalse() {
 				// scale to zero while dependencies are not ready
+				minReplicas = zero
 				maxReplicas = zero
 			}
 
@@ -542,14 +1037,24 @@ func ProcessorChildScaledObjectReconciler(c controllers.Config) controllers.SubR
 			if err != nil || inputAddresses == nil {
 				return nil, err
 			}
+			inputAuthentications, err := collectTriggerAuthentications(ctx, parent, inputStreams)
+			if err != nil {
+				return nil, err
+			}
 			triggers := make([]kedav1alpha1.ScaleTriggers, len(inputAddresses))
 			for i, input := range inputAddresses {
 				triggers[i].Type = "liiklus"
 				triggers[i].Metadata = map[string]string{
 					"address": input[0],
-					"group":   parent.Name,
+					"group":   consumerGroup(parent),
 					"topic":   input[1],
 				}
+				if threshold := parent.Spec.Inputs[i].LagThreshold; threshold != nil {
+					triggers[i].Metadata["lagThreshold"] = fmt.Sprintf("%d", *threshold)
+				}
+				if name := inputAuthentications[i]; name != "" {
+					triggers[i].AuthenticationRef = &kedav1alpha1.ScaledObjectAuthRef{Name: name}
+				}
 			}
 
 			child := &kedav1alpha1.ScaledObject{
@@ -562,10 +1067,10 @@ func ProcessorChildScaledObjectReconciler(c controllers.Config) controllers.SubR
 					ScaleTargetRef: &kedav1alpha1.ObjectReference{
 						DeploymentName: parent.Status.DeploymentRef.Name,
 					},
-					PollingInterval: &one,
-					CooldownPeriod:  &thirty,
+					PollingInterval: &pollingInterval,
+					CooldownPeriod:  &cooldownPeriod,
 					Triggers:        triggers,
-					MinReplicaCount: &one,
+					MinReplicaCount: &minReplicas,
 					MaxReplicaCount: &maxReplicas,
 				},
 			}
@@ -575,6 +1080,10 @@ func ProcessorChildScaledObjectReconciler(c controllers.Config) controllers.SubR
 		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.Processor, child *kedav1alpha1.ScaledObject, err error) {
 			if child == nil {
 				parent.Status.ScaledObjectRef = nil
+				parent.Status.LastActiveTime = nil
+				if parent.Spec.State != nil {
+					parent.Status.MarkScaledObjectNotRequired()
+				}
 			} else {
 				parent.Status.ScaledObjectRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
 				parent.Status.PropagateScaledObjectStatus(&child.Status)
@@ -596,6 +1105,418 @@ func ProcessorChildScaledObjectReconciler(c controllers.Config) controllers.SubR
 		},
 		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
 			bldr.Watches(&source.Kind{Type: &corev1.Secret{}}, controllers.EnqueueTracked(&corev1.Secret{}, c.Tracker, c.Scheme))
+			bldr.Watches(&source.Kind{Type: &streamingv1alpha1.Gateway{}}, controllers.EnqueueTracked(&streamingv1alpha1.Gateway{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
+
+// consumerGroup resolves the group a processor consumes its inputs with,
+// defaulting to the processor's name until the group has been resolved.
+func consumerGroup(processor *streamingv1alpha1.Processor) string {
+	if processor.Status.ConsumerGroup != "" {
+		return processor.Status.ConsumerGroup
+	}
+	return processor.Name
+}
+
+// processorRunnable is true once an image for the processor's function is
+// rolled out, processors applying an operation run without a function and
+// chains run once every function has an image
+func processorRunnable(processor *streamingv1alpha1.Processor) bool {
+	if len(processor.Spec.Chain) != 0 {
+		if len(processor.Status.Chain) != len(processor.Spec.Chain) {
+			return false
+		}
+		for _, function := range processor.Status.Chain {
+			if function.LatestImage == "" {
+				return false
+			}
+		}
+		return true
+	}
+	return processor.Spec.Operation != nil || processor.Status.DeployedImage != ""
+}
+
+func constructPodTemplate(processor *streamingv1alpha1.Processor, labels map[string]string, processorImage string, inputStreams, outputStreams []streamingv1alpha1.Stream) *corev1.PodTemplateSpec {
+	volumes, volumeMounts := constructVolumes(processor, inputStreams, outputStreams)
+	env := constructEnv(processor)
+
+	// merge provided template with controlled values
+	template := processor.Spec.Template.DeepCopy()
+	template.Labels = controllers.MergeMaps(template.Labels, labels)
+	if processor.Spec.Operation != nil {
+		// the processor applies the operation itself
+		template.Spec.Containers = template.Spec.Containers[1:]
+	} else if len(processor.Spec.Chain) != 0 {
+		// each function in the chain runs in a copy of the function container
+		base := template.Spec.Containers[0]
+		functions := make([]v1.Container, len(processor.Spec.Chain))
+		for i, function := range processor.Spec.Chain {
+			container := base.DeepCopy()
+			container.Name = function.Name
+			container.Image = processor.Status.Chain[i].LatestImage
+			container.Ports = functionPorts(function.Port, base.Ports)
+			// the invokers listen on the port named by these variables
+			port := fmt.Sprintf("%d", function.Port)
+			container.Env = append(container.Env,
+				v1.EnvVar{Name: "GRPC_PORT", Value: port},
+				v1.EnvVar{Name: "PORT", Value: port},
+			)
+			functions[i] = *container
+		}
+		template.Spec.Containers = append(functions, template.Spec.Containers[1:]...)
+	} else {
+		template.Spec.Containers[0].Image = processor.Status.DeployedImage
+		template.Spec.Containers[0].Ports = functionPorts(8081, template.Spec.Containers[0].Ports)
+	}
+	template.Spec.Containers = append(template.Spec.Containers, v1.Container{
+		Name:         "processor",
+		Image:        processorImage,
+		Env:          env,
+		VolumeMounts: volumeMounts,
+	})
+	template.Spec.Volumes = append(template.Spec.Volumes, volumes...)
+
+	return template
+}
+
+// functionPorts declares the port the function listens on ahead of the other
+// ports of the template's function container
+func functionPorts(port int32, ports []v1.ContainerPort) []v1.ContainerPort {
+	merged := []v1.ContainerPort{
+		{
+			ContainerPort: port,
+		},
+	}
+	for _, p := range ports {
+		if p.ContainerPort == port {
+			// keep the template's declaration of the function port
+			merged[0] = p
+			continue
+		}
+		merged = append(merged, p)
+	}
+	return merged
+}
+
+func constructVolumes(processor *streamingv1alpha1.Processor, inputStreams, outputStreams []streamingv1alpha1.Stream) ([]corev1.Volume, []corev1.VolumeMount) {
+	// streams from other namespaces are bound from copies
+	binding := func(stream streamingv1alpha1.Stream) (string, string) {
+		metadata, secret := streamBinding(stream)
+		if stream.Namespace != processor.Namespace {
+			name := processorBindingName(processor, stream)
+			if metadata != "" {
+				metadata = name
+			}
+			if secret != "" {
+				secret = name
+			}
+		}
+		return metadata, secret
+	}
+
+	return constructBindingVolumes(inputStreams, outputStreams, binding)
+}
+
+// constructBindingVolumes mounts the binding of each input and output stream
+// at the paths the processor expects. The binding func resolves the names of
+// the metadata config map and secret bound for a stream.
+func constructBindingVolumes(inputStreams, outputStreams []streamingv1alpha1.Stream, binding func(stream streamingv1alpha1.Stream) (string, string)) ([]corev1.Volume, []corev1.VolumeMount) {
+	volumes := []corev1.Volume{}
+	volumeMounts := []corev1.VolumeMount{}
+
+	// De-dupe streams and create one volume for each
+	streams := make(map[types.UID]streamingv1alpha1.Stream)
+	for _, s := range inputStreams {
+		streams[s.UID] = s
+	}
+	for _, s := range outputStreams {
+		streams[s.UID] = s
+	}
+	for _, stream := range streams {
+		metadata, secret := binding(stream)
+		if metadata != "" {
+			volumes = append(volumes,
+				corev1.Volume{
+					Name: fmt.Sprintf("stream-%s-metadata", stream.UID),
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: metadata,
+							},
+						},
+					},
+				},
+			)
+		}
+		if secret != "" {
+			volumes = append(volumes,
+				corev1.Volume{
+					Name: fmt.Sprintf("stream-%s-secret", stream.UID),
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: secret,
+						},
+					},
+				},
+			)
+		}
+	}
+
+	// Create one volume mount for each *binding*, split into inputs/outputs.
+	// The consumer of those will know to count from 0..Nbindings-1 thanks to the INPUT/OUTPUT_NAMES var
+	for i, stream := range inputStreams {
+		metadata, secret := binding(stream)
+		if metadata != "" {
+			volumeMounts = append(volumeMounts,
+				corev1.VolumeMount{
+					Name:      fmt.Sprintf("stream-%s-metadata", stream.UID),
+					MountPath: fmt.Sprintf("%s/input_%03d/metadata", bindingsRootPath, i),
+					ReadOnly:  true,
+				},
+			)
+		}
+		if secret != "" {
+			volumeMounts = append(volumeMounts,
+				corev1.VolumeMount{
+					Name:      fmt.Sprintf("stream-%s-secret", stream.UID),
+					MountPath: fmt.Sprintf("%s/input_%03d/secret", bindingsRootPath, i),
+					ReadOnly:  true,
+				},
+			)
+		}
+	}
+	for i, stream := range outputStreams {
+		metadata, secret := binding(stream)
+		if metadata != "" {
+			volumeMounts = append(volumeMounts,
+				corev1.VolumeMount{
+					Name:      fmt.Sprintf("stream-%s-metadata", stream.UID),
+					MountPath: fmt.Sprintf("%s/output_%03d/metadata", bindingsRootPath, i),
+					ReadOnly:  true,
+				},
+			)
+		}
+		if secret != "" {
+			volumeMounts = append(volumeMounts,
+				corev1.VolumeMount{
+					Name:      fmt.Sprintf("stream-%s-secret", stream.UID),
+					MountPath: fmt.Sprintf("%s/output_%03d/secret", bindingsRootPath, i),
+					ReadOnly:  true,
+				},
+			)
+		}
+	}
+
+	// sort volumes to avoid update diffs caused by iteration order
+	sort.SliceStable(volumes, func(i, j int) bool {
+		return volumes[i].Name < volumes[j].Name
+	})
+
+	return volumes, volumeMounts
+}
+
+// streamBinding resolves the names of the metadata config map and secret
+// published by the stream
+func streamBinding(stream streamingv1alpha1.Stream) (string, string) {
+	return stream.Status.Binding.MetadataRef.Name, stream.Status.Binding.SecretRef.Name
+}
+
+// processorBindingName is the name of the copy of the binding for a stream
+// from another namespace. The stream's UID is used as the namespace and name
+// of the stream cannot be joined unambiguously.
+func processorBindingName(processor *streamingv1alpha1.Processor, stream streamingv1alpha1.Stream) string {
+	return fmt.Sprintf("%s-stream-%s-binding", processor.Name, stream.UID)
+}
+
+// encodeStartOffset renders where the processor starts consuming an input
+// as either earliest, latest, an RFC3339 timestamp or semicolon delimited
+// partition=offset pairs
+func encodeStartOffset(binding streamingv1alpha1.InputStreamBinding) string {
+	if binding.StartTime != nil {
+		return binding.StartTime.UTC().Format(time.RFC3339)
+	}
+	if len(binding.PartitionOffsets) != 0 {
+		partitionOffsets := make([]streamingv1alpha1.PartitionOffset, len(binding.PartitionOffsets))
+		copy(partitionOffsets, binding.PartitionOffsets)
+		// sort partitions to avoid update diffs caused by declaration order
+		sort.SliceStable(partitionOffsets, func(i, j int) bool {
+			return partitionOffsets[i].Partition < partitionOffsets[j].Partition
+		})
+		pairs := make([]string, len(partitionOffsets))
+		for i, po := range partitionOffsets {
+			pairs[i] = fmt.Sprintf("%d=%d", po.Partition, po.Offset)
+		}
+		return strings.Join(pairs, ";")
+	}
+	return binding.StartOffset
+}
+
+func constructEnv(processor *streamingv1alpha1.Processor) []v1.EnvVar {
+	inputStartOffsets := make([]string, len(processor.Spec.Inputs))
+	for i, binding := range processor.Spec.Inputs {
+		inputStartOffsets[i] = encodeStartOffset(binding)
+	}
+	inputAliases := make([]string, len(processor.Spec.Inputs))
+	for i, binding := range processor.Spec.Inputs {
+		inputAliases[i] = binding.Alias
+	}
+	outputAliases := make([]string, len(processor.Spec.Outputs))
+	for i, binding := range processor.Spec.Outputs {
+		outputAliases[i] = binding.Alias
+	}
+
+	env := []v1.EnvVar{
+		{
+			Name:  "CNB_BINDINGS",
+			Value: bindingsRootPath,
+		},
+		{
+			Name:  "INPUT_START_OFFSETS",
+			Value: strings.Join(inputStartOffsets, ","),
+		},
+		{
+			Name:  "INPUT_NAMES",
+			Value: strings.Join(inputAliases, ","),
+		},
+		{
+			Name:  "OUTPUT_NAMES",
+			Value: strings.Join(outputAliases, ","),
+		},
+		{
+			Name:  "GROUP",
+			Value: consumerGroup(processor),
+		},
+	}
+
+	if operation := processor.Spec.Operation; operation != nil {
+		// encoding plain structs cannot fail
+		encoded, _ := json.Marshal(operation)
+		env = append(env, v1.EnvVar{
+			Name:  "OPERATION",
+			Value: string(encoded),
+		})
+	} else if len(processor.Spec.Chain) != 0 {
+		// functions are invoked in order, each receiving the prior result
+		functions := make([]string, len(processor.Spec.Chain))
+		for i, function := range processor.Spec.Chain {
+			functions[i] = fmt.Sprintf("localhost:%d", function.Port)
+		}
+		env = append(env, v1.EnvVar{
+			Name:  "FUNCTIONS",
+			Value: strings.Join(functions, ","),
+		})
+	} else {
+		env = append(env, v1.EnvVar{
+			Name:  "FUNCTION",
+			Value: "localhost:8081",
+		})
+	}
+
+	if window := processor.Spec.Window; window != nil {
+		env = append(env,
+			v1.EnvVar{
+				Name:  "WINDOW_TYPE",
+				Value: window.Type,
+			},
+			v1.EnvVar{
+				Name:  "WINDOW_SIZE",
+				Value: encodeWindowExtent(window.Size),
+			},
+		)
+		if window.Slide != nil {
+			env = append(env, v1.EnvVar{
+				Name:  "WINDOW_SLIDE",
+				Value: encodeWindowExtent(*window.Slide),
+			})
+		}
+	}
+
+	if state := processor.Spec.State; state != nil {
+		switch {
+		case state.Volume != nil:
+			env = append(env,
+				v1.EnvVar{
+					Name:  "STATE_STORE",
+					Value: "volume",
+				},
+				v1.EnvVar{
+					Name:  "STATE_DIR",
+					Value: stateRootPath,
+				},
+			)
+		case state.Changelog != nil:
+			env = append(env, v1.EnvVar{
+				Name:  "STATE_STORE",
+				Value: "changelog",
+			})
+		}
+	}
+
+	return env
+}
+
+// encodeWindowExtent renders a window extent as either a count of messages or
+// a duration, like 30s
+func encodeWindowExtent(extent streamingv1alpha1.WindowExtent) string {
+	if extent.Duration != nil {
+		return extent.Duration.Duration.String()
+	}
+	return fmt.Sprintf("%d", *extent.Count)
+}
+
+func ProcessorConsumerLagReconciler(c controllers.Config, lagMonitor *ConsumerLagMonitor) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ConsumerLag")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.Processor) error {
+			if lagMonitor == nil {
+				return nil
+			}
+			lags, ok := lagMonitor.Lag(types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name})
+			if !ok {
+				// not yet observed, keep the last reported lag
+				return nil
+			}
+			if lags == nil {
+				// the last reported lag is stale
+				parent.Status.Lag = nil
+				parent.Status.InputLags = nil
+				parent.Status.MarkLagUnknown("lag has not been observed for %d polls", lagStaleIntervals)
+				return nil
+			}
+
+			threshold := lagMonitor.Threshold
+			if parent.Spec.LaggingThreshold != nil {
+				threshold = *parent.Spec.LaggingThreshold
+			}
+
+			var total int64
+			var lagging []string
+			for _, lag := range lags {
+				total += lag.Lag
+				if lag.Lag > threshold {
+					lagging = append(lagging, fmt.Sprintf("%s (%d)", lag.Stream, lag.Lag))
+				}
+			}
+			parent.Status.Lag = &total
+			parent.Status.InputLags = lags
+			if len(lagging) != 0 {
+				parent.Status.MarkLagging("lag exceeds threshold of %d on %s", threshold, strings.Join(lagging, ", "))
+			} else {
+				parent.Status.MarkNotLagging()
+			}
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			if lagMonitor == nil {
+				return nil
+			}
+			// reconcile processors as their lag changes
+			bldr.Watches(&source.Channel{Source: lagMonitor.Events()}, &handler.EnqueueRequestForObject{})
 			return nil
 		},
 	}
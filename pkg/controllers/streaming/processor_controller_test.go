@@ -19,11 +19,17 @@ package streaming_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -42,13 +48,16 @@ import (
 func TestProcessorReconciler(t *testing.T) {
 	testNamespace := "test-namespace"
 	testSystemNamespace := "system-namespace"
+	testSharedNamespace := "shared-namespace"
 	testName := "test-processor"
 	testKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
 	testImagePrefix := "example.com/repo"
 	testSha256 := "cf8b4c69d5460f88530e1c80b8856a70801f31c50b191c8413043ba9b160a43e"
 	testImage := fmt.Sprintf("%s@sha256:%s", testImagePrefix, testSha256)
 	testProcessorImage := fmt.Sprintf("%s/processor", testImagePrefix)
+	testGatewayName := "test-gateway"
 
+	processorConditionLagging := factories.Condition().Type(streamingv1alpha1.ProcessorConditionLagging).Info()
 	processorConditionDeploymentReady := factories.Condition().Type(streamingv1alpha1.ProcessorConditionDeploymentReady)
 	processorConditionReady := factories.Condition().Type(streamingv1alpha1.ProcessorConditionReady)
 	processorConditionScaledObjectReady := factories.Condition().Type(streamingv1alpha1.ProcessorConditionScaledObjectReady)
@@ -70,12 +79,15 @@ func TestProcessorReconciler(t *testing.T) {
 		NamespaceName(testNamespace, "my-container").
 		StatusLatestImage(testImage)
 
+	testGateway := factories.Gateway().
+		NamespaceName(testNamespace, testGatewayName)
 	testStream1 := factories.Stream().
 		NamespaceName(testNamespace, "stream-1").
 		ObjectMeta(func(om factories.ObjectMeta) {
 			om.Created(1)
 			om.UID("00000000-0000-0000-0000-000000000001")
 		}).
+		Gateway(testGatewayName).
 		ContentType("text/plain").
 		StatusBinding("stream-1-binding-metadata", "stream-1-binding-secret").
 		StatusReady()
@@ -85,6 +97,7 @@ func TestProcessorReconciler(t *testing.T) {
 			om.Created(1)
 			om.UID("00000000-0000-0000-0000-000000000002")
 		}).
+		Gateway(testGatewayName).
 		ContentType("text/plain").
 		StatusBinding("stream-2-binding-metadata", "stream-2-binding-secret").
 		StatusReady()
@@ -94,6 +107,7 @@ func TestProcessorReconciler(t *testing.T) {
 			om.Created(1)
 			om.UID("00000000-0000-0000-0000-000000000003")
 		}).
+		Gateway(testGatewayName).
 		ContentType("text/plain").
 		StatusBinding("stream-3-binding-metadata", "stream-3-binding-secret").
 		StatusReady()
@@ -103,9 +117,24 @@ func TestProcessorReconciler(t *testing.T) {
 			om.Created(1)
 			om.UID("00000000-0000-0000-0000-000000000004")
 		}).
+		Gateway(testGatewayName).
 		ContentType("text/plain").
 		StatusBinding("stream-4-binding-metadata", "stream-4-binding-secret").
 		StatusReady()
+	testStreamShared := factories.Stream().
+		NamespaceName(testSharedNamespace, "shared-stream").
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.UID("00000000-0000-0000-0000-000000000005")
+		}).
+		Gateway(testGatewayName).
+		ContentType("text/plain").
+		StatusBinding("shared-stream-binding-metadata", "shared-stream-binding-secret").
+		StatusReady()
+	testStreamGrant := factories.StreamGrant().
+		NamespaceName(testSharedNamespace, "shared-grant").
+		Streams("shared-stream").
+		Namespaces(testNamespace)
 
 	scheme := runtime.NewScheme()
 	_ = clientgoscheme.AddToScheme(scheme)
@@ -122,6 +151,7 @@ func TestProcessorReconciler(t *testing.T) {
 	processor := processorMinimal.
 		Default().
 		StatusLatestImage(testImage).
+		StatusDeployedImage(testImage).
 		StatusDeploymentRef("%s-processor-000", testName).
 		StatusScaledObjectRef("%s-processor-000", testName)
 
@@ -177,6 +207,96 @@ func TestProcessorReconciler(t *testing.T) {
 			om.Created(1)
 		})
 
+	filterOperation := &streamingv1alpha1.ProcessorOperation{
+		Filter: &streamingv1alpha1.FilterOperation{
+			Expression: `contentType == "application/json"`,
+		},
+	}
+
+	chain := []streamingv1alpha1.ChainedFunction{
+		{Name: "parse", Build: streamingv1alpha1.Build{FunctionRef: "my-function"}, Port: 8081},
+		{Name: "enrich", Build: streamingv1alpha1.Build{ContainerRef: "my-container"}, Port: 8082},
+	}
+
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("256Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("256Mi"),
+		},
+	}
+	readinessProbe := &corev1.Probe{
+		Handler: corev1.Handler{
+			TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8081)},
+		},
+	}
+	toleration := corev1.Toleration{
+		Key:      "dedicated",
+		Operator: corev1.TolerationOpEqual,
+		Value:    "streaming",
+		Effect:   corev1.TaintEffectNoSchedule,
+	}
+
+	stateVolume := &streamingv1alpha1.ProcessorState{
+		Volume: &streamingv1alpha1.StateVolume{
+			Size: resource.MustParse("1Gi"),
+		},
+	}
+	stateChangelog := &streamingv1alpha1.ProcessorState{
+		Changelog: &streamingv1alpha1.StateChangelog{
+			Gateway: corev1.LocalObjectReference{Name: "my-gateway"},
+		},
+	}
+	changelogStreamCreate := factories.Stream().
+		NamespaceName(testNamespace, fmt.Sprintf("%s-changelog", testName)).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.AddLabel(streamingv1alpha1.ProcessorLabelKey, testName)
+			om.ControlledBy(processor, scheme)
+		}).
+		Gateway("my-gateway").
+		ContentType("application/octet-stream")
+	changelogStreamGiven := changelogStreamCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.UID("00000000-0000-0000-0000-000000000005")
+		})
+
+	statefulSetCreate := factories.StatefulSet().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.GenerateName("%s-processor-", testName)
+			om.AddLabel(streamingv1alpha1.ProcessorLabelKey, testName)
+			om.ControlledBy(processor, scheme)
+		}).
+		AddSelectorLabel(streamingv1alpha1.ProcessorLabelKey, testName).
+		PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+			pts.ContainerNamed("function", func(c *corev1.Container) {
+				c.Image = testImage
+				c.Ports = []corev1.ContainerPort{
+					{ContainerPort: 8081},
+				}
+			})
+			pts.ContainerNamed("processor", func(c *corev1.Container) {
+				c.Image = testProcessorImage
+				c.Env = []corev1.EnvVar{
+					{Name: "CNB_BINDINGS", Value: "/var/riff/bindings"},
+					{Name: "INPUT_START_OFFSETS", Value: ""},
+					{Name: "INPUT_NAMES", Value: ""},
+					{Name: "OUTPUT_NAMES", Value: ""},
+					{Name: "GROUP", Value: testName},
+					{Name: "FUNCTION", Value: "localhost:8081"},
+				}
+			})
+		}).
+		ServiceName(fmt.Sprintf("%s-processor", testName)).
+		Replicas(1)
+	statefulSetGiven := statefulSetCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Name("%s-processor-000", testName)
+			om.Created(1)
+		})
+
 	t.Run("ProcessorReconciler", func(t *testing.T) {
 		table := rtesting.Table{{
 			Name: "processor does not exist",
@@ -234,6 +354,8 @@ func TestProcessorReconciler(t *testing.T) {
 						processorConditionStreamsReady.True(),
 					).
 					StatusLatestImage(testImage).
+					StatusDeployedImage(testImage).
+					StatusConsumerGroup(testName).
 					StatusDeploymentRef("%s-processor-001", testName).
 					StatusScaledObjectRef("%s-processor-002", testName),
 			},
@@ -248,9 +370,11 @@ func TestProcessorReconciler(t *testing.T) {
 					StatusConditions(
 						deploymentConditionAvailable.True(),
 						deploymentConditionProgressing.True(),
-					),
+					).
+					StatusReplicas(2),
 				scaledObjectGiven.
-					ScaleTargetRefDeployment("%s-processor-000", testName),
+					ScaleTargetRefDeployment("%s-processor-000", testName).
+					StatusLastActiveTime(5),
 			},
 			ExpectTracks: []rtesting.TrackRequest{
 				rtesting.NewTrackRequest(processorImagesConfigMap, processor, scheme),
@@ -269,8 +393,12 @@ func TestProcessorReconciler(t *testing.T) {
 						processorConditionStreamsReady.True(),
 					).
 					StatusLatestImage(testImage).
+					StatusDeployedImage(testImage).
+					StatusConsumerGroup(testName).
 					StatusDeploymentRef("%s-processor-000", testName).
-					StatusScaledObjectRef("%s-processor-000", testName),
+					StatusScaledObjectRef("%s-processor-000", testName).
+					StatusReplicas(2).
+					StatusLastActiveTime(5),
 			},
 		}}
 
@@ -285,6 +413,7 @@ func TestProcessorReconciler(t *testing.T) {
 					Tracker:   tracker,
 				},
 				testSystemNamespace,
+				nil,
 			)
 		})
 	})
@@ -373,6 +502,76 @@ func TestProcessorReconciler(t *testing.T) {
 					rtesting.NewTrackRequest(testContainer, processor, scheme),
 				},
 			},
+			{
+				Name: "operation",
+				Parent: processor.
+					Operation(filterOperation),
+				ExpectParent: processor.
+					Operation(filterOperation).
+					StatusLatestImage(""),
+			},
+			{
+				Name: "chain",
+				Parent: processor.
+					Chain(chain...),
+				GivenObjects: []rtesting.Factory{
+					testFunction,
+					testContainer,
+				},
+				ExpectParent: processor.
+					Chain(chain...).
+					StatusLatestImage("").
+					StatusChain(
+						streamingv1alpha1.ChainedFunctionStatus{Name: "parse", LatestImage: testImage},
+						streamingv1alpha1.ChainedFunctionStatus{Name: "enrich", LatestImage: testImage},
+					),
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(testFunction, processor, scheme),
+					rtesting.NewTrackRequest(testContainer, processor, scheme),
+				},
+			},
+			{
+				Name: "chain, function not found",
+				Parent: processor.
+					Chain(chain...),
+				GivenObjects: []rtesting.Factory{
+					testContainer,
+				},
+				ExpectParent: processor.
+					Chain(chain...).
+					StatusLatestImage("").
+					StatusChain(
+						streamingv1alpha1.ChainedFunctionStatus{Name: "parse"},
+						streamingv1alpha1.ChainedFunctionStatus{Name: "enrich", LatestImage: testImage},
+					),
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(testFunction, processor, scheme),
+					rtesting.NewTrackRequest(testContainer, processor, scheme),
+				},
+			},
+			{
+				Name: "chain, lookup error",
+				Parent: processor.
+					Chain(chain...),
+				WithReactors: []rtesting.ReactionFunc{
+					rtesting.InduceFailure("get", "Function"),
+				},
+				ShouldErr: true,
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(testFunction, processor, scheme),
+				},
+			},
+			{
+				Name: "chain removed",
+				Parent: processor.
+					Image(testImage).
+					StatusChain(
+						streamingv1alpha1.ChainedFunctionStatus{Name: "parse", LatestImage: testImage},
+					),
+				ExpectParent: processor.
+					Image(testImage).
+					StatusLatestImage(testImage),
+			},
 			{
 				Name: "function build",
 				Parent: processor.
@@ -423,12 +622,240 @@ func TestProcessorReconciler(t *testing.T) {
 		})
 	})
 
+	t.Run("ProcessorRolloutReconciler", func(t *testing.T) {
+		newImage := fmt.Sprintf("%s@sha256:%s", testImagePrefix, "5d3b3f1b2e0a6c1a7b0e4f3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e")
+		paused := &streamingv1alpha1.ProcessorRollout{
+			Paused: true,
+		}
+		promoted := &streamingv1alpha1.ProcessorRollout{
+			Paused:  true,
+			Promote: "v2",
+		}
+
+		table := rtesting.SubTable{
+			{
+				Name: "roll out latest image",
+				Parent: processorMinimal.
+					Default().
+					StatusLatestImage(testImage),
+				ExpectParent: processorMinimal.
+					Default().
+					StatusLatestImage(testImage).
+					StatusDeployedImage(testImage),
+			},
+			{
+				Name: "roll out new image",
+				Parent: processor.
+					StatusLatestImage(newImage),
+				ExpectParent: processor.
+					StatusLatestImage(newImage).
+					StatusDeployedImage(newImage),
+			},
+			{
+				Name: "paused, pin deployed image",
+				Parent: processor.
+					Rollout(paused).
+					StatusLatestImage(newImage),
+				ExpectParent: processor.
+					Rollout(paused).
+					StatusLatestImage(newImage).
+					StatusDeployedImage(testImage),
+			},
+			{
+				Name: "paused, nothing deployed",
+				Parent: processorMinimal.
+					Default().
+					Rollout(paused).
+					StatusLatestImage(testImage),
+				ExpectParent: processorMinimal.
+					Default().
+					Rollout(paused).
+					StatusLatestImage(testImage).
+					StatusDeployedImage(testImage),
+			},
+			{
+				Name: "paused, promote",
+				Parent: processor.
+					Rollout(promoted).
+					StatusLatestImage(newImage),
+				ExpectParent: processor.
+					Rollout(promoted).
+					StatusLatestImage(newImage).
+					StatusDeployedImage(newImage).
+					StatusPromoted("v2"),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Promoted",
+						`Promoted image %q`, newImage),
+				},
+			},
+			{
+				Name: "paused, already promoted",
+				Parent: processor.
+					Rollout(promoted).
+					StatusLatestImage(newImage).
+					StatusPromoted("v2"),
+				ExpectParent: processor.
+					Rollout(promoted).
+					StatusLatestImage(newImage).
+					StatusPromoted("v2"),
+			},
+			{
+				Name: "resumed",
+				Parent: processor.
+					Rollout(&streamingv1alpha1.ProcessorRollout{Promote: "v3"}).
+					StatusLatestImage(newImage).
+					StatusPromoted("v2"),
+				ExpectParent: processor.
+					Rollout(&streamingv1alpha1.ProcessorRollout{Promote: "v3"}).
+					StatusLatestImage(newImage).
+					StatusDeployedImage(newImage).
+					StatusPromoted("v3"),
+			},
+			{
+				Name: "chain",
+				Parent: processor.
+					Chain(chain...).
+					StatusLatestImage(""),
+				ExpectParent: processor.
+					Chain(chain...).
+					StatusLatestImage("").
+					StatusDeployedImage(""),
+			},
+			{
+				Name: "operation",
+				Parent: processor.
+					Operation(filterOperation).
+					StatusLatestImage(""),
+				ExpectParent: processor.
+					Operation(filterOperation).
+					StatusLatestImage("").
+					StatusDeployedImage(""),
+			},
+		}
+
+		table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
+			return streaming.ProcessorRolloutReconciler(
+				controllers.Config{
+					Client:    client,
+					APIReader: client,
+					Recorder:  recorder,
+					Log:       log,
+					Scheme:    scheme,
+					Tracker:   tracker,
+				},
+			)
+		})
+	})
+
+	t.Run("ProcessorConsumerGroupReconciler", func(t *testing.T) {
+		table := rtesting.SubTable{
+			{
+				Name:   "default group",
+				Parent: processor,
+				ExpectParent: processor.
+					StatusConsumerGroup(testName),
+			},
+			{
+				Name: "replay",
+				Parent: processor.
+					ObjectMeta(func(om factories.ObjectMeta) {
+						om.Generation(2)
+					}).
+					Replay("first"),
+				ExpectParent: processor.
+					ObjectMeta(func(om factories.ObjectMeta) {
+						om.Generation(2)
+					}).
+					Replay("first").
+					StatusConsumerGroup("%s-2", testName).
+					StatusReplayHistory(
+						streamingv1alpha1.ProcessorReplay{Replay: "first", ConsumerGroup: fmt.Sprintf("%s-2", testName)},
+					),
+			},
+			{
+				Name: "replay again",
+				Parent: processor.
+					ObjectMeta(func(om factories.ObjectMeta) {
+						om.Generation(3)
+					}).
+					Replay("second").
+					StatusConsumerGroup("%s-2", testName).
+					StatusReplayHistory(
+						streamingv1alpha1.ProcessorReplay{Replay: "first", ConsumerGroup: fmt.Sprintf("%s-2", testName)},
+					),
+				ExpectParent: processor.
+					ObjectMeta(func(om factories.ObjectMeta) {
+						om.Generation(3)
+					}).
+					Replay("second").
+					StatusConsumerGroup("%s-3", testName).
+					StatusReplayHistory(
+						streamingv1alpha1.ProcessorReplay{Replay: "first", ConsumerGroup: fmt.Sprintf("%s-2", testName)},
+						streamingv1alpha1.ProcessorReplay{Replay: "second", ConsumerGroup: fmt.Sprintf("%s-3", testName)},
+					),
+			},
+			{
+				Name: "replay unchanged",
+				Parent: processor.
+					ObjectMeta(func(om factories.ObjectMeta) {
+						om.Generation(3)
+					}).
+					Replay("first").
+					StatusReplayHistory(
+						streamingv1alpha1.ProcessorReplay{Replay: "first", ConsumerGroup: fmt.Sprintf("%s-2", testName)},
+					),
+				ExpectParent: processor.
+					ObjectMeta(func(om factories.ObjectMeta) {
+						om.Generation(3)
+					}).
+					Replay("first").
+					StatusConsumerGroup("%s-2", testName).
+					StatusReplayHistory(
+						streamingv1alpha1.ProcessorReplay{Replay: "first", ConsumerGroup: fmt.Sprintf("%s-2", testName)},
+					),
+			},
+			{
+				Name: "replay removed",
+				Parent: processor.
+					ObjectMeta(func(om factories.ObjectMeta) {
+						om.Generation(3)
+					}).
+					StatusConsumerGroup("%s-2", testName).
+					StatusReplayHistory(
+						streamingv1alpha1.ProcessorReplay{Replay: "first", ConsumerGroup: fmt.Sprintf("%s-2", testName)},
+					),
+				ExpectParent: processor.
+					ObjectMeta(func(om factories.ObjectMeta) {
+						om.Generation(3)
+					}).
+					StatusConsumerGroup("%s-3", testName).
+					StatusReplayHistory(
+						streamingv1alpha1.ProcessorReplay{Replay: "first", ConsumerGroup: fmt.Sprintf("%s-2", testName)},
+						streamingv1alpha1.ProcessorReplay{Replay: "", ConsumerGroup: fmt.Sprintf("%s-3", testName)},
+					),
+			},
+		}
+
+		table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
+			return streaming.ProcessorConsumerGroupReconciler(
+				controllers.Config{
+					Client:    client,
+					APIReader: client,
+					Recorder:  recorder,
+					Log:       log,
+					Scheme:    scheme,
+					Tracker:   tracker,
+				},
+			)
+		})
+	})
+
 	t.Run("ProcessorResolveStreamsReconciler", func(t *testing.T) {
 		table := rtesting.SubTable{
 			{
 				Name: "skip",
 				Parent: processor.
-					StatusLatestImage(""),
+					StatusDeployedImage(""),
 				ExpectStashedValues: map[controllers.StashKey]interface{}{
 					streaming.InputStreamsStashKey:  nil,
 					streaming.OutputStreamsStashKey: nil,
@@ -558,6 +985,60 @@ func TestProcessorReconciler(t *testing.T) {
 					streaming.OutputStreamsStashKey: []streamingv1alpha1.Stream{},
 				},
 			},
+			{
+				Name: "resolve granted stream from another namespace",
+				Parent: processor.
+					Inputs(
+						streamingv1alpha1.InputStreamBinding{Stream: "shared-namespace/shared-stream", Alias: "alias-1", StartOffset: streamingv1alpha1.Earliest},
+					),
+				GivenObjects: []rtesting.Factory{
+					testStreamShared,
+					testStreamGrant,
+				},
+				ExpectParent: processor.
+					Inputs(
+						streamingv1alpha1.InputStreamBinding{Stream: "shared-namespace/shared-stream", Alias: "alias-1", StartOffset: streamingv1alpha1.Earliest},
+					).
+					StatusConditions(
+						processorConditionStreamsReady.True(),
+					),
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(testStreamShared, processor, scheme),
+				},
+				ExpectStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
+						*testStreamShared.Create(),
+					},
+					streaming.OutputStreamsStashKey: []streamingv1alpha1.Stream{},
+				},
+			},
+			{
+				Name: "stream not granted",
+				Parent: processor.
+					Outputs(
+						streamingv1alpha1.OutputStreamBinding{Stream: "shared-namespace/shared-stream", Alias: "alias-1"},
+					),
+				GivenObjects: []rtesting.Factory{
+					testStreamShared,
+					testStreamGrant.
+						Namespaces("other-namespace"),
+				},
+				ExpectParent: processor.
+					Outputs(
+						streamingv1alpha1.OutputStreamBinding{Stream: "shared-namespace/shared-stream", Alias: "alias-1"},
+					).
+					StatusConditions(
+						processorConditionReady.False().Reason("StreamNotReady", "stream shared-namespace/shared-stream is not granted to namespace test-namespace"),
+						processorConditionStreamsReady.False().Reason("StreamNotReady", "stream shared-namespace/shared-stream is not granted to namespace test-namespace"),
+					),
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(testStreamShared, processor, scheme),
+				},
+				ExpectStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:  []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey: nil,
+				},
+			},
 		}
 
 		table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
@@ -574,11 +1055,231 @@ func TestProcessorReconciler(t *testing.T) {
 		})
 	})
 
-	t.Run("ProcessorChildDeploymentReconciler", func(t *testing.T) {
+	t.Run("ProcessorChildBindingsReconciler", func(t *testing.T) {
+		sharedMetadata := factories.ConfigMap().
+			NamespaceName(testSharedNamespace, "shared-stream-binding-metadata").
+			AddData("topic", "shared-namespace_shared-stream")
+		sharedSecret := factories.Secret().
+			NamespaceName(testSharedNamespace, "shared-stream-binding-secret").
+			AddData("gateway", "shared-gateway:6565")
+		metadataCopy := factories.ConfigMap().
+			NamespaceName(testNamespace, "test-processor-stream-00000000-0000-0000-0000-000000000005-binding").
+			ObjectMeta(func(om factories.ObjectMeta) {
+				om.AddLabel(streamingv1alpha1.ProcessorLabelKey, testName)
+				om.ControlledBy(processor, scheme)
+			}).
+			AddData("topic", "shared-namespace_shared-stream")
+		secretCopy := factories.Secret().
+			NamespaceName(testNamespace, "test-processor-stream-00000000-0000-0000-0000-000000000005-binding").
+			ObjectMeta(func(om factories.ObjectMeta) {
+				om.AddLabel(streamingv1alpha1.ProcessorLabelKey, testName)
+				om.ControlledBy(processor, scheme)
+			}).
+			AddData("gateway", "shared-gateway:6565")
+
+		// namespace b-c with stream d, and namespace b with stream c-d
+		collidingStream1 := testStreamShared.
+			NamespaceName("b-c", "d").
+			ObjectMeta(func(om factories.ObjectMeta) {
+				om.UID("00000000-0000-0000-0000-000000000006")
+			}).
+			StatusBinding("", "d-binding-secret")
+		collidingStream2 := testStreamShared.
+			NamespaceName("b", "c-d").
+			ObjectMeta(func(om factories.ObjectMeta) {
+				om.UID("00000000-0000-0000-0000-000000000007")
+			}).
+			StatusBinding("", "c-d-binding-secret")
+		collidingSecret1 := factories.Secret().
+			NamespaceName("b-c", "d-binding-secret").
+			AddData("authToken", "token-1")
+		collidingSecret2 := factories.Secret().
+			NamespaceName("b", "c-d-binding-secret").
+			AddData("authToken", "token-2")
+		collidingSecretCopy1 := factories.Secret().
+			NamespaceName(testNamespace, "test-processor-stream-00000000-0000-0000-0000-000000000006-binding").
+			ObjectMeta(func(om factories.ObjectMeta) {
+				om.AddLabel(streamingv1alpha1.ProcessorLabelKey, testName)
+				om.ControlledBy(processor, scheme)
+			}).
+			AddData("authToken", "token-1")
+		collidingSecretCopy2 := factories.Secret().
+			NamespaceName(testNamespace, "test-processor-stream-00000000-0000-0000-0000-000000000007-binding").
+			ObjectMeta(func(om factories.ObjectMeta) {
+				om.AddLabel(streamingv1alpha1.ProcessorLabelKey, testName)
+				om.ControlledBy(processor, scheme)
+			}).
+			AddData("authToken", "token-2")
+
 		table := rtesting.SubTable{
 			{
-				Name:   "skip, missing latest image",
-				Parent: processorMinimal,
+				Name:   "skip, streams in the same namespace",
+				Parent: processor,
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:  []streamingv1alpha1.Stream{*testStream1.Create()},
+					streaming.OutputStreamsStashKey: []streamingv1alpha1.Stream{*testStream2.Create()},
+				},
+				ExpectParent: processor,
+			},
+			{
+				Name:   "copy binding",
+				Parent: processor,
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:  []streamingv1alpha1.Stream{*testStreamShared.Create()},
+					streaming.OutputStreamsStashKey: []streamingv1alpha1.Stream{},
+				},
+				GivenObjects: []rtesting.Factory{
+					sharedMetadata,
+					sharedSecret,
+				},
+				ExpectParent: processor,
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(sharedMetadata, processor, scheme),
+					rtesting.NewTrackRequest(sharedSecret, processor, scheme),
+				},
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Created",
+						`Created ConfigMap "%s"`, metadataCopy.Create().Name),
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Created",
+						`Created Secret "%s"`, secretCopy.Create().Name),
+				},
+				ExpectCreates: []rtesting.Factory{
+					metadataCopy,
+					secretCopy,
+				},
+			},
+			{
+				Name:   "copy bindings of streams with colliding names",
+				Parent: processor,
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
+						*collidingStream1.Create(),
+						*collidingStream2.Create(),
+					},
+					streaming.OutputStreamsStashKey: []streamingv1alpha1.Stream{},
+				},
+				GivenObjects: []rtesting.Factory{
+					collidingSecret1,
+					collidingSecret2,
+				},
+				ExpectParent: processor,
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(collidingSecret1, processor, scheme),
+					rtesting.NewTrackRequest(collidingSecret2, processor, scheme),
+				},
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Created",
+						`Created Secret "%s"`, collidingSecretCopy1.Create().Name),
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Created",
+						`Created Secret "%s"`, collidingSecretCopy2.Create().Name),
+				},
+				ExpectCreates: []rtesting.Factory{
+					collidingSecretCopy1,
+					collidingSecretCopy2,
+				},
+			},
+			{
+				Name:   "update binding copy",
+				Parent: processor,
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:  []streamingv1alpha1.Stream{*testStreamShared.Create()},
+					streaming.OutputStreamsStashKey: []streamingv1alpha1.Stream{},
+				},
+				GivenObjects: []rtesting.Factory{
+					sharedMetadata.
+						AddData("partitions", "3"),
+					sharedSecret,
+					metadataCopy,
+					secretCopy,
+				},
+				ExpectParent: processor,
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(sharedMetadata, processor, scheme),
+					rtesting.NewTrackRequest(sharedSecret, processor, scheme),
+				},
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Updated",
+						`Updated ConfigMap "%s"`, metadataCopy.Create().Name),
+				},
+				ExpectUpdates: []rtesting.Factory{
+					metadataCopy.
+						AddData("partitions", "3"),
+				},
+			},
+			{
+				Name:   "delete unwanted binding copy",
+				Parent: processor,
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:  []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey: []streamingv1alpha1.Stream{},
+				},
+				GivenObjects: []rtesting.Factory{
+					metadataCopy,
+					secretCopy,
+				},
+				ExpectParent: processor,
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Deleted",
+						`Deleted ConfigMap "%s"`, metadataCopy.Create().Name),
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Deleted",
+						`Deleted Secret "%s"`, secretCopy.Create().Name),
+				},
+				ExpectDeletes: []rtesting.DeleteRef{
+					{Group: "", Kind: "ConfigMap", Namespace: testNamespace, Name: metadataCopy.Create().Name},
+					{Group: "", Kind: "Secret", Namespace: testNamespace, Name: secretCopy.Create().Name},
+				},
+			},
+		}
+
+		table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
+			return streaming.ProcessorChildBindingsReconciler(
+				controllers.Config{
+					Client:    client,
+					APIReader: client,
+					Recorder:  recorder,
+					Log:       log,
+					Scheme:    scheme,
+					Tracker:   tracker,
+				},
+			)
+		})
+	})
+
+	t.Run("ProcessorChildDeploymentReconciler", func(t *testing.T) {
+		startTime := metav1.Date(2020, time.April, 1, 12, 0, 0, 0, time.UTC)
+		maxSurge := intstr.FromString("25%")
+		maxUnavailable := intstr.FromInt(0)
+
+		table := rtesting.SubTable{
+			{
+				Name: "delete deployment, stateful",
+				Parent: processor.
+					State(stateVolume),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
+				},
+				GivenObjects: []rtesting.Factory{
+					deploymentGiven,
+				},
+				ExpectParent: processorMinimal.
+					Default().
+					State(stateVolume).
+					StatusLatestImage(testImage).
+					StatusDeployedImage(testImage).
+					StatusScaledObjectRef("%s-processor-000", testName),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Deleted",
+						`Deleted Deployment "%s-processor-000"`, testName),
+				},
+				ExpectDeletes: []rtesting.DeleteRef{
+					{Group: "apps", Kind: "Deployment", Namespace: testNamespace, Name: fmt.Sprintf("%s-processor-000", testName)},
+				},
+			},
+			{
+				Name:   "skip, missing latest image",
+				Parent: processorMinimal,
 				GivenStashedValues: map[controllers.StashKey]interface{}{
 					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
 					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
@@ -588,7 +1289,7 @@ func TestProcessorReconciler(t *testing.T) {
 			{
 				Name: "skip, missing input streams",
 				Parent: processorMinimal.
-					StatusLatestImage(testImage),
+					StatusDeployedImage(testImage),
 				GivenStashedValues: map[controllers.StashKey]interface{}{
 					streaming.InputStreamsStashKey:    nil,
 					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
@@ -598,7 +1299,7 @@ func TestProcessorReconciler(t *testing.T) {
 			{
 				Name: "skip, missing output streams",
 				Parent: processorMinimal.
-					StatusLatestImage(testImage),
+					StatusDeployedImage(testImage),
 				GivenStashedValues: map[controllers.StashKey]interface{}{
 					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
 					streaming.OutputStreamsStashKey:   nil,
@@ -608,7 +1309,7 @@ func TestProcessorReconciler(t *testing.T) {
 			{
 				Name: "skip, missing processor images map",
 				Parent: processorMinimal.
-					StatusLatestImage(testImage),
+					StatusDeployedImage(testImage),
 				GivenStashedValues: map[controllers.StashKey]interface{}{
 					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
 					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
@@ -618,7 +1319,7 @@ func TestProcessorReconciler(t *testing.T) {
 			{
 				Name: "skip, missing processor images map, missing processor image",
 				Parent: processorMinimal.
-					StatusLatestImage(testImage),
+					StatusDeployedImage(testImage),
 				GivenStashedValues: map[controllers.StashKey]interface{}{
 					streaming.InputStreamsStashKey:  []streamingv1alpha1.Stream{},
 					streaming.OutputStreamsStashKey: []streamingv1alpha1.Stream{},
@@ -795,42 +1496,90 @@ func TestProcessorReconciler(t *testing.T) {
 						}),
 				},
 			}, {
-				Name: "update deployment",
+				Name: "create deployment, rollout strategy",
 				Parent: processor.
+					Rollout(&streamingv1alpha1.ProcessorRollout{
+						MaxSurge:       &maxSurge,
+						MaxUnavailable: &maxUnavailable,
+					}),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
+				},
+				ExpectParent: processor.
+					Rollout(&streamingv1alpha1.ProcessorRollout{
+						MaxSurge:       &maxSurge,
+						MaxUnavailable: &maxUnavailable,
+					}).
+					StatusDeploymentRef("%s-processor-001", testName),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Created",
+						`Created Deployment "%s-processor-001"`, testName),
+				},
+				ExpectCreates: []rtesting.Factory{
+					deploymentCreate.
+						Strategy(appsv1.DeploymentStrategy{
+							Type: appsv1.RollingUpdateDeploymentStrategyType,
+							RollingUpdate: &appsv1.RollingUpdateDeployment{
+								MaxSurge:       &maxSurge,
+								MaxUnavailable: &maxUnavailable,
+							},
+						}),
+				},
+			}, {
+				Name: "create deployment, operation",
+				Parent: processorMinimal.
+					Default().
+					Operation(filterOperation).
 					Inputs(
 						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					).
+					Outputs(
+						testStream3.CreateOutputStreamBinding("alias-out-3"),
 					),
 				GivenStashedValues: map[controllers.StashKey]interface{}{
 					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
 						*testStream1.Create(),
 					},
-					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey: []streamingv1alpha1.Stream{
+						*testStream3.Create(),
+					},
 					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
 				},
-				GivenObjects: []rtesting.Factory{
-					deploymentGiven,
-				},
-				ExpectParent: processor.
+				ExpectParent: processorMinimal.
+					Default().
+					Operation(filterOperation).
 					Inputs(
 						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
 					).
-					Outputs().
-					StatusDeploymentRef("%s-processor-000", testName),
+					Outputs(
+						testStream3.CreateOutputStreamBinding("alias-out-3"),
+					).
+					StatusDeploymentRef("%s-processor-001", testName),
 				ExpectEvents: []rtesting.Event{
-					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Updated",
-						`Updated Deployment "%s-processor-000"`, testName),
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Created",
+						`Created Deployment "%s-processor-001"`, testName),
 				},
-				ExpectUpdates: []rtesting.Factory{
-					deploymentGiven.
+				ExpectCreates: []rtesting.Factory{
+					factories.Deployment().
+						ObjectMeta(func(om factories.ObjectMeta) {
+							om.Namespace(testNamespace)
+							om.GenerateName("%s-processor-", testName)
+							om.AddLabel(streamingv1alpha1.ProcessorLabelKey, testName)
+							om.ControlledBy(processor, scheme)
+						}).
+						AddSelectorLabel(streamingv1alpha1.ProcessorLabelKey, testName).
 						PodTemplateSpec(func(pts factories.PodTemplateSpec) {
 							pts.ContainerNamed("processor", func(c *corev1.Container) {
+								c.Image = testProcessorImage
 								c.Env = []corev1.EnvVar{
 									{Name: "CNB_BINDINGS", Value: "/var/riff/bindings"},
 									{Name: "INPUT_START_OFFSETS", Value: "earliest"},
 									{Name: "INPUT_NAMES", Value: "alias-in-1"},
-									{Name: "OUTPUT_NAMES", Value: ""},
+									{Name: "OUTPUT_NAMES", Value: "alias-out-3"},
 									{Name: "GROUP", Value: "test-processor"},
-									{Name: "FUNCTION", Value: "localhost:8081"},
+									{Name: "OPERATION", Value: `{"filter":{"expression":"contentType == \"application/json\""}}`},
 								}
 								c.VolumeMounts = []corev1.VolumeMount{
 									{
@@ -843,6 +1592,16 @@ func TestProcessorReconciler(t *testing.T) {
 										ReadOnly:  true,
 										MountPath: "/var/riff/bindings/input_000/secret",
 									},
+									{
+										Name:      "stream-00000000-0000-0000-0000-000000000003-metadata",
+										ReadOnly:  true,
+										MountPath: "/var/riff/bindings/output_000/metadata",
+									},
+									{
+										Name:      "stream-00000000-0000-0000-0000-000000000003-secret",
+										ReadOnly:  true,
+										MountPath: "/var/riff/bindings/output_000/secret",
+									},
 								}
 							})
 							pts.Volumes(
@@ -864,92 +1623,1021 @@ func TestProcessorReconciler(t *testing.T) {
 										},
 									},
 								},
+								corev1.Volume{
+									Name: "stream-00000000-0000-0000-0000-000000000003-metadata",
+									VolumeSource: corev1.VolumeSource{
+										ConfigMap: &corev1.ConfigMapVolumeSource{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: "stream-3-binding-metadata",
+											},
+										},
+									},
+								},
+								corev1.Volume{
+									Name: "stream-00000000-0000-0000-0000-000000000003-secret",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											SecretName: "stream-3-binding-secret",
+										},
+									},
+								},
 							)
-						}),
-				},
-			},
-		}
-
-		table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
-			return streaming.ProcessorChildDeploymentReconciler(
-				controllers.Config{
-					Client:    client,
-					APIReader: client,
-					Recorder:  recorder,
-					Log:       log,
-					Scheme:    scheme,
-					Tracker:   tracker,
+						}).
+						Replicas(1),
 				},
-			)
-		})
-	})
-
-	t.Run("ProcessorChildScaledObjectReconciler", func(t *testing.T) {
-		table := rtesting.SubTable{
-			{
-				Name: "skip, missing deployment",
+			}, {
+				Name: "skip, chain missing image",
 				Parent: processorMinimal.
-					Inputs(
-						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
-						testStream1.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Latest),
+					Default().
+					Chain(chain...).
+					StatusChain(
+						streamingv1alpha1.ChainedFunctionStatus{Name: "parse", LatestImage: testImage},
+						streamingv1alpha1.ChainedFunctionStatus{Name: "enrich"},
 					),
 				GivenStashedValues: map[controllers.StashKey]interface{}{
-					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
-						*testStream1.Create(),
-						*testStream2.Create(),
-					},
+					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
 				},
-			},
-			{
-				Name: "skip, missing inputs",
+				ExpectParent: processorMinimal.
+					Default().
+					Chain(chain...).
+					StatusChain(
+						streamingv1alpha1.ChainedFunctionStatus{Name: "parse", LatestImage: testImage},
+						streamingv1alpha1.ChainedFunctionStatus{Name: "enrich"},
+					),
+			}, {
+				Name: "create deployment, chain",
 				Parent: processorMinimal.
-					Inputs(
-						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
-						testStream1.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Latest),
-					).
-					StatusDeploymentRef("%s-processor-000", testName),
-				GivenStashedValues: map[controllers.StashKey]interface{}{
-					streaming.InputStreamsStashKey: nil,
-				},
-			},
-			{
-				Name: "create scaled object",
-				Parent: processor.
-					Inputs(
-						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
-						testStream1.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Latest),
+					Default().
+					Chain(chain...).
+					StatusChain(
+						streamingv1alpha1.ChainedFunctionStatus{Name: "parse", LatestImage: testImage},
+						streamingv1alpha1.ChainedFunctionStatus{Name: "enrich", LatestImage: testImage},
 					),
 				GivenStashedValues: map[controllers.StashKey]interface{}{
-					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
-						*testStream1.Create(),
-						*testStream2.Create(),
-					},
-				},
-				GivenObjects: []rtesting.Factory{
-					factories.Secret().
-						NamespaceName(testNamespace, "stream-1-binding-secret").
-						AddData("gateway", "stream-1-gateway.local:6565").
-						AddData("topic", fmt.Sprintf("%s/%s", testNamespace, "stream-1")),
-					factories.Secret().
-						NamespaceName(testNamespace, "stream-2-binding-secret").
-						AddData("gateway", "stream-2-gateway.local:6565").
-						AddData("topic", fmt.Sprintf("%s/%s", testNamespace, "stream-2")),
+					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
 				},
-				ExpectParent: processor.
-					Inputs(
-						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
-						testStream1.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Latest),
+				ExpectParent: processorMinimal.
+					Default().
+					Chain(chain...).
+					StatusChain(
+						streamingv1alpha1.ChainedFunctionStatus{Name: "parse", LatestImage: testImage},
+						streamingv1alpha1.ChainedFunctionStatus{Name: "enrich", LatestImage: testImage},
 					).
-					StatusScaledObjectRef("%s-processor-001", testName).
-					StatusConditions(
-						processorConditionScaledObjectReady.True(),
-					),
+					StatusDeploymentRef("%s-processor-001", testName),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Created",
+						`Created Deployment "%s-processor-001"`, testName),
+				},
+				ExpectCreates: []rtesting.Factory{
+					factories.Deployment().
+						ObjectMeta(func(om factories.ObjectMeta) {
+							om.Namespace(testNamespace)
+							om.GenerateName("%s-processor-", testName)
+							om.AddLabel(streamingv1alpha1.ProcessorLabelKey, testName)
+							om.ControlledBy(processor, scheme)
+						}).
+						AddSelectorLabel(streamingv1alpha1.ProcessorLabelKey, testName).
+						PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+							pts.ContainerNamed("parse", func(c *corev1.Container) {
+								c.Image = testImage
+								c.Env = []corev1.EnvVar{
+									{Name: "GRPC_PORT", Value: "8081"},
+									{Name: "PORT", Value: "8081"},
+								}
+								c.Ports = []corev1.ContainerPort{
+									{ContainerPort: 8081},
+								}
+							})
+							pts.ContainerNamed("enrich", func(c *corev1.Container) {
+								c.Image = testImage
+								c.Env = []corev1.EnvVar{
+									{Name: "GRPC_PORT", Value: "8082"},
+									{Name: "PORT", Value: "8082"},
+								}
+								c.Ports = []corev1.ContainerPort{
+									{ContainerPort: 8082},
+								}
+							})
+							pts.ContainerNamed("processor", func(c *corev1.Container) {
+								c.Image = testProcessorImage
+								c.Env = []corev1.EnvVar{
+									{Name: "CNB_BINDINGS", Value: "/var/riff/bindings"},
+									{Name: "INPUT_START_OFFSETS", Value: ""},
+									{Name: "INPUT_NAMES", Value: ""},
+									{Name: "OUTPUT_NAMES", Value: ""},
+									{Name: "GROUP", Value: testName},
+									{Name: "FUNCTIONS", Value: "localhost:8081,localhost:8082"},
+								}
+							})
+						}).
+						Replicas(1),
+				},
+			}, {
+				Name: "create deployment, chain keeps template ports",
+				Parent: processorMinimal.
+					Default().
+					Chain(chain...).
+					PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+						pts.ContainerNamed("function", func(c *corev1.Container) {
+							c.Ports = []corev1.ContainerPort{
+								{Name: "metrics", ContainerPort: 9090},
+							}
+						})
+					}).
+					StatusChain(
+						streamingv1alpha1.ChainedFunctionStatus{Name: "parse", LatestImage: testImage},
+						streamingv1alpha1.ChainedFunctionStatus{Name: "enrich", LatestImage: testImage},
+					),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
+				},
+				ExpectParent: processorMinimal.
+					Default().
+					Chain(chain...).
+					PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+						pts.ContainerNamed("function", func(c *corev1.Container) {
+							c.Ports = []corev1.ContainerPort{
+								{Name: "metrics", ContainerPort: 9090},
+							}
+						})
+					}).
+					StatusChain(
+						streamingv1alpha1.ChainedFunctionStatus{Name: "parse", LatestImage: testImage},
+						streamingv1alpha1.ChainedFunctionStatus{Name: "enrich", LatestImage: testImage},
+					).
+					StatusDeploymentRef("%s-processor-001", testName),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Created",
+						`Created Deployment "%s-processor-001"`, testName),
+				},
+				ExpectCreates: []rtesting.Factory{
+					factories.Deployment().
+						ObjectMeta(func(om factories.ObjectMeta) {
+							om.Namespace(testNamespace)
+							om.GenerateName("%s-processor-", testName)
+							om.AddLabel(streamingv1alpha1.ProcessorLabelKey, testName)
+							om.ControlledBy(processor, scheme)
+						}).
+						AddSelectorLabel(streamingv1alpha1.ProcessorLabelKey, testName).
+						PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+							pts.ContainerNamed("parse", func(c *corev1.Container) {
+								c.Image = testImage
+								c.Env = []corev1.EnvVar{
+									{Name: "GRPC_PORT", Value: "8081"},
+									{Name: "PORT", Value: "8081"},
+								}
+								c.Ports = []corev1.ContainerPort{
+									{ContainerPort: 8081},
+									{Name: "metrics", ContainerPort: 9090},
+								}
+							})
+							pts.ContainerNamed("enrich", func(c *corev1.Container) {
+								c.Image = testImage
+								c.Env = []corev1.EnvVar{
+									{Name: "GRPC_PORT", Value: "8082"},
+									{Name: "PORT", Value: "8082"},
+								}
+								c.Ports = []corev1.ContainerPort{
+									{ContainerPort: 8082},
+									{Name: "metrics", ContainerPort: 9090},
+								}
+							})
+							pts.ContainerNamed("processor", func(c *corev1.Container) {
+								c.Image = testProcessorImage
+								c.Env = []corev1.EnvVar{
+									{Name: "CNB_BINDINGS", Value: "/var/riff/bindings"},
+									{Name: "INPUT_START_OFFSETS", Value: ""},
+									{Name: "INPUT_NAMES", Value: ""},
+									{Name: "OUTPUT_NAMES", Value: ""},
+									{Name: "GROUP", Value: testName},
+									{Name: "FUNCTIONS", Value: "localhost:8081,localhost:8082"},
+								}
+							})
+						}).
+						Replicas(1),
+				},
+			}, {
+				Name: "create deployment, template fields",
+				Parent: processorMinimal.
+					Default().
+					PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+						pts.ContainerNamed("function", func(c *corev1.Container) {
+							c.Env = []corev1.EnvVar{
+								{Name: "MY_VAR", Value: "my-value"},
+							}
+							c.Resources = resources
+							c.Command = []string{"/cnb/lifecycle/launcher"}
+							c.Args = []string{"--verbose"}
+							c.Ports = []corev1.ContainerPort{
+								{Name: "metrics", ContainerPort: 9090},
+							}
+							c.ReadinessProbe = readinessProbe
+						})
+						pts.NodeSelector(map[string]string{"disktype": "ssd"})
+						pts.Tolerations(toleration)
+					}).
+					StatusLatestImage(testImage).
+					StatusDeployedImage(testImage),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
+				},
+				ExpectParent: processorMinimal.
+					Default().
+					PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+						pts.ContainerNamed("function", func(c *corev1.Container) {
+							c.Env = []corev1.EnvVar{
+								{Name: "MY_VAR", Value: "my-value"},
+							}
+							c.Resources = resources
+							c.Command = []string{"/cnb/lifecycle/launcher"}
+							c.Args = []string{"--verbose"}
+							c.Ports = []corev1.ContainerPort{
+								{Name: "metrics", ContainerPort: 9090},
+							}
+							c.ReadinessProbe = readinessProbe
+						})
+						pts.NodeSelector(map[string]string{"disktype": "ssd"})
+						pts.Tolerations(toleration)
+					}).
+					StatusLatestImage(testImage).
+					StatusDeployedImage(testImage).
+					StatusDeploymentRef("%s-processor-001", testName),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Created",
+						`Created Deployment "%s-processor-001"`, testName),
+				},
+				ExpectCreates: []rtesting.Factory{
+					deploymentCreate.
+						PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+							pts.ContainerNamed("function", func(c *corev1.Container) {
+								c.Env = []corev1.EnvVar{
+									{Name: "MY_VAR", Value: "my-value"},
+								}
+								c.Resources = resources
+								c.Command = []string{"/cnb/lifecycle/launcher"}
+								c.Args = []string{"--verbose"}
+								c.Ports = []corev1.ContainerPort{
+									{ContainerPort: 8081},
+									{Name: "metrics", ContainerPort: 9090},
+								}
+								c.ReadinessProbe = readinessProbe
+							})
+							pts.NodeSelector(map[string]string{"disktype": "ssd"})
+							pts.Tolerations(toleration)
+						}),
+				},
+			}, {
+				Name: "update deployment",
+				Parent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
+						*testStream1.Create(),
+					},
+					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
+				},
+				GivenObjects: []rtesting.Factory{
+					deploymentGiven,
+				},
+				ExpectParent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					).
+					Outputs().
+					StatusDeploymentRef("%s-processor-000", testName),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Updated",
+						`Updated Deployment "%s-processor-000"`, testName),
+				},
+				ExpectUpdates: []rtesting.Factory{
+					deploymentGiven.
+						PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+							pts.ContainerNamed("processor", func(c *corev1.Container) {
+								c.Env = []corev1.EnvVar{
+									{Name: "CNB_BINDINGS", Value: "/var/riff/bindings"},
+									{Name: "INPUT_START_OFFSETS", Value: "earliest"},
+									{Name: "INPUT_NAMES", Value: "alias-in-1"},
+									{Name: "OUTPUT_NAMES", Value: ""},
+									{Name: "GROUP", Value: "test-processor"},
+									{Name: "FUNCTION", Value: "localhost:8081"},
+								}
+								c.VolumeMounts = []corev1.VolumeMount{
+									{
+										Name:      "stream-00000000-0000-0000-0000-000000000001-metadata",
+										ReadOnly:  true,
+										MountPath: "/var/riff/bindings/input_000/metadata",
+									},
+									{
+										Name:      "stream-00000000-0000-0000-0000-000000000001-secret",
+										ReadOnly:  true,
+										MountPath: "/var/riff/bindings/input_000/secret",
+									},
+								}
+							})
+							pts.Volumes(
+								corev1.Volume{
+									Name: "stream-00000000-0000-0000-0000-000000000001-metadata",
+									VolumeSource: corev1.VolumeSource{
+										ConfigMap: &corev1.ConfigMapVolumeSource{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: "stream-1-binding-metadata",
+											},
+										},
+									},
+								},
+								corev1.Volume{
+									Name: "stream-00000000-0000-0000-0000-000000000001-secret",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											SecretName: "stream-1-binding-secret",
+										},
+									},
+								},
+							)
+						}),
+				},
+			},
+			{
+				Name: "update deployment, start positions",
+				Parent: processor.
+					Inputs(
+						streamingv1alpha1.InputStreamBinding{
+							Stream:    "stream-1",
+							Alias:     "alias-in-1",
+							StartTime: &startTime,
+						},
+						streamingv1alpha1.InputStreamBinding{
+							Stream: "stream-1",
+							Alias:  "alias-in-2",
+							PartitionOffsets: []streamingv1alpha1.PartitionOffset{
+								{Partition: 1, Offset: 17},
+								{Partition: 0, Offset: 42},
+							},
+						},
+					),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
+						*testStream1.Create(),
+						*testStream1.Create(),
+					},
+					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
+				},
+				GivenObjects: []rtesting.Factory{
+					deploymentGiven,
+				},
+				ExpectParent: processor.
+					Inputs(
+						streamingv1alpha1.InputStreamBinding{
+							Stream:    "stream-1",
+							Alias:     "alias-in-1",
+							StartTime: &startTime,
+						},
+						streamingv1alpha1.InputStreamBinding{
+							Stream: "stream-1",
+							Alias:  "alias-in-2",
+							PartitionOffsets: []streamingv1alpha1.PartitionOffset{
+								{Partition: 1, Offset: 17},
+								{Partition: 0, Offset: 42},
+							},
+						},
+					).
+					Outputs().
+					StatusDeploymentRef("%s-processor-000", testName),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Updated",
+						`Updated Deployment "%s-processor-000"`, testName),
+				},
+				ExpectUpdates: []rtesting.Factory{
+					deploymentGiven.
+						PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+							pts.ContainerNamed("processor", func(c *corev1.Container) {
+								c.Env = []corev1.EnvVar{
+									{Name: "CNB_BINDINGS", Value: "/var/riff/bindings"},
+									{Name: "INPUT_START_OFFSETS", Value: "2020-04-01T12:00:00Z,0=42;1=17"},
+									{Name: "INPUT_NAMES", Value: "alias-in-1,alias-in-2"},
+									{Name: "OUTPUT_NAMES", Value: ""},
+									{Name: "GROUP", Value: "test-processor"},
+									{Name: "FUNCTION", Value: "localhost:8081"},
+								}
+								c.VolumeMounts = []corev1.VolumeMount{
+									{
+										Name:      "stream-00000000-0000-0000-0000-000000000001-metadata",
+										ReadOnly:  true,
+										MountPath: "/var/riff/bindings/input_000/metadata",
+									},
+									{
+										Name:      "stream-00000000-0000-0000-0000-000000000001-secret",
+										ReadOnly:  true,
+										MountPath: "/var/riff/bindings/input_000/secret",
+									},
+									{
+										Name:      "stream-00000000-0000-0000-0000-000000000001-metadata",
+										ReadOnly:  true,
+										MountPath: "/var/riff/bindings/input_001/metadata",
+									},
+									{
+										Name:      "stream-00000000-0000-0000-0000-000000000001-secret",
+										ReadOnly:  true,
+										MountPath: "/var/riff/bindings/input_001/secret",
+									},
+								}
+							})
+							pts.Volumes(
+								corev1.Volume{
+									Name: "stream-00000000-0000-0000-0000-000000000001-metadata",
+									VolumeSource: corev1.VolumeSource{
+										ConfigMap: &corev1.ConfigMapVolumeSource{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: "stream-1-binding-metadata",
+											},
+										},
+									},
+								},
+								corev1.Volume{
+									Name: "stream-00000000-0000-0000-0000-000000000001-secret",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											SecretName: "stream-1-binding-secret",
+										},
+									},
+								},
+							)
+						}),
+				},
+			},
+		}
+
+		table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
+			return streaming.ProcessorChildDeploymentReconciler(
+				controllers.Config{
+					Client:    client,
+					APIReader: client,
+					Recorder:  recorder,
+					Log:       log,
+					Scheme:    scheme,
+					Tracker:   tracker,
+				},
+			)
+		})
+	})
+
+	t.Run("ProcessorChildChangelogStreamReconciler", func(t *testing.T) {
+		table := rtesting.SubTable{
+			{
+				Name:   "skip, stateless",
+				Parent: processor,
+			},
+			{
+				Name:   "skip, volume state",
+				Parent: processor.State(stateVolume),
+			},
+			{
+				Name: "create changelog stream",
+				Parent: processor.
+					State(stateChangelog),
+				ExpectParent: processor.
+					State(stateChangelog).
+					StatusChangelogStreamRef("%s-changelog", testName).
+					StatusConditions(
+						processorConditionReady.False().Reason("StreamNotReady", fmt.Sprintf("changelog stream %s-changelog is not ready: stream has no ready condition", testName)),
+						processorConditionStreamsReady.False().Reason("StreamNotReady", fmt.Sprintf("changelog stream %s-changelog is not ready: stream has no ready condition", testName)),
+					),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Created",
+						`Created Stream "%s-changelog"`, testName),
+				},
+				ExpectCreates: []rtesting.Factory{
+					changelogStreamCreate,
+				},
+			},
+			{
+				Name: "changelog stream ready",
+				Parent: processor.
+					State(stateChangelog).
+					StatusConditions(
+						processorConditionStreamsReady.True(),
+					),
+				GivenObjects: []rtesting.Factory{
+					changelogStreamGiven.
+						StatusReady(),
+				},
+				ExpectParent: processor.
+					State(stateChangelog).
+					StatusChangelogStreamRef("%s-changelog", testName).
+					StatusConditions(
+						processorConditionStreamsReady.True(),
+					),
+			},
+			{
+				Name: "delete changelog stream, stateless",
+				Parent: processor.
+					StatusChangelogStreamRef("%s-changelog", testName),
+				GivenObjects: []rtesting.Factory{
+					changelogStreamGiven,
+				},
+				ExpectParent: processor,
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Deleted",
+						`Deleted Stream "%s-changelog"`, testName),
+				},
+				ExpectDeletes: []rtesting.DeleteRef{
+					{Group: "streaming.projectriff.io", Kind: "Stream", Namespace: testNamespace, Name: fmt.Sprintf("%s-changelog", testName)},
+				},
+			},
+		}
+
+		table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
+			return streaming.ProcessorChildChangelogStreamReconciler(
+				controllers.Config{
+					Client:    client,
+					APIReader: client,
+					Recorder:  recorder,
+					Log:       log,
+					Scheme:    scheme,
+					Tracker:   tracker,
+				},
+			)
+		})
+	})
+
+	t.Run("ProcessorChildStatefulSetReconciler", func(t *testing.T) {
+		table := rtesting.SubTable{
+			{
+				Name: "skip, stateless",
+				Parent: processor.
+					StatusLatestImage(testImage),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
+				},
+			},
+			{
+				Name: "create statefulset, volume state",
+				Parent: processor.
+					State(stateVolume).
+					Window(&streamingv1alpha1.Window{
+						Type: streamingv1alpha1.SlidingWindow,
+						Size: streamingv1alpha1.WindowExtent{
+							Duration: &metav1.Duration{Duration: time.Minute},
+						},
+						Slide: &streamingv1alpha1.WindowExtent{
+							Duration: &metav1.Duration{Duration: 10 * time.Second},
+						},
+					}).
+					Scale(streamingv1alpha1.Scale{
+						Min: rtesting.Int32Ptr(3),
+					}),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
+				},
+				ExpectParent: processor.
+					State(stateVolume).
+					Window(&streamingv1alpha1.Window{
+						Type: streamingv1alpha1.SlidingWindow,
+						Size: streamingv1alpha1.WindowExtent{
+							Duration: &metav1.Duration{Duration: time.Minute},
+						},
+						Slide: &streamingv1alpha1.WindowExtent{
+							Duration: &metav1.Duration{Duration: 10 * time.Second},
+						},
+					}).
+					Scale(streamingv1alpha1.Scale{
+						Min: rtesting.Int32Ptr(3),
+					}).
+					StatusStatefulSetRef("%s-processor-001", testName),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Created",
+						`Created StatefulSet "%s-processor-001"`, testName),
+				},
+				ExpectCreates: []rtesting.Factory{
+					statefulSetCreate.
+						Replicas(3).
+						PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+							pts.ContainerNamed("processor", func(c *corev1.Container) {
+								c.Env = append(c.Env,
+									corev1.EnvVar{Name: "WINDOW_TYPE", Value: "sliding"},
+									corev1.EnvVar{Name: "WINDOW_SIZE", Value: "1m0s"},
+									corev1.EnvVar{Name: "WINDOW_SLIDE", Value: "10s"},
+									corev1.EnvVar{Name: "STATE_STORE", Value: "volume"},
+									corev1.EnvVar{Name: "STATE_DIR", Value: "/var/riff/state"},
+								)
+								c.VolumeMounts = []corev1.VolumeMount{
+									{Name: "state", MountPath: "/var/riff/state"},
+								}
+							})
+						}).
+						VolumeClaimTemplates(corev1.PersistentVolumeClaim{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "state",
+							},
+							Spec: corev1.PersistentVolumeClaimSpec{
+								AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{
+										corev1.ResourceStorage: resource.MustParse("1Gi"),
+									},
+								},
+							},
+						}),
+				},
+			},
+			{
+				Name: "skip, changelog stream not bound",
+				Parent: processor.
+					State(stateChangelog).
+					StatusChangelogStreamRef("%s-changelog", testName),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
+				},
+				GivenObjects: []rtesting.Factory{
+					changelogStreamGiven,
+				},
+			},
+			{
+				Name: "update statefulset, changelog state",
+				Parent: processor.
+					State(stateChangelog).
+					StatusChangelogStreamRef("%s-changelog", testName),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
+				},
+				GivenObjects: []rtesting.Factory{
+					changelogStreamGiven.
+						StatusBinding("changelog-binding-metadata", "changelog-binding-secret"),
+					statefulSetGiven.
+						StatusObservedGeneration(1).
+						StatusReplicas(1, 0),
+				},
+				ExpectParent: processor.
+					State(stateChangelog).
+					StatusChangelogStreamRef("%s-changelog", testName).
+					StatusStatefulSetRef("%s-processor-000", testName).
+					StatusReplicas(1).
+					StatusConditions(
+						processorConditionDeploymentReady.Unknown().Reason("StatefulSetProgressing", "0 of 1 replicas ready"),
+						processorConditionReady.Unknown().Reason("StatefulSetProgressing", "0 of 1 replicas ready"),
+					),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Updated",
+						`Updated StatefulSet "%s-processor-000"`, testName),
+				},
+				ExpectUpdates: []rtesting.Factory{
+					statefulSetGiven.
+						StatusObservedGeneration(1).
+						StatusReplicas(1, 0).
+						PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+							pts.ContainerNamed("processor", func(c *corev1.Container) {
+								c.Env = append(c.Env,
+									corev1.EnvVar{Name: "STATE_STORE", Value: "changelog"},
+								)
+								c.VolumeMounts = []corev1.VolumeMount{
+									{
+										Name:      "stream-00000000-0000-0000-0000-000000000005-metadata",
+										ReadOnly:  true,
+										MountPath: "/var/riff/bindings/state/metadata",
+									},
+									{
+										Name:      "stream-00000000-0000-0000-0000-000000000005-secret",
+										ReadOnly:  true,
+										MountPath: "/var/riff/bindings/state/secret",
+									},
+								}
+							})
+							pts.Volumes(
+								corev1.Volume{
+									Name: "stream-00000000-0000-0000-0000-000000000005-metadata",
+									VolumeSource: corev1.VolumeSource{
+										ConfigMap: &corev1.ConfigMapVolumeSource{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: "changelog-binding-metadata",
+											},
+										},
+									},
+								},
+								corev1.Volume{
+									Name: "stream-00000000-0000-0000-0000-000000000005-secret",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											SecretName: "changelog-binding-secret",
+										},
+									},
+								},
+							)
+						}),
+				},
+			},
+			{
+				Name: "update statefulset, keeps immutable service name",
+				Parent: processor.
+					State(stateChangelog).
+					StatusChangelogStreamRef("%s-changelog", testName),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey:    []streamingv1alpha1.Stream{},
+					streaming.OutputStreamsStashKey:   []streamingv1alpha1.Stream{},
+					streaming.ProcessorImagesStashKey: processorImagesConfigMap.Create().Data,
+				},
+				GivenObjects: []rtesting.Factory{
+					changelogStreamGiven.
+						StatusBinding("changelog-binding-metadata", "changelog-binding-secret"),
+					statefulSetGiven.
+						ServiceName("").
+						StatusObservedGeneration(1).
+						StatusReplicas(1, 0),
+				},
+				ExpectParent: processor.
+					State(stateChangelog).
+					StatusChangelogStreamRef("%s-changelog", testName).
+					StatusStatefulSetRef("%s-processor-000", testName).
+					StatusReplicas(1).
+					StatusConditions(
+						processorConditionDeploymentReady.Unknown().Reason("StatefulSetProgressing", "0 of 1 replicas ready"),
+						processorConditionReady.Unknown().Reason("StatefulSetProgressing", "0 of 1 replicas ready"),
+					),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Updated",
+						`Updated StatefulSet "%s-processor-000"`, testName),
+				},
+				ExpectUpdates: []rtesting.Factory{
+					statefulSetGiven.
+						ServiceName("").
+						StatusObservedGeneration(1).
+						StatusReplicas(1, 0).
+						PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+							pts.ContainerNamed("processor", func(c *corev1.Container) {
+								c.Env = append(c.Env,
+									corev1.EnvVar{Name: "STATE_STORE", Value: "changelog"},
+								)
+								c.VolumeMounts = []corev1.VolumeMount{
+									{
+										Name:      "stream-00000000-0000-0000-0000-000000000005-metadata",
+										ReadOnly:  true,
+										MountPath: "/var/riff/bindings/state/metadata",
+									},
+									{
+										Name:      "stream-00000000-0000-0000-0000-000000000005-secret",
+										ReadOnly:  true,
+										MountPath: "/var/riff/bindings/state/secret",
+									},
+								}
+							})
+							pts.Volumes(
+								corev1.Volume{
+									Name: "stream-00000000-0000-0000-0000-000000000005-metadata",
+									VolumeSource: corev1.VolumeSource{
+										ConfigMap: &corev1.ConfigMapVolumeSource{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: "changelog-binding-metadata",
+											},
+										},
+									},
+								},
+								corev1.Volume{
+									Name: "stream-00000000-0000-0000-0000-000000000005-secret",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											SecretName: "changelog-binding-secret",
+										},
+									},
+								},
+							)
+						}),
+				},
+			},
+		}
+
+		table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
+			return streaming.ProcessorChildStatefulSetReconciler(
+				controllers.Config{
+					Client:    client,
+					APIReader: client,
+					Recorder:  recorder,
+					Log:       log,
+					Scheme:    scheme,
+					Tracker:   tracker,
+				},
+			)
+		})
+	})
+
+	t.Run("ProcessorChildScaledObjectReconciler", func(t *testing.T) {
+		table := rtesting.SubTable{
+			{
+				Name: "not required, stateful",
+				Parent: processor.
+					State(stateVolume),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{},
+				},
+				ExpectParent: processorMinimal.
+					Default().
+					State(stateVolume).
+					StatusLatestImage(testImage).
+					StatusDeployedImage(testImage).
+					StatusDeploymentRef("%s-processor-000", testName).
+					StatusConditions(
+						processorConditionScaledObjectReady.True(),
+					),
+			},
+			{
+				Name: "skip, missing deployment",
+				Parent: processorMinimal.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream1.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Latest),
+					),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
+						*testStream1.Create(),
+						*testStream2.Create(),
+					},
+				},
+			},
+			{
+				Name: "skip, missing inputs",
+				Parent: processorMinimal.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream1.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Latest),
+					).
+					StatusDeploymentRef("%s-processor-000", testName),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey: nil,
+				},
+			},
+			{
+				Name: "create scaled object",
+				Parent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream1.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Latest),
+					),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
+						*testStream1.Create(),
+						*testStream2.Create(),
+					},
+				},
+				GivenObjects: []rtesting.Factory{
+					factories.Secret().
+						NamespaceName(testNamespace, "stream-1-binding-secret").
+						AddData("gateway", "stream-1-gateway.local:6565").
+						AddData("topic", fmt.Sprintf("%s/%s", testNamespace, "stream-1")),
+					factories.Secret().
+						NamespaceName(testNamespace, "stream-2-binding-secret").
+						AddData("gateway", "stream-2-gateway.local:6565").
+						AddData("topic", fmt.Sprintf("%s/%s", testNamespace, "stream-2")),
+				},
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(testGateway, processor, scheme),
+					rtesting.NewTrackRequest(testGateway, processor, scheme),
+				},
+				ExpectParent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream1.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Latest),
+					).
+					StatusScaledObjectRef("%s-processor-001", testName).
+					StatusConditions(
+						processorConditionScaledObjectReady.True(),
+					),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Created",
+						`Created ScaledObject "%s-processor-001"`, testName),
+				},
+				ExpectCreates: []rtesting.Factory{
+					scaledObjectCreate.
+						Triggers(
+							kedav1alpha1.ScaleTriggers{
+								Type: "liiklus",
+								Metadata: map[string]string{
+									"address": "stream-1-gateway.local:6565",
+									"group":   testName,
+									"topic":   fmt.Sprintf("%s/stream-1", testNamespace),
+								},
+							},
+							kedav1alpha1.ScaleTriggers{
+								Type: "liiklus",
+								Metadata: map[string]string{
+									"address": "stream-2-gateway.local:6565",
+									"group":   testName,
+									"topic":   fmt.Sprintf("%s/stream-2", testNamespace),
+								},
+							},
+						),
+				},
+			},
+			{
+				Name: "create scaled object, authenticated gateway",
+				Parent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
+						*testStream1.Create(),
+					},
+				},
+				GivenObjects: []rtesting.Factory{
+					testGateway.
+						ObjectMeta(func(om factories.ObjectMeta) {
+							om.AddAnnotation(streamingv1alpha1.GatewayTriggerAuthenticationAnnotationKey, "kafka")
+						}),
+					factories.Secret().
+						NamespaceName(testNamespace, "stream-1-binding-secret").
+						AddData("gateway", "stream-1-gateway.local:6565").
+						AddData("topic", fmt.Sprintf("%s/%s", testNamespace, "stream-1")),
+				},
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(testGateway, processor, scheme),
+				},
+				ExpectParent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					).
+					StatusScaledObjectRef("%s-processor-001", testName).
+					StatusConditions(
+						processorConditionScaledObjectReady.True(),
+					),
 				ExpectEvents: []rtesting.Event{
 					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Created",
 						`Created ScaledObject "%s-processor-001"`, testName),
 				},
-				ExpectCreates: []rtesting.Factory{
-					scaledObjectCreate.
+				ExpectCreates: []rtesting.Factory{
+					scaledObjectCreate.
+						Triggers(
+							kedav1alpha1.ScaleTriggers{
+								Type: "liiklus",
+								Metadata: map[string]string{
+									"address": "stream-1-gateway.local:6565",
+									"group":   testName,
+									"topic":   fmt.Sprintf("%s/stream-1", testNamespace),
+								},
+								AuthenticationRef: &kedav1alpha1.ScaledObjectAuthRef{Name: "kafka"},
+							},
+						),
+				},
+			},
+			{
+				Name: "update scaled object",
+				Parent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream1.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Latest),
+					),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
+						*testStream1.Create(),
+						*testStream2.Create(),
+					},
+				},
+				GivenObjects: []rtesting.Factory{
+					scaledObjectGiven,
+					factories.Secret().
+						NamespaceName(testNamespace, "stream-1-binding-secret").
+						AddData("gateway", "stream-1-gateway.local:6565").
+						AddData("topic", fmt.Sprintf("%s/%s", testNamespace, "stream-1")),
+					factories.Secret().
+						NamespaceName(testNamespace, "stream-2-binding-secret").
+						AddData("gateway", "stream-2-gateway.local:6565").
+						AddData("topic", fmt.Sprintf("%s/%s", testNamespace, "stream-2")),
+				},
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(testGateway, processor, scheme),
+					rtesting.NewTrackRequest(testGateway, processor, scheme),
+				},
+				ExpectParent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream1.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Latest),
+					).
+					StatusConditions(
+						processorConditionScaledObjectReady.True(),
+					),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Updated",
+						`Updated ScaledObject "%s-processor-000"`, testName),
+				},
+				ExpectUpdates: []rtesting.Factory{
+					scaledObjectGiven.
 						Triggers(
 							kedav1alpha1.ScaleTriggers{
 								Type: "liiklus",
@@ -971,12 +2659,23 @@ func TestProcessorReconciler(t *testing.T) {
 				},
 			},
 			{
-				Name: "update scaled object",
+				Name: "custom scale",
 				Parent: processor.
 					Inputs(
 						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
-						testStream1.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Latest),
-					),
+						streamingv1alpha1.InputStreamBinding{
+							Stream:       "stream-2",
+							Alias:        "alias-in-2",
+							StartOffset:  streamingv1alpha1.Latest,
+							LagThreshold: rtesting.Int32Ptr(100),
+						},
+					).
+					Scale(streamingv1alpha1.Scale{
+						Min:             rtesting.Int32Ptr(0),
+						Max:             rtesting.Int32Ptr(5),
+						PollingInterval: rtesting.Int32Ptr(10),
+						CooldownPeriod:  rtesting.Int32Ptr(300),
+					}),
 				GivenStashedValues: map[controllers.StashKey]interface{}{
 					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
 						*testStream1.Create(),
@@ -994,11 +2693,26 @@ func TestProcessorReconciler(t *testing.T) {
 						AddData("gateway", "stream-2-gateway.local:6565").
 						AddData("topic", fmt.Sprintf("%s/%s", testNamespace, "stream-2")),
 				},
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(testGateway, processor, scheme),
+					rtesting.NewTrackRequest(testGateway, processor, scheme),
+				},
 				ExpectParent: processor.
 					Inputs(
 						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
-						testStream1.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Latest),
+						streamingv1alpha1.InputStreamBinding{
+							Stream:       "stream-2",
+							Alias:        "alias-in-2",
+							StartOffset:  streamingv1alpha1.Latest,
+							LagThreshold: rtesting.Int32Ptr(100),
+						},
 					).
+					Scale(streamingv1alpha1.Scale{
+						Min:             rtesting.Int32Ptr(0),
+						Max:             rtesting.Int32Ptr(5),
+						PollingInterval: rtesting.Int32Ptr(10),
+						CooldownPeriod:  rtesting.Int32Ptr(300),
+					}).
 					StatusConditions(
 						processorConditionScaledObjectReady.True(),
 					),
@@ -1008,6 +2722,10 @@ func TestProcessorReconciler(t *testing.T) {
 				},
 				ExpectUpdates: []rtesting.Factory{
 					scaledObjectGiven.
+						PollingInterval(10).
+						CooldownPeriod(300).
+						MinReplicaCount(0).
+						MaxReplicaCount(5).
 						Triggers(
 							kedav1alpha1.ScaleTriggers{
 								Type: "liiklus",
@@ -1020,9 +2738,58 @@ func TestProcessorReconciler(t *testing.T) {
 							kedav1alpha1.ScaleTriggers{
 								Type: "liiklus",
 								Metadata: map[string]string{
-									"address": "stream-2-gateway.local:6565",
-									"group":   testName,
-									"topic":   fmt.Sprintf("%s/stream-2", testNamespace),
+									"address":      "stream-2-gateway.local:6565",
+									"group":        testName,
+									"topic":        fmt.Sprintf("%s/stream-2", testNamespace),
+									"lagThreshold": "100",
+								},
+							},
+						),
+				},
+			},
+			{
+				Name: "replayed consumer group",
+				Parent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					).
+					StatusConsumerGroup("%s-2", testName),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
+						*testStream1.Create(),
+					},
+				},
+				GivenObjects: []rtesting.Factory{
+					scaledObjectGiven,
+					factories.Secret().
+						NamespaceName(testNamespace, "stream-1-binding-secret").
+						AddData("gateway", "stream-1-gateway.local:6565").
+						AddData("topic", fmt.Sprintf("%s/%s", testNamespace, "stream-1")),
+				},
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(testGateway, processor, scheme),
+				},
+				ExpectParent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					).
+					StatusConsumerGroup("%s-2", testName).
+					StatusConditions(
+						processorConditionScaledObjectReady.True(),
+					),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Updated",
+						`Updated ScaledObject "%s-processor-000"`, testName),
+				},
+				ExpectUpdates: []rtesting.Factory{
+					scaledObjectGiven.
+						Triggers(
+							kedav1alpha1.ScaleTriggers{
+								Type: "liiklus",
+								Metadata: map[string]string{
+									"address": "stream-1-gateway.local:6565",
+									"group":   fmt.Sprintf("%s-2", testName),
+									"topic":   fmt.Sprintf("%s/stream-1", testNamespace),
 								},
 							},
 						),
@@ -1049,10 +2816,72 @@ func TestProcessorReconciler(t *testing.T) {
 						AddData("gateway", "stream-1-gateway.local:6565").
 						AddData("topic", fmt.Sprintf("%s/%s", testNamespace, "stream-1")),
 				},
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(testGateway, processor, scheme),
+				},
+				ExpectParent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					).
+					StatusConditions(
+						processorConditionScaledObjectReady.True(),
+						processorConditionStreamsReady.False(),
+					),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Updated",
+						`Updated ScaledObject "%s-processor-000"`, testName),
+				},
+				ExpectUpdates: []rtesting.Factory{
+					scaledObjectGiven.
+						MinReplicaCount(0).
+						MaxReplicaCount(0).
+						Triggers(
+							kedav1alpha1.ScaleTriggers{
+								Type: "liiklus",
+								Metadata: map[string]string{
+									"address": "stream-1-gateway.local:6565",
+									"group":   testName,
+									"topic":   fmt.Sprintf("%s/stream-1", testNamespace),
+								},
+							},
+						),
+				},
+			},
+			{
+				Name: "scale to zero for not ready streams, with min replicas",
+				Parent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					).
+					Scale(streamingv1alpha1.Scale{
+						Min: rtesting.Int32Ptr(3),
+					}).
+					StatusConditions(
+						processorConditionStreamsReady.False(),
+					),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
+						*testStream1.Create(),
+					},
+				},
+				GivenObjects: []rtesting.Factory{
+					scaledObjectGiven.
+						MinReplicaCount(3),
+					factories.Secret().
+						NamespaceName(testNamespace, "stream-1-binding-secret").
+						AddData("gateway", "stream-1-gateway.local:6565").
+						AddData("topic", fmt.Sprintf("%s/%s", testNamespace, "stream-1")),
+				},
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(testGateway, processor, scheme),
+				},
 				ExpectParent: processor.
 					Inputs(
 						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
 					).
+					Scale(streamingv1alpha1.Scale{
+						Min: rtesting.Int32Ptr(3),
+					}).
 					StatusConditions(
 						processorConditionScaledObjectReady.True(),
 						processorConditionStreamsReady.False(),
@@ -1063,6 +2892,7 @@ func TestProcessorReconciler(t *testing.T) {
 				},
 				ExpectUpdates: []rtesting.Factory{
 					scaledObjectGiven.
+						MinReplicaCount(0).
 						MaxReplicaCount(0).
 						Triggers(
 							kedav1alpha1.ScaleTriggers{
@@ -1076,6 +2906,60 @@ func TestProcessorReconciler(t *testing.T) {
 						),
 				},
 			},
+			{
+				Name: "min replicas above the default max replicas",
+				Parent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					).
+					Scale(streamingv1alpha1.Scale{
+						Min: rtesting.Int32Ptr(50),
+					}),
+				GivenStashedValues: map[controllers.StashKey]interface{}{
+					streaming.InputStreamsStashKey: []streamingv1alpha1.Stream{
+						*testStream1.Create(),
+					},
+				},
+				GivenObjects: []rtesting.Factory{
+					scaledObjectGiven,
+					factories.Secret().
+						NamespaceName(testNamespace, "stream-1-binding-secret").
+						AddData("gateway", "stream-1-gateway.local:6565").
+						AddData("topic", fmt.Sprintf("%s/%s", testNamespace, "stream-1")),
+				},
+				ExpectTracks: []rtesting.TrackRequest{
+					rtesting.NewTrackRequest(testGateway, processor, scheme),
+				},
+				ExpectParent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					).
+					Scale(streamingv1alpha1.Scale{
+						Min: rtesting.Int32Ptr(50),
+					}).
+					StatusConditions(
+						processorConditionScaledObjectReady.True(),
+					),
+				ExpectEvents: []rtesting.Event{
+					rtesting.NewEvent(processor, scheme, corev1.EventTypeNormal, "Updated",
+						`Updated ScaledObject "%s-processor-000"`, testName),
+				},
+				ExpectUpdates: []rtesting.Factory{
+					scaledObjectGiven.
+						MinReplicaCount(50).
+						MaxReplicaCount(50).
+						Triggers(
+							kedav1alpha1.ScaleTriggers{
+								Type: "liiklus",
+								Metadata: map[string]string{
+									"address": "stream-1-gateway.local:6565",
+									"group":   testName,
+									"topic":   fmt.Sprintf("%s/stream-1", testNamespace),
+								},
+							},
+						),
+				},
+			},
 			{
 				Name: "binding secret not found",
 				Parent: processorMinimal.
@@ -1161,4 +3045,164 @@ func TestProcessorReconciler(t *testing.T) {
 			)
 		})
 	})
+
+	t.Run("ProcessorConsumerLagReconciler", func(t *testing.T) {
+		table := rtesting.SubTable{
+			{
+				Name: "lag not observed",
+				Parent: processor.
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					),
+			},
+			{
+				Name: "not lagging",
+				Parent: processor.
+					NamespaceName(testNamespace, "caught-up").
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream2.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Earliest),
+					),
+				ExpectParent: processor.
+					NamespaceName(testNamespace, "caught-up").
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream2.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Earliest),
+					).
+					StatusInputLags(
+						streamingv1alpha1.ProcessorInputLag{Stream: "stream-1", Lag: 10},
+						streamingv1alpha1.ProcessorInputLag{Stream: "stream-2", Lag: 0},
+					).
+					StatusConditions(
+						processorConditionLagging.False(),
+					),
+			},
+			{
+				Name: "lagging",
+				Parent: processor.
+					NamespaceName(testNamespace, "behind").
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream2.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Earliest),
+					),
+				ExpectParent: processor.
+					NamespaceName(testNamespace, "behind").
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream2.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Earliest),
+					).
+					StatusInputLags(
+						streamingv1alpha1.ProcessorInputLag{Stream: "stream-1", Lag: 150},
+						streamingv1alpha1.ProcessorInputLag{Stream: "stream-2", Lag: 20},
+					).
+					StatusConditions(
+						processorConditionLagging.True().Reason("LagAboveThreshold", "lag exceeds threshold of 100 on stream-1 (150)"),
+					),
+			},
+			{
+				Name: "lagging threshold override",
+				Parent: processor.
+					NamespaceName(testNamespace, "behind").
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream2.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Earliest),
+					).
+					LaggingThreshold(10),
+				ExpectParent: processor.
+					NamespaceName(testNamespace, "behind").
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream2.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Earliest),
+					).
+					LaggingThreshold(10).
+					StatusInputLags(
+						streamingv1alpha1.ProcessorInputLag{Stream: "stream-1", Lag: 150},
+						streamingv1alpha1.ProcessorInputLag{Stream: "stream-2", Lag: 20},
+					).
+					StatusConditions(
+						processorConditionLagging.True().Reason("LagAboveThreshold", "lag exceeds threshold of 10 on stream-1 (150), stream-2 (20)"),
+					),
+			},
+			{
+				Name: "caught up after lagging",
+				Parent: processor.
+					NamespaceName(testNamespace, "caught-up").
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream2.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Earliest),
+					).
+					StatusInputLags(
+						streamingv1alpha1.ProcessorInputLag{Stream: "stream-1", Lag: 150},
+						streamingv1alpha1.ProcessorInputLag{Stream: "stream-2", Lag: 20},
+					).
+					StatusConditions(
+						processorConditionLagging.True().Reason("LagAboveThreshold", "lag exceeds threshold of 100 on stream-1 (150)"),
+					),
+				ExpectParent: processor.
+					NamespaceName(testNamespace, "caught-up").
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+						testStream2.CreateInputStreamBinding("alias-in-2", streamingv1alpha1.Earliest),
+					).
+					StatusInputLags(
+						streamingv1alpha1.ProcessorInputLag{Stream: "stream-1", Lag: 10},
+						streamingv1alpha1.ProcessorInputLag{Stream: "stream-2", Lag: 0},
+					).
+					StatusConditions(
+						processorConditionLagging.False(),
+					),
+			},
+			{
+				Name: "lag stale",
+				Parent: processor.
+					NamespaceName(testNamespace, "unobserved").
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					).
+					StatusInputLags(
+						streamingv1alpha1.ProcessorInputLag{Stream: "stream-1", Lag: 150},
+					).
+					StatusConditions(
+						processorConditionLagging.True().Reason("LagAboveThreshold", "lag exceeds threshold of 100 on stream-1 (150)"),
+					),
+				ExpectParent: processor.
+					NamespaceName(testNamespace, "unobserved").
+					Inputs(
+						testStream1.CreateInputStreamBinding("alias-in-1", streamingv1alpha1.Earliest),
+					).
+					StatusConditions(
+						processorConditionLagging.Unknown().Reason("LagUnknown", "lag has not been observed for 3 polls"),
+					),
+			},
+		}
+
+		table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
+			lagClock := clock.NewFakeClock(time.Date(2020, time.April, 1, 12, 0, 0, 0, time.UTC))
+			lagMonitor := streaming.NewConsumerLagMonitor(client, &streaming.MockStreamProvisionerClient{}, time.Minute, 100, log)
+			lagMonitor.Clock = lagClock
+			lagMonitor.Record(types.NamespacedName{Namespace: testNamespace, Name: "unobserved"}, []streamingv1alpha1.ProcessorInputLag{
+				{Stream: "stream-1", Lag: 150},
+			})
+			lagClock.Step(4 * time.Minute)
+			lagMonitor.Record(types.NamespacedName{Namespace: testNamespace, Name: "caught-up"}, []streamingv1alpha1.ProcessorInputLag{
+				{Stream: "stream-1", Lag: 10},
+				{Stream: "stream-2", Lag: 0},
+			})
+			lagMonitor.Record(types.NamespacedName{Namespace: testNamespace, Name: "behind"}, []streamingv1alpha1.ProcessorInputLag{
+				{Stream: "stream-1", Lag: 150},
+				{Stream: "stream-2", Lag: 20},
+			})
+			return streaming.ProcessorConsumerLagReconciler(
+				controllers.Config{
+					Client:    client,
+					APIReader: client,
+					Recorder:  recorder,
+					Log:       log,
+					Scheme:    scheme,
+					Tracker:   tracker,
+				},
+				lagMonitor,
+			)
+		})
+	})
 }
@@ -41,6 +41,12 @@ import (
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 
+const (
+	pulsarTokenRootPath      = "/var/riff/pulsar/token"
+	pulsarCARootPath         = "/var/riff/pulsar/ca"
+	pulsarClientCertRootPath = "/var/riff/pulsar/client"
+)
+
 func PulsarGatewayReconciler(c controllers.Config, namespace string) *controllers.ParentReconciler {
 	c.Log = c.Log.WithName("PulsarGateway")
 
@@ -103,6 +109,12 @@ func PulsarGatewayChildGatewayReconciler(c controllers.Config) controllers.SubRe
 				if err != nil {
 					return nil, err
 				}
+				tenant, namespace, err := parent.PulsarNamespace()
+				if err != nil {
+					return nil, err
+				}
+				volumes, volumeMounts, gatewayEnv, provisionerEnv := pulsarGatewaySecurity(parent)
+				gatewayEnv = append(gatewayEnv, gatewayPositionsEnv(parent.Spec.PositionsStore)...)
 
 				template = &corev1.PodTemplateSpec{
 					ObjectMeta: metav1.ObjectMeta{
@@ -113,27 +125,37 @@ func PulsarGatewayChildGatewayReconciler(c controllers.Config) controllers.SubRe
 							{
 								Name:  "gateway",
 								Image: parent.Status.GatewayImage,
-								Env: []corev1.EnvVar{
-									{Name: "pulsar_serviceUrl", Value: parent.Spec.ServiceURL},
-									{Name: "storage_positions_type", Value: "MEMORY"},
-									{Name: "storage_records_type", Value: "PULSAR"},
-									{Name: "server_port", Value: "8000"},
-								},
+								Env: append(
+									append(
+										[]corev1.EnvVar{
+											{Name: "pulsar_serviceUrl", Value: parent.Spec.ServiceURL},
+										},
+										gatewayEnv...,
+									),
+									corev1.EnvVar{Name: "storage_records_type", Value: "PULSAR"},
+									corev1.EnvVar{Name: "server_port", Value: "8000"},
+								),
+								VolumeMounts: volumeMounts,
 							},
 							{
 								Name:  "provisioner",
 								Image: parent.Status.ProvisionerImage,
-								Env: []corev1.EnvVar{
-									{Name: "GATEWAY", Value: fmt.Sprintf("%s:6565", gatewayAddress.Hostname())},
-									{Name: "BROKER", Value: parent.Spec.ServiceURL},
-									// TODO make tenant and namespace configurable
-									{Name: "TENANT", Value: "public"},
-									{Name: "NAMESPACE", Value: "default"},
-								},
+								Env: append(
+									[]corev1.EnvVar{
+										{Name: "GATEWAY", Value: fmt.Sprintf("%s:6565", gatewayAddress.Hostname())},
+										{Name: "BROKER", Value: parent.Spec.ServiceURL},
+										{Name: "TENANT", Value: tenant},
+										{Name: "NAMESPACE", Value: namespace},
+									},
+									provisionerEnv...,
+								),
+								VolumeMounts: volumeMounts,
 							},
 						},
+						Volumes: volumes,
 					},
 				}
+				tuneGatewayTemplate(template, parent.Spec.GatewayDeploymentSpec)
 			}
 
 			child := &streamingv1alpha1.Gateway{
@@ -144,11 +166,13 @@ func PulsarGatewayChildGatewayReconciler(c controllers.Config) controllers.SubRe
 					Namespace:   parent.Namespace,
 				},
 				Spec: streamingv1alpha1.GatewaySpec{
+					Replicas: parent.Spec.Replicas,
 					Template: template,
 					Ports: []corev1.ServicePort{
 						{Name: "gateway", Port: 6565},
 						{Name: "provisioner", Port: 80, TargetPort: intstr.FromInt(8080)},
 					},
+					Exposure: parent.Spec.Exposure,
 				},
 			}
 
@@ -164,6 +188,7 @@ func PulsarGatewayChildGatewayReconciler(c controllers.Config) controllers.SubRe
 			}
 			parent.Status.GatewayRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
 			parent.Status.Address = child.Status.Address
+			parent.Status.PublicAddress = child.Status.PublicAddress
 			parent.Status.PropagateGatewayStatus(&child.Status)
 		},
 		MergeBeforeUpdate: func(current, desired *streamingv1alpha1.Gateway) {
@@ -182,3 +207,71 @@ func PulsarGatewayChildGatewayReconciler(c controllers.Config) controllers.SubRe
 		},
 	}
 }
+
+// pulsarGatewaySecurity resolves the volumes and environment the gateway and
+// provisioner containers need to authenticate to Pulsar with a token or TLS
+// client certificate. Credentials are mounted into both containers from the
+// referenced secrets.
+func pulsarGatewaySecurity(parent *streamingv1alpha1.PulsarGateway) ([]corev1.Volume, []corev1.VolumeMount, []corev1.EnvVar, []corev1.EnvVar) {
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+	var gatewayEnv []corev1.EnvVar
+	var provisionerEnv []corev1.EnvVar
+
+	mount := func(name, secretName, path string, keys ...string) {
+		items := make([]corev1.KeyToPath, len(keys))
+		for i, key := range keys {
+			items[i] = corev1.KeyToPath{Key: key, Path: key}
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secretName,
+					Items:      items,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      name,
+			MountPath: path,
+			ReadOnly:  true,
+		})
+	}
+	authenticate := func(plugin, params string) {
+		gatewayEnv = append(gatewayEnv,
+			corev1.EnvVar{Name: "pulsar_authPluginClassName", Value: plugin},
+			corev1.EnvVar{Name: "pulsar_authPluginParams", Value: params},
+		)
+		provisionerEnv = append(provisionerEnv,
+			corev1.EnvVar{Name: "AUTH_PLUGIN", Value: plugin},
+			corev1.EnvVar{Name: "AUTH_PARAMS", Value: params},
+		)
+	}
+
+	if tls := parent.Spec.TLS; tls != nil && tls.CASecretRef != nil {
+		mount("pulsar-ca", tls.CASecretRef.Name, pulsarCARootPath, streamingv1alpha1.PulsarGatewayCAKey)
+		caFile := fmt.Sprintf("%s/%s", pulsarCARootPath, streamingv1alpha1.PulsarGatewayCAKey)
+		gatewayEnv = append(gatewayEnv, corev1.EnvVar{Name: "pulsar_tlsTrustCertsFilePath", Value: caFile})
+		provisionerEnv = append(provisionerEnv, corev1.EnvVar{Name: "TLS_TRUST_CERTS_FILE", Value: caFile})
+	}
+	if tokenSecretRef := parent.Spec.TokenSecretRef; tokenSecretRef != nil {
+		mount("pulsar-token", tokenSecretRef.Name, pulsarTokenRootPath, streamingv1alpha1.PulsarGatewayTokenKey)
+		authenticate(
+			"org.apache.pulsar.client.impl.auth.AuthenticationToken",
+			fmt.Sprintf("file://%s/%s", pulsarTokenRootPath, streamingv1alpha1.PulsarGatewayTokenKey),
+		)
+	}
+	if tls := parent.Spec.TLS; tls != nil && tls.ClientCertSecretRef != nil {
+		mount("pulsar-client-cert", tls.ClientCertSecretRef.Name, pulsarClientCertRootPath, streamingv1alpha1.PulsarGatewayClientCertKey, streamingv1alpha1.PulsarGatewayClientKeyKey)
+		authenticate(
+			"org.apache.pulsar.client.impl.auth.AuthenticationTls",
+			fmt.Sprintf("tlsCertFile:%s/%s,tlsKeyFile:%s/%s",
+				pulsarClientCertRootPath, streamingv1alpha1.PulsarGatewayClientCertKey,
+				pulsarClientCertRootPath, streamingv1alpha1.PulsarGatewayClientKeyKey,
+			),
+		)
+	}
+
+	return volumes, volumeMounts, gatewayEnv, provisionerEnv
+}
@@ -125,6 +125,7 @@ func TestPulsarGatewayReconciler(t *testing.T) {
 					{Name: "NAMESPACE", Value: "default"},
 				}
 			})
+			probeGatewayContainers(pts)
 		})
 
 	table := rtesting.Table{{
@@ -284,6 +285,157 @@ func TestPulsarGatewayReconciler(t *testing.T) {
 					pulsarGatewayConditionReady.False().Reason("TestReason", "a human readable message"),
 				),
 		},
+	}, {
+		Name: "tenant and namespace templates",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			pulsarGateway.
+				Tenant("riff").
+				PulsarNamespace("{{ .Namespace }}-{{ .Name }}").
+				StatusAddress(testProvisionerURL),
+			pulsarGatewayImagesConfigMap,
+			gatewayComplete.
+				StatusReady(),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(pulsarGatewayImagesConfigMap, pulsarGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(pulsarGateway, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Gateway "%s"`, testName),
+			rtesting.NewEvent(pulsarGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			gatewayComplete.
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("provisioner", func(c *corev1.Container) {
+						c.Env = []corev1.EnvVar{
+							{Name: "GATEWAY", Value: fmt.Sprintf("%s:6565", testProvisionerHostname)},
+							{Name: "BROKER", Value: testServiceURL},
+							{Name: "TENANT", Value: "riff"},
+							{Name: "NAMESPACE", Value: fmt.Sprintf("%s-%s", testNamespace, testName)},
+						}
+					})
+				}).
+				StatusReady(),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			pulsarGateway.
+				Tenant("riff").
+				PulsarNamespace("{{ .Namespace }}-{{ .Name }}").
+				StatusAddress(testProvisionerURL).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					pulsarGatewayConditionGatewayReady.True(),
+					pulsarGatewayConditionReady.True(),
+				),
+		},
+	}, {
+		Name: "invalid namespace template",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			pulsarGatewayReady.
+				PulsarNamespace("{{ .Namespace").
+				StatusAddress(testProvisionerURL),
+			pulsarGatewayImagesConfigMap,
+			gatewayComplete.
+				StatusReady(),
+		},
+		ShouldErr: true,
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(pulsarGatewayImagesConfigMap, pulsarGateway, scheme),
+		},
+	}, {
+		Name: "token and tls authentication",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			pulsarGateway.
+				TokenSecretRef("pulsar-token").
+				TLS(&streamingv1alpha1.PulsarTLS{
+					CASecretRef: &corev1.LocalObjectReference{Name: "pulsar-ca"},
+				}).
+				StatusAddress(testProvisionerURL),
+			pulsarGatewayImagesConfigMap,
+			gatewayComplete.
+				StatusReady(),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(pulsarGatewayImagesConfigMap, pulsarGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(pulsarGateway, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Gateway "%s"`, testName),
+			rtesting.NewEvent(pulsarGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			gatewayComplete.
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					volumeMounts := []corev1.VolumeMount{
+						{Name: "pulsar-ca", MountPath: "/var/riff/pulsar/ca", ReadOnly: true},
+						{Name: "pulsar-token", MountPath: "/var/riff/pulsar/token", ReadOnly: true},
+					}
+					pts.ContainerNamed("gateway", func(c *corev1.Container) {
+						c.Env = []corev1.EnvVar{
+							{Name: "pulsar_serviceUrl", Value: testServiceURL},
+							{Name: "pulsar_tlsTrustCertsFilePath", Value: "/var/riff/pulsar/ca/ca.crt"},
+							{Name: "pulsar_authPluginClassName", Value: "org.apache.pulsar.client.impl.auth.AuthenticationToken"},
+							{Name: "pulsar_authPluginParams", Value: "file:///var/riff/pulsar/token/token"},
+							{Name: "storage_positions_type", Value: "MEMORY"},
+							{Name: "storage_records_type", Value: "PULSAR"},
+							{Name: "server_port", Value: "8000"},
+						}
+						c.VolumeMounts = volumeMounts
+					})
+					pts.ContainerNamed("provisioner", func(c *corev1.Container) {
+						c.Env = []corev1.EnvVar{
+							{Name: "GATEWAY", Value: fmt.Sprintf("%s:6565", testProvisionerHostname)},
+							{Name: "BROKER", Value: testServiceURL},
+							{Name: "TENANT", Value: "public"},
+							{Name: "NAMESPACE", Value: "default"},
+							{Name: "TLS_TRUST_CERTS_FILE", Value: "/var/riff/pulsar/ca/ca.crt"},
+							{Name: "AUTH_PLUGIN", Value: "org.apache.pulsar.client.impl.auth.AuthenticationToken"},
+							{Name: "AUTH_PARAMS", Value: "file:///var/riff/pulsar/token/token"},
+						}
+						c.VolumeMounts = volumeMounts
+					})
+					pts.Volumes(
+						corev1.Volume{
+							Name: "pulsar-ca",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: "pulsar-ca",
+									Items:      []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+								},
+							},
+						},
+						corev1.Volume{
+							Name: "pulsar-token",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: "pulsar-token",
+									Items:      []corev1.KeyToPath{{Key: "token", Path: "token"}},
+								},
+							},
+						},
+					)
+				}).
+				StatusReady(),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			pulsarGateway.
+				TokenSecretRef("pulsar-token").
+				TLS(&streamingv1alpha1.PulsarTLS{
+					CASecretRef: &corev1.LocalObjectReference{Name: "pulsar-ca"},
+				}).
+				StatusAddress(testProvisionerURL).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					pulsarGatewayConditionGatewayReady.True(),
+					pulsarGatewayConditionReady.True(),
+				),
+		},
 	}, {
 		Name: "missing gateway images configmap",
 		Key:  testKey,
@@ -0,0 +1,462 @@
+/*
+Copyright 2020 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/controllers/streaming"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/controllers/testing/factories"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+func TestRedisGatewayReconciler(t *testing.T) {
+	testNamespace := "test-namespace"
+	testSystemNamespace := "system-namespace"
+	testName := "test-gateway"
+	testKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
+	testImagePrefix := "example.com/repo"
+	testGatewayImage := fmt.Sprintf("%s/%s", testImagePrefix, "gateway")
+	testProvisionerImage := fmt.Sprintf("%s/%s", testImagePrefix, "provisioner")
+	testProvisionerHostname := fmt.Sprintf("%s.%s.svc.cluster.local", testName, testNamespace)
+	testProvisionerURL := fmt.Sprintf("http://%s", testProvisionerHostname)
+	testRedisAddress := "redis.local:6379"
+
+	redisGatewayImages := "riff-streaming-redis-gateway" // contains image names for the redis gateway
+	gatewayImageKey := "gatewayImage"
+	provisionerImageKey := "provisionerImage"
+
+	redisGatewayConditionGatewayReady := factories.Condition().Type(streamingv1alpha1.RedisGatewayConditionGatewayReady)
+	redisGatewayConditionReady := factories.Condition().Type(streamingv1alpha1.RedisGatewayConditionReady)
+	gatewayConditionReady := factories.Condition().Type(streamingv1alpha1.GatewayConditionReady)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	redisGatewayImagesConfigMap := factories.ConfigMap().
+		NamespaceName(testSystemNamespace, redisGatewayImages).
+		AddData(gatewayImageKey, testGatewayImage).
+		AddData(provisionerImageKey, testProvisionerImage)
+
+	redisGatewayMinimal := factories.RedisGateway().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.Generation(1)
+		}).
+		Address(testRedisAddress)
+	redisGateway := redisGatewayMinimal.
+		StatusGatewayRef(testName).
+		StatusGatewayImage(testGatewayImage).
+		StatusProvisionerImage(testProvisionerImage)
+	redisGatewayReady := redisGateway.
+		StatusObservedGeneration(1).
+		StatusConditions(
+			redisGatewayConditionGatewayReady.True(),
+			redisGatewayConditionReady.True(),
+		)
+
+	gatewayCreate := factories.Gateway().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.AddLabel(streamingv1alpha1.RedisGatewayLabelKey, testName)
+			om.AddLabel(streamingv1alpha1.GatewayTypeLabelKey, streamingv1alpha1.RedisGatewayType)
+			om.ControlledBy(redisGateway, scheme)
+		}).
+		Ports(
+			corev1.ServicePort{Name: "gateway", Port: 6565},
+			corev1.ServicePort{Name: "provisioner", Port: 80, TargetPort: intstr.FromInt(8080)},
+		)
+	gatewayGiven := gatewayCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+		}).
+		StatusAddress(testProvisionerURL)
+	gatewayComplete := gatewayGiven.
+		PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+			pts.AddLabel(streamingv1alpha1.RedisGatewayLabelKey, testName)
+			pts.AddLabel(streamingv1alpha1.GatewayTypeLabelKey, streamingv1alpha1.RedisGatewayType)
+			pts.ContainerNamed("gateway", func(c *corev1.Container) {
+				c.Image = testGatewayImage
+				c.Env = []corev1.EnvVar{
+					{Name: "redis_address", Value: testRedisAddress},
+					{Name: "redis_tls", Value: "false"},
+					{Name: "storage_positions_type", Value: "REDIS"},
+					{Name: "storage_records_type", Value: "REDIS"},
+					{Name: "server_port", Value: "8000"},
+				}
+			})
+			pts.ContainerNamed("provisioner", func(c *corev1.Container) {
+				c.Image = testProvisionerImage
+				c.Env = []corev1.EnvVar{
+					{Name: "GATEWAY", Value: fmt.Sprintf("%s:6565", testProvisionerHostname)},
+					{Name: "REDIS_ADDRESS", Value: testRedisAddress},
+					{Name: "REDIS_TLS", Value: "false"},
+				}
+			})
+			probeGatewayContainers(pts)
+		})
+
+	table := rtesting.Table{{
+		Name: "redisgateway does not exist",
+		Key:  testKey,
+	}, {
+		Name: "ignore deleted redisgateway",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			redisGateway.
+				ObjectMeta(func(om factories.ObjectMeta) {
+					om.Deleted(1)
+				}),
+		},
+	}, {
+		Name: "error fetching redisgateway",
+		Key:  testKey,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("get", "RedisGateway"),
+		},
+		GivenObjects: []rtesting.Factory{
+			redisGateway,
+		},
+		ShouldErr: true,
+	}, {
+		Name: "creates gateway",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			redisGatewayMinimal,
+			redisGatewayImagesConfigMap,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(redisGatewayImagesConfigMap, redisGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(redisGateway, scheme, corev1.EventTypeNormal, "Created",
+				`Created Gateway "%s"`, testName),
+			rtesting.NewEvent(redisGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			gatewayCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			redisGateway.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					redisGatewayConditionGatewayReady.Unknown(),
+					redisGatewayConditionReady.Unknown(),
+				),
+		},
+	}, {
+		Name: "propagate address",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			redisGateway,
+			redisGatewayImagesConfigMap,
+			gatewayGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(redisGatewayImagesConfigMap, redisGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(redisGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			redisGateway.
+				StatusAddress(testProvisionerURL).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					redisGatewayConditionGatewayReady.Unknown(),
+					redisGatewayConditionReady.Unknown(),
+				),
+		},
+	}, {
+		Name: "updates gateway",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			redisGateway.
+				StatusAddress(testProvisionerURL),
+			redisGatewayImagesConfigMap,
+			gatewayGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(redisGatewayImagesConfigMap, redisGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(redisGateway, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Gateway "%s"`, testName),
+			rtesting.NewEvent(redisGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			gatewayComplete,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			redisGateway.
+				StatusAddress(testProvisionerURL).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					redisGatewayConditionGatewayReady.Unknown(),
+					redisGatewayConditionReady.Unknown(),
+				),
+		},
+	}, {
+		Name: "updates gateway, with password and tls",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			redisGateway.
+				PasswordSecretRef("redis", "password").
+				TLS(true).
+				StatusAddress(testProvisionerURL),
+			redisGatewayImagesConfigMap,
+			gatewayGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(redisGatewayImagesConfigMap, redisGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(redisGateway, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Gateway "%s"`, testName),
+			rtesting.NewEvent(redisGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			gatewayComplete.
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					password := corev1.EnvVar{
+						ValueFrom: &corev1.EnvVarSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "redis"},
+								Key:                  "password",
+							},
+						},
+					}
+					pts.ContainerNamed("gateway", func(c *corev1.Container) {
+						password.Name = "redis_password"
+						c.Env = []corev1.EnvVar{
+							{Name: "redis_address", Value: testRedisAddress},
+							{Name: "redis_tls", Value: "true"},
+							password,
+							{Name: "storage_positions_type", Value: "REDIS"},
+							{Name: "storage_records_type", Value: "REDIS"},
+							{Name: "server_port", Value: "8000"},
+						}
+					})
+					pts.ContainerNamed("provisioner", func(c *corev1.Container) {
+						password.Name = "REDIS_PASSWORD"
+						c.Env = []corev1.EnvVar{
+							{Name: "GATEWAY", Value: fmt.Sprintf("%s:6565", testProvisionerHostname)},
+							{Name: "REDIS_ADDRESS", Value: testRedisAddress},
+							{Name: "REDIS_TLS", Value: "true"},
+							password,
+						}
+					})
+				}),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			redisGateway.
+				PasswordSecretRef("redis", "password").
+				TLS(true).
+				StatusAddress(testProvisionerURL).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					redisGatewayConditionGatewayReady.Unknown(),
+					redisGatewayConditionReady.Unknown(),
+				),
+		},
+	}, {
+		Name: "ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			redisGateway.
+				StatusAddress(testProvisionerURL),
+			redisGatewayImagesConfigMap,
+			gatewayComplete.
+				StatusReady(),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(redisGatewayImagesConfigMap, redisGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(redisGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			redisGateway.
+				StatusAddress(testProvisionerURL).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					redisGatewayConditionGatewayReady.True(),
+					redisGatewayConditionReady.True(),
+				),
+		},
+	}, {
+		Name: "not ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			redisGateway.
+				StatusAddress(testProvisionerURL),
+			redisGatewayImagesConfigMap,
+			gatewayComplete.
+				StatusConditions(
+					gatewayConditionReady.False().Reason("TestReason", "a human readable message"),
+				),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(redisGatewayImagesConfigMap, redisGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(redisGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			redisGateway.
+				StatusAddress(testProvisionerURL).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					redisGatewayConditionGatewayReady.False().Reason("TestReason", "a human readable message"),
+					redisGatewayConditionReady.False().Reason("TestReason", "a human readable message"),
+				),
+		},
+	}, {
+		Name: "missing gateway images configmap",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			redisGatewayReady,
+		},
+		ShouldErr: true,
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(redisGatewayImagesConfigMap, redisGateway, scheme),
+		},
+	}, {
+		Name: "invalid address",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			redisGatewayReady.
+				StatusAddress("\000"),
+			redisGatewayImagesConfigMap,
+			gatewayGiven.
+				StatusAddress("\000"),
+		},
+		ShouldErr: true,
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(redisGatewayImagesConfigMap, redisGateway, scheme),
+		},
+	}, {
+		Name: "conflicting gateway",
+		Key:  testKey,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("create", "Gateway", rtesting.InduceFailureOpts{
+				Error: apierrs.NewAlreadyExists(schema.GroupResource{}, testName),
+			}),
+		},
+		GivenObjects: []rtesting.Factory{
+			redisGatewayMinimal,
+			redisGatewayImagesConfigMap,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(redisGatewayImagesConfigMap, redisGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(redisGateway, scheme, corev1.EventTypeWarning, "CreationFailed",
+				`Failed to create Gateway "%s":  "%s" already exists`, testName, testName),
+			rtesting.NewEvent(redisGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			gatewayCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			redisGatewayMinimal.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					redisGatewayConditionGatewayReady.False().Reason("NotOwned", `There is an existing Gateway "test-gateway" that the RedisGateway does not own.`),
+					redisGatewayConditionReady.False().Reason("NotOwned", `There is an existing Gateway "test-gateway" that the RedisGateway does not own.`),
+				).
+				StatusGatewayImage(testGatewayImage).
+				StatusProvisionerImage(testProvisionerImage),
+		},
+	}, {
+		Name: "conflicting gateway, owned",
+		Key:  testKey,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("create", "Gateway", rtesting.InduceFailureOpts{
+				Error: apierrs.NewAlreadyExists(schema.GroupResource{}, testName),
+			}),
+		},
+		GivenObjects: []rtesting.Factory{
+			redisGatewayMinimal,
+			redisGatewayImagesConfigMap,
+		},
+		APIGivenObjects: []rtesting.Factory{
+			gatewayGiven,
+		},
+		ShouldErr: true,
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(redisGatewayImagesConfigMap, redisGateway, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(redisGateway, scheme, corev1.EventTypeWarning, "CreationFailed",
+				`Failed to create Gateway "%s":  "%s" already exists`, testName, testName),
+			rtesting.NewEvent(redisGateway, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			gatewayCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			redisGatewayMinimal.
+				StatusConditions(
+					redisGatewayConditionGatewayReady.Unknown(),
+					redisGatewayConditionReady.Unknown(),
+				).
+				StatusGatewayImage(testGatewayImage).
+				StatusProvisionerImage(testProvisionerImage),
+		},
+	}}
+
+	table.Test(t, scheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
+		return streaming.RedisGatewayReconciler(
+			controllers.Config{
+				Client:    client,
+				APIReader: apiReader,
+				Recorder:  recorder,
+				Log:       log,
+				Scheme:    scheme,
+				Tracker:   tracker,
+			},
+			testSystemNamespace,
+		)
+	})
+}
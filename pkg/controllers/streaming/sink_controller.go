@@ -0,0 +1,380 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/projectriff/system/pkg/apis"
+	corev1alpha1 "github.com/projectriff/system/pkg/apis/core/v1alpha1"
+	knativev1alpha1 "github.com/projectriff/system/pkg/apis/knative/v1alpha1"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/refs"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+const (
+	SinkImagesStashKey           controllers.StashKey = "sink-images"
+	SinkInputStreamStashKey      controllers.StashKey = "sink-input-stream"
+	SinkDeadLetterStreamStashKey controllers.StashKey = "sink-dead-letter-stream"
+)
+
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=sinks,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=sinks/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streams,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.projectriff.io,resources=deployers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=knative.projectriff.io,resources=deployers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+// SinkTargetKinds are the deployer kinds served by the cluster. The core and
+// knative runtimes are installed independently of the streaming runtime, a sink
+// targeting a deployer kind that is not served is not ready.
+type SinkTargetKinds struct {
+	CoreDeployer    bool
+	KnativeDeployer bool
+}
+
+func SinkReconciler(c controllers.Config, namespace string, targets SinkTargetKinds) *controllers.ParentReconciler {
+	c.Log = c.Log.WithName("Sink")
+
+	return &controllers.ParentReconciler{
+		Type: &streamingv1alpha1.Sink{},
+		SubReconcilers: []controllers.SubReconciler{
+			SinkSyncImages(c, namespace),
+			SinkResolveStreamsReconciler(c),
+			SinkResolveTargetReconciler(c, targets),
+			SinkChildDeploymentReconciler(c),
+		},
+
+		Config: c,
+	}
+}
+
+func SinkSyncImages(c controllers.Config, namespace string) controllers.SubReconciler {
+	c.Log = c.Log.WithName("SyncImages")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.Sink) error {
+			config := corev1.ConfigMap{}
+			key := types.NamespacedName{Namespace: namespace, Name: sinkImages}
+			// track config for new images
+			c.Tracker.Track(
+				tracker.NewKey(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, key),
+				types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+			)
+			if err := c.Get(ctx, key, &config); err != nil {
+				return err
+			}
+			controllers.StashValue(ctx, SinkImagesStashKey, config.Data)
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &corev1.ConfigMap{}}, controllers.EnqueueTracked(&corev1.ConfigMap{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
+
+func SinkResolveStreamsReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ResolveStreams")
+
+	resolveStream := func(ctx context.Context, parent *streamingv1alpha1.Sink, name string) (*streamingv1alpha1.Stream, error) {
+		var stream streamingv1alpha1.Stream
+		key := types.NamespacedName{Namespace: parent.Namespace, Name: name}
+		// track stream for new coordinates
+		c.Tracker.Track(
+			tracker.NewKey(stream.GetGroupVersionKind(), key),
+			types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+		)
+		if err := c.Get(ctx, key, &stream); err != nil {
+			if apierrs.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return &stream, nil
+	}
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.Sink) error {
+			streams := []*streamingv1alpha1.Stream{}
+
+			input, err := resolveStream(ctx, parent, parent.Spec.Input)
+			if err != nil {
+				return err
+			}
+			if input == nil {
+				parent.Status.MarkStreamsNotReady(fmt.Sprintf("stream %s not found", parent.Spec.Input))
+				return nil
+			}
+			controllers.StashValue(ctx, SinkInputStreamStashKey, input)
+			streams = append(streams, input)
+
+			if parent.Spec.DeadLetter != "" {
+				deadLetter, err := resolveStream(ctx, parent, parent.Spec.DeadLetter)
+				if err != nil {
+					return err
+				}
+				if deadLetter == nil {
+					parent.Status.MarkStreamsNotReady(fmt.Sprintf("stream %s not found", parent.Spec.DeadLetter))
+					return nil
+				}
+				controllers.StashValue(ctx, SinkDeadLetterStreamStashKey, deadLetter)
+				streams = append(streams, deadLetter)
+			}
+
+			parent.Status.MarkStreamsReady()
+			for _, stream := range streams {
+				ready := stream.Status.GetCondition(stream.Status.GetReadyConditionType())
+				if ready == nil {
+					ready = &apis.Condition{Message: "stream has no ready condition"}
+				}
+				if !ready.IsTrue() {
+					parent.Status.MarkStreamsNotReady(fmt.Sprintf("stream %s is not ready: %s", stream.Name, ready.Message))
+					break
+				}
+			}
+
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &streamingv1alpha1.Stream{}}, controllers.EnqueueTracked(&streamingv1alpha1.Stream{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
+
+func SinkResolveTargetReconciler(c controllers.Config, targets SinkTargetKinds) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ResolveTarget")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.Sink) error {
+			target := parent.Spec.Target
+			parent.Status.TargetURL = ""
+
+			var address *apis.Addressable
+			switch {
+			case target.URL != "":
+				parent.Status.TargetURL = target.URL
+				parent.Status.MarkTargetReady()
+				return nil
+
+			case target.CoreDeployerRef != "" && !targets.CoreDeployer:
+				parent.Status.MarkTargetNotReady(fmt.Sprintf("deployer %s cannot be resolved, the core runtime is not installed", target.CoreDeployerRef))
+				return nil
+
+			case target.CoreDeployerRef != "":
+				var deployer corev1alpha1.Deployer
+				key := types.NamespacedName{Namespace: parent.Namespace, Name: target.CoreDeployerRef}
+				// track deployer for address changes
+				c.Tracker.Track(
+					tracker.NewKey(deployer.GetGroupVersionKind(), key),
+					types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+				)
+				if err := c.Get(ctx, key, &deployer); err != nil {
+					if apierrs.IsNotFound(err) {
+						parent.Status.MarkTargetNotReady(fmt.Sprintf("deployer %s not found", target.CoreDeployerRef))
+						return nil
+					}
+					return err
+				}
+				address = deployer.Status.Address
+
+			case target.KnativeDeployerRef != "" && !targets.KnativeDeployer:
+				parent.Status.MarkTargetNotReady(fmt.Sprintf("deployer %s cannot be resolved, the knative runtime is not installed", target.KnativeDeployerRef))
+				return nil
+
+			case target.KnativeDeployerRef != "":
+				var deployer knativev1alpha1.Deployer
+				key := types.NamespacedName{Namespace: parent.Namespace, Name: target.KnativeDeployerRef}
+				// track deployer for address changes
+				c.Tracker.Track(
+					tracker.NewKey(deployer.GetGroupVersionKind(), key),
+					types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+				)
+				if err := c.Get(ctx, key, &deployer); err != nil {
+					if apierrs.IsNotFound(err) {
+						parent.Status.MarkTargetNotReady(fmt.Sprintf("deployer %s not found", target.KnativeDeployerRef))
+						return nil
+					}
+					return err
+				}
+				address = deployer.Status.Address
+
+			default:
+				return fmt.Errorf("invalid target")
+			}
+
+			if address == nil || address.URL == "" {
+				parent.Status.MarkTargetNotReady("target has no address")
+				return nil
+			}
+			parent.Status.TargetURL = address.URL
+			parent.Status.MarkTargetReady()
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			// watching a kind that is not served fails the manager on start
+			if targets.CoreDeployer {
+				bldr.Watches(&source.Kind{Type: &corev1alpha1.Deployer{}}, controllers.EnqueueTracked(&corev1alpha1.Deployer{}, c.Tracker, c.Scheme))
+			}
+			if targets.KnativeDeployer {
+				bldr.Watches(&source.Kind{Type: &knativev1alpha1.Deployer{}}, controllers.EnqueueTracked(&knativev1alpha1.Deployer{}, c.Tracker, c.Scheme))
+			}
+			return nil
+		},
+	}
+}
+
+func SinkChildDeploymentReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildDeployment")
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.Sink{},
+		ChildType:     &appsv1.Deployment{},
+		ChildListType: &appsv1.DeploymentList{},
+
+		DesiredChild: func(ctx context.Context, parent *streamingv1alpha1.Sink) (*appsv1.Deployment, error) {
+			if parent.Status.TargetURL == "" {
+				// no target, skip
+				return nil, nil
+			}
+			input, ok := controllers.RetrieveValue(ctx, SinkInputStreamStashKey).(*streamingv1alpha1.Stream)
+			if !ok {
+				return nil, nil
+			}
+			outputs := []streamingv1alpha1.Stream{}
+			if parent.Spec.DeadLetter != "" {
+				deadLetter, ok := controllers.RetrieveValue(ctx, SinkDeadLetterStreamStashKey).(*streamingv1alpha1.Stream)
+				if !ok {
+					return nil, nil
+				}
+				outputs = append(outputs, *deadLetter)
+			}
+			images, ok := controllers.RetrieveValue(ctx, SinkImagesStashKey).(map[string]string)
+			if !ok {
+				return nil, nil
+			}
+			image := images[sinkImageKey]
+			if image == "" {
+				return nil, nil
+			}
+
+			labels := controllers.MergeMaps(parent.Labels, map[string]string{
+				streamingv1alpha1.SinkLabelKey: parent.Name,
+			})
+			volumes, volumeMounts := constructBindingVolumes([]streamingv1alpha1.Stream{*input}, outputs, streamBinding)
+			// changes to the target address roll the sink pods
+			env := []corev1.EnvVar{
+				{Name: "CNB_BINDINGS", Value: bindingsRootPath},
+				{Name: "INPUT_NAMES", Value: input.Name},
+				{Name: "OUTPUT_NAMES", Value: parent.Spec.DeadLetter},
+				{Name: "GROUP", Value: parent.Name},
+				{Name: "TARGET_URL", Value: parent.Status.TargetURL},
+			}
+			if retry := parent.Spec.Retry; retry != nil {
+				env = append(env, corev1.EnvVar{Name: "RETRY_MAX_ATTEMPTS", Value: fmt.Sprintf("%d", retry.MaxAttempts)})
+				if retry.InitialBackoff != nil {
+					env = append(env, corev1.EnvVar{Name: "RETRY_INITIAL_BACKOFF", Value: retry.InitialBackoff.Duration.String()})
+				}
+				if retry.MaxBackoff != nil {
+					env = append(env, corev1.EnvVar{Name: "RETRY_MAX_BACKOFF", Value: retry.MaxBackoff.Duration.String()})
+				}
+			}
+
+			child := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: fmt.Sprintf("%s-sink-", parent.Name),
+					Namespace:    parent.Namespace,
+					Labels:       labels,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							streamingv1alpha1.SinkLabelKey: parent.Name,
+						},
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: labels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name:         "sink",
+									Image:        image,
+									Env:          env,
+									VolumeMounts: volumeMounts,
+								},
+							},
+							Volumes: volumes,
+						},
+					},
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.Sink, child *appsv1.Deployment, err error) {
+			if err != nil {
+				return
+			}
+			if child == nil {
+				parent.Status.DeploymentRef = nil
+			} else {
+				parent.Status.DeploymentRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+				parent.Status.PropagateDeploymentStatus(&child.Status)
+			}
+		},
+		HarmonizeImmutableFields: func(current, desired *appsv1.Deployment) {
+			desired.Spec.Replicas = current.Spec.Replicas
+		},
+		MergeBeforeUpdate: func(current, desired *appsv1.Deployment) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *appsv1.Deployment) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.sinkDeploymentController",
+		Sanitize: func(child *appsv1.Deployment) interface{} {
+			return child.Spec
+		},
+	}
+}
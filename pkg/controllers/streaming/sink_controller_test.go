@@ -0,0 +1,679 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/projectriff/system/pkg/apis/core/v1alpha1"
+	knativev1alpha1 "github.com/projectriff/system/pkg/apis/knative/v1alpha1"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/controllers/streaming"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/controllers/testing/factories"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+func TestSinkReconciler(t *testing.T) {
+	testNamespace := "test-namespace"
+	testSystemNamespace := "system-namespace"
+	testName := "test-sink"
+	testKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
+	testSinkImage := "example.com/repo/sink"
+	testTargetURL := "http://example.com/target"
+	testDeployerURL := "http://my-deployer.test-namespace.svc.cluster.local"
+
+	sinkImages := "riff-streaming-sink" // contains image names for the sink
+	sinkImageKey := "sinkImage"
+
+	sinkConditionDeploymentReady := factories.Condition().Type(streamingv1alpha1.SinkConditionDeploymentReady)
+	sinkConditionReady := factories.Condition().Type(streamingv1alpha1.SinkConditionReady)
+	sinkConditionStreamsReady := factories.Condition().Type(streamingv1alpha1.SinkConditionStreamsReady)
+	sinkConditionTargetReady := factories.Condition().Type(streamingv1alpha1.SinkConditionTargetReady)
+	deploymentConditionAvailable := factories.Condition().Type("Available")
+	deploymentConditionProgressing := factories.Condition().Type("Progressing")
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = corev1alpha1.AddToScheme(scheme)
+	_ = knativev1alpha1.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	sinkImagesConfigMap := factories.ConfigMap().
+		NamespaceName(testSystemNamespace, sinkImages).
+		AddData(sinkImageKey, testSinkImage)
+
+	testInputStream := factories.Stream().
+		NamespaceName(testNamespace, "my-stream").
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.UID("00000000-0000-0000-0000-000000000001")
+		}).
+		StatusBinding("my-stream-binding-metadata", "my-stream-binding-secret").
+		StatusReady()
+	testDeadLetterStream := factories.Stream().
+		NamespaceName(testNamespace, "my-errors").
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.UID("00000000-0000-0000-0000-000000000002")
+		}).
+		StatusBinding("my-errors-binding-metadata", "my-errors-binding-secret").
+		StatusReady()
+
+	testCoreDeployer := factories.DeployerCore().
+		NamespaceName(testNamespace, "my-deployer").
+		StatusAddressURL(testDeployerURL)
+	testKnativeDeployer := factories.DeployerKnative().
+		NamespaceName(testNamespace, "my-deployer")
+
+	sinkMinimal := factories.Sink().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.Generation(1)
+		}).
+		Input(testInputStream.Create().Name).
+		TargetURL(testTargetURL).
+		Retry(3, time.Second, 30*time.Second)
+	sink := sinkMinimal.
+		StatusTargetURL(testTargetURL).
+		StatusDeploymentRef("%s-sink-000", testName)
+
+	inputVolumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "stream-00000000-0000-0000-0000-000000000001-metadata",
+			MountPath: "/var/riff/bindings/input_000/metadata",
+			ReadOnly:  true,
+		},
+		{
+			Name:      "stream-00000000-0000-0000-0000-000000000001-secret",
+			MountPath: "/var/riff/bindings/input_000/secret",
+			ReadOnly:  true,
+		},
+	}
+	inputVolumes := []corev1.Volume{
+		{
+			Name: "stream-00000000-0000-0000-0000-000000000001-metadata",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "my-stream-binding-metadata",
+					},
+				},
+			},
+		},
+		{
+			Name: "stream-00000000-0000-0000-0000-000000000001-secret",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: "my-stream-binding-secret",
+				},
+			},
+		},
+	}
+	deadLetterVolumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "stream-00000000-0000-0000-0000-000000000002-metadata",
+			MountPath: "/var/riff/bindings/output_000/metadata",
+			ReadOnly:  true,
+		},
+		{
+			Name:      "stream-00000000-0000-0000-0000-000000000002-secret",
+			MountPath: "/var/riff/bindings/output_000/secret",
+			ReadOnly:  true,
+		},
+	}
+	deadLetterVolumes := []corev1.Volume{
+		{
+			Name: "stream-00000000-0000-0000-0000-000000000002-metadata",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "my-errors-binding-metadata",
+					},
+				},
+			},
+		},
+		{
+			Name: "stream-00000000-0000-0000-0000-000000000002-secret",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: "my-errors-binding-secret",
+				},
+			},
+		},
+	}
+
+	deploymentCreate := factories.Deployment().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.GenerateName("%s-sink-", testName)
+			om.AddLabel(streamingv1alpha1.SinkLabelKey, testName)
+			om.ControlledBy(sink, scheme)
+		}).
+		AddSelectorLabel(streamingv1alpha1.SinkLabelKey, testName).
+		PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+			pts.ContainerNamed("sink", func(c *corev1.Container) {
+				c.Image = testSinkImage
+				c.Env = []corev1.EnvVar{
+					{Name: "CNB_BINDINGS", Value: "/var/riff/bindings"},
+					{Name: "INPUT_NAMES", Value: "my-stream"},
+					{Name: "OUTPUT_NAMES", Value: ""},
+					{Name: "GROUP", Value: testName},
+					{Name: "TARGET_URL", Value: testTargetURL},
+					{Name: "RETRY_MAX_ATTEMPTS", Value: "3"},
+					{Name: "RETRY_INITIAL_BACKOFF", Value: "1s"},
+					{Name: "RETRY_MAX_BACKOFF", Value: "30s"},
+				}
+				c.VolumeMounts = inputVolumeMounts
+			})
+			pts.Volumes(inputVolumes...)
+		})
+	deploymentGiven := deploymentCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Name("%s%s", om.Create().GenerateName, "000")
+			om.Created(1)
+		})
+
+	table := rtesting.Table{{
+		Name: "sink does not exist",
+		Key:  testKey,
+	}, {
+		Name: "ignore deleted sink",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			sink.
+				ObjectMeta(func(om factories.ObjectMeta) {
+					om.Deleted(1)
+				}),
+		},
+	}, {
+		Name: "error fetching sink",
+		Key:  testKey,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("get", "Sink"),
+		},
+		ShouldErr: true,
+	}, {
+		Name: "create deployment",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			sinkMinimal,
+			sinkImagesConfigMap,
+			testInputStream,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(sinkImagesConfigMap, sinkMinimal, scheme),
+			rtesting.NewTrackRequest(testInputStream, sinkMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(sinkMinimal, scheme, corev1.EventTypeNormal, "Created",
+				`Created Deployment "%s-sink-001"`, testName),
+			rtesting.NewEvent(sinkMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			deploymentCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			sinkMinimal.
+				StatusConditions(
+					sinkConditionDeploymentReady.Unknown(),
+					sinkConditionReady.Unknown(),
+					sinkConditionStreamsReady.True(),
+					sinkConditionTargetReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusTargetURL(testTargetURL).
+				StatusDeploymentRef("%s-sink-001", testName),
+		},
+	}, {
+		Name: "ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			sink.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					sinkConditionDeploymentReady.True(),
+					sinkConditionReady.True(),
+					sinkConditionStreamsReady.True(),
+					sinkConditionTargetReady.True(),
+				),
+			sinkImagesConfigMap,
+			testInputStream,
+			deploymentGiven.
+				StatusConditions(
+					deploymentConditionAvailable.True(),
+					deploymentConditionProgressing.True(),
+				),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(sinkImagesConfigMap, sink, scheme),
+			rtesting.NewTrackRequest(testInputStream, sink, scheme),
+		},
+	}, {
+		Name: "images missing",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			sinkMinimal,
+			testInputStream,
+		},
+		ShouldErr: true,
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(sinkImagesConfigMap, sinkMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(sinkMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			sinkMinimal.
+				StatusConditions(
+					sinkConditionDeploymentReady.Unknown(),
+					sinkConditionReady.Unknown(),
+					sinkConditionStreamsReady.Unknown(),
+					sinkConditionTargetReady.Unknown(),
+				),
+		},
+	}, {
+		Name: "input stream not found",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			sinkMinimal,
+			sinkImagesConfigMap,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(sinkImagesConfigMap, sinkMinimal, scheme),
+			rtesting.NewTrackRequest(testInputStream, sinkMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(sinkMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			sinkMinimal.
+				StatusConditions(
+					sinkConditionDeploymentReady.Unknown(),
+					sinkConditionReady.False().Reason("StreamNotReady", "stream my-stream not found"),
+					sinkConditionStreamsReady.False().Reason("StreamNotReady", "stream my-stream not found"),
+					sinkConditionTargetReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusTargetURL(testTargetURL),
+		},
+	}, {
+		Name: "dead letter stream not found",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			sinkMinimal.
+				DeadLetter(testDeadLetterStream.Create().Name),
+			sinkImagesConfigMap,
+			testInputStream,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(sinkImagesConfigMap, sinkMinimal, scheme),
+			rtesting.NewTrackRequest(testInputStream, sinkMinimal, scheme),
+			rtesting.NewTrackRequest(testDeadLetterStream, sinkMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(sinkMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			sinkMinimal.
+				DeadLetter(testDeadLetterStream.Create().Name).
+				StatusConditions(
+					sinkConditionDeploymentReady.Unknown(),
+					sinkConditionReady.False().Reason("StreamNotReady", "stream my-errors not found"),
+					sinkConditionStreamsReady.False().Reason("StreamNotReady", "stream my-errors not found"),
+					sinkConditionTargetReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusTargetURL(testTargetURL),
+		},
+	}, {
+		Name: "stream not ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			sink,
+			sinkImagesConfigMap,
+			testInputStream.
+				StatusConditions(),
+			deploymentGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(sinkImagesConfigMap, sink, scheme),
+			rtesting.NewTrackRequest(testInputStream, sink, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(sink, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			sink.
+				StatusConditions(
+					sinkConditionDeploymentReady.Unknown(),
+					sinkConditionReady.False().Reason("StreamNotReady", "stream my-stream is not ready: stream has no ready condition"),
+					sinkConditionStreamsReady.False().Reason("StreamNotReady", "stream my-stream is not ready: stream has no ready condition"),
+					sinkConditionTargetReady.True(),
+				).
+				StatusObservedGeneration(1),
+		},
+	}, {
+		Name: "create deployment, core deployer target with dead letter",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			sinkMinimal.
+				TargetCoreDeployerRef(testCoreDeployer.Create().Name).
+				DeadLetter(testDeadLetterStream.Create().Name),
+			sinkImagesConfigMap,
+			testInputStream,
+			testDeadLetterStream,
+			testCoreDeployer,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(sinkImagesConfigMap, sinkMinimal, scheme),
+			rtesting.NewTrackRequest(testInputStream, sinkMinimal, scheme),
+			rtesting.NewTrackRequest(testDeadLetterStream, sinkMinimal, scheme),
+			rtesting.NewTrackRequest(testCoreDeployer, sinkMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(sinkMinimal, scheme, corev1.EventTypeNormal, "Created",
+				`Created Deployment "%s-sink-001"`, testName),
+			rtesting.NewEvent(sinkMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			deploymentCreate.
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("sink", func(c *corev1.Container) {
+						c.Env[2].Value = "my-errors"
+						c.Env[4].Value = testDeployerURL
+						c.VolumeMounts = append(c.VolumeMounts, deadLetterVolumeMounts...)
+					})
+					pts.Volumes(append(inputVolumes, deadLetterVolumes...)...)
+				}),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			sinkMinimal.
+				TargetCoreDeployerRef(testCoreDeployer.Create().Name).
+				DeadLetter(testDeadLetterStream.Create().Name).
+				StatusConditions(
+					sinkConditionDeploymentReady.Unknown(),
+					sinkConditionReady.Unknown(),
+					sinkConditionStreamsReady.True(),
+					sinkConditionTargetReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusTargetURL(testDeployerURL).
+				StatusDeploymentRef("%s-sink-001", testName),
+		},
+	}, {
+		Name: "update deployment, target address changed",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			sink.
+				TargetCoreDeployerRef(testCoreDeployer.Create().Name),
+			sinkImagesConfigMap,
+			testInputStream,
+			testCoreDeployer,
+			deploymentGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(sinkImagesConfigMap, sink, scheme),
+			rtesting.NewTrackRequest(testInputStream, sink, scheme),
+			rtesting.NewTrackRequest(testCoreDeployer, sink, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(sink, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Deployment "%s-sink-000"`, testName),
+			rtesting.NewEvent(sink, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			deploymentGiven.
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("sink", func(c *corev1.Container) {
+						c.Env[4].Value = testDeployerURL
+					})
+				}),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			sink.
+				TargetCoreDeployerRef(testCoreDeployer.Create().Name).
+				StatusConditions(
+					sinkConditionDeploymentReady.Unknown(),
+					sinkConditionReady.Unknown(),
+					sinkConditionStreamsReady.True(),
+					sinkConditionTargetReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusTargetURL(testDeployerURL),
+		},
+	}, {
+		Name: "core deployer not found",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			sinkMinimal.
+				TargetCoreDeployerRef(testCoreDeployer.Create().Name),
+			sinkImagesConfigMap,
+			testInputStream,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(sinkImagesConfigMap, sinkMinimal, scheme),
+			rtesting.NewTrackRequest(testInputStream, sinkMinimal, scheme),
+			rtesting.NewTrackRequest(testCoreDeployer, sinkMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(sinkMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			sinkMinimal.
+				TargetCoreDeployerRef(testCoreDeployer.Create().Name).
+				StatusConditions(
+					sinkConditionDeploymentReady.Unknown(),
+					sinkConditionReady.False().Reason("TargetNotReady", "deployer my-deployer not found"),
+					sinkConditionStreamsReady.True(),
+					sinkConditionTargetReady.False().Reason("TargetNotReady", "deployer my-deployer not found"),
+				).
+				StatusObservedGeneration(1),
+		},
+	}, {
+		Name: "knative deployer without address, delete deployment",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			sink.
+				TargetKnativeDeployerRef(testKnativeDeployer.Create().Name),
+			sinkImagesConfigMap,
+			testInputStream,
+			testKnativeDeployer,
+			deploymentGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(sinkImagesConfigMap, sink, scheme),
+			rtesting.NewTrackRequest(testInputStream, sink, scheme),
+			rtesting.NewTrackRequest(testKnativeDeployer, sink, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(sink, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted Deployment "%s-sink-000"`, testName),
+			rtesting.NewEvent(sink, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectDeletes: []rtesting.DeleteRef{
+			{Group: "apps", Kind: "Deployment", Namespace: testNamespace, Name: fmt.Sprintf("%s-sink-000", testName)},
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			sinkMinimal.
+				TargetKnativeDeployerRef(testKnativeDeployer.Create().Name).
+				StatusConditions(
+					sinkConditionDeploymentReady.Unknown(),
+					sinkConditionReady.False().Reason("TargetNotReady", "target has no address"),
+					sinkConditionStreamsReady.True(),
+					sinkConditionTargetReady.False().Reason("TargetNotReady", "target has no address"),
+				).
+				StatusObservedGeneration(1),
+		},
+	}}
+
+	table.Test(t, scheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
+		return streaming.SinkReconciler(
+			controllers.Config{
+				Client:    client,
+				APIReader: apiReader,
+				Recorder:  recorder,
+				Log:       log,
+				Scheme:    scheme,
+				Tracker:   tracker,
+			},
+			testSystemNamespace,
+			streaming.SinkTargetKinds{CoreDeployer: true, KnativeDeployer: true},
+		)
+	})
+
+	t.Run("streaming runtime only", func(t *testing.T) {
+		// the core and knative runtimes are not installed, their kinds are unknown
+		streamingScheme := runtime.NewScheme()
+		_ = clientgoscheme.AddToScheme(streamingScheme)
+		_ = streamingv1alpha1.AddToScheme(streamingScheme)
+
+		table := rtesting.Table{{
+			Name: "create deployment",
+			Key:  testKey,
+			GivenObjects: []rtesting.Factory{
+				sinkMinimal,
+				sinkImagesConfigMap,
+				testInputStream,
+			},
+			ExpectTracks: []rtesting.TrackRequest{
+				rtesting.NewTrackRequest(sinkImagesConfigMap, sinkMinimal, streamingScheme),
+				rtesting.NewTrackRequest(testInputStream, sinkMinimal, streamingScheme),
+			},
+			ExpectEvents: []rtesting.Event{
+				rtesting.NewEvent(sinkMinimal, streamingScheme, corev1.EventTypeNormal, "Created",
+					`Created Deployment "%s-sink-001"`, testName),
+				rtesting.NewEvent(sinkMinimal, streamingScheme, corev1.EventTypeNormal, "StatusUpdated",
+					`Updated status`),
+			},
+			ExpectCreates: []rtesting.Factory{
+				deploymentCreate,
+			},
+			ExpectStatusUpdates: []rtesting.Factory{
+				sinkMinimal.
+					StatusConditions(
+						sinkConditionDeploymentReady.Unknown(),
+						sinkConditionReady.Unknown(),
+						sinkConditionStreamsReady.True(),
+						sinkConditionTargetReady.True(),
+					).
+					StatusObservedGeneration(1).
+					StatusTargetURL(testTargetURL).
+					StatusDeploymentRef("%s-sink-001", testName),
+			},
+		}, {
+			Name: "core deployer target",
+			Key:  testKey,
+			GivenObjects: []rtesting.Factory{
+				sinkMinimal.
+					TargetCoreDeployerRef(testCoreDeployer.Create().Name),
+				sinkImagesConfigMap,
+				testInputStream,
+			},
+			ExpectTracks: []rtesting.TrackRequest{
+				rtesting.NewTrackRequest(sinkImagesConfigMap, sinkMinimal, streamingScheme),
+				rtesting.NewTrackRequest(testInputStream, sinkMinimal, streamingScheme),
+			},
+			ExpectEvents: []rtesting.Event{
+				rtesting.NewEvent(sinkMinimal, streamingScheme, corev1.EventTypeNormal, "StatusUpdated",
+					`Updated status`),
+			},
+			ExpectStatusUpdates: []rtesting.Factory{
+				sinkMinimal.
+					TargetCoreDeployerRef(testCoreDeployer.Create().Name).
+					StatusConditions(
+						sinkConditionDeploymentReady.Unknown(),
+						sinkConditionReady.False().Reason("TargetNotReady", "deployer my-deployer cannot be resolved, the core runtime is not installed"),
+						sinkConditionStreamsReady.True(),
+						sinkConditionTargetReady.False().Reason("TargetNotReady", "deployer my-deployer cannot be resolved, the core runtime is not installed"),
+					).
+					StatusObservedGeneration(1),
+			},
+		}, {
+			Name: "knative deployer target, delete deployment",
+			Key:  testKey,
+			GivenObjects: []rtesting.Factory{
+				sink.
+					TargetKnativeDeployerRef(testKnativeDeployer.Create().Name),
+				sinkImagesConfigMap,
+				testInputStream,
+				deploymentGiven,
+			},
+			ExpectTracks: []rtesting.TrackRequest{
+				rtesting.NewTrackRequest(sinkImagesConfigMap, sink, streamingScheme),
+				rtesting.NewTrackRequest(testInputStream, sink, streamingScheme),
+			},
+			ExpectEvents: []rtesting.Event{
+				rtesting.NewEvent(sink, streamingScheme, corev1.EventTypeNormal, "Deleted",
+					`Deleted Deployment "%s-sink-000"`, testName),
+				rtesting.NewEvent(sink, streamingScheme, corev1.EventTypeNormal, "StatusUpdated",
+					`Updated status`),
+			},
+			ExpectDeletes: []rtesting.DeleteRef{
+				{Group: "apps", Kind: "Deployment", Namespace: testNamespace, Name: fmt.Sprintf("%s-sink-000", testName)},
+			},
+			ExpectStatusUpdates: []rtesting.Factory{
+				sinkMinimal.
+					TargetKnativeDeployerRef(testKnativeDeployer.Create().Name).
+					StatusConditions(
+						sinkConditionDeploymentReady.Unknown(),
+						sinkConditionReady.False().Reason("TargetNotReady", "deployer my-deployer cannot be resolved, the knative runtime is not installed"),
+						sinkConditionStreamsReady.True(),
+						sinkConditionTargetReady.False().Reason("TargetNotReady", "deployer my-deployer cannot be resolved, the knative runtime is not installed"),
+					).
+					StatusObservedGeneration(1),
+			},
+		}}
+
+		table.Test(t, streamingScheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
+			return streaming.SinkReconciler(
+				controllers.Config{
+					Client:    client,
+					APIReader: apiReader,
+					Recorder:  recorder,
+					Log:       log,
+					Scheme:    streamingScheme,
+					Tracker:   tracker,
+				},
+				testSystemNamespace,
+				streaming.SinkTargetKinds{},
+			)
+		})
+	})
+}
@@ -19,36 +19,68 @@ package streaming
 import (
 	"context"
 	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-
+	"k8s.io/apimachinery/pkg/util/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/projectriff/system/pkg/apis"
 	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
 	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/refs"
 	"github.com/projectriff/system/pkg/tracker"
 )
 
-const streamAddressStashKey controllers.StashKey = "stream-address"
+const (
+	streamAddressStashKey          controllers.StashKey = "stream-address"
+	streamMigrationAddressStashKey controllers.StashKey = "stream-migration-address"
+	streamMigrationImagesStashKey  controllers.StashKey = "stream-migration-images"
+)
+
+const (
+	processorInputStreamsIndexField  = ".spec.inputStreams"
+	processorOutputStreamsIndexField = ".spec.outputStreams"
+)
 
 // +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streams,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streams/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=processors,verbs=get;list;watch
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streamquotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 
-func StreamReconciler(c controllers.Config, provisioner StreamProvisionerClient) *controllers.ParentReconciler {
+func StreamReconciler(c controllers.Config, namespace string, provisioner StreamProvisionerClient, drainInterval time.Duration, clock clock.Clock) *controllers.ParentReconciler {
 	c.Log = c.Log.WithName("Stream")
 
 	return &controllers.ParentReconciler{
 		Type: &streamingv1alpha1.Stream{},
 		SubReconcilers: []controllers.SubReconciler{
 			StreamProvisionReconciler(c, provisioner),
+			StreamMigrationReconciler(c, namespace, provisioner, drainInterval, clock),
+			StreamChildMigrationJobReconciler(c),
+			StreamChildMigrationFinalJobReconciler(c),
 			StreamChildBindingMetadataReconciler(c),
 			StreamChildBindingSecretReconciler(c),
 			StreamSyncBindingCondition(c),
+			StreamTopologyReconciler(c),
+			StreamQuotaUsageReconciler(c),
 		},
 
 		Config: c,
@@ -58,47 +90,487 @@ func StreamReconciler(c controllers.Config, provisioner StreamProvisionerClient)
 func StreamProvisionReconciler(c controllers.Config, provisioner StreamProvisionerClient) controllers.SubReconciler {
 	c.Log = c.Log.WithName("Provision")
 
+	// provision delegates to the provisioner of the gateway via its REST API.
+	// A message is returned when the gateway is not able to provision the
+	// stream.
+	provision := func(ctx context.Context, stream *streamingv1alpha1.Stream, gatewayName string) (*StreamAddress, string, error) {
+		var gateway streamingv1alpha1.Gateway
+		gatewayKey := types.NamespacedName{Namespace: stream.Namespace, Name: gatewayName}
+		c.Tracker.Track(
+			tracker.NewKey(gateway.GetGroupVersionKind(), gatewayKey),
+			types.NamespacedName{Namespace: stream.Namespace, Name: stream.Name},
+		)
+		if err := c.Get(ctx, gatewayKey, &gateway); err != nil {
+			if apierrs.IsNotFound(err) {
+				return nil, fmt.Sprintf("Gateway %q not found", gatewayKey.Name), nil
+			}
+			return nil, err.Error(), err
+		}
+		if gateway.Status.Address == nil || !gateway.Status.IsReady() {
+			return nil, fmt.Sprintf("Gateway %q not ready", gatewayKey.Name), nil
+		}
+		provisionerURL, err := streamProvisionerURL(&gateway, types.NamespacedName{Namespace: stream.Namespace, Name: stream.Name})
+		if err != nil {
+			return nil, "", err
+		}
+		address, err := provisioner.ProvisionStream(stream, provisionerURL)
+		if err != nil {
+			return nil, err.Error(), err
+		}
+		return address, "", nil
+	}
+
 	return &controllers.SyncReconciler{
 		Sync: func(ctx context.Context, stream *streamingv1alpha1.Stream) error {
-			// delegate to the provisioner via its REST API
-			var provisionerURL string
-
-			var gateway streamingv1alpha1.Gateway
-			gatewayKey := types.NamespacedName{Namespace: stream.Namespace, Name: stream.Spec.Gateway.Name}
-			c.Tracker.Track(
-				tracker.NewKey(gateway.GetGroupVersionKind(), gatewayKey),
-				types.NamespacedName{Namespace: stream.Namespace, Name: stream.Name},
-			)
-			if err := c.Get(ctx, gatewayKey, &gateway); err != nil {
-				if apierrs.IsNotFound(err) {
-					stream.Status.MarkStreamProvisionFailed(fmt.Sprintf("Gateway %q not found", gatewayKey.Name))
-					return nil
-				}
-				stream.Status.MarkStreamProvisionFailed(err.Error())
+			// the binding addresses the bound gateway until a migration to
+			// the gateway in the spec completes
+			bound := stream.Status.Gateway
+			if bound == "" {
+				stream.Status.Phase = streamingv1alpha1.StreamPhaseProvisioning
+				bound = stream.Spec.Gateway.Name
+			}
+			address, message, err := provision(ctx, stream, bound)
+			if message != "" {
+				stream.Status.MarkStreamProvisionFailed(message)
+			}
+			if address == nil {
 				return err
 			}
-			if gateway.Status.Address == nil || !gateway.Status.IsReady() {
-				stream.Status.MarkStreamProvisionFailed(fmt.Sprintf("Gateway %q not ready", gatewayKey.Name))
+			// stash for later child reconcilers
+			controllers.StashValue(ctx, streamAddressStashKey, *address)
+			stream.Status.MarkStreamProvisioned()
+			stream.Status.PropagatePartitions(stream.Spec.Partitions, address.Partitions)
+			stream.Status.Gateway = bound
+
+			target := stream.Spec.Gateway.Name
+			if bound == target {
+				if stream.Status.Migration != nil {
+					c.Log.Info("migration cancelled", "stream", stream.Name, "gateway", stream.Status.Migration.To)
+				}
+				stream.Status.Migration = nil
+				stream.Status.Phase = streamingv1alpha1.StreamPhaseActive
 				return nil
 			}
-			url, err := gateway.Status.Address.Parse()
-			if err != nil {
+
+			migration := stream.Status.Migration
+			if migration == nil || migration.To != target {
+				c.Log.Info("migrating stream", "stream", stream.Name, "from", bound, "to", target)
+				migration = &streamingv1alpha1.StreamMigration{
+					From:       bound,
+					To:         target,
+					Generation: stream.Generation,
+				}
+				stream.Status.Migration = migration
+			}
+			stream.Status.Phase = streamingv1alpha1.StreamPhaseMigrating
+			targetAddress, message, err := provision(ctx, stream, target)
+			// the stream remains ready on the bound gateway
+			migration.Message = message
+			if targetAddress == nil {
 				return err
 			}
-			provisionerURL = fmt.Sprintf("http://%s/%s/%s", url.Hostname(), stream.Namespace, stream.Name)
+			controllers.StashValue(ctx, streamMigrationAddressStashKey, *targetAddress)
+			return nil
+		},
 
-			address, err := provisioner.ProvisionStream(stream, provisionerURL)
+		Config: c,
+	}
+}
+
+func StreamMigrationReconciler(c controllers.Config, namespace string, provisioner StreamProvisionerClient, drainInterval time.Duration, clock clock.Clock) controllers.SubReconciler {
+	c.Log = c.Log.WithName("Migration")
+
+	// undrainedConsumers returns the names of the processors that have not
+	// consumed every message on the bound gateway, as determined by drained
+	undrainedConsumers := func(ctx context.Context, stream *streamingv1alpha1.Stream, drained func(*streamingv1alpha1.Processor) (bool, error)) ([]string, error) {
+		key := types.NamespacedName{Namespace: stream.Namespace, Name: stream.Name}
+		var processors streamingv1alpha1.ProcessorList
+		if err := c.List(ctx, &processors, client.MatchingField(processorInputStreamsIndexField, key.String())); err != nil {
+			return nil, err
+		}
+		consumers := processorNames(stream, processors.Items, processorInputStreams)
+		isDrained := map[string]bool{}
+		for i := range processors.Items {
+			processor := &processors.Items[i]
+			ok, err := drained(processor)
 			if err != nil {
-				stream.Status.MarkStreamProvisionFailed(err.Error())
-				return err
+				return nil, err
 			}
-			// stash for later child reconcilers
-			controllers.StashValue(ctx, streamAddressStashKey, *address)
-			stream.Status.MarkStreamProvisioned()
-			return nil
+			if !ok {
+				continue
+			}
+			name := processor.Name
+			if processor.Namespace != stream.Namespace {
+				name = fmt.Sprintf("%s/%s", processor.Namespace, processor.Name)
+			}
+			isDrained[name] = true
+		}
+		pending := []string{}
+		for _, consumer := range consumers {
+			if !isDrained[consumer] {
+				pending = append(pending, consumer)
+			}
+		}
+		return pending, nil
+	}
+
+	// reportedDrained is true when the processor last reported no lag on the
+	// stream
+	reportedDrained := func(stream *streamingv1alpha1.Stream) func(*streamingv1alpha1.Processor) (bool, error) {
+		key := types.NamespacedName{Namespace: stream.Namespace, Name: stream.Name}
+		return func(processor *streamingv1alpha1.Processor) (bool, error) {
+			for _, lag := range processor.Status.InputLags {
+				if streamingv1alpha1.StreamNamespacedName(processor.Namespace, lag.Stream) == key && lag.Lag == 0 {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+	}
+
+	// observedDrained queries the bound gateway for the lag of the
+	// processor's consumer group on the stream
+	observedDrained := func(ctx context.Context, stream *streamingv1alpha1.Stream) (func(*streamingv1alpha1.Processor) (bool, error), error) {
+		key := types.NamespacedName{Namespace: stream.Namespace, Name: stream.Name}
+		var gateway streamingv1alpha1.Gateway
+		if err := c.Get(ctx, types.NamespacedName{Namespace: stream.Namespace, Name: stream.Status.Gateway}, &gateway); err != nil {
+			return nil, err
+		}
+		provisionerURL, err := streamProvisionerURL(&gateway, key)
+		if err != nil {
+			return nil, err
+		}
+		return func(processor *streamingv1alpha1.Processor) (bool, error) {
+			if processor.Status.ConsumerGroup == "" {
+				return false, nil
+			}
+			lag, err := provisioner.ConsumerLag(ctx, provisionerURL, processor.Status.ConsumerGroup)
+			if err != nil {
+				return false, err
+			}
+			return lag.Total() == 0, nil
+		}, nil
+	}
+
+	// stashMirrorImages stashes the images for the jobs copying messages to
+	// the new gateway
+	stashMirrorImages := func(ctx context.Context, stream *streamingv1alpha1.Stream) error {
+		config := corev1.ConfigMap{}
+		key := types.NamespacedName{Namespace: namespace, Name: streamMirrorImages}
+		// track config for new images
+		c.Tracker.Track(
+			tracker.NewKey(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, key),
+			types.NamespacedName{Namespace: stream.Namespace, Name: stream.Name},
+		)
+		if err := c.Get(ctx, key, &config); err != nil {
+			return err
+		}
+		controllers.StashValue(ctx, streamMigrationImagesStashKey, config.Data)
+		return nil
+	}
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, stream *streamingv1alpha1.Stream) (ctrl.Result, error) {
+			migration := stream.Status.Migration
+			if migration == nil {
+				return ctrl.Result{}, nil
+			}
+			targetAddress, ok := controllers.RetrieveValue(ctx, streamMigrationAddressStashKey).(StreamAddress)
+			if !ok {
+				// not yet provisioned on the new gateway
+				return ctrl.Result{}, nil
+			}
+			policy := stream.Spec.Migration
+			if policy == nil {
+				policy = &streamingv1alpha1.StreamMigrationPolicy{}
+			}
+
+			if policy.CopyData && migration.CopyCompletionTime == nil {
+				stream.Status.Phase = streamingv1alpha1.StreamPhaseCopying
+				return ctrl.Result{}, stashMirrorImages(ctx, stream)
+			}
+
+			if (policy.Drain == nil || *policy.Drain) && !migration.DrainConfirmed {
+				pending, err := undrainedConsumers(ctx, stream, reportedDrained(stream))
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				stream.Status.Phase = streamingv1alpha1.StreamPhaseDraining
+				if len(pending) != 0 {
+					migration.DrainedTime = nil
+					migration.Message = fmt.Sprintf("waiting for consumers to drain: %s", strings.Join(pending, ", "))
+					return ctrl.Result{}, nil
+				}
+				// the lag of a consumer is polled from the gateway, a lag of
+				// zero must be observed by the next poll before the drain is
+				// confirmed
+				now := clock.Now()
+				if migration.DrainedTime == nil {
+					drainedTime := metav1.NewTime(now)
+					migration.DrainedTime = &drainedTime
+				}
+				if remaining := migration.DrainedTime.Add(drainInterval).Sub(now); remaining > 0 {
+					migration.Message = "confirming consumers are drained"
+					return ctrl.Result{RequeueAfter: remaining}, nil
+				}
+				// the reported lag may predate the drained time, confirm with
+				// a lag queried from the gateway now
+				drained, err := observedDrained(ctx, stream)
+				if err == nil {
+					pending, err = undrainedConsumers(ctx, stream, drained)
+				}
+				if err != nil {
+					migration.Message = fmt.Sprintf("unable to confirm consumers are drained: %s", err)
+					return ctrl.Result{RequeueAfter: drainInterval}, nil
+				}
+				if len(pending) != 0 {
+					migration.DrainedTime = nil
+					migration.Message = fmt.Sprintf("waiting for consumers to drain: %s", strings.Join(pending, ", "))
+					return ctrl.Result{RequeueAfter: drainInterval}, nil
+				}
+				migration.DrainConfirmed = true
+			}
+
+			if policy.CopyData && migration.FinalCopyCompletionTime == nil {
+				// copy messages written to the previous gateway since the
+				// initial copy reached the head of the stream
+				stream.Status.Phase = streamingv1alpha1.StreamPhaseFinalizing
+				return ctrl.Result{}, stashMirrorImages(ctx, stream)
+			}
+
+			// repoint the binding to the new gateway
+			c.Log.Info("migrated stream", "stream", stream.Name, "from", migration.From, "to", migration.To)
+			c.Recorder.Eventf(stream, corev1.EventTypeNormal, "Migrated",
+				"Migrated from Gateway %q to %q", migration.From, migration.To)
+			controllers.StashValue(ctx, streamAddressStashKey, targetAddress)
+			stream.Status.Gateway = migration.To
+			stream.Status.Migration = nil
+			stream.Status.Phase = streamingv1alpha1.StreamPhaseActive
+			return ctrl.Result{}, nil
 		},
 
 		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &corev1.ConfigMap{}}, controllers.EnqueueTracked(&corev1.ConfigMap{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
+
+func StreamChildMigrationJobReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildMigrationJob")
+
+	return streamChildMigrationJobReconciler(c,
+		streamingv1alpha1.StreamPhaseCopying,
+		streamingv1alpha1.StreamMigrationCopyInitial,
+		".metadata.streamMigrationJobController",
+		func(migration *streamingv1alpha1.StreamMigration, completionTime *metav1.Time) {
+			migration.CopyCompletionTime = completionTime
+		},
+	)
+}
+
+func StreamChildMigrationFinalJobReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildMigrationFinalJob")
+
+	return streamChildMigrationJobReconciler(c,
+		streamingv1alpha1.StreamPhaseFinalizing,
+		streamingv1alpha1.StreamMigrationCopyFinal,
+		".metadata.streamMigrationFinalJobController",
+		func(migration *streamingv1alpha1.StreamMigration, completionTime *metav1.Time) {
+			migration.FinalCopyCompletionTime = completionTime
+		},
+	)
+}
+
+// streamChildMigrationJobReconciler runs a job copying messages to the new
+// gateway while the stream is in the phase. The initial and final copies
+// share a consumer group, the final copy resumes from the offset the initial
+// copy reached.
+func streamChildMigrationJobReconciler(c controllers.Config, phase streamingv1alpha1.StreamPhase, copyLabel, indexField string, complete func(*streamingv1alpha1.StreamMigration, *metav1.Time)) controllers.SubReconciler {
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.Stream{},
+		ChildType:     &batchv1.Job{},
+		ChildListType: &batchv1.JobList{},
+
+		DesiredChild: func(ctx context.Context, parent *streamingv1alpha1.Stream) (*batchv1.Job, error) {
+			if parent.Status.Migration == nil || parent.Status.Phase != phase {
+				return nil, nil
+			}
+			images, ok := controllers.RetrieveValue(ctx, streamMigrationImagesStashKey).(map[string]string)
+			if !ok {
+				return nil, nil
+			}
+			image := images[streamMirrorImageKey]
+			if image == "" {
+				return nil, nil
+			}
+			metadata, secret := streamBinding(*parent)
+			if metadata == "" || secret == "" {
+				return nil, nil
+			}
+
+			labels := controllers.MergeMaps(parent.Labels, map[string]string{
+				streamingv1alpha1.StreamLabelKey:              parent.Name,
+				streamingv1alpha1.StreamMigrationCopyLabelKey: copyLabel,
+			})
+			child := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: fmt.Sprintf("%s-migration-", parent.Name),
+					Namespace:    parent.Namespace,
+					Labels:       labels,
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: labels,
+						},
+						Spec: streamMigrationPodSpec(parent, image, metadata, secret),
+					},
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.Stream, child *batchv1.Job, err error) {
+			migration := parent.Status.Migration
+			if err != nil || migration == nil {
+				return
+			}
+			if child == nil {
+				// the job of the other copy is referenced while it runs
+				if parent.Status.Phase == phase || !streamMigrationCopyPhase(parent.Status.Phase) {
+					migration.JobRef = nil
+				}
+				return
+			}
+			migration.JobRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+			migration.Message = fmt.Sprintf("copying messages to Gateway %q", migration.To)
+			for _, condition := range child.Status.Conditions {
+				if condition.Status != corev1.ConditionTrue {
+					continue
+				}
+				switch condition.Type {
+				case batchv1.JobComplete:
+					completionTime := child.Status.CompletionTime
+					if completionTime == nil {
+						completionTime = &condition.LastTransitionTime
+					}
+					complete(migration, completionTime.DeepCopy())
+					migration.Message = ""
+				case batchv1.JobFailed:
+					migration.Message = fmt.Sprintf("copying messages failed: %s", condition.Message)
+				}
+			}
+		},
+		MergeBeforeUpdate: func(current, desired *batchv1.Job) {
+			current.Labels = desired.Labels
+		},
+		// the pod template of a job is immutable, a job that was created is
+		// left to run to completion
+		SemanticEquals: func(a1, a2 *batchv1.Job) bool {
+			return equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+		OurChild: func(child *batchv1.Job) bool {
+			// jobs created before the final copy existed are initial copies
+			if copyLabel == streamingv1alpha1.StreamMigrationCopyFinal {
+				return child.Labels[streamingv1alpha1.StreamMigrationCopyLabelKey] == copyLabel
+			}
+			return child.Labels[streamingv1alpha1.StreamMigrationCopyLabelKey] != streamingv1alpha1.StreamMigrationCopyFinal
+		},
+
+		Config:     c,
+		IndexField: indexField,
+		Sanitize: func(child *batchv1.Job) interface{} {
+			return child.Spec
+		},
+	}
+}
+
+// streamMigrationCopyPhase is true for phases in which a job copies messages
+// to the new gateway
+func streamMigrationCopyPhase(phase streamingv1alpha1.StreamPhase) bool {
+	return phase == streamingv1alpha1.StreamPhaseCopying || phase == streamingv1alpha1.StreamPhaseFinalizing
+}
+
+// streamMigrationPodSpec copies the messages on the bound gateway to the new
+// gateway. Both ends are bound from the stream's binding, the address of the
+// new gateway is held in the binding secret while the stream migrates.
+func streamMigrationPodSpec(stream *streamingv1alpha1.Stream, image, metadata, secret string) corev1.PodSpec {
+	return corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name:  "mirror",
+				Image: image,
+				Env: []corev1.EnvVar{
+					{Name: "CNB_BINDINGS", Value: bindingsRootPath},
+					{Name: "INPUT_NAMES", Value: stream.Name},
+					{Name: "INPUT_START_OFFSETS", Value: streamingv1alpha1.Earliest},
+					{Name: "OUTPUT_NAMES", Value: stream.Name},
+					{Name: "GROUP", Value: fmt.Sprintf("%s-migration-%s-%d", stream.Name, stream.Status.Migration.To, stream.Status.Migration.Generation)},
+					{Name: "STOP_AT_HEAD", Value: "true"},
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      "binding-metadata",
+						MountPath: fmt.Sprintf("%s/input_000/metadata", bindingsRootPath),
+						ReadOnly:  true,
+					},
+					{
+						Name:      "binding-secret-from",
+						MountPath: fmt.Sprintf("%s/input_000/secret", bindingsRootPath),
+						ReadOnly:  true,
+					},
+					{
+						Name:      "binding-metadata",
+						MountPath: fmt.Sprintf("%s/output_000/metadata", bindingsRootPath),
+						ReadOnly:  true,
+					},
+					{
+						Name:      "binding-secret-to",
+						MountPath: fmt.Sprintf("%s/output_000/secret", bindingsRootPath),
+						ReadOnly:  true,
+					},
+				},
+			},
+		},
+		RestartPolicy: corev1.RestartPolicyOnFailure,
+		Volumes: []corev1.Volume{
+			{
+				Name: "binding-metadata",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: metadata,
+						},
+					},
+				},
+			},
+			{
+				Name: "binding-secret-from",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: secret,
+						Items: []corev1.KeyToPath{
+							{Key: "gateway", Path: "gateway"},
+							{Key: "topic", Path: "topic"},
+						},
+					},
+				},
+			},
+			{
+				Name: "binding-secret-to",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: secret,
+						Items: []corev1.KeyToPath{
+							{Key: "migrationGateway", Path: "gateway"},
+							{Key: "migrationTopic", Path: "topic"},
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -115,6 +587,10 @@ func StreamChildBindingMetadataReconciler(c controllers.Config) controllers.SubR
 			if !ok {
 				return nil, nil
 			}
+			gateway, err := streamBoundGateway(ctx, c, parent)
+			if err != nil {
+				return nil, err
+			}
 
 			child := &corev1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
@@ -135,6 +611,14 @@ func StreamChildBindingMetadataReconciler(c controllers.Config) controllers.SubR
 					"contentType": parent.Spec.ContentType,
 				},
 			}
+			if gateway != nil && gateway.Status.PublicAddress != nil {
+				// producers outside the cluster publish to the public address
+				publicGateway, err := publicGatewayAddress(gateway.Status.PublicAddress)
+				if err != nil {
+					return nil, err
+				}
+				child.Data["publicGateway"] = publicGateway
+			}
 
 			return child, nil
 		},
@@ -199,6 +683,30 @@ func StreamChildBindingSecretReconciler(c controllers.Config) controllers.SubRec
 					"topic":   []byte(address.Topic),
 				},
 			}
+			if migrationAddress, ok := controllers.RetrieveValue(ctx, streamMigrationAddressStashKey).(StreamAddress); ok && parent.Status.Migration != nil {
+				// the new gateway is addressed by the migration job
+				child.Data["migrationGateway"] = []byte(migrationAddress.Gateway)
+				child.Data["migrationTopic"] = []byte(migrationAddress.Topic)
+			}
+
+			gateway, err := streamBoundGateway(ctx, c, parent)
+			if err != nil {
+				return nil, err
+			}
+			if gateway != nil && gateway.Spec.Exposure != nil && gateway.Spec.Exposure.AuthSecretRef != nil {
+				// clients of the stream authenticate to the gateway with its token
+				var secret corev1.Secret
+				key := types.NamespacedName{Namespace: gateway.Namespace, Name: gateway.Spec.Exposure.AuthSecretRef.Name}
+				// track the secret for a rotated token
+				c.Tracker.Track(
+					tracker.NewKey(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, key),
+					types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+				)
+				if err := c.Get(ctx, key, &secret); err != nil {
+					return nil, err
+				}
+				child.Data["authToken"] = secret.Data[streamingv1alpha1.GatewayAuthTokenKey]
+			}
 
 			return child, nil
 		},
@@ -227,7 +735,11 @@ func StreamChildBindingSecretReconciler(c controllers.Config) controllers.SubRec
 				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
 		},
 
-		Config:     c,
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &corev1.Secret{}}, controllers.EnqueueTracked(&corev1.Secret{}, c.Tracker, c.Scheme))
+			return nil
+		},
 		IndexField: ".metadata.bindingSecretController",
 		Sanitize: func(child *corev1.Secret) interface{} {
 			return child.Name
@@ -235,6 +747,41 @@ func StreamChildBindingSecretReconciler(c controllers.Config) controllers.SubRec
 	}
 }
 
+// streamBoundGateway gets the gateway the stream's binding addresses, nil when
+// the stream is not yet bound or the gateway is not found. The provision
+// reconciler tracks the gateway.
+func streamBoundGateway(ctx context.Context, c controllers.Config, stream *streamingv1alpha1.Stream) (*streamingv1alpha1.Gateway, error) {
+	if stream.Status.Gateway == "" {
+		return nil, nil
+	}
+	var gateway streamingv1alpha1.Gateway
+	key := types.NamespacedName{Namespace: stream.Namespace, Name: stream.Status.Gateway}
+	if err := c.Get(ctx, key, &gateway); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &gateway, nil
+}
+
+// publicGatewayAddress renders the public address of a gateway as the host
+// and port producers outside the cluster dial
+func publicGatewayAddress(address *apis.Addressable) (string, error) {
+	u, err := address.Parse()
+	if err != nil {
+		return "", err
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
+
 func StreamSyncBindingCondition(c controllers.Config) controllers.SubReconciler {
 	c.Log = c.Log.WithName("BindingCondition")
 
@@ -253,3 +800,159 @@ func StreamSyncBindingCondition(c controllers.Config) controllers.SubReconciler
 		Config: c,
 	}
 }
+
+func StreamTopologyReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("Topology")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, stream *streamingv1alpha1.Stream) error {
+			key := types.NamespacedName{Namespace: stream.Namespace, Name: stream.Name}
+
+			var consumers streamingv1alpha1.ProcessorList
+			if err := c.List(ctx, &consumers, client.MatchingField(processorInputStreamsIndexField, key.String())); err != nil {
+				return err
+			}
+			var producers streamingv1alpha1.ProcessorList
+			if err := c.List(ctx, &producers, client.MatchingField(processorOutputStreamsIndexField, key.String())); err != nil {
+				return err
+			}
+
+			stream.Status.Consumers = processorNames(stream, consumers.Items, processorInputStreams)
+			stream.Status.Producers = processorNames(stream, producers.Items, processorOutputStreams)
+
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			if err := mgr.GetFieldIndexer().IndexField(&streamingv1alpha1.Processor{}, processorInputStreamsIndexField, func(obj runtime.Object) []string {
+				return processorStreamKeys(obj.(*streamingv1alpha1.Processor), processorInputStreams)
+			}); err != nil {
+				return err
+			}
+			if err := mgr.GetFieldIndexer().IndexField(&streamingv1alpha1.Processor{}, processorOutputStreamsIndexField, func(obj runtime.Object) []string {
+				return processorStreamKeys(obj.(*streamingv1alpha1.Processor), processorOutputStreams)
+			}); err != nil {
+				return err
+			}
+			// processors reconcile each stream they read from or write to
+			bldr.Watches(&source.Kind{Type: &streamingv1alpha1.Processor{}}, &handler.EnqueueRequestsFromMapFunc{
+				ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+					processor, ok := a.Object.(*streamingv1alpha1.Processor)
+					if !ok {
+						return nil
+					}
+					var requests []reconcile.Request
+					for _, key := range processorStreamKeys(processor, processorInputStreams) {
+						requests = append(requests, reconcile.Request{NamespacedName: parseNamespacedName(key)})
+					}
+					for _, key := range processorStreamKeys(processor, processorOutputStreams) {
+						requests = append(requests, reconcile.Request{NamespacedName: parseNamespacedName(key)})
+					}
+					return requests
+				}),
+			})
+			return nil
+		},
+	}
+}
+
+func StreamQuotaUsageReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("QuotaUsage")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, stream *streamingv1alpha1.Stream) error {
+			var quotas streamingv1alpha1.StreamQuotaList
+			if err := c.List(ctx, &quotas, client.InNamespace(stream.Namespace)); err != nil {
+				return err
+			}
+			sort.Slice(quotas.Items, func(i, j int) bool {
+				return quotas.Items[i].Name < quotas.Items[j].Name
+			})
+
+			stream.Status.Quotas = nil
+			for _, quota := range quotas.Items {
+				stream.Status.Quotas = append(stream.Status.Quotas, quota.Usage())
+			}
+
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			// quotas reconcile each stream in their namespace
+			bldr.Watches(&source.Kind{Type: &streamingv1alpha1.StreamQuota{}}, &handler.EnqueueRequestsFromMapFunc{
+				ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+					var streams streamingv1alpha1.StreamList
+					if err := c.List(context.Background(), &streams, client.InNamespace(a.Meta.GetNamespace())); err != nil {
+						c.Log.Error(err, "unable to list streams", "namespace", a.Meta.GetNamespace())
+						return nil
+					}
+					requests := make([]reconcile.Request, len(streams.Items))
+					for i, stream := range streams.Items {
+						requests[i] = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: stream.Namespace, Name: stream.Name}}
+					}
+					return requests
+				}),
+			})
+			return nil
+		},
+	}
+}
+
+func processorInputStreams(processor *streamingv1alpha1.Processor) []string {
+	streams := make([]string, len(processor.Spec.Inputs))
+	for i, input := range processor.Spec.Inputs {
+		streams[i] = input.Stream
+	}
+	return streams
+}
+
+func processorOutputStreams(processor *streamingv1alpha1.Processor) []string {
+	streams := make([]string, len(processor.Spec.Outputs))
+	for i, output := range processor.Spec.Outputs {
+		streams[i] = output.Stream
+	}
+	return streams
+}
+
+// processorStreamKeys resolves the stream references of a processor into
+// `namespace/name` keys
+func processorStreamKeys(processor *streamingv1alpha1.Processor, streams func(*streamingv1alpha1.Processor) []string) []string {
+	keys := []string{}
+	for _, stream := range streams(processor) {
+		keys = append(keys, streamingv1alpha1.StreamNamespacedName(processor.Namespace, stream).String())
+	}
+	return keys
+}
+
+// processorNames returns the sorted names of the processors that reference
+// the stream, qualified by namespace when in another namespace
+func processorNames(stream *streamingv1alpha1.Stream, processors []streamingv1alpha1.Processor, streams func(*streamingv1alpha1.Processor) []string) []string {
+	key := types.NamespacedName{Namespace: stream.Namespace, Name: stream.Name}.String()
+	names := []string{}
+	for i := range processors {
+		processor := &processors[i]
+		for _, k := range processorStreamKeys(processor, streams) {
+			if k != key {
+				continue
+			}
+			name := processor.Name
+			if processor.Namespace != stream.Namespace {
+				name = fmt.Sprintf("%s/%s", processor.Namespace, processor.Name)
+			}
+			names = append(names, name)
+			break
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseNamespacedName(key string) types.NamespacedName {
+	parts := strings.SplitN(key, "/", 2)
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+}
@@ -19,6 +19,7 @@ package streaming_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/mock"
@@ -27,8 +28,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -45,9 +48,14 @@ func TestStreamReconciler(t *testing.T) {
 	var streamProvisioner *streaming.MockStreamProvisionerClient
 
 	testNamespace := "test-namespace"
+	testSystemNamespace := "system-namespace"
 	testName := "test-stream"
 	testKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
 	testGateway := "test-gateway"
+	testNewGateway := "new-gateway"
+	testMirrorImage := "example.com/repo/mirror"
+	testNow := time.Unix(100, 0)
+	testDrainInterval := 30 * time.Second
 	testBindingMetadata := fmt.Sprintf("%s-stream-binding-metadata", testName)
 	testBindingSecret := fmt.Sprintf("%s-stream-binding-secret", testName)
 	testProvisionerHost := fmt.Sprintf("%s.%s.svc.cluster.local", testGateway, testNamespace)
@@ -55,10 +63,18 @@ func TestStreamReconciler(t *testing.T) {
 	testAddressGateway := fmt.Sprintf("%s:6565", testProvisionerHost)
 	testAddressTopic := fmt.Sprintf("%s/%s", testNamespace, testName)
 	testAddress := &streaming.StreamAddress{Gateway: testAddressGateway, Topic: testAddressTopic}
+	testNewProvisionerHost := fmt.Sprintf("%s.%s.svc.cluster.local", testNewGateway, testNamespace)
+	testNewProvisionerURL := fmt.Sprintf("http://%s/%s/%s", testNewProvisionerHost, testNamespace, testName)
+	testNewAddressGateway := fmt.Sprintf("%s:6565", testNewProvisionerHost)
+	testNewAddress := &streaming.StreamAddress{Gateway: testNewAddressGateway, Topic: testAddressTopic}
+
+	streamMirrorImages := "riff-streaming-stream-mirror" // contains image names for the stream mirror
+	streamMirrorImageKey := "mirrorImage"
 
 	streamConditionBindingReady := factories.Condition().Type(streamingv1alpha1.StreamConditionBindingReady)
 	streamConditionReady := factories.Condition().Type(streamingv1alpha1.StreamConditionReady)
 	streamConditionResourceAvailable := factories.Condition().Type(streamingv1alpha1.StreamConditionResourceAvailable)
+	streamConditionPartitionsApplied := factories.Condition().Type(streamingv1alpha1.StreamConditionPartitionsApplied).Info()
 	gatewayConditionReady := factories.Condition().Type(streamingv1alpha1.GatewayConditionReady)
 
 	scheme := runtime.NewScheme()
@@ -81,7 +97,13 @@ func TestStreamReconciler(t *testing.T) {
 			streamConditionReady.True(),
 			streamConditionResourceAvailable.True(),
 		).
-		StatusBinding(testBindingMetadata, testBindingSecret)
+		StatusBinding(testBindingMetadata, testBindingSecret).
+		StatusGateway(testGateway).
+		StatusPhase(streamingv1alpha1.StreamPhaseActive)
+	streamMigrating := streamReady.
+		Gateway(testNewGateway).
+		StatusMigration(testGateway, testNewGateway).
+		StatusMigrationGeneration(1)
 
 	bindingMetadataCreate := factories.ConfigMap().
 		ObjectMeta(func(om factories.ObjectMeta) {
@@ -114,6 +136,10 @@ func TestStreamReconciler(t *testing.T) {
 			om.Created(1)
 		})
 
+	bindingSecretMigrating := bindingSecretGiven.
+		AddData("migrationGateway", testNewAddressGateway).
+		AddData("migrationTopic", testAddressTopic)
+
 	gatewayMinimal := factories.Gateway().
 		NamespaceName(testNamespace, testGateway)
 	gateway := gatewayMinimal.
@@ -121,6 +147,115 @@ func TestStreamReconciler(t *testing.T) {
 			gatewayConditionReady.True(),
 		).
 		StatusAddress(testProvisionerURL)
+	gatewayExposed := gateway.
+		Exposure(&streamingv1alpha1.GatewayExposure{
+			Type:          streamingv1alpha1.GatewayExposureLoadBalancer,
+			AuthSecretRef: &corev1.LocalObjectReference{Name: "gateway-auth"},
+		}).
+		StatusPublicAddress("http://203.0.113.10:6565")
+	gatewayAuthSecret := factories.Secret().
+		NamespaceName(testNamespace, "gateway-auth").
+		AddData(streamingv1alpha1.GatewayAuthTokenKey, "s3cr3t")
+	newGateway := factories.Gateway().
+		NamespaceName(testNamespace, testNewGateway).
+		StatusConditions(
+			gatewayConditionReady.True(),
+		).
+		StatusAddress(testNewProvisionerURL)
+
+	streamMirrorImagesConfigMap := factories.ConfigMap().
+		NamespaceName(testSystemNamespace, streamMirrorImages).
+		AddData(streamMirrorImageKey, testMirrorImage)
+
+	consumer := factories.Processor().
+		NamespaceName(testNamespace, "consumer").
+		Inputs(stream.CreateInputStreamBinding("", "")).
+		StatusConsumerGroup("consumer-group").
+		StatusInputLags(streamingv1alpha1.ProcessorInputLag{Stream: testName, Lag: 5})
+	consumerDrained := consumer.
+		StatusInputLags(streamingv1alpha1.ProcessorInputLag{Stream: testName, Lag: 0})
+
+	migrationJobCreate := factories.Job().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.GenerateName("%s-migration-", testName)
+			om.AddLabel(streamingv1alpha1.StreamLabelKey, testName)
+			om.AddLabel(streamingv1alpha1.StreamMigrationCopyLabelKey, streamingv1alpha1.StreamMigrationCopyInitial)
+			om.ControlledBy(stream, scheme)
+		}).
+		PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+			pts.AddLabel(streamingv1alpha1.StreamLabelKey, testName)
+			pts.AddLabel(streamingv1alpha1.StreamMigrationCopyLabelKey, streamingv1alpha1.StreamMigrationCopyInitial)
+			pts.ContainerNamed("mirror", func(c *corev1.Container) {
+				c.Image = testMirrorImage
+				c.Env = []corev1.EnvVar{
+					{Name: "CNB_BINDINGS", Value: "/var/riff/bindings"},
+					{Name: "INPUT_NAMES", Value: testName},
+					{Name: "INPUT_START_OFFSETS", Value: "earliest"},
+					{Name: "OUTPUT_NAMES", Value: testName},
+					{Name: "GROUP", Value: fmt.Sprintf("%s-migration-%s-1", testName, testNewGateway)},
+					{Name: "STOP_AT_HEAD", Value: "true"},
+				}
+				c.VolumeMounts = []corev1.VolumeMount{
+					{Name: "binding-metadata", MountPath: "/var/riff/bindings/input_000/metadata", ReadOnly: true},
+					{Name: "binding-secret-from", MountPath: "/var/riff/bindings/input_000/secret", ReadOnly: true},
+					{Name: "binding-metadata", MountPath: "/var/riff/bindings/output_000/metadata", ReadOnly: true},
+					{Name: "binding-secret-to", MountPath: "/var/riff/bindings/output_000/secret", ReadOnly: true},
+				}
+			})
+			pts.Volumes(
+				corev1.Volume{
+					Name: "binding-metadata",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: testBindingMetadata},
+						},
+					},
+				},
+				corev1.Volume{
+					Name: "binding-secret-from",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: testBindingSecret,
+							Items: []corev1.KeyToPath{
+								{Key: "gateway", Path: "gateway"},
+								{Key: "topic", Path: "topic"},
+							},
+						},
+					},
+				},
+				corev1.Volume{
+					Name: "binding-secret-to",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: testBindingSecret,
+							Items: []corev1.KeyToPath{
+								{Key: "migrationGateway", Path: "gateway"},
+								{Key: "migrationTopic", Path: "topic"},
+							},
+						},
+					},
+				},
+			)
+			pts.RestartPolicy(corev1.RestartPolicyOnFailure)
+		})
+	migrationJobGiven := migrationJobCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Name("%s%s", om.Create().GenerateName, "000")
+			om.Created(1)
+		})
+	migrationFinalJobCreate := migrationJobCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.AddLabel(streamingv1alpha1.StreamMigrationCopyLabelKey, streamingv1alpha1.StreamMigrationCopyFinal)
+		}).
+		PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+			pts.AddLabel(streamingv1alpha1.StreamMigrationCopyLabelKey, streamingv1alpha1.StreamMigrationCopyFinal)
+		})
+	migrationFinalJobGiven := migrationFinalJobCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Name("%s%s", om.Create().GenerateName, "000")
+			om.Created(1)
+		})
 
 	matchedByObject := func(expectedFactory rtesting.Factory) interface{} {
 		return mock.MatchedBy(func(actual apis.Object) bool {
@@ -220,6 +355,196 @@ func TestStreamReconciler(t *testing.T) {
 			bindingMetadataGiven,
 			bindingSecretGiven,
 		},
+	}, {
+		Name: "partitions applied by the gateway",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamReady.
+				Partitions(3),
+			gateway,
+			bindingMetadataGiven,
+			bindingSecretGiven,
+		},
+		Prepare: func(t *testing.T) error {
+			address := *testAddress
+			address.Partitions = 3
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream.Partitions(3)), testProvisionerURL).Return(&address, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamReady.
+				Partitions(3).
+				StatusConditions(
+					streamConditionBindingReady.True(),
+					streamConditionPartitionsApplied.True(),
+					streamConditionReady.True(),
+					streamConditionResourceAvailable.True(),
+				).
+				StatusPartitions(3),
+		},
+	}, {
+		Name: "partitions not reported by the gateway",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamReady.
+				Partitions(3),
+			gateway,
+			bindingMetadataGiven,
+			bindingSecretGiven,
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream.Partitions(3)), testProvisionerURL).Return(testAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamReady.
+				Partitions(3).
+				StatusConditions(
+					streamConditionBindingReady.True(),
+					streamConditionPartitionsApplied.False().Reason("PartitionsNotReported", "gateway did not report the partitions of the topic, 3 partitions may not be applied"),
+					streamConditionReady.True(),
+					streamConditionResourceAvailable.True(),
+				),
+		},
+	}, {
+		Name: "partitions differ from the topic on the gateway",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamReady.
+				Partitions(3),
+			gateway,
+			bindingMetadataGiven,
+			bindingSecretGiven,
+		},
+		Prepare: func(t *testing.T) error {
+			address := *testAddress
+			address.Partitions = 1
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream.Partitions(3)), testProvisionerURL).Return(&address, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamReady.
+				Partitions(3).
+				StatusConditions(
+					streamConditionBindingReady.True(),
+					streamConditionPartitionsApplied.False().Reason("PartitionsNotApplied", "topic has 1 partitions, 3 requested"),
+					streamConditionReady.True(),
+					streamConditionResourceAvailable.True(),
+				).
+				StatusPartitions(1),
+		},
+	}, {
+		Name: "report quota usage",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamReady,
+			gateway,
+			bindingMetadataGiven,
+			bindingSecretGiven,
+			factories.StreamQuota().
+				NamespaceName(testNamespace, "test-quota").
+				MaxStreams(10).
+				StatusUsed(3, 5),
+			factories.StreamQuota().
+				NamespaceName(testNamespace, "gateways").
+				Gateways(testGateway),
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamReady.
+				StatusQuotas(
+					streamingv1alpha1.StreamQuotaUsage{
+						Name: "gateways",
+					},
+					streamingv1alpha1.StreamQuotaUsage{
+						Name:       "test-quota",
+						Streams:    3,
+						MaxStreams: rtesting.Int32Ptr(10),
+						Partitions: 5,
+					},
+				),
+		},
+	}, {
+		Name: "binding for exposed gateway",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamReady,
+			gatewayExposed,
+			gatewayAuthSecret,
+			bindingMetadataGiven,
+			bindingSecretGiven,
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gatewayExposed, stream, scheme),
+			rtesting.NewTrackRequest(gatewayAuthSecret, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated ConfigMap "%s"`, testBindingMetadata),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Secret "%s"`, testBindingSecret),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			bindingMetadataGiven.
+				AddData("publicGateway", "203.0.113.10:6565"),
+			bindingSecretGiven.
+				AddData("authToken", "s3cr3t"),
+		},
 	}, {
 		Name: "missing gateway",
 		Key:  testKey,
@@ -247,7 +572,8 @@ func TestStreamReconciler(t *testing.T) {
 					streamConditionBindingReady.Unknown(),
 					streamConditionReady.False().Reason("ProvisionFailed", `Gateway "test-gateway" not found`),
 					streamConditionResourceAvailable.False().Reason("ProvisionFailed", `Gateway "test-gateway" not found`),
-				),
+				).
+				StatusPhase(streamingv1alpha1.StreamPhaseProvisioning),
 		},
 	}, {
 		Name: "error fetching gateway",
@@ -280,7 +606,8 @@ func TestStreamReconciler(t *testing.T) {
 					streamConditionBindingReady.Unknown(),
 					streamConditionReady.False().Reason("ProvisionFailed", "inducing failure for get Gateway"),
 					streamConditionResourceAvailable.False().Reason("ProvisionFailed", "inducing failure for get Gateway"),
-				),
+				).
+				StatusPhase(streamingv1alpha1.StreamPhaseProvisioning),
 		},
 	}, {
 		Name: "gateway is not ready",
@@ -313,7 +640,8 @@ func TestStreamReconciler(t *testing.T) {
 					streamConditionBindingReady.Unknown(),
 					streamConditionReady.False().Reason("ProvisionFailed", `Gateway "test-gateway" not ready`),
 					streamConditionResourceAvailable.False().Reason("ProvisionFailed", `Gateway "test-gateway" not ready`),
-				),
+				).
+				StatusPhase(streamingv1alpha1.StreamPhaseProvisioning),
 		},
 	}, {
 		Name: "gateway has invalid address",
@@ -344,7 +672,8 @@ func TestStreamReconciler(t *testing.T) {
 					streamConditionBindingReady.Unknown(),
 					streamConditionReady.Unknown(),
 					streamConditionResourceAvailable.Unknown(),
-				),
+				).
+				StatusPhase(streamingv1alpha1.StreamPhaseProvisioning),
 		},
 	}, {
 		Name: "provision failed",
@@ -375,7 +704,8 @@ func TestStreamReconciler(t *testing.T) {
 					streamConditionBindingReady.Unknown(),
 					streamConditionReady.False().Reason("ProvisionFailed", "remote error"),
 					streamConditionResourceAvailable.False().Reason("ProvisionFailed", "remote error"),
-				),
+				).
+				StatusPhase(streamingv1alpha1.StreamPhaseProvisioning),
 		},
 	}, {
 		Name: "conflicting binding metadata",
@@ -418,7 +748,9 @@ func TestStreamReconciler(t *testing.T) {
 					streamConditionReady.False().Reason("BindingFailed", `binding metadata "test-stream-stream-binding-metadata" already exists`),
 					streamConditionResourceAvailable.True(),
 				).
-				StatusBinding("", testBindingSecret),
+				StatusBinding("", testBindingSecret).
+				StatusGateway(testGateway).
+				StatusPhase(streamingv1alpha1.StreamPhaseActive),
 		},
 	}, {
 		Name: "conflicting binding secret",
@@ -461,22 +793,815 @@ func TestStreamReconciler(t *testing.T) {
 					streamConditionReady.False().Reason("BindingFailed", `binding secret "test-stream-stream-binding-secret" already exists`),
 					streamConditionResourceAvailable.True(),
 				).
-				StatusBinding(testBindingMetadata, ""),
+				StatusBinding(testBindingMetadata, "").
+				StatusGateway(testGateway).
+				StatusPhase(streamingv1alpha1.StreamPhaseActive),
 		},
-	}}
-
-	table.Test(t, scheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
-		streamProvisioner = &streaming.MockStreamProvisionerClient{}
-		return streaming.StreamReconciler(
-			controllers.Config{
-				Client:    client,
-				APIReader: apiReader,
-				Recorder:  recorder,
-				Log:       log,
-				Scheme:    scheme,
-				Tracker:   tracker,
-			},
-			streamProvisioner,
+	}, {
+		Name: "migrate, wait for consumers to drain",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamReady.
+				Gateway(testNewGateway),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretGiven,
+			consumer,
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Secret "%s"`, testBindingSecret),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			bindingSecretMigrating,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamMigrating.
+				StatusPhase(streamingv1alpha1.StreamPhaseDraining).
+				StatusMigrationMessage("waiting for consumers to drain: consumer").
+				StatusConsumers("consumer"),
+		},
+	}, {
+		Name: "migrate, consumers drained",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamMigrating.
+				StatusPhase(streamingv1alpha1.StreamPhaseDraining).
+				StatusMigrationMessage("waiting for consumers to drain: consumer"),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretMigrating,
+			consumerDrained,
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamMigrating.
+				StatusPhase(streamingv1alpha1.StreamPhaseDraining).
+				StatusMigrationDrainedTime(testNow.Unix()).
+				StatusMigrationMessage("confirming consumers are drained").
+				StatusConsumers("consumer"),
+		},
+		ExpectedResult: ctrl.Result{RequeueAfter: testDrainInterval},
+	}, {
+		Name: "migrate, confirming consumers are drained",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamMigrating.
+				StatusPhase(streamingv1alpha1.StreamPhaseDraining).
+				StatusMigrationDrainedTime(testNow.Unix() - 10).
+				StatusMigrationMessage("confirming consumers are drained").
+				StatusConsumers("consumer"),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretMigrating,
+			consumerDrained,
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+		},
+		ExpectedResult: ctrl.Result{RequeueAfter: testDrainInterval - 10*time.Second},
+	}, {
+		Name: "migrate, consumers no longer drained",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamMigrating.
+				StatusPhase(streamingv1alpha1.StreamPhaseDraining).
+				StatusMigrationDrainedTime(testNow.Unix() - 10).
+				StatusMigrationMessage("confirming consumers are drained").
+				StatusConsumers("consumer"),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretMigrating,
+			consumer,
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamMigrating.
+				StatusPhase(streamingv1alpha1.StreamPhaseDraining).
+				StatusMigrationMessage("waiting for consumers to drain: consumer").
+				StatusConsumers("consumer"),
+		},
+	}, {
+		Name: "migrate, gateway reports consumers not drained",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamMigrating.
+				StatusPhase(streamingv1alpha1.StreamPhaseDraining).
+				StatusMigrationDrainedTime(testNow.Unix() - 30).
+				StatusMigrationMessage("confirming consumers are drained").
+				StatusConsumers("consumer"),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretMigrating,
+			consumerDrained,
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			streamProvisioner.On("ConsumerLag", mock.Anything, testProvisionerURL, "consumer-group").Return(&streaming.ConsumerLag{
+				Partitions: []streaming.PartitionLag{{Partition: 0, Offset: 20, Lag: 3}},
+			}, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamMigrating.
+				StatusPhase(streamingv1alpha1.StreamPhaseDraining).
+				StatusMigrationMessage("waiting for consumers to drain: consumer").
+				StatusConsumers("consumer"),
+		},
+		ExpectedResult: ctrl.Result{RequeueAfter: testDrainInterval},
+	}, {
+		Name: "migrate, unable to confirm consumers are drained",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamMigrating.
+				StatusPhase(streamingv1alpha1.StreamPhaseDraining).
+				StatusMigrationDrainedTime(testNow.Unix() - 30).
+				StatusMigrationMessage("confirming consumers are drained").
+				StatusConsumers("consumer"),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretMigrating,
+			consumerDrained,
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			streamProvisioner.On("ConsumerLag", mock.Anything, testProvisionerURL, "consumer-group").Return(nil, fmt.Errorf("inducing failure"))
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamMigrating.
+				StatusPhase(streamingv1alpha1.StreamPhaseDraining).
+				StatusMigrationDrainedTime(testNow.Unix() - 30).
+				StatusMigrationMessage("unable to confirm consumers are drained: inducing failure").
+				StatusConsumers("consumer"),
+		},
+		ExpectedResult: ctrl.Result{RequeueAfter: testDrainInterval},
+	}, {
+		Name: "migrate, consumers drain confirmed",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamMigrating.
+				StatusPhase(streamingv1alpha1.StreamPhaseDraining).
+				StatusMigrationDrainedTime(testNow.Unix() - 30).
+				StatusMigrationMessage("confirming consumers are drained").
+				StatusConsumers("consumer"),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretMigrating,
+			consumerDrained,
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			streamProvisioner.On("ConsumerLag", mock.Anything, testProvisionerURL, "consumer-group").Return(&streaming.ConsumerLag{
+				Partitions: []streaming.PartitionLag{{Partition: 0, Offset: 20, Lag: 0}},
+			}, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Migrated",
+				`Migrated from Gateway "%s" to "%s"`, testGateway, testNewGateway),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Secret "%s"`, testBindingSecret),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			bindingSecretGiven.
+				AddData("gateway", testNewAddressGateway),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamReady.
+				Gateway(testNewGateway).
+				StatusGateway(testNewGateway).
+				StatusConsumers("consumer"),
+		},
+	}, {
+		Name: "migrate, without draining",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamReady.
+				Gateway(testNewGateway).
+				Migration(false, false),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretGiven,
+			consumer,
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Migrated",
+				`Migrated from Gateway "%s" to "%s"`, testGateway, testNewGateway),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Secret "%s"`, testBindingSecret),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectUpdates: []rtesting.Factory{
+			bindingSecretGiven.
+				AddData("gateway", testNewAddressGateway),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamReady.
+				Gateway(testNewGateway).
+				Migration(false, false).
+				StatusGateway(testNewGateway).
+				StatusConsumers("consumer"),
+		},
+	}, {
+		Name: "migrate, new gateway not found",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamReady.
+				Gateway(testNewGateway),
+			gateway,
+			bindingMetadataGiven,
+			bindingSecretGiven,
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamMigrating.
+				StatusPhase(streamingv1alpha1.StreamPhaseMigrating).
+				StatusMigrationMessage(`Gateway "new-gateway" not found`),
+		},
+	}, {
+		Name: "migrate, copy data",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamReady.
+				Gateway(testNewGateway).
+				Migration(true, true),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretGiven,
+			streamMirrorImagesConfigMap,
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Created",
+				`Created Job "%s-migration-001"`, testName),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Secret "%s"`, testBindingSecret),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			migrationJobCreate,
+		},
+		ExpectUpdates: []rtesting.Factory{
+			bindingSecretMigrating,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamMigrating.
+				Migration(true, true).
+				StatusPhase(streamingv1alpha1.StreamPhaseCopying).
+				StatusMigrationJobRef("%s-migration-001", testName).
+				StatusMigrationMessage(`copying messages to Gateway "new-gateway"`),
+		},
+	}, {
+		Name: "migrate, copy complete",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamMigrating.
+				Migration(true, true).
+				StatusPhase(streamingv1alpha1.StreamPhaseCopying).
+				StatusMigrationJobRef("%s-migration-000", testName).
+				StatusMigrationMessage(`copying messages to Gateway "new-gateway"`),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretMigrating,
+			streamMirrorImagesConfigMap,
+			migrationJobGiven.
+				StatusComplete(5),
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamMigrating.
+				Migration(true, true).
+				StatusPhase(streamingv1alpha1.StreamPhaseCopying).
+				StatusMigrationJobRef("%s-migration-000", testName).
+				StatusMigrationCopyCompletionTime(5),
+		},
+	}, {
+		Name: "migrate, copy failed",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamMigrating.
+				Migration(true, true).
+				StatusPhase(streamingv1alpha1.StreamPhaseCopying).
+				StatusMigrationJobRef("%s-migration-000", testName).
+				StatusMigrationMessage(`copying messages to Gateway "new-gateway"`),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretMigrating,
+			streamMirrorImagesConfigMap,
+			migrationJobGiven.
+				StatusFailed("BackoffLimitExceeded", "Job has reached the specified backoff limit"),
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamMigrating.
+				Migration(true, true).
+				StatusPhase(streamingv1alpha1.StreamPhaseCopying).
+				StatusMigrationJobRef("%s-migration-000", testName).
+				StatusMigrationMessage("copying messages failed: Job has reached the specified backoff limit"),
+		},
+	}, {
+		Name: "migrate, copy complete, final copy",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamMigrating.
+				Migration(true, true).
+				StatusPhase(streamingv1alpha1.StreamPhaseDraining).
+				StatusMigrationCopyCompletionTime(5).
+				StatusMigrationDrainedTime(testNow.Unix() - 30).
+				StatusMigrationMessage("confirming consumers are drained").
+				StatusConsumers("consumer"),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretMigrating,
+			streamMirrorImagesConfigMap,
+			consumerDrained,
+			migrationJobGiven.
+				StatusComplete(5),
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			streamProvisioner.On("ConsumerLag", mock.Anything, testProvisionerURL, "consumer-group").Return(&streaming.ConsumerLag{
+				Partitions: []streaming.PartitionLag{{Partition: 0, Offset: 20, Lag: 0}},
+			}, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted Job "%s-migration-000"`, testName),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Created",
+				`Created Job "%s-migration-001"`, testName),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectDeletes: []rtesting.DeleteRef{
+			{Group: "batch", Kind: "Job", Namespace: testNamespace, Name: fmt.Sprintf("%s-migration-000", testName)},
+		},
+		ExpectCreates: []rtesting.Factory{
+			migrationFinalJobCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamMigrating.
+				Migration(true, true).
+				StatusPhase(streamingv1alpha1.StreamPhaseFinalizing).
+				StatusMigrationCopyCompletionTime(5).
+				StatusMigrationDrainedTime(testNow.Unix()-30).
+				StatusMigrationDrainConfirmed().
+				StatusMigrationJobRef("%s-migration-001", testName).
+				StatusMigrationMessage(`copying messages to Gateway "new-gateway"`).
+				StatusConsumers("consumer"),
+		},
+	}, {
+		Name: "migrate, final copy complete",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamMigrating.
+				Migration(true, true).
+				StatusPhase(streamingv1alpha1.StreamPhaseFinalizing).
+				StatusMigrationCopyCompletionTime(5).
+				StatusMigrationDrainedTime(testNow.Unix()-30).
+				StatusMigrationDrainConfirmed().
+				StatusMigrationJobRef("%s-migration-000", testName).
+				StatusMigrationMessage(`copying messages to Gateway "new-gateway"`).
+				StatusConsumers("consumer"),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretMigrating,
+			streamMirrorImagesConfigMap,
+			// messages written since the drain are not rechecked
+			consumer,
+			migrationFinalJobGiven.
+				StatusComplete(80),
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamMigrating.
+				Migration(true, true).
+				StatusPhase(streamingv1alpha1.StreamPhaseFinalizing).
+				StatusMigrationCopyCompletionTime(5).
+				StatusMigrationDrainedTime(testNow.Unix()-30).
+				StatusMigrationDrainConfirmed().
+				StatusMigrationJobRef("%s-migration-000", testName).
+				StatusMigrationFinalCopyCompletionTime(80).
+				StatusConsumers("consumer"),
+		},
+	}, {
+		Name: "migrate, final copy complete, repoint",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamMigrating.
+				Migration(true, true).
+				StatusPhase(streamingv1alpha1.StreamPhaseFinalizing).
+				StatusMigrationCopyCompletionTime(5).
+				StatusMigrationDrainedTime(testNow.Unix()-30).
+				StatusMigrationDrainConfirmed().
+				StatusMigrationJobRef("%s-migration-000", testName).
+				StatusMigrationFinalCopyCompletionTime(80).
+				StatusConsumers("consumer"),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretMigrating,
+			consumer,
+			migrationFinalJobGiven.
+				StatusComplete(80),
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testNewProvisionerURL).Return(testNewAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+			rtesting.NewTrackRequest(newGateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Migrated",
+				`Migrated from Gateway "%s" to "%s"`, testGateway, testNewGateway),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted Job "%s-migration-000"`, testName),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Secret "%s"`, testBindingSecret),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectDeletes: []rtesting.DeleteRef{
+			{Group: "batch", Kind: "Job", Namespace: testNamespace, Name: fmt.Sprintf("%s-migration-000", testName)},
+		},
+		ExpectUpdates: []rtesting.Factory{
+			bindingSecretGiven.
+				AddData("gateway", testNewAddressGateway),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamReady.
+				Gateway(testNewGateway).
+				Migration(true, true).
+				StatusGateway(testNewGateway).
+				StatusConsumers("consumer"),
+		},
+	}, {
+		Name: "rollback migration",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			streamReady.
+				Migration(true, true).
+				StatusMigration(testGateway, testNewGateway).
+				StatusPhase(streamingv1alpha1.StreamPhaseCopying).
+				StatusMigrationJobRef("%s-migration-000", testName).
+				StatusMigrationMessage(`copying messages to Gateway "new-gateway"`),
+			gateway,
+			newGateway,
+			bindingMetadataGiven,
+			bindingSecretMigrating,
+			migrationJobGiven,
+		},
+		Prepare: func(t *testing.T) error {
+			streamProvisioner.On("ProvisionStream", matchedByObject(stream), testProvisionerURL).Return(testAddress, nil)
+			return nil
+		},
+		CleanUp: func(t *testing.T) error {
+			streamProvisioner.AssertExpectations(t)
+			return nil
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(gateway, stream, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted Job "%s-migration-000"`, testName),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "Updated",
+				`Updated Secret "%s"`, testBindingSecret),
+			rtesting.NewEvent(stream, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectDeletes: []rtesting.DeleteRef{
+			{Group: "batch", Kind: "Job", Namespace: testNamespace, Name: fmt.Sprintf("%s-migration-000", testName)},
+		},
+		ExpectUpdates: []rtesting.Factory{
+			bindingSecretGiven,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			streamReady.
+				Migration(true, true),
+		},
+	}}
+
+	table.Test(t, scheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
+		streamProvisioner = &streaming.MockStreamProvisionerClient{}
+		return streaming.StreamReconciler(
+			controllers.Config{
+				Client:    client,
+				APIReader: apiReader,
+				Recorder:  recorder,
+				Log:       log,
+				Scheme:    scheme,
+				Tracker:   tracker,
+			},
+			testSystemNamespace,
+			streamProvisioner,
+			testDrainInterval,
+			clock.NewFakeClock(testNow),
+		)
+	})
+}
+
+func TestStreamTopologyReconciler(t *testing.T) {
+	testNamespace := "test-namespace"
+	testOtherNamespace := "other-namespace"
+	testName := "test-stream"
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	stream := factories.Stream().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.Generation(1)
+		})
+	otherStream := factories.Stream().
+		NamespaceName(testNamespace, "other-stream")
+
+	consumer := factories.Processor().
+		NamespaceName(testNamespace, "consumer").
+		Inputs(stream.CreateInputStreamBinding("", "")).
+		Outputs(otherStream.CreateOutputStreamBinding(""))
+	producer := factories.Processor().
+		NamespaceName(testNamespace, "producer").
+		Inputs(otherStream.CreateInputStreamBinding("", "")).
+		Outputs(stream.CreateOutputStreamBinding(""))
+	sharedConsumer := factories.Processor().
+		NamespaceName(testOtherNamespace, "shared-consumer").
+		Inputs(streamingv1alpha1.InputStreamBinding{Stream: fmt.Sprintf("%s/%s", testNamespace, testName)})
+	unrelated := factories.Processor().
+		NamespaceName(testNamespace, "unrelated").
+		Inputs(otherStream.CreateInputStreamBinding("", "")).
+		Outputs(otherStream.CreateOutputStreamBinding(""))
+	sameNameOtherNamespace := factories.Processor().
+		NamespaceName(testOtherNamespace, "same-name").
+		Inputs(streamingv1alpha1.InputStreamBinding{Stream: testName})
+
+	table := rtesting.SubTable{{
+		Name:   "no processors",
+		Parent: stream,
+	}, {
+		Name:   "consumers and producers",
+		Parent: stream,
+		GivenObjects: []rtesting.Factory{
+			consumer,
+			producer,
+			sharedConsumer,
+			unrelated,
+			sameNameOtherNamespace,
+		},
+		ExpectParent: stream.
+			StatusConsumers("consumer", "other-namespace/shared-consumer").
+			StatusProducers("producer"),
+	}, {
+		Name: "remove stale processors",
+		Parent: stream.
+			StatusConsumers("consumer", "removed").
+			StatusProducers("producer"),
+		GivenObjects: []rtesting.Factory{
+			consumer,
+		},
+		ExpectParent: stream.
+			StatusConsumers("consumer"),
+	}, {
+		Name:   "error listing processors",
+		Parent: stream,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("list", "ProcessorList"),
+		},
+		ShouldErr: true,
+	}}
+
+	table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
+		return streaming.StreamTopologyReconciler(
+			controllers.Config{
+				Client:    client,
+				APIReader: client,
+				Recorder:  recorder,
+				Log:       log,
+				Scheme:    scheme,
+				Tracker:   tracker,
+			},
 		)
 	})
 }
@@ -18,23 +18,61 @@ package streaming
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
 
 	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
 )
 
 type StreamProvisionerClient interface {
+	// ProvisionStream creates the stream's topic with
+	// `PUT <provisionerURL>?partitions=<partitions>`, answered with a
+	// StreamAddress as JSON. Provisioners that partition topics report the
+	// partitions of the topic in the address, others ignore the parameter.
 	ProvisionStream(stream *streamingv1alpha1.Stream, provisionerURL string) (*StreamAddress, error)
+	// ConsumerLag queries the lag of the consumer group on the stream with
+	// `GET <provisionerURL>/groups/<group>`, answered with a ConsumerLag as
+	// JSON. Provisioners that do not serve the endpoint respond with an
+	// error status, the lag of their streams is not observed.
+	ConsumerLag(ctx context.Context, provisionerURL string, group string) (*ConsumerLag, error)
 }
 
 type StreamAddress struct {
 	Gateway string `json:"gateway,omitempty"`
 	Topic   string `json:"topic,omitempty"`
+	// Partitions of the topic, reported by provisioners that partition
+	// topics. Provisioners that ignore the requested partitions leave it
+	// unset.
+	Partitions int32 `json:"partitions,omitempty"`
+}
+
+// ConsumerLag is the number of messages on each partition of a stream that
+// have not yet been consumed by a consumer group
+type ConsumerLag struct {
+	Partitions []PartitionLag `json:"partitions,omitempty"`
+}
+
+type PartitionLag struct {
+	Partition int32 `json:"partition"`
+	// Offset of the next message the consumer group will receive
+	Offset int64 `json:"offset"`
+	Lag    int64 `json:"lag"`
+}
+
+// Total lag across all partitions
+func (l *ConsumerLag) Total() int64 {
+	var total int64
+	for _, p := range l.Partitions {
+		total += p.Lag
+	}
+	return total
 }
 
 type streamProvisionerRestClient struct {
@@ -50,6 +88,10 @@ func NewStreamProvisionerClient(httpClient *http.Client, logger logr.Logger) Str
 }
 
 func (s *streamProvisionerRestClient) ProvisionStream(stream *streamingv1alpha1.Stream, provisionerURL string) (*StreamAddress, error) {
+	if stream.Spec.Partitions != nil {
+		// gateways that partition topics create the topic with the partitions
+		provisionerURL = fmt.Sprintf("%s?partitions=%d", provisionerURL, *stream.Spec.Partitions)
+	}
 	req, err := http.NewRequest(http.MethodPut, provisionerURL, bytes.NewReader([]byte{}))
 	if err != nil {
 		return nil, err
@@ -74,3 +116,41 @@ func (s *streamProvisionerRestClient) ProvisionStream(stream *streamingv1alpha1.
 	}
 	return address, nil
 }
+
+func (s *streamProvisionerRestClient) ConsumerLag(ctx context.Context, provisionerURL string, group string) (*ConsumerLag, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/groups/%s", provisionerURL, url.PathEscape(group)), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			s.logger.Error(err, "Error closing consumer lag response body")
+		}
+	}()
+	if res.StatusCode >= 400 {
+		msg, _ := ioutil.ReadAll(res.Body)
+		return nil, fmt.Errorf("status: %d, body: %q", res.StatusCode, string(msg))
+	}
+	lag := &ConsumerLag{}
+	if err := json.NewDecoder(res.Body).Decode(lag); err != nil {
+		return nil, err
+	}
+	return lag, nil
+}
+
+// streamProvisionerURL is the location of the stream on the gateway's
+// provisioner
+func streamProvisionerURL(gateway *streamingv1alpha1.Gateway, stream types.NamespacedName) (string, error) {
+	if gateway.Status.Address == nil {
+		return "", fmt.Errorf("gateway %q has no address", gateway.Name)
+	}
+	u, err := gateway.Status.Address.Parse()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("http://%s/%s/%s", u.Host, stream.Namespace, stream.Name), nil
+}
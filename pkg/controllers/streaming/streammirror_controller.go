@@ -0,0 +1,415 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/projectriff/system/pkg/apis"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/refs"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+const (
+	StreamMirrorImagesStashKey            controllers.StashKey = "stream-mirror-images"
+	StreamMirrorSourceStreamStashKey      controllers.StashKey = "stream-mirror-source-stream"
+	StreamMirrorDestinationStreamStashKey controllers.StashKey = "stream-mirror-destination-stream"
+)
+
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streammirrors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streammirrors/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streams,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+func StreamMirrorReconciler(c controllers.Config, namespace string, lagMonitor *ConsumerLagMonitor) *controllers.ParentReconciler {
+	c.Log = c.Log.WithName("StreamMirror")
+
+	return &controllers.ParentReconciler{
+		Type: &streamingv1alpha1.StreamMirror{},
+		SubReconcilers: []controllers.SubReconciler{
+			StreamMirrorSyncImages(c, namespace),
+			StreamMirrorResolveStreamsReconciler(c),
+			StreamMirrorChildDeploymentReconciler(c),
+			StreamMirrorChildJobReconciler(c),
+			StreamMirrorLagReconciler(c, lagMonitor),
+		},
+
+		Config: c,
+	}
+}
+
+func StreamMirrorSyncImages(c controllers.Config, namespace string) controllers.SubReconciler {
+	c.Log = c.Log.WithName("SyncImages")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.StreamMirror) error {
+			config := corev1.ConfigMap{}
+			key := types.NamespacedName{Namespace: namespace, Name: streamMirrorImages}
+			// track config for new images
+			c.Tracker.Track(
+				tracker.NewKey(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, key),
+				types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+			)
+			if err := c.Get(ctx, key, &config); err != nil {
+				return err
+			}
+			controllers.StashValue(ctx, StreamMirrorImagesStashKey, config.Data)
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &corev1.ConfigMap{}}, controllers.EnqueueTracked(&corev1.ConfigMap{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
+
+func StreamMirrorResolveStreamsReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ResolveStreams")
+
+	resolveStream := func(ctx context.Context, parent *streamingv1alpha1.StreamMirror, name string) (*streamingv1alpha1.Stream, error) {
+		var stream streamingv1alpha1.Stream
+		key := types.NamespacedName{Namespace: parent.Namespace, Name: name}
+		// track stream for new coordinates
+		c.Tracker.Track(
+			tracker.NewKey(stream.GetGroupVersionKind(), key),
+			types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+		)
+		if err := c.Get(ctx, key, &stream); err != nil {
+			if apierrs.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return &stream, nil
+	}
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.StreamMirror) error {
+			parent.Status.ConsumerGroup = streamMirrorConsumerGroup(parent)
+
+			streams := []*streamingv1alpha1.Stream{}
+			for _, item := range []struct {
+				name string
+				key  controllers.StashKey
+			}{
+				{name: parent.Spec.Source, key: StreamMirrorSourceStreamStashKey},
+				{name: parent.Spec.Destination, key: StreamMirrorDestinationStreamStashKey},
+			} {
+				stream, err := resolveStream(ctx, parent, item.name)
+				if err != nil {
+					return err
+				}
+				if stream == nil {
+					parent.Status.MarkStreamsNotReady(fmt.Sprintf("stream %s not found", item.name))
+					return nil
+				}
+				controllers.StashValue(ctx, item.key, stream)
+				streams = append(streams, stream)
+			}
+
+			parent.Status.MarkStreamsReady()
+			for _, stream := range streams {
+				ready := stream.Status.GetCondition(stream.Status.GetReadyConditionType())
+				if ready == nil {
+					ready = &apis.Condition{Message: "stream has no ready condition"}
+				}
+				if !ready.IsTrue() {
+					parent.Status.MarkStreamsNotReady(fmt.Sprintf("stream %s is not ready: %s", stream.Name, ready.Message))
+					break
+				}
+			}
+
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &streamingv1alpha1.Stream{}}, controllers.EnqueueTracked(&streamingv1alpha1.Stream{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
+
+func StreamMirrorChildDeploymentReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildDeployment")
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.StreamMirror{},
+		ChildType:     &appsv1.Deployment{},
+		ChildListType: &appsv1.DeploymentList{},
+
+		DesiredChild: func(ctx context.Context, parent *streamingv1alpha1.StreamMirror) (*appsv1.Deployment, error) {
+			if parent.Spec.Mode != streamingv1alpha1.ContinuousMirror {
+				// one-shot mirrors run as a job
+				return nil, nil
+			}
+			podSpec, ok := streamMirrorPodSpec(ctx, parent)
+			if !ok {
+				return nil, nil
+			}
+
+			labels := controllers.MergeMaps(parent.Labels, map[string]string{
+				streamingv1alpha1.StreamMirrorLabelKey: parent.Name,
+			})
+			child := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: fmt.Sprintf("%s-mirror-", parent.Name),
+					Namespace:    parent.Namespace,
+					Labels:       labels,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							streamingv1alpha1.StreamMirrorLabelKey: parent.Name,
+						},
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: labels,
+						},
+						Spec: *podSpec,
+					},
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.StreamMirror, child *appsv1.Deployment, err error) {
+			if err != nil {
+				return
+			}
+			if child == nil {
+				parent.Status.DeploymentRef = nil
+			} else {
+				parent.Status.DeploymentRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+				parent.Status.PropagateDeploymentStatus(&child.Status)
+			}
+		},
+		HarmonizeImmutableFields: func(current, desired *appsv1.Deployment) {
+			desired.Spec.Replicas = current.Spec.Replicas
+		},
+		MergeBeforeUpdate: func(current, desired *appsv1.Deployment) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *appsv1.Deployment) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.streamMirrorDeploymentController",
+		Sanitize: func(child *appsv1.Deployment) interface{} {
+			return child.Spec
+		},
+	}
+}
+
+func StreamMirrorChildJobReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildJob")
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.StreamMirror{},
+		ChildType:     &batchv1.Job{},
+		ChildListType: &batchv1.JobList{},
+
+		DesiredChild: func(ctx context.Context, parent *streamingv1alpha1.StreamMirror) (*batchv1.Job, error) {
+			if parent.Spec.Mode != streamingv1alpha1.OneShotMirror {
+				// continuous mirrors run as a deployment
+				return nil, nil
+			}
+			podSpec, ok := streamMirrorPodSpec(ctx, parent)
+			if !ok {
+				return nil, nil
+			}
+			podSpec.RestartPolicy = corev1.RestartPolicyOnFailure
+
+			labels := controllers.MergeMaps(parent.Labels, map[string]string{
+				streamingv1alpha1.StreamMirrorLabelKey: parent.Name,
+			})
+			child := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: fmt.Sprintf("%s-mirror-", parent.Name),
+					Namespace:    parent.Namespace,
+					Labels:       labels,
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: labels,
+						},
+						Spec: *podSpec,
+					},
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.StreamMirror, child *batchv1.Job, err error) {
+			if err != nil {
+				return
+			}
+			if child == nil {
+				parent.Status.JobRef = nil
+				parent.Status.CompletionTime = nil
+			} else {
+				parent.Status.JobRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+				parent.Status.PropagateJobStatus(&child.Status)
+			}
+		},
+		MergeBeforeUpdate: func(current, desired *batchv1.Job) {
+			current.Labels = desired.Labels
+		},
+		// the pod template of a job is immutable, a job that was created is
+		// left to run to completion
+		SemanticEquals: func(a1, a2 *batchv1.Job) bool {
+			return equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.streamMirrorJobController",
+		Sanitize: func(child *batchv1.Job) interface{} {
+			return child.Spec
+		},
+	}
+}
+
+func StreamMirrorLagReconciler(c controllers.Config, lagMonitor *ConsumerLagMonitor) controllers.SubReconciler {
+	c.Log = c.Log.WithName("Lag")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.StreamMirror) error {
+			if lagMonitor == nil {
+				return nil
+			}
+			lag, ok := lagMonitor.MirrorLag(types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name})
+			if !ok {
+				// not yet observed, keep the last reported lag
+				return nil
+			}
+			if lag == nil {
+				// the last reported lag is stale
+				parent.Status.Partitions = nil
+				parent.Status.Lag = nil
+				return nil
+			}
+
+			partitions := make([]streamingv1alpha1.StreamMirrorPartition, len(lag.Partitions))
+			for i, p := range lag.Partitions {
+				partitions[i] = streamingv1alpha1.StreamMirrorPartition{
+					Partition: p.Partition,
+					Offset:    p.Offset,
+					Lag:       p.Lag,
+				}
+			}
+			total := lag.Total()
+			parent.Status.Partitions = partitions
+			parent.Status.Lag = &total
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			if lagMonitor == nil {
+				return nil
+			}
+			// reconcile mirrors as their lag changes
+			bldr.Watches(&source.Channel{Source: lagMonitor.MirrorEvents()}, &handler.EnqueueRequestForObject{})
+			return nil
+		},
+	}
+}
+
+// streamMirrorConsumerGroup is the consumer group the mirror consumes its
+// source stream with
+func streamMirrorConsumerGroup(mirror *streamingv1alpha1.StreamMirror) string {
+	return fmt.Sprintf("streammirror-%s", mirror.Name)
+}
+
+// streamMirrorPodSpec is the pod run by either the deployment or job of the
+// mirror. False is returned when the streams or images are not yet resolved.
+func streamMirrorPodSpec(ctx context.Context, parent *streamingv1alpha1.StreamMirror) (*corev1.PodSpec, bool) {
+	source, ok := controllers.RetrieveValue(ctx, StreamMirrorSourceStreamStashKey).(*streamingv1alpha1.Stream)
+	if !ok {
+		return nil, false
+	}
+	destination, ok := controllers.RetrieveValue(ctx, StreamMirrorDestinationStreamStashKey).(*streamingv1alpha1.Stream)
+	if !ok {
+		return nil, false
+	}
+	images, ok := controllers.RetrieveValue(ctx, StreamMirrorImagesStashKey).(map[string]string)
+	if !ok {
+		return nil, false
+	}
+	image := images[streamMirrorImageKey]
+	if image == "" {
+		return nil, false
+	}
+
+	volumes, volumeMounts := constructBindingVolumes([]streamingv1alpha1.Stream{*source}, []streamingv1alpha1.Stream{*destination}, streamBinding)
+	env := []corev1.EnvVar{
+		{Name: "CNB_BINDINGS", Value: bindingsRootPath},
+		{Name: "INPUT_NAMES", Value: source.Name},
+		{Name: "INPUT_START_OFFSETS", Value: parent.Spec.StartOffset},
+		{Name: "OUTPUT_NAMES", Value: destination.Name},
+		{Name: "GROUP", Value: parent.Status.ConsumerGroup},
+		{Name: "STOP_AT_HEAD", Value: fmt.Sprintf("%t", parent.Spec.Mode == streamingv1alpha1.OneShotMirror)},
+	}
+	if filter := parent.Spec.Filter; filter != nil {
+		if len(filter.ContentTypes) != 0 {
+			env = append(env, corev1.EnvVar{Name: "FILTER_CONTENT_TYPES", Value: strings.Join(filter.ContentTypes, ",")})
+		}
+		if len(filter.Headers) != 0 {
+			// map keys are marshaled in sorted order
+			headers, _ := json.Marshal(filter.Headers)
+			env = append(env, corev1.EnvVar{Name: "FILTER_HEADERS", Value: string(headers)})
+		}
+	}
+
+	return &corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name:         "mirror",
+				Image:        image,
+				Env:          env,
+				VolumeMounts: volumeMounts,
+			},
+		},
+		Volumes: volumes,
+	}, true
+}
@@ -0,0 +1,661 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/controllers/streaming"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/controllers/testing/factories"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+func TestStreamMirrorReconciler(t *testing.T) {
+	testNamespace := "test-namespace"
+	testSystemNamespace := "system-namespace"
+	testName := "test-mirror"
+	testKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
+	testMirrorImage := "example.com/repo/mirror"
+	testConsumerGroup := fmt.Sprintf("streammirror-%s", testName)
+
+	streamMirrorImages := "riff-streaming-stream-mirror" // contains image names for the stream mirror
+	streamMirrorImageKey := "mirrorImage"
+
+	streamMirrorConditionMirrorReady := factories.Condition().Type(streamingv1alpha1.StreamMirrorConditionMirrorReady)
+	streamMirrorConditionReady := factories.Condition().Type(streamingv1alpha1.StreamMirrorConditionReady)
+	streamMirrorConditionStreamsReady := factories.Condition().Type(streamingv1alpha1.StreamMirrorConditionStreamsReady)
+	deploymentConditionAvailable := factories.Condition().Type("Available")
+	deploymentConditionProgressing := factories.Condition().Type("Progressing")
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	streamMirrorImagesConfigMap := factories.ConfigMap().
+		NamespaceName(testSystemNamespace, streamMirrorImages).
+		AddData(streamMirrorImageKey, testMirrorImage)
+
+	testSourceStream := factories.Stream().
+		NamespaceName(testNamespace, "my-source").
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.UID("00000000-0000-0000-0000-000000000001")
+		}).
+		StatusBinding("my-source-binding-metadata", "my-source-binding-secret").
+		StatusReady()
+	testDestinationStream := factories.Stream().
+		NamespaceName(testNamespace, "my-destination").
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.UID("00000000-0000-0000-0000-000000000002")
+		}).
+		StatusBinding("my-destination-binding-metadata", "my-destination-binding-secret").
+		StatusReady()
+
+	mirrorMinimal := factories.StreamMirror().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.Generation(1)
+		}).
+		Source(testSourceStream.Create().Name).
+		Destination(testDestinationStream.Create().Name).
+		Default()
+	mirror := mirrorMinimal.
+		StatusConsumerGroup(testConsumerGroup).
+		StatusDeploymentRef("%s-mirror-000", testName)
+	oneShotMirror := mirrorMinimal.
+		Mode(streamingv1alpha1.OneShotMirror).
+		StatusConsumerGroup(testConsumerGroup).
+		StatusJobRef("%s-mirror-000", testName)
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "stream-00000000-0000-0000-0000-000000000001-metadata",
+			MountPath: "/var/riff/bindings/input_000/metadata",
+			ReadOnly:  true,
+		},
+		{
+			Name:      "stream-00000000-0000-0000-0000-000000000001-secret",
+			MountPath: "/var/riff/bindings/input_000/secret",
+			ReadOnly:  true,
+		},
+		{
+			Name:      "stream-00000000-0000-0000-0000-000000000002-metadata",
+			MountPath: "/var/riff/bindings/output_000/metadata",
+			ReadOnly:  true,
+		},
+		{
+			Name:      "stream-00000000-0000-0000-0000-000000000002-secret",
+			MountPath: "/var/riff/bindings/output_000/secret",
+			ReadOnly:  true,
+		},
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: "stream-00000000-0000-0000-0000-000000000001-metadata",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "my-source-binding-metadata",
+					},
+				},
+			},
+		},
+		{
+			Name: "stream-00000000-0000-0000-0000-000000000001-secret",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: "my-source-binding-secret",
+				},
+			},
+		},
+		{
+			Name: "stream-00000000-0000-0000-0000-000000000002-metadata",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "my-destination-binding-metadata",
+					},
+				},
+			},
+		},
+		{
+			Name: "stream-00000000-0000-0000-0000-000000000002-secret",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: "my-destination-binding-secret",
+				},
+			},
+		},
+	}
+	mirrorContainer := func(stopAtHead string) func(*corev1.Container) {
+		return func(c *corev1.Container) {
+			c.Image = testMirrorImage
+			c.Env = []corev1.EnvVar{
+				{Name: "CNB_BINDINGS", Value: "/var/riff/bindings"},
+				{Name: "INPUT_NAMES", Value: "my-source"},
+				{Name: "INPUT_START_OFFSETS", Value: "earliest"},
+				{Name: "OUTPUT_NAMES", Value: "my-destination"},
+				{Name: "GROUP", Value: testConsumerGroup},
+				{Name: "STOP_AT_HEAD", Value: stopAtHead},
+			}
+			c.VolumeMounts = volumeMounts
+		}
+	}
+
+	deploymentCreate := factories.Deployment().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.GenerateName("%s-mirror-", testName)
+			om.AddLabel(streamingv1alpha1.StreamMirrorLabelKey, testName)
+			om.ControlledBy(mirror, scheme)
+		}).
+		AddSelectorLabel(streamingv1alpha1.StreamMirrorLabelKey, testName).
+		PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+			pts.ContainerNamed("mirror", mirrorContainer("false"))
+			pts.Volumes(volumes...)
+		})
+	deploymentGiven := deploymentCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Name("%s%s", om.Create().GenerateName, "000")
+			om.Created(1)
+		})
+
+	jobCreate := factories.Job().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.GenerateName("%s-mirror-", testName)
+			om.AddLabel(streamingv1alpha1.StreamMirrorLabelKey, testName)
+			om.ControlledBy(oneShotMirror, scheme)
+		}).
+		PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+			pts.AddLabel(streamingv1alpha1.StreamMirrorLabelKey, testName)
+			pts.ContainerNamed("mirror", mirrorContainer("true"))
+			pts.Volumes(volumes...)
+			pts.RestartPolicy(corev1.RestartPolicyOnFailure)
+		})
+	jobGiven := jobCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Name("%s%s", om.Create().GenerateName, "000")
+			om.Created(1)
+		})
+
+	table := rtesting.Table{{
+		Name: "mirror does not exist",
+		Key:  testKey,
+	}, {
+		Name: "ignore deleted mirror",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			mirror.
+				ObjectMeta(func(om factories.ObjectMeta) {
+					om.Deleted(1)
+				}),
+		},
+	}, {
+		Name: "error fetching mirror",
+		Key:  testKey,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("get", "StreamMirror"),
+		},
+		ShouldErr: true,
+	}, {
+		Name: "create deployment",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			mirrorMinimal,
+			streamMirrorImagesConfigMap,
+			testSourceStream,
+			testDestinationStream,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, mirrorMinimal, scheme),
+			rtesting.NewTrackRequest(testSourceStream, mirrorMinimal, scheme),
+			rtesting.NewTrackRequest(testDestinationStream, mirrorMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(mirrorMinimal, scheme, corev1.EventTypeNormal, "Created",
+				`Created Deployment "%s-mirror-001"`, testName),
+			rtesting.NewEvent(mirrorMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			deploymentCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			mirrorMinimal.
+				StatusConditions(
+					streamMirrorConditionMirrorReady.Unknown(),
+					streamMirrorConditionReady.Unknown(),
+					streamMirrorConditionStreamsReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusConsumerGroup(testConsumerGroup).
+				StatusDeploymentRef("%s-mirror-001", testName),
+		},
+	}, {
+		Name: "create deployment, with filter",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			mirrorMinimal.
+				StartOffset(streamingv1alpha1.Latest).
+				Filter(&streamingv1alpha1.StreamMirrorFilter{
+					ContentTypes: []string{"application/json", "text/*"},
+					Headers:      map[string]string{"tenant": "acme", "region": "eu"},
+				}),
+			streamMirrorImagesConfigMap,
+			testSourceStream,
+			testDestinationStream,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, mirrorMinimal, scheme),
+			rtesting.NewTrackRequest(testSourceStream, mirrorMinimal, scheme),
+			rtesting.NewTrackRequest(testDestinationStream, mirrorMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(mirrorMinimal, scheme, corev1.EventTypeNormal, "Created",
+				`Created Deployment "%s-mirror-001"`, testName),
+			rtesting.NewEvent(mirrorMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			deploymentCreate.
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("mirror", func(c *corev1.Container) {
+						c.Env[2].Value = "latest"
+						c.Env = append(c.Env,
+							corev1.EnvVar{Name: "FILTER_CONTENT_TYPES", Value: "application/json,text/*"},
+							corev1.EnvVar{Name: "FILTER_HEADERS", Value: `{"region":"eu","tenant":"acme"}`},
+						)
+					})
+				}),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			mirrorMinimal.
+				StartOffset(streamingv1alpha1.Latest).
+				Filter(&streamingv1alpha1.StreamMirrorFilter{
+					ContentTypes: []string{"application/json", "text/*"},
+					Headers:      map[string]string{"tenant": "acme", "region": "eu"},
+				}).
+				StatusConditions(
+					streamMirrorConditionMirrorReady.Unknown(),
+					streamMirrorConditionReady.Unknown(),
+					streamMirrorConditionStreamsReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusConsumerGroup(testConsumerGroup).
+				StatusDeploymentRef("%s-mirror-001", testName),
+		},
+	}, {
+		Name: "ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			mirror.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					streamMirrorConditionMirrorReady.True(),
+					streamMirrorConditionReady.True(),
+					streamMirrorConditionStreamsReady.True(),
+				),
+			streamMirrorImagesConfigMap,
+			testSourceStream,
+			testDestinationStream,
+			deploymentGiven.
+				StatusConditions(
+					deploymentConditionAvailable.True(),
+					deploymentConditionProgressing.True(),
+				),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, mirror, scheme),
+			rtesting.NewTrackRequest(testSourceStream, mirror, scheme),
+			rtesting.NewTrackRequest(testDestinationStream, mirror, scheme),
+		},
+	}, {
+		Name: "images missing",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			mirrorMinimal,
+			testSourceStream,
+			testDestinationStream,
+		},
+		ShouldErr: true,
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, mirrorMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(mirrorMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			mirrorMinimal.
+				StatusConditions(
+					streamMirrorConditionMirrorReady.Unknown(),
+					streamMirrorConditionReady.Unknown(),
+					streamMirrorConditionStreamsReady.Unknown(),
+				),
+		},
+	}, {
+		Name: "source stream not found",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			mirrorMinimal,
+			streamMirrorImagesConfigMap,
+			testDestinationStream,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, mirrorMinimal, scheme),
+			rtesting.NewTrackRequest(testSourceStream, mirrorMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(mirrorMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			mirrorMinimal.
+				StatusConditions(
+					streamMirrorConditionMirrorReady.Unknown(),
+					streamMirrorConditionReady.False().Reason("StreamNotReady", "stream my-source not found"),
+					streamMirrorConditionStreamsReady.False().Reason("StreamNotReady", "stream my-source not found"),
+				).
+				StatusObservedGeneration(1).
+				StatusConsumerGroup(testConsumerGroup),
+		},
+	}, {
+		Name: "destination stream not found",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			mirrorMinimal,
+			streamMirrorImagesConfigMap,
+			testSourceStream,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, mirrorMinimal, scheme),
+			rtesting.NewTrackRequest(testSourceStream, mirrorMinimal, scheme),
+			rtesting.NewTrackRequest(testDestinationStream, mirrorMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(mirrorMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			mirrorMinimal.
+				StatusConditions(
+					streamMirrorConditionMirrorReady.Unknown(),
+					streamMirrorConditionReady.False().Reason("StreamNotReady", "stream my-destination not found"),
+					streamMirrorConditionStreamsReady.False().Reason("StreamNotReady", "stream my-destination not found"),
+				).
+				StatusObservedGeneration(1).
+				StatusConsumerGroup(testConsumerGroup),
+		},
+	}, {
+		Name: "stream not ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			mirror,
+			streamMirrorImagesConfigMap,
+			testSourceStream,
+			testDestinationStream.
+				StatusConditions(),
+			deploymentGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, mirror, scheme),
+			rtesting.NewTrackRequest(testSourceStream, mirror, scheme),
+			rtesting.NewTrackRequest(testDestinationStream, mirror, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(mirror, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			mirror.
+				StatusConditions(
+					streamMirrorConditionMirrorReady.Unknown(),
+					streamMirrorConditionReady.False().Reason("StreamNotReady", "stream my-destination is not ready: stream has no ready condition"),
+					streamMirrorConditionStreamsReady.False().Reason("StreamNotReady", "stream my-destination is not ready: stream has no ready condition"),
+				).
+				StatusObservedGeneration(1),
+		},
+	}, {
+		Name: "one-shot, create job",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			mirrorMinimal.
+				Mode(streamingv1alpha1.OneShotMirror),
+			streamMirrorImagesConfigMap,
+			testSourceStream,
+			testDestinationStream,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, mirrorMinimal, scheme),
+			rtesting.NewTrackRequest(testSourceStream, mirrorMinimal, scheme),
+			rtesting.NewTrackRequest(testDestinationStream, mirrorMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(mirrorMinimal, scheme, corev1.EventTypeNormal, "Created",
+				`Created Job "%s-mirror-001"`, testName),
+			rtesting.NewEvent(mirrorMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			jobCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			mirrorMinimal.
+				Mode(streamingv1alpha1.OneShotMirror).
+				StatusConditions(
+					streamMirrorConditionMirrorReady.Unknown().Reason("Mirroring", "0 active pods"),
+					streamMirrorConditionReady.Unknown().Reason("Mirroring", "0 active pods"),
+					streamMirrorConditionStreamsReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusConsumerGroup(testConsumerGroup).
+				StatusJobRef("%s-mirror-001", testName),
+		},
+	}, {
+		Name: "one-shot, completed",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			oneShotMirror,
+			streamMirrorImagesConfigMap,
+			testSourceStream,
+			testDestinationStream,
+			jobGiven.
+				StatusComplete(5),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, oneShotMirror, scheme),
+			rtesting.NewTrackRequest(testSourceStream, oneShotMirror, scheme),
+			rtesting.NewTrackRequest(testDestinationStream, oneShotMirror, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(oneShotMirror, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			oneShotMirror.
+				StatusConditions(
+					streamMirrorConditionMirrorReady.True(),
+					streamMirrorConditionReady.True(),
+					streamMirrorConditionStreamsReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusCompletionTime(5),
+		},
+	}, {
+		Name: "one-shot, failed",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			oneShotMirror,
+			streamMirrorImagesConfigMap,
+			testSourceStream,
+			testDestinationStream,
+			jobGiven.
+				StatusFailed("BackoffLimitExceeded", "Job has reached the specified backoff limit"),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, oneShotMirror, scheme),
+			rtesting.NewTrackRequest(testSourceStream, oneShotMirror, scheme),
+			rtesting.NewTrackRequest(testDestinationStream, oneShotMirror, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(oneShotMirror, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			oneShotMirror.
+				StatusConditions(
+					streamMirrorConditionMirrorReady.False().Reason("BackoffLimitExceeded", "Job has reached the specified backoff limit"),
+					streamMirrorConditionReady.False().Reason("BackoffLimitExceeded", "Job has reached the specified backoff limit"),
+					streamMirrorConditionStreamsReady.True(),
+				).
+				StatusObservedGeneration(1),
+		},
+	}, {
+		Name: "switch to one-shot, delete deployment and create job",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			mirror.
+				Mode(streamingv1alpha1.OneShotMirror),
+			streamMirrorImagesConfigMap,
+			testSourceStream,
+			testDestinationStream,
+			deploymentGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamMirrorImagesConfigMap, mirror, scheme),
+			rtesting.NewTrackRequest(testSourceStream, mirror, scheme),
+			rtesting.NewTrackRequest(testDestinationStream, mirror, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(mirror, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted Deployment "%s-mirror-000"`, testName),
+			rtesting.NewEvent(mirror, scheme, corev1.EventTypeNormal, "Created",
+				`Created Job "%s-mirror-001"`, testName),
+			rtesting.NewEvent(mirror, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectDeletes: []rtesting.DeleteRef{
+			{Group: "apps", Kind: "Deployment", Namespace: testNamespace, Name: fmt.Sprintf("%s-mirror-000", testName)},
+		},
+		ExpectCreates: []rtesting.Factory{
+			jobCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			mirrorMinimal.
+				Mode(streamingv1alpha1.OneShotMirror).
+				StatusConditions(
+					streamMirrorConditionMirrorReady.Unknown().Reason("Mirroring", "0 active pods"),
+					streamMirrorConditionReady.Unknown().Reason("Mirroring", "0 active pods"),
+					streamMirrorConditionStreamsReady.True(),
+				).
+				StatusObservedGeneration(1).
+				StatusConsumerGroup(testConsumerGroup).
+				StatusJobRef("%s-mirror-001", testName),
+		},
+	}}
+
+	table.Test(t, scheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
+		return streaming.StreamMirrorReconciler(
+			controllers.Config{
+				Client:    client,
+				APIReader: apiReader,
+				Recorder:  recorder,
+				Log:       log,
+				Scheme:    scheme,
+				Tracker:   tracker,
+			},
+			testSystemNamespace,
+			nil,
+		)
+	})
+
+	t.Run("StreamMirrorLagReconciler", func(t *testing.T) {
+		table := rtesting.SubTable{
+			{
+				Name: "lag not observed",
+				Parent: mirror.
+					NamespaceName(testNamespace, "other-mirror"),
+			},
+			{
+				Name:   "lag observed",
+				Parent: mirror,
+				ExpectParent: mirror.
+					StatusPartitions(
+						streamingv1alpha1.StreamMirrorPartition{Partition: 0, Offset: 40, Lag: 2},
+						streamingv1alpha1.StreamMirrorPartition{Partition: 1, Offset: 35, Lag: 0},
+					),
+			},
+			{
+				Name: "lag stale",
+				Parent: mirror.
+					NamespaceName(testNamespace, "unobserved-mirror").
+					StatusPartitions(
+						streamingv1alpha1.StreamMirrorPartition{Partition: 0, Offset: 40, Lag: 2},
+					),
+				ExpectParent: mirror.
+					NamespaceName(testNamespace, "unobserved-mirror"),
+			},
+		}
+
+		table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
+			lagClock := clock.NewFakeClock(time.Date(2020, time.April, 1, 12, 0, 0, 0, time.UTC))
+			lagMonitor := streaming.NewConsumerLagMonitor(client, &streaming.MockStreamProvisionerClient{}, time.Minute, 100, log)
+			lagMonitor.Clock = lagClock
+			lagMonitor.RecordMirror(types.NamespacedName{Namespace: testNamespace, Name: "unobserved-mirror"}, streaming.ConsumerLag{
+				Partitions: []streaming.PartitionLag{
+					{Partition: 0, Offset: 40, Lag: 2},
+				},
+			})
+			lagClock.Step(4 * time.Minute)
+			lagMonitor.RecordMirror(testKey, streaming.ConsumerLag{
+				Partitions: []streaming.PartitionLag{
+					{Partition: 0, Offset: 40, Lag: 2},
+					{Partition: 1, Offset: 35, Lag: 0},
+				},
+			})
+			return streaming.StreamMirrorLagReconciler(
+				controllers.Config{
+					Client:    client,
+					APIReader: client,
+					Recorder:  recorder,
+					Log:       log,
+					Scheme:    scheme,
+					Tracker:   tracker,
+				},
+				lagMonitor,
+			)
+		})
+	})
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+)
+
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streamquotas,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streamquotas/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streams,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+func StreamQuotaReconciler(c controllers.Config) *controllers.ParentReconciler {
+	c.Log = c.Log.WithName("StreamQuota")
+
+	return &controllers.ParentReconciler{
+		Type: &streamingv1alpha1.StreamQuota{},
+		SubReconcilers: []controllers.SubReconciler{
+			StreamQuotaSyncUsage(c),
+		},
+
+		Config: c,
+	}
+}
+
+func StreamQuotaSyncUsage(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("SyncUsage")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, quota *streamingv1alpha1.StreamQuota) error {
+			var streams streamingv1alpha1.StreamList
+			if err := c.List(ctx, &streams, client.InNamespace(quota.Namespace)); err != nil {
+				return err
+			}
+			quota.Status.Streams, quota.Status.Partitions = streamQuotaUsed(streams.Items, "")
+
+			// streams created before the quota are not rejected, but are
+			// reported against it
+			quota.Status.MarkWithinQuota()
+			if max := quota.Spec.MaxStreams; max != nil && quota.Status.Streams > *max {
+				quota.Status.MarkQuotaExceeded("%d of %d streams in use", quota.Status.Streams, *max)
+				return nil
+			}
+			if max := quota.Spec.MaxPartitions; max != nil && quota.Status.Partitions > *max {
+				quota.Status.MarkQuotaExceeded("%d of %d partitions in use", quota.Status.Partitions, *max)
+				return nil
+			}
+			for _, stream := range streams.Items {
+				if stream.DeletionTimestamp == nil && !quota.AllowsGateway(stream.Spec.Gateway.Name) {
+					quota.Status.MarkQuotaExceeded("stream %q uses gateway %q which is not allowed", stream.Name, stream.Spec.Gateway.Name)
+					return nil
+				}
+			}
+
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			// streams reconcile each quota in their namespace
+			bldr.Watches(&source.Kind{Type: &streamingv1alpha1.Stream{}}, &handler.EnqueueRequestsFromMapFunc{
+				ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+					var quotas streamingv1alpha1.StreamQuotaList
+					if err := c.List(context.Background(), &quotas, client.InNamespace(a.Meta.GetNamespace())); err != nil {
+						c.Log.Error(err, "unable to list stream quotas", "namespace", a.Meta.GetNamespace())
+						return nil
+					}
+					requests := make([]reconcile.Request, len(quotas.Items))
+					for i, quota := range quotas.Items {
+						requests[i] = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: quota.Namespace, Name: quota.Name}}
+					}
+					return requests
+				}),
+			})
+			return nil
+		},
+	}
+}
+
+// streamQuotaUsed counts the streams, and their partitions, held against the
+// quotas of a namespace. The named stream is excluded so it may be admitted in
+// its place.
+func streamQuotaUsed(streams []streamingv1alpha1.Stream, exclude string) (int32, int32) {
+	var count, partitions int32
+	for i := range streams {
+		stream := &streams[i]
+		if stream.Name == exclude || stream.DeletionTimestamp != nil {
+			continue
+		}
+		count++
+		partitions += stream.GetPartitions()
+	}
+	return count, partitions
+}
+
+// admitStream checks the stream against a quota given the streams and
+// partitions already in use. Limits are only checked for the change an update
+// makes, so existing streams remain updatable after a quota is lowered.
+func admitStream(quota *streamingv1alpha1.StreamQuota, stream, old *streamingv1alpha1.Stream, count, partitions int32) error {
+	gateway := stream.Spec.Gateway.Name
+	if (old == nil || old.Spec.Gateway.Name != gateway) && !quota.AllowsGateway(gateway) {
+		return fmt.Errorf("stream quota %q does not allow gateway %q, allowed gateways are %v", quota.Name, gateway, quota.Spec.Gateways)
+	}
+	if max := quota.Spec.MaxStreams; old == nil && max != nil && count >= *max {
+		return fmt.Errorf("stream quota %q exceeded: %d of %d streams in use", quota.Name, count, *max)
+	}
+	requested := stream.GetPartitions()
+	if max := quota.Spec.MaxPartitions; max != nil && (old == nil || requested > old.GetPartitions()) && partitions+requested > *max {
+		return fmt.Errorf("stream quota %q exceeded: %d partitions requested, %d of %d partitions in use", quota.Name, requested, partitions, *max)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming_test
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/controllers/streaming"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/controllers/testing/factories"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+func TestStreamQuotaReconciler(t *testing.T) {
+	testNamespace := "test-namespace"
+	testName := "test-quota"
+	testKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
+
+	streamQuotaConditionReady := factories.Condition().Type(streamingv1alpha1.StreamQuotaConditionReady)
+	streamQuotaConditionWithinQuota := factories.Condition().Type(streamingv1alpha1.StreamQuotaConditionWithinQuota)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	quota := factories.StreamQuota().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.Generation(1)
+		}).
+		MaxStreams(2).
+		MaxPartitions(4).
+		Gateways("kafka")
+
+	stream1 := factories.Stream().
+		NamespaceName(testNamespace, "stream-1").
+		Gateway("kafka")
+	stream2 := factories.Stream().
+		NamespaceName(testNamespace, "stream-2").
+		Gateway("kafka").
+		Partitions(3)
+	otherStream := factories.Stream().
+		NamespaceName("other-namespace", "other-stream").
+		Gateway("other")
+
+	table := rtesting.Table{{
+		Name: "quota does not exist",
+		Key:  testKey,
+	}, {
+		Name: "ignore deleted quota",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			quota.
+				ObjectMeta(func(om factories.ObjectMeta) {
+					om.Deleted(1)
+				}),
+		},
+	}, {
+		Name: "error fetching quota",
+		Key:  testKey,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("get", "StreamQuota"),
+		},
+		ShouldErr: true,
+	}, {
+		Name: "error listing streams",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			quota,
+		},
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("list", "StreamList"),
+		},
+		ShouldErr: true,
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(quota, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			quota.
+				StatusConditions(
+					streamQuotaConditionReady.Unknown(),
+					streamQuotaConditionWithinQuota.Unknown(),
+				),
+		},
+	}, {
+		Name: "within quota",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			quota,
+			stream1,
+			stream2,
+			otherStream,
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(quota, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			quota.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					streamQuotaConditionReady.True(),
+					streamQuotaConditionWithinQuota.True(),
+				).
+				StatusUsed(2, 4),
+		},
+	}, {
+		Name: "ignore deleted streams",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			quota,
+			stream1,
+			stream2.
+				ObjectMeta(func(om factories.ObjectMeta) {
+					om.Deleted(1)
+				}),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(quota, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			quota.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					streamQuotaConditionReady.True(),
+					streamQuotaConditionWithinQuota.True(),
+				).
+				StatusUsed(1, 1),
+		},
+	}, {
+		Name: "streams exceeded",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			quota.
+				MaxStreams(1),
+			stream1,
+			stream2,
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(quota, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			quota.
+				MaxStreams(1).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					streamQuotaConditionReady.False().Reason("QuotaExceeded", "2 of 1 streams in use"),
+					streamQuotaConditionWithinQuota.False().Reason("QuotaExceeded", "2 of 1 streams in use"),
+				).
+				StatusUsed(2, 4),
+		},
+	}, {
+		Name: "partitions exceeded",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			quota.
+				MaxPartitions(3),
+			stream1,
+			stream2,
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(quota, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			quota.
+				MaxPartitions(3).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					streamQuotaConditionReady.False().Reason("QuotaExceeded", "4 of 3 partitions in use"),
+					streamQuotaConditionWithinQuota.False().Reason("QuotaExceeded", "4 of 3 partitions in use"),
+				).
+				StatusUsed(2, 4),
+		},
+	}, {
+		Name: "gateway not allowed",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			quota,
+			stream1,
+			stream2.
+				Gateway("pulsar"),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(quota, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			quota.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					streamQuotaConditionReady.False().Reason("QuotaExceeded", `stream "stream-2" uses gateway "pulsar" which is not allowed`),
+					streamQuotaConditionWithinQuota.False().Reason("QuotaExceeded", `stream "stream-2" uses gateway "pulsar" which is not allowed`),
+				).
+				StatusUsed(2, 4),
+		},
+	}}
+
+	table.Test(t, scheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
+		return streaming.StreamQuotaReconciler(
+			controllers.Config{
+				Client:    client,
+				APIReader: apiReader,
+				Recorder:  recorder,
+				Log:       log,
+				Scheme:    scheme,
+				Tracker:   tracker,
+			},
+		)
+	})
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-streaming-projectriff-io-v1alpha1-stream-quota,mutating=false,failurePolicy=fail,groups=streaming.projectriff.io,resources=streams,verbs=create;update,versions=v1alpha1,name=quota.streams.streaming.projectriff.io
+
+// StreamQuotaWebhookPath is the path the stream quota webhook is served from
+const StreamQuotaWebhookPath = "/validate-streaming-projectriff-io-v1alpha1-stream-quota"
+
+// StreamQuotaWebhook admits streams within the stream quotas of their
+// namespace. Streams are rejected that would exceed the streams or partitions
+// allowed by a quota, or that use a gateway a quota does not allow.
+func StreamQuotaWebhook(c client.Client, log logr.Logger) *webhook.Admission {
+	return &webhook.Admission{
+		Handler: &streamQuotaHandler{
+			client: c,
+			log:    log,
+		},
+	}
+}
+
+type streamQuotaHandler struct {
+	client client.Client
+	log    logr.Logger
+}
+
+func (h *streamQuotaHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	stream := &streamingv1alpha1.Stream{}
+	if err := json.Unmarshal(req.Object.Raw, stream); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	var old *streamingv1alpha1.Stream
+	if req.Operation == admissionv1beta1.Update {
+		old = &streamingv1alpha1.Stream{}
+		if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	var quotas streamingv1alpha1.StreamQuotaList
+	if err := h.client.List(ctx, &quotas, client.InNamespace(req.Namespace)); err != nil {
+		h.log.Error(err, "unable to list stream quotas", "namespace", req.Namespace)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(quotas.Items) == 0 {
+		return admission.Allowed("")
+	}
+
+	var streams streamingv1alpha1.StreamList
+	if err := h.client.List(ctx, &streams, client.InNamespace(req.Namespace)); err != nil {
+		h.log.Error(err, "unable to list streams", "namespace", req.Namespace)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	count, partitions := streamQuotaUsed(streams.Items, req.Name)
+	for i := range quotas.Items {
+		if err := admitStream(&quotas.Items[i], stream, old, count, partitions); err != nil {
+			return admission.Denied(err.Error())
+		}
+	}
+
+	return admission.Allowed("")
+}
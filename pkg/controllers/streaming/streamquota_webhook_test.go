@@ -0,0 +1,150 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers/streaming"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/controllers/testing/factories"
+)
+
+func TestStreamQuotaWebhook(t *testing.T) {
+	testNamespace := "test-namespace"
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	quota := factories.StreamQuota().
+		NamespaceName(testNamespace, "test-quota").
+		MaxStreams(2).
+		MaxPartitions(4).
+		Gateways("kafka")
+	existing := factories.Stream().
+		NamespaceName(testNamespace, "existing").
+		Gateway("kafka").
+		Partitions(2)
+	legacy := factories.Stream().
+		NamespaceName(testNamespace, "legacy").
+		Gateway("pulsar")
+	other := factories.Stream().
+		NamespaceName("other-namespace", "other").
+		Gateway("pulsar").
+		Partitions(10)
+	stream := factories.Stream().
+		NamespaceName(testNamespace, "test-stream").
+		Gateway("kafka")
+
+	for _, c := range []struct {
+		name     string
+		given    []rtesting.Factory
+		stream   rtesting.Factory
+		old      rtesting.Factory
+		expected string
+	}{{
+		name:   "no quota",
+		given:  []rtesting.Factory{existing, legacy},
+		stream: stream.Gateway("pulsar").Partitions(10),
+	}, {
+		name:   "within quota",
+		given:  []rtesting.Factory{quota, existing, other},
+		stream: stream.Partitions(2),
+	}, {
+		name:     "streams exceeded",
+		given:    []rtesting.Factory{quota, existing, legacy, other},
+		stream:   stream,
+		expected: `stream quota "test-quota" exceeded: 2 of 2 streams in use`,
+	}, {
+		name:   "update at stream limit",
+		given:  []rtesting.Factory{quota, existing, legacy},
+		stream: existing.ContentType("text/plain"),
+		old:    existing,
+	}, {
+		name:     "partitions exceeded",
+		given:    []rtesting.Factory{quota, existing},
+		stream:   stream.Partitions(3),
+		expected: `stream quota "test-quota" exceeded: 3 partitions requested, 2 of 4 partitions in use`,
+	}, {
+		name:     "partitions increased beyond quota",
+		given:    []rtesting.Factory{quota, existing, stream.Partitions(2)},
+		stream:   existing.Partitions(3),
+		old:      existing,
+		expected: `stream quota "test-quota" exceeded: 3 partitions requested, 2 of 4 partitions in use`,
+	}, {
+		name:   "partitions decreased over quota",
+		given:  []rtesting.Factory{quota.MaxPartitions(1), existing, stream},
+		stream: existing.Partitions(1),
+		old:    existing,
+	}, {
+		name:     "gateway not allowed",
+		given:    []rtesting.Factory{quota},
+		stream:   stream.Gateway("pulsar"),
+		expected: `stream quota "test-quota" does not allow gateway "pulsar", allowed gateways are [kafka]`,
+	}, {
+		name:     "gateway changed to one not allowed",
+		given:    []rtesting.Factory{quota, existing},
+		stream:   existing.Gateway("pulsar"),
+		old:      existing,
+		expected: `stream quota "test-quota" does not allow gateway "pulsar", allowed gateways are [kafka]`,
+	}, {
+		name:   "update on gateway no longer allowed",
+		given:  []rtesting.Factory{quota, legacy},
+		stream: legacy.ContentType("text/plain"),
+		old:    legacy,
+	}} {
+		t.Run(c.name, func(t *testing.T) {
+			objects := []runtime.Object{}
+			for _, f := range c.given {
+				objects = append(objects, f.CreateObject())
+			}
+			webhook := streaming.StreamQuotaWebhook(fake.NewFakeClientWithScheme(scheme, objects...), rtesting.TestLogger(t))
+
+			target := c.stream.CreateObject()
+			req := admission.Request{
+				AdmissionRequest: admissionv1beta1.AdmissionRequest{
+					Operation: admissionv1beta1.Create,
+					Namespace: target.GetNamespace(),
+					Name:      target.GetName(),
+				},
+			}
+			req.Object.Raw, _ = json.Marshal(target)
+			if c.old != nil {
+				req.Operation = admissionv1beta1.Update
+				req.OldObject.Raw, _ = json.Marshal(c.old.CreateObject())
+			}
+
+			resp := webhook.Handle(context.Background(), req)
+			if expected, actual := c.expected == "", resp.Allowed; expected != actual {
+				t.Errorf("expected allowed %v, got %v: %+v", expected, actual, resp.Result)
+			}
+			if c.expected != "" && string(resp.Result.Reason) != c.expected {
+				t.Errorf("expected reason %q, got %q", c.expected, resp.Result.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/projectriff/system/pkg/apis"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/refs"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+const (
+	StreamTapImagesStashKey  controllers.StashKey = "stream-tap-images"
+	StreamTapStreamStashKey  controllers.StashKey = "stream-tap-stream"
+	StreamTapExpiredStashKey controllers.StashKey = "stream-tap-expired"
+)
+
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streamtaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streamtaps/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=streaming.projectriff.io,resources=streams,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+func StreamTapReconciler(c controllers.Config, namespace string, clock clock.Clock) *controllers.ParentReconciler {
+	c.Log = c.Log.WithName("StreamTap")
+
+	return &controllers.ParentReconciler{
+		Type: &streamingv1alpha1.StreamTap{},
+		SubReconcilers: []controllers.SubReconciler{
+			StreamTapSyncImages(c, namespace),
+			StreamTapExpirationReconciler(c, clock),
+			StreamTapResolveStreamReconciler(c),
+			StreamTapChildDeploymentReconciler(c),
+		},
+
+		Config: c,
+	}
+}
+
+func StreamTapSyncImages(c controllers.Config, namespace string) controllers.SubReconciler {
+	c.Log = c.Log.WithName("SyncImages")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.StreamTap) error {
+			config := corev1.ConfigMap{}
+			key := types.NamespacedName{Namespace: namespace, Name: streamTapImages}
+			// track config for new images
+			c.Tracker.Track(
+				tracker.NewKey(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, key),
+				types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+			)
+			if err := c.Get(ctx, key, &config); err != nil {
+				return err
+			}
+			controllers.StashValue(ctx, StreamTapImagesStashKey, config.Data)
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &corev1.ConfigMap{}}, controllers.EnqueueTracked(&corev1.ConfigMap{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
+
+func StreamTapExpirationReconciler(c controllers.Config, clock clock.Clock) controllers.SubReconciler {
+	c.Log = c.Log.WithName("Expiration")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.StreamTap) (ctrl.Result, error) {
+			expiration := metav1.NewTime(parent.CreationTimestamp.Add(parent.Spec.TTL.Duration))
+			parent.Status.ExpirationTime = &expiration
+
+			remaining := expiration.Sub(clock.Now())
+			if remaining <= 0 {
+				parent.Status.MarkExpired()
+				controllers.StashValue(ctx, StreamTapExpiredStashKey, true)
+				return ctrl.Result{}, nil
+			}
+			// reconcile again once the tap expires
+			return ctrl.Result{RequeueAfter: remaining}, nil
+		},
+
+		Config: c,
+	}
+}
+
+func StreamTapResolveStreamReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ResolveStream")
+
+	return &controllers.SyncReconciler{
+		Sync: func(ctx context.Context, parent *streamingv1alpha1.StreamTap) error {
+			var stream streamingv1alpha1.Stream
+			key := types.NamespacedName{Namespace: parent.Namespace, Name: parent.Spec.Stream}
+			// track stream for new coordinates
+			c.Tracker.Track(
+				tracker.NewKey(stream.GetGroupVersionKind(), key),
+				types.NamespacedName{Namespace: parent.Namespace, Name: parent.Name},
+			)
+			if err := c.Get(ctx, key, &stream); err != nil {
+				if apierrs.IsNotFound(err) {
+					parent.Status.MarkStreamNotReady(fmt.Sprintf("stream %s not found", parent.Spec.Stream))
+					return nil
+				}
+				return err
+			}
+
+			ready := stream.Status.GetCondition(stream.Status.GetReadyConditionType())
+			if ready == nil {
+				ready = &apis.Condition{Message: "stream has no ready condition"}
+			}
+			if !ready.IsTrue() {
+				parent.Status.MarkStreamNotReady(fmt.Sprintf("stream %s is not ready: %s", stream.Name, ready.Message))
+				return nil
+			}
+			parent.Status.MarkStreamReady()
+			controllers.StashValue(ctx, StreamTapStreamStashKey, &stream)
+
+			return nil
+		},
+
+		Config: c,
+		Setup: func(mgr controllers.Manager, bldr *controllers.Builder) error {
+			bldr.Watches(&source.Kind{Type: &streamingv1alpha1.Stream{}}, controllers.EnqueueTracked(&streamingv1alpha1.Stream{}, c.Tracker, c.Scheme))
+			return nil
+		},
+	}
+}
+
+func StreamTapChildDeploymentReconciler(c controllers.Config) controllers.SubReconciler {
+	c.Log = c.Log.WithName("ChildDeployment")
+
+	one := int32(1)
+
+	return &controllers.ChildReconciler{
+		ParentType:    &streamingv1alpha1.StreamTap{},
+		ChildType:     &appsv1.Deployment{},
+		ChildListType: &appsv1.DeploymentList{},
+
+		DesiredChild: func(ctx context.Context, parent *streamingv1alpha1.StreamTap) (*appsv1.Deployment, error) {
+			if expired, _ := controllers.RetrieveValue(ctx, StreamTapExpiredStashKey).(bool); expired {
+				// expired taps stop consuming
+				return nil, nil
+			}
+			stream, ok := controllers.RetrieveValue(ctx, StreamTapStreamStashKey).(*streamingv1alpha1.Stream)
+			if !ok {
+				return nil, nil
+			}
+			images, ok := controllers.RetrieveValue(ctx, StreamTapImagesStashKey).(map[string]string)
+			if !ok {
+				return nil, nil
+			}
+			image := images[streamTapImageKey]
+			if image == "" {
+				return nil, nil
+			}
+
+			// the tap locates the gateway and topic from the stream's binding
+			volumes, volumeMounts := constructBindingVolumes([]streamingv1alpha1.Stream{*stream}, nil, streamBinding)
+			labels := controllers.MergeMaps(parent.Labels, map[string]string{
+				streamingv1alpha1.StreamTapLabelKey: parent.Name,
+			})
+			child := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: fmt.Sprintf("%s-tap-", parent.Name),
+					Namespace:    parent.Namespace,
+					Labels:       labels,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: &one,
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							streamingv1alpha1.StreamTapLabelKey: parent.Name,
+						},
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: labels,
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name:  "tap",
+									Image: image,
+									Env: []corev1.EnvVar{
+										{Name: "CNB_BINDINGS", Value: bindingsRootPath},
+										{Name: "INPUT_NAMES", Value: stream.Name},
+										{Name: "TAP_COUNT", Value: fmt.Sprintf("%d", *parent.Spec.Count)},
+									},
+									VolumeMounts: volumeMounts,
+								},
+							},
+							Volumes: volumes,
+						},
+					},
+				},
+			}
+
+			return child, nil
+		},
+		ReflectChildStatusOnParent: func(parent *streamingv1alpha1.StreamTap, child *appsv1.Deployment, err error) {
+			if err != nil {
+				return
+			}
+			if child == nil {
+				parent.Status.DeploymentRef = nil
+			} else {
+				parent.Status.DeploymentRef = refs.NewTypedLocalObjectReferenceForObject(child, c.Scheme)
+				parent.Status.PropagateDeploymentStatus(&child.Status)
+			}
+		},
+		MergeBeforeUpdate: func(current, desired *appsv1.Deployment) {
+			current.Labels = desired.Labels
+			current.Spec = desired.Spec
+		},
+		SemanticEquals: func(a1, a2 *appsv1.Deployment) bool {
+			return equality.Semantic.DeepEqual(a1.Spec, a2.Spec) &&
+				equality.Semantic.DeepEqual(a1.Labels, a2.Labels)
+		},
+
+		Config:     c,
+		IndexField: ".metadata.streamTapDeploymentController",
+		Sanitize: func(child *appsv1.Deployment) interface{} {
+			return child.Spec
+		},
+	}
+}
@@ -0,0 +1,439 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streaming_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	"github.com/projectriff/system/pkg/controllers"
+	"github.com/projectriff/system/pkg/controllers/streaming"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/controllers/testing/factories"
+	"github.com/projectriff/system/pkg/tracker"
+)
+
+func TestStreamTapReconciler(t *testing.T) {
+	testNamespace := "test-namespace"
+	testSystemNamespace := "system-namespace"
+	testName := "test-tap"
+	testKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
+	testTapImage := "example.com/repo/tap"
+	// the tap is created at 1s, the default ttl expires it at 601s
+	testNow := time.Unix(61, 0)
+
+	streamTapImages := "riff-streaming-stream-tap" // contains image names for the stream tap
+	streamTapImageKey := "tapImage"
+
+	streamTapConditionReady := factories.Condition().Type(streamingv1alpha1.StreamTapConditionReady)
+	streamTapConditionStreamReady := factories.Condition().Type(streamingv1alpha1.StreamTapConditionStreamReady)
+	streamTapConditionTapReady := factories.Condition().Type(streamingv1alpha1.StreamTapConditionTapReady)
+	deploymentConditionAvailable := factories.Condition().Type("Available")
+	deploymentConditionProgressing := factories.Condition().Type("Progressing")
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = streamingv1alpha1.AddToScheme(scheme)
+
+	streamTapImagesConfigMap := factories.ConfigMap().
+		NamespaceName(testSystemNamespace, streamTapImages).
+		AddData(streamTapImageKey, testTapImage)
+
+	testStream := factories.Stream().
+		NamespaceName(testNamespace, "my-stream").
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.UID("00000000-0000-0000-0000-000000000001")
+		}).
+		StatusBinding("my-stream-binding-metadata", "my-stream-binding-secret").
+		StatusReady()
+
+	tapMinimal := factories.StreamTap().
+		NamespaceName(testNamespace, testName).
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Created(1)
+			om.Generation(1)
+		}).
+		Stream(testStream.Create().Name).
+		Default()
+	tap := tapMinimal.
+		StatusExpirationTime(601).
+		StatusDeploymentRef("%s-tap-000", testName)
+	expiredTap := tapMinimal.
+		TTL(30 * time.Second)
+
+	deploymentCreate := factories.Deployment().
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Namespace(testNamespace)
+			om.GenerateName("%s-tap-", testName)
+			om.AddLabel(streamingv1alpha1.StreamTapLabelKey, testName)
+			om.ControlledBy(tap, scheme)
+		}).
+		Replicas(1).
+		AddSelectorLabel(streamingv1alpha1.StreamTapLabelKey, testName).
+		PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+			pts.ContainerNamed("tap", func(c *corev1.Container) {
+				c.Image = testTapImage
+				c.Env = []corev1.EnvVar{
+					{Name: "CNB_BINDINGS", Value: "/var/riff/bindings"},
+					{Name: "INPUT_NAMES", Value: "my-stream"},
+					{Name: "TAP_COUNT", Value: "10"},
+				}
+				c.VolumeMounts = []corev1.VolumeMount{
+					{
+						Name:      "stream-00000000-0000-0000-0000-000000000001-metadata",
+						MountPath: "/var/riff/bindings/input_000/metadata",
+						ReadOnly:  true,
+					},
+					{
+						Name:      "stream-00000000-0000-0000-0000-000000000001-secret",
+						MountPath: "/var/riff/bindings/input_000/secret",
+						ReadOnly:  true,
+					},
+				}
+			})
+			pts.Volumes(
+				corev1.Volume{
+					Name: "stream-00000000-0000-0000-0000-000000000001-metadata",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: "my-stream-binding-metadata",
+							},
+						},
+					},
+				},
+				corev1.Volume{
+					Name: "stream-00000000-0000-0000-0000-000000000001-secret",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "my-stream-binding-secret",
+						},
+					},
+				},
+			)
+		})
+	deploymentGiven := deploymentCreate.
+		ObjectMeta(func(om factories.ObjectMeta) {
+			om.Name("%s%s", om.Create().GenerateName, "000")
+			om.Created(1)
+		})
+
+	table := rtesting.Table{{
+		Name: "tap does not exist",
+		Key:  testKey,
+	}, {
+		Name: "ignore deleted tap",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			tap.
+				ObjectMeta(func(om factories.ObjectMeta) {
+					om.Deleted(1)
+				}),
+		},
+	}, {
+		Name: "error fetching tap",
+		Key:  testKey,
+		WithReactors: []rtesting.ReactionFunc{
+			rtesting.InduceFailure("get", "StreamTap"),
+		},
+		ShouldErr: true,
+	}, {
+		Name: "create deployment",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			tapMinimal,
+			streamTapImagesConfigMap,
+			testStream,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamTapImagesConfigMap, tapMinimal, scheme),
+			rtesting.NewTrackRequest(testStream, tapMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(tapMinimal, scheme, corev1.EventTypeNormal, "Created",
+				`Created Deployment "%s-tap-001"`, testName),
+			rtesting.NewEvent(tapMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			deploymentCreate,
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			tapMinimal.
+				StatusConditions(
+					streamTapConditionReady.Unknown(),
+					streamTapConditionStreamReady.True(),
+					streamTapConditionTapReady.Unknown(),
+				).
+				StatusObservedGeneration(1).
+				StatusExpirationTime(601).
+				StatusDeploymentRef("%s-tap-001", testName),
+		},
+		ExpectedResult: ctrl.Result{RequeueAfter: 9 * time.Minute},
+	}, {
+		Name: "create deployment, with count",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			tapMinimal.
+				Count(3),
+			streamTapImagesConfigMap,
+			testStream,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamTapImagesConfigMap, tapMinimal, scheme),
+			rtesting.NewTrackRequest(testStream, tapMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(tapMinimal, scheme, corev1.EventTypeNormal, "Created",
+				`Created Deployment "%s-tap-001"`, testName),
+			rtesting.NewEvent(tapMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectCreates: []rtesting.Factory{
+			deploymentCreate.
+				PodTemplateSpec(func(pts factories.PodTemplateSpec) {
+					pts.ContainerNamed("tap", func(c *corev1.Container) {
+						c.Env[2].Value = "3"
+					})
+				}),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			tapMinimal.
+				Count(3).
+				StatusConditions(
+					streamTapConditionReady.Unknown(),
+					streamTapConditionStreamReady.True(),
+					streamTapConditionTapReady.Unknown(),
+				).
+				StatusObservedGeneration(1).
+				StatusExpirationTime(601).
+				StatusDeploymentRef("%s-tap-001", testName),
+		},
+		ExpectedResult: ctrl.Result{RequeueAfter: 9 * time.Minute},
+	}, {
+		Name: "ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			tap.
+				StatusObservedGeneration(1).
+				StatusConditions(
+					streamTapConditionReady.True(),
+					streamTapConditionStreamReady.True(),
+					streamTapConditionTapReady.True(),
+				),
+			streamTapImagesConfigMap,
+			testStream,
+			deploymentGiven.
+				StatusConditions(
+					deploymentConditionAvailable.True(),
+					deploymentConditionProgressing.True(),
+				),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamTapImagesConfigMap, tap, scheme),
+			rtesting.NewTrackRequest(testStream, tap, scheme),
+		},
+		ExpectedResult: ctrl.Result{RequeueAfter: 9 * time.Minute},
+	}, {
+		Name: "expired, delete deployment",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			expiredTap.
+				StatusExpirationTime(31).
+				StatusDeploymentRef("%s-tap-000", testName).
+				StatusObservedGeneration(1).
+				StatusConditions(
+					streamTapConditionReady.True(),
+					streamTapConditionStreamReady.True(),
+					streamTapConditionTapReady.True(),
+				),
+			streamTapImagesConfigMap,
+			testStream,
+			deploymentGiven,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamTapImagesConfigMap, expiredTap, scheme),
+			rtesting.NewTrackRequest(testStream, expiredTap, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(expiredTap, scheme, corev1.EventTypeNormal, "Deleted",
+				`Deleted Deployment "%s-tap-000"`, testName),
+			rtesting.NewEvent(expiredTap, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectDeletes: []rtesting.DeleteRef{
+			{Group: "apps", Kind: "Deployment", Namespace: testNamespace, Name: fmt.Sprintf("%s-tap-000", testName)},
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			expiredTap.
+				StatusConditions(
+					streamTapConditionReady.False().Reason("Expired", "the tap expired and stopped consuming"),
+					streamTapConditionStreamReady.True(),
+					streamTapConditionTapReady.False().Reason("Expired", "the tap expired and stopped consuming"),
+				).
+				StatusObservedGeneration(1).
+				StatusExpirationTime(31),
+		},
+	}, {
+		Name: "images missing",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			tapMinimal,
+			testStream,
+		},
+		ShouldErr: true,
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamTapImagesConfigMap, tapMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(tapMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			tapMinimal.
+				StatusConditions(
+					streamTapConditionReady.Unknown(),
+					streamTapConditionStreamReady.Unknown(),
+					streamTapConditionTapReady.Unknown(),
+				),
+		},
+	}, {
+		Name: "stream not found",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			tapMinimal,
+			streamTapImagesConfigMap,
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamTapImagesConfigMap, tapMinimal, scheme),
+			rtesting.NewTrackRequest(testStream, tapMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(tapMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			tapMinimal.
+				StatusConditions(
+					streamTapConditionReady.False().Reason("StreamNotReady", "stream my-stream not found"),
+					streamTapConditionStreamReady.False().Reason("StreamNotReady", "stream my-stream not found"),
+					streamTapConditionTapReady.Unknown(),
+				).
+				StatusObservedGeneration(1).
+				StatusExpirationTime(601),
+		},
+		ExpectedResult: ctrl.Result{RequeueAfter: 9 * time.Minute},
+	}, {
+		Name: "stream not ready",
+		Key:  testKey,
+		GivenObjects: []rtesting.Factory{
+			tapMinimal,
+			streamTapImagesConfigMap,
+			testStream.
+				StatusConditions(
+					factories.Condition().Type(streamingv1alpha1.StreamConditionReady).False().Reason("Oops", "the gateway is down"),
+				),
+		},
+		ExpectTracks: []rtesting.TrackRequest{
+			rtesting.NewTrackRequest(streamTapImagesConfigMap, tapMinimal, scheme),
+			rtesting.NewTrackRequest(testStream, tapMinimal, scheme),
+		},
+		ExpectEvents: []rtesting.Event{
+			rtesting.NewEvent(tapMinimal, scheme, corev1.EventTypeNormal, "StatusUpdated",
+				`Updated status`),
+		},
+		ExpectStatusUpdates: []rtesting.Factory{
+			tapMinimal.
+				StatusConditions(
+					streamTapConditionReady.False().Reason("StreamNotReady", "stream my-stream is not ready: the gateway is down"),
+					streamTapConditionStreamReady.False().Reason("StreamNotReady", "stream my-stream is not ready: the gateway is down"),
+					streamTapConditionTapReady.Unknown(),
+				).
+				StatusObservedGeneration(1).
+				StatusExpirationTime(601),
+		},
+		ExpectedResult: ctrl.Result{RequeueAfter: 9 * time.Minute},
+	}}
+
+	table.Test(t, scheme, func(t *testing.T, row *rtesting.Testcase, client client.Client, apiReader client.Reader, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) reconcile.Reconciler {
+		return streaming.StreamTapReconciler(
+			controllers.Config{
+				Client:    client,
+				APIReader: apiReader,
+				Recorder:  recorder,
+				Log:       log,
+				Scheme:    scheme,
+				Tracker:   tracker,
+			},
+			testSystemNamespace,
+			clock.NewFakeClock(testNow),
+		)
+	})
+
+	t.Run("StreamTapExpirationReconciler", func(t *testing.T) {
+		table := rtesting.SubTable{
+			{
+				Name:   "requeue until expired",
+				Parent: tapMinimal,
+				ExpectParent: tapMinimal.
+					StatusExpirationTime(601),
+				ExpectedResult: ctrl.Result{RequeueAfter: 9 * time.Minute},
+			},
+			{
+				Name:   "expired",
+				Parent: expiredTap,
+				ExpectParent: expiredTap.
+					StatusConditions(
+						streamTapConditionReady.False().Reason("Expired", "the tap expired and stopped consuming"),
+						streamTapConditionTapReady.False().Reason("Expired", "the tap expired and stopped consuming"),
+					).
+					StatusExpirationTime(31),
+				ExpectStashedValues: map[controllers.StashKey]interface{}{
+					streaming.StreamTapExpiredStashKey: true,
+				},
+			},
+		}
+
+		table.Test(t, scheme, func(t *testing.T, row *rtesting.SubTestcase, client client.Client, tracker tracker.Tracker, recorder record.EventRecorder, log logr.Logger) controllers.SubReconciler {
+			return streaming.StreamTapExpirationReconciler(
+				controllers.Config{
+					Client:    client,
+					APIReader: client,
+					Recorder:  recorder,
+					Log:       log,
+					Scheme:    scheme,
+					Tracker:   tracker,
+				},
+				clock.NewFakeClock(testNow),
+			)
+		})
+	})
+}
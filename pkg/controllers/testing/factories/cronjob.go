@@ -0,0 +1,117 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectriff/system/pkg/apis"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+)
+
+type cronJob struct {
+	target *batchv1beta1.CronJob
+}
+
+var (
+	_ rtesting.Factory = (*cronJob)(nil)
+)
+
+func CronJob(seed ...*batchv1beta1.CronJob) *cronJob {
+	var target *batchv1beta1.CronJob
+	switch len(seed) {
+	case 0:
+		target = &batchv1beta1.CronJob{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &cronJob{
+		target: target,
+	}
+}
+
+func (f *cronJob) deepCopy() *cronJob {
+	return CronJob(f.target.DeepCopy())
+}
+
+func (f *cronJob) Create() *batchv1beta1.CronJob {
+	return f.deepCopy().target
+}
+
+func (f *cronJob) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *cronJob) mutation(m func(*batchv1beta1.CronJob)) *cronJob {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *cronJob) NamespaceName(namespace, name string) *cronJob {
+	return f.mutation(func(cj *batchv1beta1.CronJob) {
+		cj.ObjectMeta.Namespace = namespace
+		cj.ObjectMeta.Name = name
+	})
+}
+
+func (f *cronJob) ObjectMeta(nf func(ObjectMeta)) *cronJob {
+	return f.mutation(func(cj *batchv1beta1.CronJob) {
+		omf := objectMeta(cj.ObjectMeta)
+		nf(omf)
+		cj.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *cronJob) Schedule(schedule string) *cronJob {
+	return f.mutation(func(cj *batchv1beta1.CronJob) {
+		cj.Spec.Schedule = schedule
+	})
+}
+
+func (f *cronJob) ConcurrencyPolicy(policy batchv1beta1.ConcurrencyPolicy) *cronJob {
+	return f.mutation(func(cj *batchv1beta1.CronJob) {
+		cj.Spec.ConcurrencyPolicy = policy
+	})
+}
+
+func (f *cronJob) AddJobTemplateLabel(key, value string) *cronJob {
+	return f.mutation(func(cj *batchv1beta1.CronJob) {
+		cj.Spec.JobTemplate.ObjectMeta = objectMeta(cj.Spec.JobTemplate.ObjectMeta).AddLabel(key, value).Create()
+		cj.Spec.JobTemplate.Spec.Template = podTemplateSpec(cj.Spec.JobTemplate.Spec.Template).AddLabel(key, value).Create()
+	})
+}
+
+func (f *cronJob) PodTemplateSpec(nf func(PodTemplateSpec)) *cronJob {
+	return f.mutation(func(cj *batchv1beta1.CronJob) {
+		ptsf := podTemplateSpec(cj.Spec.JobTemplate.Spec.Template)
+		nf(ptsf)
+		cj.Spec.JobTemplate.Spec.Template = ptsf.Create()
+	})
+}
+
+func (f *cronJob) StatusLastScheduleTime(sec int64) *cronJob {
+	return f.mutation(func(cj *batchv1beta1.CronJob) {
+		timestamp := metav1.Unix(sec, 0)
+		cj.Status.LastScheduleTime = &timestamp
+	})
+}
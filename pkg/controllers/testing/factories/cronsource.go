@@ -0,0 +1,142 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectriff/system/pkg/apis"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+type cronSource struct {
+	target *streamingv1alpha1.CronSource
+}
+
+var (
+	_ rtesting.Factory = (*cronSource)(nil)
+)
+
+func CronSource(seed ...*streamingv1alpha1.CronSource) *cronSource {
+	var target *streamingv1alpha1.CronSource
+	switch len(seed) {
+	case 0:
+		target = &streamingv1alpha1.CronSource{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &cronSource{
+		target: target,
+	}
+}
+
+func (f *cronSource) deepCopy() *cronSource {
+	return CronSource(f.target.DeepCopy())
+}
+
+func (f *cronSource) Create() *streamingv1alpha1.CronSource {
+	return f.deepCopy().target
+}
+
+func (f *cronSource) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *cronSource) mutation(m func(*streamingv1alpha1.CronSource)) *cronSource {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *cronSource) NamespaceName(namespace, name string) *cronSource {
+	return f.mutation(func(source *streamingv1alpha1.CronSource) {
+		source.ObjectMeta.Namespace = namespace
+		source.ObjectMeta.Name = name
+	})
+}
+
+func (f *cronSource) ObjectMeta(nf func(ObjectMeta)) *cronSource {
+	return f.mutation(func(source *streamingv1alpha1.CronSource) {
+		omf := objectMeta(source.ObjectMeta)
+		nf(omf)
+		source.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *cronSource) Schedule(schedule string) *cronSource {
+	return f.mutation(func(source *streamingv1alpha1.CronSource) {
+		source.Spec.Schedule = schedule
+	})
+}
+
+func (f *cronSource) Output(stream string) *cronSource {
+	return f.mutation(func(source *streamingv1alpha1.CronSource) {
+		source.Spec.Output = stream
+	})
+}
+
+func (f *cronSource) Payload(payload string) *cronSource {
+	return f.mutation(func(source *streamingv1alpha1.CronSource) {
+		source.Spec.Payload = payload
+	})
+}
+
+func (f *cronSource) StatusConditions(conditions ...*condition) *cronSource {
+	return f.mutation(func(source *streamingv1alpha1.CronSource) {
+		c := make([]apis.Condition, len(conditions))
+		for i, cg := range conditions {
+			c[i] = cg.Create()
+		}
+		source.Status.Conditions = c
+	})
+}
+
+func (f *cronSource) StatusObservedGeneration(generation int64) *cronSource {
+	return f.mutation(func(source *streamingv1alpha1.CronSource) {
+		source.Status.ObservedGeneration = generation
+	})
+}
+
+func (f *cronSource) StatusCronJobRef(format string, a ...interface{}) *cronSource {
+	return f.mutation(func(source *streamingv1alpha1.CronSource) {
+		source.Status.CronJobRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("batch"),
+			Kind:     "CronJob",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *cronSource) StatusLastScheduleTime(sec int64) *cronSource {
+	return f.mutation(func(source *streamingv1alpha1.CronSource) {
+		timestamp := metav1.Unix(sec, 0)
+		source.Status.LastScheduleTime = &timestamp
+	})
+}
+
+func (f *cronSource) StatusNextScheduleTime(sec int64) *cronSource {
+	return f.mutation(func(source *streamingv1alpha1.CronSource) {
+		timestamp := metav1.Unix(sec, 0)
+		source.Status.NextScheduleTime = &timestamp
+	})
+}
@@ -103,6 +103,12 @@ func (f *deployment) Replicas(replicas int32) *deployment {
 	})
 }
 
+func (f *deployment) Strategy(strategy appsv1.DeploymentStrategy) *deployment {
+	return f.mutation(func(deployment *appsv1.Deployment) {
+		deployment.Spec.Strategy = strategy
+	})
+}
+
 func (f *deployment) AddSelectorLabel(key, value string) *deployment {
 	return f.mutation(func(deployment *appsv1.Deployment) {
 		if deployment.Spec.Selector == nil {
@@ -128,3 +134,9 @@ func (f *deployment) StatusConditions(conditions ...*condition) *deployment {
 		deployment.Status.Conditions = c
 	})
 }
+
+func (f *deployment) StatusReplicas(replicas int32) *deployment {
+	return f.mutation(func(deployment *appsv1.Deployment) {
+		deployment.Status.Replicas = replicas
+	})
+}
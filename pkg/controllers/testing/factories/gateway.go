@@ -101,6 +101,18 @@ func (f *gateway) Ports(ports ...corev1.ServicePort) *gateway {
 	})
 }
 
+func (f *gateway) Replicas(replicas int32) *gateway {
+	return f.mutation(func(g *streamingv1alpha1.Gateway) {
+		g.Spec.Replicas = rtesting.Int32Ptr(replicas)
+	})
+}
+
+func (f *gateway) Exposure(exposure *streamingv1alpha1.GatewayExposure) *gateway {
+	return f.mutation(func(g *streamingv1alpha1.Gateway) {
+		g.Spec.Exposure = exposure
+	})
+}
+
 func (f *gateway) StatusConditions(conditions ...*condition) *gateway {
 	return f.mutation(func(g *streamingv1alpha1.Gateway) {
 		c := make([]apis.Condition, len(conditions))
@@ -149,3 +161,40 @@ func (f *gateway) StatusAddress(format string, a ...interface{}) *gateway {
 		}
 	})
 }
+
+func (f *gateway) StatusPodDisruptionBudgetRef(format string, a ...interface{}) *gateway {
+	return f.mutation(func(g *streamingv1alpha1.Gateway) {
+		g.Status.PodDisruptionBudgetRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("policy"),
+			Kind:     "PodDisruptionBudget",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *gateway) StatusPublicServiceRef(format string, a ...interface{}) *gateway {
+	return f.mutation(func(g *streamingv1alpha1.Gateway) {
+		g.Status.PublicServiceRef = &refs.TypedLocalObjectReference{
+			Kind: "Service",
+			Name: fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *gateway) StatusIngressRef(format string, a ...interface{}) *gateway {
+	return f.mutation(func(g *streamingv1alpha1.Gateway) {
+		g.Status.IngressRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("networking.k8s.io"),
+			Kind:     "Ingress",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *gateway) StatusPublicAddress(format string, a ...interface{}) *gateway {
+	return f.mutation(func(g *streamingv1alpha1.Gateway) {
+		g.Status.PublicAddress = &apis.Addressable{
+			URL: fmt.Sprintf(format, a...),
+		}
+	})
+}
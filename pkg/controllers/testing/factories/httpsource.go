@@ -0,0 +1,155 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+
+	"github.com/projectriff/system/pkg/apis"
+	corev1alpha1 "github.com/projectriff/system/pkg/apis/core/v1alpha1"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+type httpSource struct {
+	target *streamingv1alpha1.HTTPSource
+}
+
+var (
+	_ rtesting.Factory = (*httpSource)(nil)
+)
+
+func HTTPSource(seed ...*streamingv1alpha1.HTTPSource) *httpSource {
+	var target *streamingv1alpha1.HTTPSource
+	switch len(seed) {
+	case 0:
+		target = &streamingv1alpha1.HTTPSource{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &httpSource{
+		target: target,
+	}
+}
+
+func (f *httpSource) deepCopy() *httpSource {
+	return HTTPSource(f.target.DeepCopy())
+}
+
+func (f *httpSource) Create() *streamingv1alpha1.HTTPSource {
+	return f.deepCopy().target
+}
+
+func (f *httpSource) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *httpSource) mutation(m func(*streamingv1alpha1.HTTPSource)) *httpSource {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *httpSource) NamespaceName(namespace, name string) *httpSource {
+	return f.mutation(func(source *streamingv1alpha1.HTTPSource) {
+		source.ObjectMeta.Namespace = namespace
+		source.ObjectMeta.Name = name
+	})
+}
+
+func (f *httpSource) ObjectMeta(nf func(ObjectMeta)) *httpSource {
+	return f.mutation(func(source *streamingv1alpha1.HTTPSource) {
+		omf := objectMeta(source.ObjectMeta)
+		nf(omf)
+		source.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *httpSource) Output(stream string) *httpSource {
+	return f.mutation(func(source *streamingv1alpha1.HTTPSource) {
+		source.Spec.Output = stream
+	})
+}
+
+func (f *httpSource) IngressPolicy(policy corev1alpha1.IngressPolicy) *httpSource {
+	return f.mutation(func(source *streamingv1alpha1.HTTPSource) {
+		source.Spec.IngressPolicy = policy
+	})
+}
+
+func (f *httpSource) StatusConditions(conditions ...*condition) *httpSource {
+	return f.mutation(func(source *streamingv1alpha1.HTTPSource) {
+		c := make([]apis.Condition, len(conditions))
+		for i, cg := range conditions {
+			c[i] = cg.Create()
+		}
+		source.Status.Conditions = c
+	})
+}
+
+func (f *httpSource) StatusObservedGeneration(generation int64) *httpSource {
+	return f.mutation(func(source *streamingv1alpha1.HTTPSource) {
+		source.Status.ObservedGeneration = generation
+	})
+}
+
+func (f *httpSource) StatusDeploymentRef(format string, a ...interface{}) *httpSource {
+	return f.mutation(func(source *streamingv1alpha1.HTTPSource) {
+		source.Status.DeploymentRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("apps"),
+			Kind:     "Deployment",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *httpSource) StatusServiceRef(format string, a ...interface{}) *httpSource {
+	return f.mutation(func(source *streamingv1alpha1.HTTPSource) {
+		source.Status.ServiceRef = &refs.TypedLocalObjectReference{
+			APIGroup: nil,
+			Kind:     "Service",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *httpSource) StatusIngressRef(format string, a ...interface{}) *httpSource {
+	return f.mutation(func(source *streamingv1alpha1.HTTPSource) {
+		source.Status.IngressRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("networking.k8s.io"),
+			Kind:     "Ingress",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *httpSource) StatusAddressURL(format string, a ...interface{}) *httpSource {
+	return f.mutation(func(source *streamingv1alpha1.HTTPSource) {
+		source.Status.Address = &apis.Addressable{
+			URL: fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *httpSource) StatusURL(format string, a ...interface{}) *httpSource {
+	return f.mutation(func(source *streamingv1alpha1.HTTPSource) {
+		source.Status.URL = fmt.Sprintf(format, a...)
+	})
+}
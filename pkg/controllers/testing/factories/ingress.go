@@ -84,6 +84,10 @@ func (f *ingress) ObjectMeta(nf func(ObjectMeta)) *ingress {
 }
 
 func (f *ingress) HostToService(host, serviceName string) *ingress {
+	return f.HostToServicePort(host, serviceName, 80)
+}
+
+func (f *ingress) HostToServicePort(host, serviceName string, port int) *ingress {
 	return f.mutation(func(i *networkingv1beta1.Ingress) {
 		i.Spec = networkingv1beta1.IngressSpec{
 			Rules: []networkingv1beta1.IngressRule{{
@@ -94,7 +98,7 @@ func (f *ingress) HostToService(host, serviceName string) *ingress {
 							Path: "/",
 							Backend: networkingv1beta1.IngressBackend{
 								ServiceName: serviceName,
-								ServicePort: intstr.FromInt(80),
+								ServicePort: intstr.FromInt(port),
 							},
 						}},
 					},
@@ -104,6 +108,15 @@ func (f *ingress) HostToService(host, serviceName string) *ingress {
 	})
 }
 
+func (f *ingress) TLS(secretName string, hosts ...string) *ingress {
+	return f.mutation(func(i *networkingv1beta1.Ingress) {
+		i.Spec.TLS = append(i.Spec.TLS, networkingv1beta1.IngressTLS{
+			Hosts:      hosts,
+			SecretName: secretName,
+		})
+	})
+}
+
 func (f *ingress) StatusLoadBalancer(ingress ...corev1.LoadBalancerIngress) *ingress {
 	return f.mutation(func(i *networkingv1beta1.Ingress) {
 		i.Status.LoadBalancer.Ingress = ingress
@@ -0,0 +1,121 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectriff/system/pkg/apis"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+)
+
+type job struct {
+	target *batchv1.Job
+}
+
+var (
+	_ rtesting.Factory = (*job)(nil)
+)
+
+func Job(seed ...*batchv1.Job) *job {
+	var target *batchv1.Job
+	switch len(seed) {
+	case 0:
+		target = &batchv1.Job{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &job{
+		target: target,
+	}
+}
+
+func (f *job) deepCopy() *job {
+	return Job(f.target.DeepCopy())
+}
+
+func (f *job) Create() *batchv1.Job {
+	return f.deepCopy().target
+}
+
+func (f *job) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *job) mutation(m func(*batchv1.Job)) *job {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *job) NamespaceName(namespace, name string) *job {
+	return f.mutation(func(j *batchv1.Job) {
+		j.ObjectMeta.Namespace = namespace
+		j.ObjectMeta.Name = name
+	})
+}
+
+func (f *job) ObjectMeta(nf func(ObjectMeta)) *job {
+	return f.mutation(func(j *batchv1.Job) {
+		omf := objectMeta(j.ObjectMeta)
+		nf(omf)
+		j.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *job) PodTemplateSpec(nf func(PodTemplateSpec)) *job {
+	return f.mutation(func(j *batchv1.Job) {
+		ptsf := podTemplateSpec(j.Spec.Template)
+		nf(ptsf)
+		j.Spec.Template = ptsf.Create()
+	})
+}
+
+func (f *job) StatusActive(active int32) *job {
+	return f.mutation(func(j *batchv1.Job) {
+		j.Status.Active = active
+	})
+}
+
+func (f *job) StatusComplete(sec int64) *job {
+	return f.mutation(func(j *batchv1.Job) {
+		timestamp := metav1.Unix(sec, 0)
+		j.Status.CompletionTime = &timestamp
+		j.Status.Succeeded = 1
+		j.Status.Conditions = append(j.Status.Conditions, batchv1.JobCondition{
+			Type:   batchv1.JobComplete,
+			Status: corev1.ConditionTrue,
+		})
+	})
+}
+
+func (f *job) StatusFailed(reason, message string) *job {
+	return f.mutation(func(j *batchv1.Job) {
+		j.Status.Conditions = append(j.Status.Conditions, batchv1.JobCondition{
+			Type:    batchv1.JobFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  reason,
+			Message: message,
+		})
+	})
+}
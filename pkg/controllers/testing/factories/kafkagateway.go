@@ -19,6 +19,8 @@ package factories
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/projectriff/system/pkg/apis"
 	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
 	rtesting "github.com/projectriff/system/pkg/controllers/testing"
@@ -87,6 +89,48 @@ func (f *kafkaGateway) BootstrapServers(bootstrapServers string) *kafkaGateway {
 	})
 }
 
+func (f *kafkaGateway) TLS(tls *streamingv1alpha1.KafkaTLS) *kafkaGateway {
+	return f.mutation(func(g *streamingv1alpha1.KafkaGateway) {
+		g.Spec.TLS = tls
+	})
+}
+
+func (f *kafkaGateway) SASL(sasl *streamingv1alpha1.KafkaSASL) *kafkaGateway {
+	return f.mutation(func(g *streamingv1alpha1.KafkaGateway) {
+		g.Spec.SASL = sasl
+	})
+}
+
+func (f *kafkaGateway) Replicas(replicas int32) *kafkaGateway {
+	return f.mutation(func(g *streamingv1alpha1.KafkaGateway) {
+		g.Spec.Replicas = rtesting.Int32Ptr(replicas)
+	})
+}
+
+func (f *kafkaGateway) Resources(resources *corev1.ResourceRequirements) *kafkaGateway {
+	return f.mutation(func(g *streamingv1alpha1.KafkaGateway) {
+		g.Spec.Resources = resources
+	})
+}
+
+func (f *kafkaGateway) NodeSelector(nodeSelector map[string]string) *kafkaGateway {
+	return f.mutation(func(g *streamingv1alpha1.KafkaGateway) {
+		g.Spec.NodeSelector = nodeSelector
+	})
+}
+
+func (f *kafkaGateway) Tolerations(tolerations ...corev1.Toleration) *kafkaGateway {
+	return f.mutation(func(g *streamingv1alpha1.KafkaGateway) {
+		g.Spec.Tolerations = tolerations
+	})
+}
+
+func (f *kafkaGateway) PositionsStore(store *streamingv1alpha1.GatewayPositionsStore) *kafkaGateway {
+	return f.mutation(func(g *streamingv1alpha1.KafkaGateway) {
+		g.Spec.PositionsStore = store
+	})
+}
+
 func (f *kafkaGateway) StatusConditions(conditions ...*condition) *kafkaGateway {
 	return f.mutation(func(g *streamingv1alpha1.KafkaGateway) {
 		c := make([]apis.Condition, len(conditions))
@@ -119,6 +163,16 @@ func (f *kafkaGateway) StatusGatewayRef(format string, a ...interface{}) *kafkaG
 	})
 }
 
+func (f *kafkaGateway) StatusTriggerAuthenticationRef(format string, a ...interface{}) *kafkaGateway {
+	return f.mutation(func(g *streamingv1alpha1.KafkaGateway) {
+		g.Status.TriggerAuthenticationRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("keda.k8s.io"),
+			Kind:     "TriggerAuthentication",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
 func (f *kafkaGateway) StatusAddress(format string, a ...interface{}) *kafkaGateway {
 	return f.mutation(func(g *streamingv1alpha1.KafkaGateway) {
 		g.Status.Address = &apis.Addressable{
@@ -19,6 +19,8 @@ package factories
 import (
 	"fmt"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"github.com/projectriff/system/pkg/apis"
 	kedav1alpha1 "github.com/projectriff/system/pkg/apis/thirdparty/keda/v1alpha1"
 	rtesting "github.com/projectriff/system/pkg/controllers/testing"
@@ -121,3 +123,10 @@ func (f *kedaScaledObject) MaxReplicaCount(maxReplicaCount int32) *kedaScaledObj
 		s.Spec.MaxReplicaCount = &maxReplicaCount
 	})
 }
+
+func (f *kedaScaledObject) StatusLastActiveTime(sec int64) *kedaScaledObject {
+	return f.mutation(func(s *kedav1alpha1.ScaledObject) {
+		timestamp := metav1.Unix(sec, 0)
+		s.Status.LastActiveTime = &timestamp
+	})
+}
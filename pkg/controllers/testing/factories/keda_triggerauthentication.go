@@ -0,0 +1,88 @@
+/*
+Copyright 2020 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+
+	"github.com/projectriff/system/pkg/apis"
+	kedav1alpha1 "github.com/projectriff/system/pkg/apis/thirdparty/keda/v1alpha1"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+)
+
+type kedaTriggerAuthentication struct {
+	target *kedav1alpha1.TriggerAuthentication
+}
+
+var (
+	_ rtesting.Factory = (*kedaTriggerAuthentication)(nil)
+)
+
+func KedaTriggerAuthentication(seed ...*kedav1alpha1.TriggerAuthentication) *kedaTriggerAuthentication {
+	var target *kedav1alpha1.TriggerAuthentication
+	switch len(seed) {
+	case 0:
+		target = &kedav1alpha1.TriggerAuthentication{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &kedaTriggerAuthentication{
+		target: target,
+	}
+}
+
+func (f *kedaTriggerAuthentication) deepCopy() *kedaTriggerAuthentication {
+	return KedaTriggerAuthentication(f.target.DeepCopy())
+}
+
+func (f *kedaTriggerAuthentication) Create() *kedav1alpha1.TriggerAuthentication {
+	return f.deepCopy().target
+}
+
+func (f *kedaTriggerAuthentication) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *kedaTriggerAuthentication) mutation(m func(*kedav1alpha1.TriggerAuthentication)) *kedaTriggerAuthentication {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *kedaTriggerAuthentication) NamespaceName(namespace, name string) *kedaTriggerAuthentication {
+	return f.mutation(func(a *kedav1alpha1.TriggerAuthentication) {
+		a.ObjectMeta.Namespace = namespace
+		a.ObjectMeta.Name = name
+	})
+}
+
+func (f *kedaTriggerAuthentication) ObjectMeta(nf func(ObjectMeta)) *kedaTriggerAuthentication {
+	return f.mutation(func(a *kedav1alpha1.TriggerAuthentication) {
+		omf := objectMeta(a.ObjectMeta)
+		nf(omf)
+		a.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *kedaTriggerAuthentication) SecretTargetRefs(refs ...kedav1alpha1.AuthSecretTargetRef) *kedaTriggerAuthentication {
+	return f.mutation(func(a *kedav1alpha1.TriggerAuthentication) {
+		a.Spec.PodIdentity.Provider = kedav1alpha1.PodIdentityProviderNone
+		a.Spec.SecretTargetRef = refs
+	})
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+
+	"github.com/projectriff/system/pkg/apis"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+type natsGateway struct {
+	target *streamingv1alpha1.NATSGateway
+}
+
+var (
+	_ rtesting.Factory = (*natsGateway)(nil)
+)
+
+func NATSGateway(seed ...*streamingv1alpha1.NATSGateway) *natsGateway {
+	var target *streamingv1alpha1.NATSGateway
+	switch len(seed) {
+	case 0:
+		target = &streamingv1alpha1.NATSGateway{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &natsGateway{
+		target: target,
+	}
+}
+
+func (f *natsGateway) deepCopy() *natsGateway {
+	return NATSGateway(f.target.DeepCopy())
+}
+
+func (f *natsGateway) Create() *streamingv1alpha1.NATSGateway {
+	return f.deepCopy().target
+}
+
+func (f *natsGateway) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *natsGateway) mutation(m func(*streamingv1alpha1.NATSGateway)) *natsGateway {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *natsGateway) NamespaceName(namespace, name string) *natsGateway {
+	return f.mutation(func(g *streamingv1alpha1.NATSGateway) {
+		g.ObjectMeta.Namespace = namespace
+		g.ObjectMeta.Name = name
+	})
+}
+
+func (f *natsGateway) ObjectMeta(nf func(ObjectMeta)) *natsGateway {
+	return f.mutation(func(g *streamingv1alpha1.NATSGateway) {
+		omf := objectMeta(g.ObjectMeta)
+		nf(omf)
+		g.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *natsGateway) SecretRef(name string) *natsGateway {
+	return f.mutation(func(g *streamingv1alpha1.NATSGateway) {
+		g.Spec.SecretRef.Name = name
+	})
+}
+
+func (f *natsGateway) StatusConditions(conditions ...*condition) *natsGateway {
+	return f.mutation(func(g *streamingv1alpha1.NATSGateway) {
+		c := make([]apis.Condition, len(conditions))
+		for i, cg := range conditions {
+			c[i] = cg.Create()
+		}
+		g.Status.Conditions = c
+	})
+}
+
+func (f *natsGateway) StatusReady() *natsGateway {
+	return f.StatusConditions(
+		Condition().Type(streamingv1alpha1.NATSGatewayConditionReady).True(),
+	)
+}
+
+func (f *natsGateway) StatusObservedGeneration(generation int64) *natsGateway {
+	return f.mutation(func(g *streamingv1alpha1.NATSGateway) {
+		g.Status.ObservedGeneration = generation
+	})
+}
+
+func (f *natsGateway) StatusGatewayRef(format string, a ...interface{}) *natsGateway {
+	return f.mutation(func(g *streamingv1alpha1.NATSGateway) {
+		g.Status.GatewayRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("streaming.projectriff.io"),
+			Kind:     "Gateway",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *natsGateway) StatusAddress(format string, a ...interface{}) *natsGateway {
+	return f.mutation(func(g *streamingv1alpha1.NATSGateway) {
+		g.Status.Address = &apis.Addressable{
+			URL: fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *natsGateway) StatusGatewayImage(image string) *natsGateway {
+	return f.mutation(func(g *streamingv1alpha1.NATSGateway) {
+		g.Status.GatewayImage = image
+	})
+}
+
+func (f *natsGateway) StatusProvisionerImage(image string) *natsGateway {
+	return f.mutation(func(g *streamingv1alpha1.NATSGateway) {
+		g.Status.ProvisionerImage = image
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+
+	"github.com/projectriff/system/pkg/apis"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+)
+
+type pipeline struct {
+	target *streamingv1alpha1.Pipeline
+}
+
+var (
+	_ rtesting.Factory = (*pipeline)(nil)
+)
+
+func Pipeline(seed ...*streamingv1alpha1.Pipeline) *pipeline {
+	var target *streamingv1alpha1.Pipeline
+	switch len(seed) {
+	case 0:
+		target = &streamingv1alpha1.Pipeline{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &pipeline{
+		target: target,
+	}
+}
+
+func (f *pipeline) deepCopy() *pipeline {
+	return Pipeline(f.target.DeepCopy())
+}
+
+func (f *pipeline) Create() *streamingv1alpha1.Pipeline {
+	return f.deepCopy().target
+}
+
+func (f *pipeline) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *pipeline) mutation(m func(*streamingv1alpha1.Pipeline)) *pipeline {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *pipeline) Default() *pipeline {
+	return f.mutation(func(p *streamingv1alpha1.Pipeline) {
+		p.Default()
+	})
+}
+
+func (f *pipeline) NamespaceName(namespace, name string) *pipeline {
+	return f.mutation(func(p *streamingv1alpha1.Pipeline) {
+		p.ObjectMeta.Namespace = namespace
+		p.ObjectMeta.Name = name
+	})
+}
+
+func (f *pipeline) ObjectMeta(nf func(ObjectMeta)) *pipeline {
+	return f.mutation(func(p *streamingv1alpha1.Pipeline) {
+		omf := objectMeta(p.ObjectMeta)
+		nf(omf)
+		p.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *pipeline) Gateway(name string) *pipeline {
+	return f.mutation(func(p *streamingv1alpha1.Pipeline) {
+		p.Spec.Gateway.Name = name
+	})
+}
+
+func (f *pipeline) Streams(streams ...streamingv1alpha1.PipelineStream) *pipeline {
+	return f.mutation(func(p *streamingv1alpha1.Pipeline) {
+		p.Spec.Streams = streams
+	})
+}
+
+func (f *pipeline) Steps(steps ...streamingv1alpha1.PipelineStep) *pipeline {
+	return f.mutation(func(p *streamingv1alpha1.Pipeline) {
+		p.Spec.Steps = steps
+	})
+}
+
+func (f *pipeline) StatusConditions(conditions ...*condition) *pipeline {
+	return f.mutation(func(p *streamingv1alpha1.Pipeline) {
+		c := make([]apis.Condition, len(conditions))
+		for i, cg := range conditions {
+			c[i] = cg.Create()
+		}
+		p.Status.Conditions = c
+	})
+}
+
+func (f *pipeline) StatusObservedGeneration(generation int64) *pipeline {
+	return f.mutation(func(p *streamingv1alpha1.Pipeline) {
+		p.Status.ObservedGeneration = generation
+	})
+}
+
+func (f *pipeline) StatusSteps(steps ...streamingv1alpha1.PipelineStepStatus) *pipeline {
+	return f.mutation(func(p *streamingv1alpha1.Pipeline) {
+		p.Status.Steps = steps
+	})
+}
@@ -27,6 +27,10 @@ type PodTemplateSpec interface {
 	AddAnnotation(key, value string) PodTemplateSpec
 	ContainerNamed(name string, cb func(*corev1.Container)) PodTemplateSpec
 	Volumes(volumes ...corev1.Volume) PodTemplateSpec
+	Affinity(affinity *corev1.Affinity) PodTemplateSpec
+	NodeSelector(nodeSelector map[string]string) PodTemplateSpec
+	Tolerations(tolerations ...corev1.Toleration) PodTemplateSpec
+	RestartPolicy(policy corev1.RestartPolicy) PodTemplateSpec
 }
 
 type podTemplateSpecImpl struct {
@@ -98,3 +102,27 @@ func (f *podTemplateSpecImpl) Volumes(volumes ...corev1.Volume) PodTemplateSpec
 		pts.Spec.Volumes = volumes
 	})
 }
+
+func (f *podTemplateSpecImpl) Affinity(affinity *corev1.Affinity) PodTemplateSpec {
+	return f.mutate(func(pts *corev1.PodTemplateSpec) {
+		pts.Spec.Affinity = affinity
+	})
+}
+
+func (f *podTemplateSpecImpl) NodeSelector(nodeSelector map[string]string) PodTemplateSpec {
+	return f.mutate(func(pts *corev1.PodTemplateSpec) {
+		pts.Spec.NodeSelector = nodeSelector
+	})
+}
+
+func (f *podTemplateSpecImpl) Tolerations(tolerations ...corev1.Toleration) PodTemplateSpec {
+	return f.mutate(func(pts *corev1.PodTemplateSpec) {
+		pts.Spec.Tolerations = tolerations
+	})
+}
+
+func (f *podTemplateSpecImpl) RestartPolicy(policy corev1.RestartPolicy) PodTemplateSpec {
+	return f.mutate(func(pts *corev1.PodTemplateSpec) {
+		pts.Spec.RestartPolicy = policy
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/projectriff/system/pkg/apis"
 	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
@@ -105,6 +106,12 @@ func (f *processor) BuildContainerRef(container *container) *processor {
 	})
 }
 
+func (f *processor) Chain(chain ...streamingv1alpha1.ChainedFunction) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Spec.Chain = chain
+	})
+}
+
 func (f *processor) Image(image string) *processor {
 	return f.PodTemplateSpec(func(pts PodTemplateSpec) {
 		pts.ContainerNamed("function", func(c *corev1.Container) {
@@ -145,10 +152,11 @@ func (f *processor) StatusConditions(conditions ...*condition) *processor {
 		for i, cg := range conditions {
 			dc := cg.Create()
 			c[i] = apis.Condition{
-				Type:    apis.ConditionType(dc.Type),
-				Status:  dc.Status,
-				Reason:  dc.Reason,
-				Message: dc.Message,
+				Type:     apis.ConditionType(dc.Type),
+				Status:   dc.Status,
+				Reason:   dc.Reason,
+				Message:  dc.Message,
+				Severity: dc.Severity,
 			}
 		}
 		processor.Status.Conditions = c
@@ -167,6 +175,30 @@ func (f *processor) StatusLatestImage(image string) *processor {
 	})
 }
 
+func (f *processor) StatusChain(chain ...streamingv1alpha1.ChainedFunctionStatus) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Status.Chain = chain
+	})
+}
+
+func (f *processor) StatusDeployedImage(image string) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Status.DeployedImage = image
+	})
+}
+
+func (f *processor) StatusPromoted(promote string) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Status.Promoted = promote
+	})
+}
+
+func (f *processor) Rollout(rollout *streamingv1alpha1.ProcessorRollout) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Spec.Rollout = rollout
+	})
+}
+
 func (f *processor) StatusDeploymentRef(format string, a ...interface{}) *processor {
 	return f.mutation(func(proc *streamingv1alpha1.Processor) {
 		proc.Status.DeploymentRef = &refs.TypedLocalObjectReference{
@@ -186,3 +218,95 @@ func (f *processor) StatusScaledObjectRef(format string, a ...interface{}) *proc
 		}
 	})
 }
+
+func (f *processor) Scale(scale streamingv1alpha1.Scale) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Spec.Scale = scale
+	})
+}
+
+func (f *processor) StatusReplicas(replicas int32) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Status.Replicas = replicas
+	})
+}
+
+func (f *processor) StatusLastActiveTime(sec int64) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		timestamp := metav1.Unix(sec, 0)
+		proc.Status.LastActiveTime = &timestamp
+	})
+}
+
+func (f *processor) Replay(replay string) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Spec.Replay = replay
+	})
+}
+
+func (f *processor) StatusConsumerGroup(format string, a ...interface{}) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Status.ConsumerGroup = fmt.Sprintf(format, a...)
+	})
+}
+
+func (f *processor) StatusReplayHistory(history ...streamingv1alpha1.ProcessorReplay) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Status.ReplayHistory = history
+	})
+}
+
+func (f *processor) Window(window *streamingv1alpha1.Window) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Spec.Window = window
+	})
+}
+
+func (f *processor) Operation(operation *streamingv1alpha1.ProcessorOperation) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Spec.Operation = operation
+	})
+}
+
+func (f *processor) State(state *streamingv1alpha1.ProcessorState) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Spec.State = state
+	})
+}
+
+func (f *processor) StatusStatefulSetRef(format string, a ...interface{}) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Status.StatefulSetRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("apps"),
+			Kind:     "StatefulSet",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *processor) StatusChangelogStreamRef(format string, a ...interface{}) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Status.ChangelogStreamRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("streaming.projectriff.io"),
+			Kind:     "Stream",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *processor) LaggingThreshold(threshold int64) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		proc.Spec.LaggingThreshold = &threshold
+	})
+}
+
+func (f *processor) StatusInputLags(lags ...streamingv1alpha1.ProcessorInputLag) *processor {
+	return f.mutation(func(proc *streamingv1alpha1.Processor) {
+		var total int64
+		for _, lag := range lags {
+			total += lag.Lag
+		}
+		proc.Status.Lag = &total
+		proc.Status.InputLags = lags
+	})
+}
@@ -19,6 +19,8 @@ package factories
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/projectriff/system/pkg/apis"
 	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
 	rtesting "github.com/projectriff/system/pkg/controllers/testing"
@@ -87,6 +89,30 @@ func (f *pulsarGateway) ServiceURL(url string) *pulsarGateway {
 	})
 }
 
+func (f *pulsarGateway) Tenant(tenant string) *pulsarGateway {
+	return f.mutation(func(g *streamingv1alpha1.PulsarGateway) {
+		g.Spec.Tenant = tenant
+	})
+}
+
+func (f *pulsarGateway) PulsarNamespace(namespace string) *pulsarGateway {
+	return f.mutation(func(g *streamingv1alpha1.PulsarGateway) {
+		g.Spec.Namespace = namespace
+	})
+}
+
+func (f *pulsarGateway) TokenSecretRef(name string) *pulsarGateway {
+	return f.mutation(func(g *streamingv1alpha1.PulsarGateway) {
+		g.Spec.TokenSecretRef = &corev1.LocalObjectReference{Name: name}
+	})
+}
+
+func (f *pulsarGateway) TLS(tls *streamingv1alpha1.PulsarTLS) *pulsarGateway {
+	return f.mutation(func(g *streamingv1alpha1.PulsarGateway) {
+		g.Spec.TLS = tls
+	})
+}
+
 func (f *pulsarGateway) StatusConditions(conditions ...*condition) *pulsarGateway {
 	return f.mutation(func(g *streamingv1alpha1.PulsarGateway) {
 		c := make([]apis.Condition, len(conditions))
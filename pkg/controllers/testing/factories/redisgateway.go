@@ -0,0 +1,157 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/projectriff/system/pkg/apis"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+type redisGateway struct {
+	target *streamingv1alpha1.RedisGateway
+}
+
+var (
+	_ rtesting.Factory = (*redisGateway)(nil)
+)
+
+func RedisGateway(seed ...*streamingv1alpha1.RedisGateway) *redisGateway {
+	var target *streamingv1alpha1.RedisGateway
+	switch len(seed) {
+	case 0:
+		target = &streamingv1alpha1.RedisGateway{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &redisGateway{
+		target: target,
+	}
+}
+
+func (f *redisGateway) deepCopy() *redisGateway {
+	return RedisGateway(f.target.DeepCopy())
+}
+
+func (f *redisGateway) Create() *streamingv1alpha1.RedisGateway {
+	return f.deepCopy().target
+}
+
+func (f *redisGateway) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *redisGateway) mutation(m func(*streamingv1alpha1.RedisGateway)) *redisGateway {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *redisGateway) NamespaceName(namespace, name string) *redisGateway {
+	return f.mutation(func(g *streamingv1alpha1.RedisGateway) {
+		g.ObjectMeta.Namespace = namespace
+		g.ObjectMeta.Name = name
+	})
+}
+
+func (f *redisGateway) ObjectMeta(nf func(ObjectMeta)) *redisGateway {
+	return f.mutation(func(g *streamingv1alpha1.RedisGateway) {
+		omf := objectMeta(g.ObjectMeta)
+		nf(omf)
+		g.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *redisGateway) Address(address string) *redisGateway {
+	return f.mutation(func(g *streamingv1alpha1.RedisGateway) {
+		g.Spec.Address = address
+	})
+}
+
+func (f *redisGateway) PasswordSecretRef(name, key string) *redisGateway {
+	return f.mutation(func(g *streamingv1alpha1.RedisGateway) {
+		g.Spec.PasswordSecretRef = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+		}
+	})
+}
+
+func (f *redisGateway) TLS(tls bool) *redisGateway {
+	return f.mutation(func(g *streamingv1alpha1.RedisGateway) {
+		g.Spec.TLS = tls
+	})
+}
+
+func (f *redisGateway) StatusConditions(conditions ...*condition) *redisGateway {
+	return f.mutation(func(g *streamingv1alpha1.RedisGateway) {
+		c := make([]apis.Condition, len(conditions))
+		for i, cg := range conditions {
+			c[i] = cg.Create()
+		}
+		g.Status.Conditions = c
+	})
+}
+
+func (f *redisGateway) StatusReady() *redisGateway {
+	return f.StatusConditions(
+		Condition().Type(streamingv1alpha1.RedisGatewayConditionReady).True(),
+	)
+}
+
+func (f *redisGateway) StatusObservedGeneration(generation int64) *redisGateway {
+	return f.mutation(func(g *streamingv1alpha1.RedisGateway) {
+		g.Status.ObservedGeneration = generation
+	})
+}
+
+func (f *redisGateway) StatusGatewayRef(format string, a ...interface{}) *redisGateway {
+	return f.mutation(func(g *streamingv1alpha1.RedisGateway) {
+		g.Status.GatewayRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("streaming.projectriff.io"),
+			Kind:     "Gateway",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *redisGateway) StatusAddress(format string, a ...interface{}) *redisGateway {
+	return f.mutation(func(g *streamingv1alpha1.RedisGateway) {
+		g.Status.Address = &apis.Addressable{
+			URL: fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *redisGateway) StatusGatewayImage(image string) *redisGateway {
+	return f.mutation(func(g *streamingv1alpha1.RedisGateway) {
+		g.Status.GatewayImage = image
+	})
+}
+
+func (f *redisGateway) StatusProvisionerImage(image string) *redisGateway {
+	return f.mutation(func(g *streamingv1alpha1.RedisGateway) {
+		g.Status.ProvisionerImage = image
+	})
+}
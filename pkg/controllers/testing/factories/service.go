@@ -101,3 +101,15 @@ func (f *service) ClusterIP(ip string) *service {
 		service.Spec.ClusterIP = ip
 	})
 }
+
+func (f *service) Type(serviceType corev1.ServiceType) *service {
+	return f.mutation(func(service *corev1.Service) {
+		service.Spec.Type = serviceType
+	})
+}
+
+func (f *service) StatusLoadBalancer(ingress ...corev1.LoadBalancerIngress) *service {
+	return f.mutation(func(service *corev1.Service) {
+		service.Status.LoadBalancer.Ingress = ingress
+	})
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectriff/system/pkg/apis"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+type sink struct {
+	target *streamingv1alpha1.Sink
+}
+
+var (
+	_ rtesting.Factory = (*sink)(nil)
+)
+
+func Sink(seed ...*streamingv1alpha1.Sink) *sink {
+	var target *streamingv1alpha1.Sink
+	switch len(seed) {
+	case 0:
+		target = &streamingv1alpha1.Sink{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &sink{
+		target: target,
+	}
+}
+
+func (f *sink) deepCopy() *sink {
+	return Sink(f.target.DeepCopy())
+}
+
+func (f *sink) Create() *streamingv1alpha1.Sink {
+	return f.deepCopy().target
+}
+
+func (f *sink) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *sink) mutation(m func(*streamingv1alpha1.Sink)) *sink {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *sink) NamespaceName(namespace, name string) *sink {
+	return f.mutation(func(sink *streamingv1alpha1.Sink) {
+		sink.ObjectMeta.Namespace = namespace
+		sink.ObjectMeta.Name = name
+	})
+}
+
+func (f *sink) ObjectMeta(nf func(ObjectMeta)) *sink {
+	return f.mutation(func(sink *streamingv1alpha1.Sink) {
+		omf := objectMeta(sink.ObjectMeta)
+		nf(omf)
+		sink.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *sink) Input(stream string) *sink {
+	return f.mutation(func(sink *streamingv1alpha1.Sink) {
+		sink.Spec.Input = stream
+	})
+}
+
+func (f *sink) DeadLetter(stream string) *sink {
+	return f.mutation(func(sink *streamingv1alpha1.Sink) {
+		sink.Spec.DeadLetter = stream
+	})
+}
+
+func (f *sink) TargetURL(url string) *sink {
+	return f.mutation(func(sink *streamingv1alpha1.Sink) {
+		sink.Spec.Target = streamingv1alpha1.SinkTarget{URL: url}
+	})
+}
+
+func (f *sink) TargetCoreDeployerRef(name string) *sink {
+	return f.mutation(func(sink *streamingv1alpha1.Sink) {
+		sink.Spec.Target = streamingv1alpha1.SinkTarget{CoreDeployerRef: name}
+	})
+}
+
+func (f *sink) TargetKnativeDeployerRef(name string) *sink {
+	return f.mutation(func(sink *streamingv1alpha1.Sink) {
+		sink.Spec.Target = streamingv1alpha1.SinkTarget{KnativeDeployerRef: name}
+	})
+}
+
+func (f *sink) Retry(maxAttempts int32, initialBackoff, maxBackoff time.Duration) *sink {
+	return f.mutation(func(sink *streamingv1alpha1.Sink) {
+		sink.Spec.Retry = &streamingv1alpha1.SinkRetry{
+			MaxAttempts:    maxAttempts,
+			InitialBackoff: &metav1.Duration{Duration: initialBackoff},
+			MaxBackoff:     &metav1.Duration{Duration: maxBackoff},
+		}
+	})
+}
+
+func (f *sink) StatusConditions(conditions ...*condition) *sink {
+	return f.mutation(func(sink *streamingv1alpha1.Sink) {
+		c := make([]apis.Condition, len(conditions))
+		for i, cg := range conditions {
+			c[i] = cg.Create()
+		}
+		sink.Status.Conditions = c
+	})
+}
+
+func (f *sink) StatusObservedGeneration(generation int64) *sink {
+	return f.mutation(func(sink *streamingv1alpha1.Sink) {
+		sink.Status.ObservedGeneration = generation
+	})
+}
+
+func (f *sink) StatusTargetURL(format string, a ...interface{}) *sink {
+	return f.mutation(func(sink *streamingv1alpha1.Sink) {
+		sink.Status.TargetURL = fmt.Sprintf(format, a...)
+	})
+}
+
+func (f *sink) StatusDeploymentRef(format string, a ...interface{}) *sink {
+	return f.mutation(func(sink *streamingv1alpha1.Sink) {
+		sink.Status.DeploymentRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("apps"),
+			Kind:     "Deployment",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectriff/system/pkg/apis"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+)
+
+type statefulSet struct {
+	target *appsv1.StatefulSet
+}
+
+var (
+	_ rtesting.Factory = (*statefulSet)(nil)
+)
+
+func StatefulSet(seed ...*appsv1.StatefulSet) *statefulSet {
+	var target *appsv1.StatefulSet
+	switch len(seed) {
+	case 0:
+		target = &appsv1.StatefulSet{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &statefulSet{
+		target: target,
+	}
+}
+
+func (f *statefulSet) deepCopy() *statefulSet {
+	return StatefulSet(f.target.DeepCopy())
+}
+
+func (f *statefulSet) Create() *appsv1.StatefulSet {
+	return f.deepCopy().target
+}
+
+func (f *statefulSet) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *statefulSet) mutation(m func(*appsv1.StatefulSet)) *statefulSet {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *statefulSet) NamespaceName(namespace, name string) *statefulSet {
+	return f.mutation(func(ss *appsv1.StatefulSet) {
+		ss.ObjectMeta.Namespace = namespace
+		ss.ObjectMeta.Name = name
+	})
+}
+
+func (f *statefulSet) ObjectMeta(nf func(ObjectMeta)) *statefulSet {
+	return f.mutation(func(ss *appsv1.StatefulSet) {
+		omf := objectMeta(ss.ObjectMeta)
+		nf(omf)
+		ss.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *statefulSet) PodTemplateSpec(nf func(PodTemplateSpec)) *statefulSet {
+	return f.mutation(func(ss *appsv1.StatefulSet) {
+		ptsf := podTemplateSpec(ss.Spec.Template)
+		nf(ptsf)
+		ss.Spec.Template = ptsf.Create()
+	})
+}
+
+func (f *statefulSet) Replicas(replicas int32) *statefulSet {
+	return f.mutation(func(ss *appsv1.StatefulSet) {
+		ss.Spec.Replicas = rtesting.Int32Ptr(replicas)
+	})
+}
+
+func (f *statefulSet) ServiceName(name string) *statefulSet {
+	return f.mutation(func(ss *appsv1.StatefulSet) {
+		ss.Spec.ServiceName = name
+	})
+}
+
+func (f *statefulSet) AddSelectorLabel(key, value string) *statefulSet {
+	return f.mutation(func(ss *appsv1.StatefulSet) {
+		if ss.Spec.Selector == nil {
+			ss.Spec.Selector = &metav1.LabelSelector{}
+		}
+		metav1.AddLabelToSelector(ss.Spec.Selector, key, value)
+		ss.Spec.Template = podTemplateSpec(ss.Spec.Template).AddLabel(key, value).Create()
+	})
+}
+
+func (f *statefulSet) VolumeClaimTemplates(claims ...corev1.PersistentVolumeClaim) *statefulSet {
+	return f.mutation(func(ss *appsv1.StatefulSet) {
+		ss.Spec.VolumeClaimTemplates = claims
+	})
+}
+
+func (f *statefulSet) StatusReplicas(replicas, readyReplicas int32) *statefulSet {
+	return f.mutation(func(ss *appsv1.StatefulSet) {
+		ss.Status.Replicas = replicas
+		ss.Status.ReadyReplicas = readyReplicas
+	})
+}
+
+func (f *statefulSet) StatusObservedGeneration(generation int64) *statefulSet {
+	return f.mutation(func(ss *appsv1.StatefulSet) {
+		ss.Status.ObservedGeneration = generation
+	})
+}
@@ -20,10 +20,12 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/projectriff/system/pkg/apis"
 	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
 	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/refs"
 )
 
 type stream struct {
@@ -109,6 +111,21 @@ func (f *stream) Gateway(name string) *stream {
 	})
 }
 
+func (f *stream) Partitions(partitions int32) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		s.Spec.Partitions = &partitions
+	})
+}
+
+func (f *stream) Migration(copyData, drain bool) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		s.Spec.Migration = &streamingv1alpha1.StreamMigrationPolicy{
+			CopyData: copyData,
+			Drain:    &drain,
+		}
+	})
+}
+
 func (f *stream) StatusConditions(conditions ...*condition) *stream {
 	return f.mutation(func(s *streamingv1alpha1.Stream) {
 		c := make([]apis.Condition, len(conditions))
@@ -137,3 +154,97 @@ func (f *stream) StatusBinding(metadataName, secretName string) *stream {
 		s.Status.Binding.SecretRef = corev1.LocalObjectReference{Name: secretName}
 	})
 }
+
+func (f *stream) StatusConsumers(consumers ...string) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		s.Status.Consumers = consumers
+	})
+}
+
+func (f *stream) StatusProducers(producers ...string) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		s.Status.Producers = producers
+	})
+}
+
+func (f *stream) StatusGateway(name string) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		s.Status.Gateway = name
+	})
+}
+
+func (f *stream) StatusPhase(phase streamingv1alpha1.StreamPhase) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		s.Status.Phase = phase
+	})
+}
+
+func (f *stream) StatusPartitions(partitions int32) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		s.Status.Partitions = &partitions
+	})
+}
+
+func (f *stream) StatusMigration(from, to string) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		s.Status.Migration = &streamingv1alpha1.StreamMigration{
+			From: from,
+			To:   to,
+		}
+	})
+}
+
+func (f *stream) StatusMigrationGeneration(generation int64) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		s.Status.Migration.Generation = generation
+	})
+}
+
+func (f *stream) StatusMigrationMessage(format string, a ...interface{}) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		s.Status.Migration.Message = fmt.Sprintf(format, a...)
+	})
+}
+
+func (f *stream) StatusMigrationJobRef(format string, a ...interface{}) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		s.Status.Migration.JobRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("batch"),
+			Kind:     "Job",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *stream) StatusMigrationCopyCompletionTime(sec int64) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		timestamp := metav1.Unix(sec, 0)
+		s.Status.Migration.CopyCompletionTime = &timestamp
+	})
+}
+
+func (f *stream) StatusMigrationDrainedTime(sec int64) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		timestamp := metav1.Unix(sec, 0)
+		s.Status.Migration.DrainedTime = &timestamp
+	})
+}
+
+func (f *stream) StatusMigrationDrainConfirmed() *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		s.Status.Migration.DrainConfirmed = true
+	})
+}
+
+func (f *stream) StatusMigrationFinalCopyCompletionTime(sec int64) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		timestamp := metav1.Unix(sec, 0)
+		s.Status.Migration.FinalCopyCompletionTime = &timestamp
+	})
+}
+
+func (f *stream) StatusQuotas(quotas ...streamingv1alpha1.StreamQuotaUsage) *stream {
+	return f.mutation(func(s *streamingv1alpha1.Stream) {
+		s.Status.Quotas = quotas
+	})
+}
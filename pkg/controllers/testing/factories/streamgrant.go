@@ -0,0 +1,93 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+
+	"github.com/projectriff/system/pkg/apis"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+)
+
+type streamGrant struct {
+	target *streamingv1alpha1.StreamGrant
+}
+
+var (
+	_ rtesting.Factory = (*streamGrant)(nil)
+)
+
+func StreamGrant(seed ...*streamingv1alpha1.StreamGrant) *streamGrant {
+	var target *streamingv1alpha1.StreamGrant
+	switch len(seed) {
+	case 0:
+		target = &streamingv1alpha1.StreamGrant{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &streamGrant{
+		target: target,
+	}
+}
+
+func (f *streamGrant) deepCopy() *streamGrant {
+	return StreamGrant(f.target.DeepCopy())
+}
+
+func (f *streamGrant) Create() *streamingv1alpha1.StreamGrant {
+	return f.deepCopy().target
+}
+
+func (f *streamGrant) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *streamGrant) mutation(m func(*streamingv1alpha1.StreamGrant)) *streamGrant {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *streamGrant) NamespaceName(namespace, name string) *streamGrant {
+	return f.mutation(func(g *streamingv1alpha1.StreamGrant) {
+		g.ObjectMeta.Namespace = namespace
+		g.ObjectMeta.Name = name
+	})
+}
+
+func (f *streamGrant) ObjectMeta(nf func(ObjectMeta)) *streamGrant {
+	return f.mutation(func(g *streamingv1alpha1.StreamGrant) {
+		omf := objectMeta(g.ObjectMeta)
+		nf(omf)
+		g.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *streamGrant) Streams(streams ...string) *streamGrant {
+	return f.mutation(func(g *streamingv1alpha1.StreamGrant) {
+		g.Spec.Streams = streams
+	})
+}
+
+func (f *streamGrant) Namespaces(namespaces ...string) *streamGrant {
+	return f.mutation(func(g *streamingv1alpha1.StreamGrant) {
+		g.Spec.Namespaces = namespaces
+	})
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectriff/system/pkg/apis"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+type streamMirror struct {
+	target *streamingv1alpha1.StreamMirror
+}
+
+var (
+	_ rtesting.Factory = (*streamMirror)(nil)
+)
+
+func StreamMirror(seed ...*streamingv1alpha1.StreamMirror) *streamMirror {
+	var target *streamingv1alpha1.StreamMirror
+	switch len(seed) {
+	case 0:
+		target = &streamingv1alpha1.StreamMirror{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &streamMirror{
+		target: target,
+	}
+}
+
+func (f *streamMirror) deepCopy() *streamMirror {
+	return StreamMirror(f.target.DeepCopy())
+}
+
+func (f *streamMirror) Create() *streamingv1alpha1.StreamMirror {
+	return f.deepCopy().target
+}
+
+func (f *streamMirror) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *streamMirror) mutation(m func(*streamingv1alpha1.StreamMirror)) *streamMirror {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *streamMirror) NamespaceName(namespace, name string) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		mirror.ObjectMeta.Namespace = namespace
+		mirror.ObjectMeta.Name = name
+	})
+}
+
+func (f *streamMirror) ObjectMeta(nf func(ObjectMeta)) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		omf := objectMeta(mirror.ObjectMeta)
+		nf(omf)
+		mirror.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *streamMirror) Default() *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		mirror.Default()
+	})
+}
+
+func (f *streamMirror) Source(stream string) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		mirror.Spec.Source = stream
+	})
+}
+
+func (f *streamMirror) Destination(stream string) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		mirror.Spec.Destination = stream
+	})
+}
+
+func (f *streamMirror) StartOffset(offset string) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		mirror.Spec.StartOffset = offset
+	})
+}
+
+func (f *streamMirror) Mode(mode string) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		mirror.Spec.Mode = mode
+	})
+}
+
+func (f *streamMirror) Filter(filter *streamingv1alpha1.StreamMirrorFilter) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		mirror.Spec.Filter = filter
+	})
+}
+
+func (f *streamMirror) StatusConditions(conditions ...*condition) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		c := make([]apis.Condition, len(conditions))
+		for i, cg := range conditions {
+			c[i] = cg.Create()
+		}
+		mirror.Status.Conditions = c
+	})
+}
+
+func (f *streamMirror) StatusObservedGeneration(generation int64) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		mirror.Status.ObservedGeneration = generation
+	})
+}
+
+func (f *streamMirror) StatusConsumerGroup(format string, a ...interface{}) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		mirror.Status.ConsumerGroup = fmt.Sprintf(format, a...)
+	})
+}
+
+func (f *streamMirror) StatusDeploymentRef(format string, a ...interface{}) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		mirror.Status.DeploymentRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("apps"),
+			Kind:     "Deployment",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *streamMirror) StatusJobRef(format string, a ...interface{}) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		mirror.Status.JobRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("batch"),
+			Kind:     "Job",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *streamMirror) StatusPartitions(partitions ...streamingv1alpha1.StreamMirrorPartition) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		var total int64
+		for _, p := range partitions {
+			total += p.Lag
+		}
+		mirror.Status.Partitions = partitions
+		mirror.Status.Lag = &total
+	})
+}
+
+func (f *streamMirror) StatusCompletionTime(sec int64) *streamMirror {
+	return f.mutation(func(mirror *streamingv1alpha1.StreamMirror) {
+		timestamp := metav1.Unix(sec, 0)
+		mirror.Status.CompletionTime = &timestamp
+	})
+}
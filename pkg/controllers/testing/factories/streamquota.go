@@ -0,0 +1,122 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+
+	"github.com/projectriff/system/pkg/apis"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+)
+
+type streamQuota struct {
+	target *streamingv1alpha1.StreamQuota
+}
+
+var (
+	_ rtesting.Factory = (*streamQuota)(nil)
+)
+
+func StreamQuota(seed ...*streamingv1alpha1.StreamQuota) *streamQuota {
+	var target *streamingv1alpha1.StreamQuota
+	switch len(seed) {
+	case 0:
+		target = &streamingv1alpha1.StreamQuota{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &streamQuota{
+		target: target,
+	}
+}
+
+func (f *streamQuota) deepCopy() *streamQuota {
+	return StreamQuota(f.target.DeepCopy())
+}
+
+func (f *streamQuota) Create() *streamingv1alpha1.StreamQuota {
+	return f.deepCopy().target
+}
+
+func (f *streamQuota) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *streamQuota) mutation(m func(*streamingv1alpha1.StreamQuota)) *streamQuota {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *streamQuota) NamespaceName(namespace, name string) *streamQuota {
+	return f.mutation(func(quota *streamingv1alpha1.StreamQuota) {
+		quota.ObjectMeta.Namespace = namespace
+		quota.ObjectMeta.Name = name
+	})
+}
+
+func (f *streamQuota) ObjectMeta(nf func(ObjectMeta)) *streamQuota {
+	return f.mutation(func(quota *streamingv1alpha1.StreamQuota) {
+		omf := objectMeta(quota.ObjectMeta)
+		nf(omf)
+		quota.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *streamQuota) MaxStreams(max int32) *streamQuota {
+	return f.mutation(func(quota *streamingv1alpha1.StreamQuota) {
+		quota.Spec.MaxStreams = &max
+	})
+}
+
+func (f *streamQuota) MaxPartitions(max int32) *streamQuota {
+	return f.mutation(func(quota *streamingv1alpha1.StreamQuota) {
+		quota.Spec.MaxPartitions = &max
+	})
+}
+
+func (f *streamQuota) Gateways(gateways ...string) *streamQuota {
+	return f.mutation(func(quota *streamingv1alpha1.StreamQuota) {
+		quota.Spec.Gateways = gateways
+	})
+}
+
+func (f *streamQuota) StatusConditions(conditions ...*condition) *streamQuota {
+	return f.mutation(func(quota *streamingv1alpha1.StreamQuota) {
+		c := make([]apis.Condition, len(conditions))
+		for i, cg := range conditions {
+			c[i] = cg.Create()
+		}
+		quota.Status.Conditions = c
+	})
+}
+
+func (f *streamQuota) StatusObservedGeneration(generation int64) *streamQuota {
+	return f.mutation(func(quota *streamingv1alpha1.StreamQuota) {
+		quota.Status.ObservedGeneration = generation
+	})
+}
+
+func (f *streamQuota) StatusUsed(streams, partitions int32) *streamQuota {
+	return f.mutation(func(quota *streamingv1alpha1.StreamQuota) {
+		quota.Status.Streams = streams
+		quota.Status.Partitions = partitions
+	})
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package factories
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectriff/system/pkg/apis"
+	streamingv1alpha1 "github.com/projectriff/system/pkg/apis/streaming/v1alpha1"
+	rtesting "github.com/projectriff/system/pkg/controllers/testing"
+	"github.com/projectriff/system/pkg/refs"
+)
+
+type streamTap struct {
+	target *streamingv1alpha1.StreamTap
+}
+
+var (
+	_ rtesting.Factory = (*streamTap)(nil)
+)
+
+func StreamTap(seed ...*streamingv1alpha1.StreamTap) *streamTap {
+	var target *streamingv1alpha1.StreamTap
+	switch len(seed) {
+	case 0:
+		target = &streamingv1alpha1.StreamTap{}
+	case 1:
+		target = seed[0]
+	default:
+		panic(fmt.Errorf("expected exactly zero or one seed, got %v", seed))
+	}
+	return &streamTap{
+		target: target,
+	}
+}
+
+func (f *streamTap) deepCopy() *streamTap {
+	return StreamTap(f.target.DeepCopy())
+}
+
+func (f *streamTap) Create() *streamingv1alpha1.StreamTap {
+	return f.deepCopy().target
+}
+
+func (f *streamTap) CreateObject() apis.Object {
+	return f.Create()
+}
+
+func (f *streamTap) mutation(m func(*streamingv1alpha1.StreamTap)) *streamTap {
+	f = f.deepCopy()
+	m(f.target)
+	return f
+}
+
+func (f *streamTap) NamespaceName(namespace, name string) *streamTap {
+	return f.mutation(func(tap *streamingv1alpha1.StreamTap) {
+		tap.ObjectMeta.Namespace = namespace
+		tap.ObjectMeta.Name = name
+	})
+}
+
+func (f *streamTap) ObjectMeta(nf func(ObjectMeta)) *streamTap {
+	return f.mutation(func(tap *streamingv1alpha1.StreamTap) {
+		omf := objectMeta(tap.ObjectMeta)
+		nf(omf)
+		tap.ObjectMeta = omf.Create()
+	})
+}
+
+func (f *streamTap) Default() *streamTap {
+	return f.mutation(func(tap *streamingv1alpha1.StreamTap) {
+		tap.Default()
+	})
+}
+
+func (f *streamTap) Stream(stream string) *streamTap {
+	return f.mutation(func(tap *streamingv1alpha1.StreamTap) {
+		tap.Spec.Stream = stream
+	})
+}
+
+func (f *streamTap) Count(count int32) *streamTap {
+	return f.mutation(func(tap *streamingv1alpha1.StreamTap) {
+		tap.Spec.Count = &count
+	})
+}
+
+func (f *streamTap) TTL(ttl time.Duration) *streamTap {
+	return f.mutation(func(tap *streamingv1alpha1.StreamTap) {
+		tap.Spec.TTL = &metav1.Duration{Duration: ttl}
+	})
+}
+
+func (f *streamTap) StatusConditions(conditions ...*condition) *streamTap {
+	return f.mutation(func(tap *streamingv1alpha1.StreamTap) {
+		c := make([]apis.Condition, len(conditions))
+		for i, cg := range conditions {
+			c[i] = cg.Create()
+		}
+		tap.Status.Conditions = c
+	})
+}
+
+func (f *streamTap) StatusObservedGeneration(generation int64) *streamTap {
+	return f.mutation(func(tap *streamingv1alpha1.StreamTap) {
+		tap.Status.ObservedGeneration = generation
+	})
+}
+
+func (f *streamTap) StatusDeploymentRef(format string, a ...interface{}) *streamTap {
+	return f.mutation(func(tap *streamingv1alpha1.StreamTap) {
+		tap.Status.DeploymentRef = &refs.TypedLocalObjectReference{
+			APIGroup: rtesting.StringPtr("apps"),
+			Kind:     "Deployment",
+			Name:     fmt.Sprintf(format, a...),
+		}
+	})
+}
+
+func (f *streamTap) StatusExpirationTime(sec int64) *streamTap {
+	return f.mutation(func(tap *streamingv1alpha1.StreamTap) {
+		timestamp := metav1.Unix(sec, 0)
+		tap.Status.ExpirationTime = &timestamp
+	})
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cron parses standard five field cron schedules, matching the
+// syntax accepted by Kubernetes CronJobs.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron schedule.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar record whether the day fields are unrestricted.
+	// When both are restricted a day matches if either field matches.
+	domStar, dowStar bool
+	// every is the fixed interval of an @every schedule, the fields are
+	// unused when set.
+	every time.Duration
+}
+
+type bounds struct {
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	minutes = bounds{min: 0, max: 59}
+	hours   = bounds{min: 0, max: 23}
+	doms    = bounds{min: 1, max: 31}
+	months  = bounds{min: 1, max: 12, names: map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is an alias for Sunday
+	dows = bounds{min: 0, max: 7, names: map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// daysInMonth is the most days each month may have, indexed by month.
+var daysInMonth = [13]uint{0, 31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// Parse parses a five field cron expression (minute, hour, day of month,
+// month, day of week), one of the predefined @ descriptors or an
+// `@every <duration>` interval. Schedules that never fire are rejected.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		return parseEvery(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+	}
+	if strings.HasPrefix(spec, "@") {
+		expanded, ok := descriptors[spec]
+		if !ok {
+			return nil, fmt.Errorf("unrecognized descriptor %q", spec)
+		}
+		spec = expanded
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected exactly 5 fields, found %d: %q", len(fields), spec)
+	}
+
+	s := &Schedule{}
+	var err error
+	if s.minute, err = parseField(fields[0], minutes); err != nil {
+		return nil, fmt.Errorf("minute: %s", err)
+	}
+	if s.hour, err = parseField(fields[1], hours); err != nil {
+		return nil, fmt.Errorf("hour: %s", err)
+	}
+	if s.dom, err = parseField(fields[2], doms); err != nil {
+		return nil, fmt.Errorf("day of month: %s", err)
+	}
+	if s.month, err = parseField(fields[3], months); err != nil {
+		return nil, fmt.Errorf("month: %s", err)
+	}
+	if s.dow, err = parseField(fields[4], dows); err != nil {
+		return nil, fmt.Errorf("day of week: %s", err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = fields[2] == "*" || fields[2] == "?"
+	s.dowStar = fields[4] == "*" || fields[4] == "?"
+	if !s.daysOccur() {
+		return nil, fmt.Errorf("schedule never fires, day of month %q does not occur in month %q", fields[2], fields[3])
+	}
+
+	return s, nil
+}
+
+// parseEvery parses the interval of an @every schedule. Like CronJobs, the
+// interval is truncated to whole seconds with a minimum of one second.
+func parseEvery(interval string) (*Schedule, error) {
+	every, err := time.ParseDuration(interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval %q", interval)
+	}
+	if every <= 0 {
+		return nil, fmt.Errorf("interval %q must be positive", interval)
+	}
+	if every < time.Second {
+		every = time.Second
+	}
+	return &Schedule{every: every.Truncate(time.Second)}, nil
+}
+
+// daysOccur reports whether a day matched by the day of month field occurs in
+// a month matched by the month field. When the day of week field is also
+// restricted either day field may match, which always occurs.
+func (s *Schedule) daysOccur() bool {
+	if s.domStar || !s.dowStar {
+		return true
+	}
+	for month := uint(1); month <= 12; month++ {
+		if s.month&(1<<month) == 0 {
+			continue
+		}
+		for day := uint(1); day <= daysInMonth[month]; day++ {
+			if s.dom&(1<<day) != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, expr := range strings.Split(field, ",") {
+		r, err := parseRange(expr, b)
+		if err != nil {
+			return 0, err
+		}
+		bits |= r
+	}
+	return bits, nil
+}
+
+func parseRange(expr string, b bounds) (uint64, error) {
+	rangeAndStep := strings.SplitN(expr, "/", 2)
+	lowAndHigh := strings.SplitN(rangeAndStep[0], "-", 2)
+
+	var start, end uint
+	var err error
+	switch {
+	case lowAndHigh[0] == "*" || lowAndHigh[0] == "?":
+		if len(lowAndHigh) > 1 {
+			return 0, fmt.Errorf("invalid range %q", expr)
+		}
+		start, end = b.min, b.max
+	default:
+		if start, err = parseValue(lowAndHigh[0], b); err != nil {
+			return 0, err
+		}
+		end = start
+		if len(lowAndHigh) > 1 {
+			if end, err = parseValue(lowAndHigh[1], b); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	step := uint(1)
+	if len(rangeAndStep) > 1 {
+		n, err := strconv.ParseUint(rangeAndStep[1], 10, 0)
+		if err != nil || n == 0 {
+			return 0, fmt.Errorf("invalid step %q", rangeAndStep[1])
+		}
+		step = uint(n)
+		if len(lowAndHigh) == 1 && lowAndHigh[0] != "*" && lowAndHigh[0] != "?" {
+			// a single value with a step runs to the end of the range
+			end = b.max
+		}
+	}
+
+	if start < b.min || end > b.max || start > end {
+		return 0, fmt.Errorf("%q is out of range %d-%d", expr, b.min, b.max)
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << i
+	}
+	return bits, nil
+}
+
+func parseValue(value string, b bounds) (uint, error) {
+	if n, ok := b.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	return uint(n), nil
+}
+
+// Next returns the first time after t that matches the schedule, or the zero
+// time if no matching time is found within five years. An @every schedule
+// fires one interval after t.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every - time.Duration(t.Nanosecond()))
+	}
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
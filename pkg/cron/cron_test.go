@@ -0,0 +1,119 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/projectriff/system/pkg/cron"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{name: "every minute", spec: "* * * * *"},
+		{name: "lists, ranges and steps", spec: "0,30 9-17 */2 1-6/2 mon-fri"},
+		{name: "names", spec: "0 0 * jan,JUL sun"},
+		{name: "sunday alias", spec: "0 0 * * 7"},
+		{name: "descriptor", spec: "@hourly"},
+		{name: "every", spec: "@every 1h"},
+		{name: "every, compound duration", spec: "@every 1h30m"},
+		{name: "leap day", spec: "0 12 29 feb *"},
+		{name: "day of month or day of week", spec: "0 0 30 feb mon"},
+		{name: "empty", spec: "", wantErr: true},
+		{name: "too few fields", spec: "* * * *", wantErr: true},
+		{name: "too many fields", spec: "0 * * * * *", wantErr: true},
+		{name: "out of range", spec: "60 * * * *", wantErr: true},
+		{name: "inverted range", spec: "* 5-1 * * *", wantErr: true},
+		{name: "zero step", spec: "*/0 * * * *", wantErr: true},
+		{name: "invalid value", spec: "* * * foo *", wantErr: true},
+		{name: "unknown descriptor", spec: "@weekdays", wantErr: true},
+		{name: "every, missing interval", spec: "@every", wantErr: true},
+		{name: "every, invalid interval", spec: "@every 5", wantErr: true},
+		{name: "every, negative interval", spec: "@every -5m", wantErr: true},
+		{name: "never fires", spec: "0 0 30 2 *", wantErr: true},
+		{name: "never fires, any of the months", spec: "0 0 31 apr,jun,sep,nov *", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := cron.Parse(test.spec)
+			if (err != nil) != test.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	from := time.Date(2019, time.December, 31, 23, 58, 30, 0, time.UTC)
+	tests := []struct {
+		name     string
+		spec     string
+		expected time.Time
+	}{
+		{
+			name:     "every minute",
+			spec:     "* * * * *",
+			expected: time.Date(2019, time.December, 31, 23, 59, 0, 0, time.UTC),
+		},
+		{
+			name:     "roll over year",
+			spec:     "*/5 * * * *",
+			expected: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of week",
+			spec:     "30 9 * * mon",
+			expected: time.Date(2020, time.January, 6, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of month or day of week",
+			spec:     "0 0 15 * fri",
+			expected: time.Date(2020, time.January, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "leap day",
+			spec:     "0 12 29 feb *",
+			expected: time.Date(2020, time.February, 29, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "every",
+			spec:     "@every 1h",
+			expected: time.Date(2020, time.January, 1, 0, 58, 30, 0, time.UTC),
+		},
+		{
+			name:     "every, less than a second",
+			spec:     "@every 10ms",
+			expected: time.Date(2019, time.December, 31, 23, 58, 31, 0, time.UTC),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule, err := cron.Parse(test.spec)
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			if actual := schedule.Next(from); !actual.Equal(test.expected) {
+				t.Errorf("Next() = %v, expected %v", actual, test.expected)
+			}
+		})
+	}
+}
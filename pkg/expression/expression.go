@@ -0,0 +1,478 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package expression parses the expressions built-in processor operations
+// evaluate against each message.
+//
+// An expression compares references to a message with literals, combining
+// comparisons with boolean operators:
+//
+//	headers["X-Tenant"] == "acme" && payload.order.total >= 100
+//
+// The message is referenced as:
+//   - headers["name"], a header of the message
+//   - payload.field or payload["field"], a field of a JSON payload
+//   - contentType, the content type of the message
+//
+// Literals are double or single quoted strings, numbers, true, false and
+// null. Comparisons use ==, !=, <, <=, > and >=, and are combined with &&,
+// || and !. A reference on its own is true when the referenced value is
+// present and not false.
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Node is a parsed expression
+type Node interface {
+	String() string
+}
+
+// Binary combines two operands with a comparison or boolean operator
+type Binary struct {
+	Op          string
+	Left, Right Node
+}
+
+func (n *Binary) String() string {
+	return fmt.Sprintf("(%s %s %s)", n.Left, n.Op, n.Right)
+}
+
+// Not negates its operand
+type Not struct {
+	Operand Node
+}
+
+func (n *Not) String() string {
+	return fmt.Sprintf("!%s", n.Operand)
+}
+
+// Header references a header of the message
+type Header struct {
+	Name string
+}
+
+func (n *Header) String() string {
+	return fmt.Sprintf("headers[%q]", n.Name)
+}
+
+// Payload references a field of a JSON payload, an empty path references
+// the whole payload
+type Payload struct {
+	Path []string
+}
+
+func (n *Payload) String() string {
+	return "payload" + FormatPath(n.Path)
+}
+
+// ContentType references the content type of the message
+type ContentType struct{}
+
+func (n *ContentType) String() string {
+	return "contentType"
+}
+
+// Literal is a string, float64, bool or nil value
+type Literal struct {
+	Value interface{}
+}
+
+func (n *Literal) String() string {
+	switch v := n.Value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return "null"
+}
+
+// Parse parses an expression
+func Parse(expr string) (Node, error) {
+	p, err := newParser(expr)
+	if err != nil {
+		return nil, err
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.unexpected(t)
+	}
+	return node, nil
+}
+
+// ParsePath parses the path to a field of a JSON payload, like
+// `order.items` or `order["line-items"]`
+func ParsePath(path string) ([]string, error) {
+	p, err := newParser(path)
+	if err != nil {
+		return nil, err
+	}
+	t := p.next()
+	if t.kind != tokenIdent {
+		return nil, p.unexpected(t)
+	}
+	segments, err := p.parsePath([]string{t.text})
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.unexpected(t)
+	}
+	return segments, nil
+}
+
+// FormatPath renders path segments as they are referenced following
+// `payload`
+func FormatPath(path []string) string {
+	var b strings.Builder
+	for _, segment := range path {
+		if isIdent(segment) {
+			b.WriteString(".")
+			b.WriteString(segment)
+		} else {
+			fmt.Fprintf(&b, "[%q]", segment)
+		}
+	}
+	return b.String()
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func newParser(expr string) (*parser, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{tokens: tokens}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) accept(punct ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokenPunct {
+		return "", false
+	}
+	for _, op := range punct {
+		if t.text == op {
+			p.i++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(punct string) error {
+	if _, ok := p.accept(punct); !ok {
+		return p.unexpected(p.peek())
+	}
+	return nil
+}
+
+func (p *parser) unexpected(t token) error {
+	if t.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of expression")
+	}
+	return fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: "||", Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: "&&", Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if _, ok := p.accept("!"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	start := p.peek()
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		// a lone literal is either always or never true
+		if l, ok := left.(*Literal); ok {
+			if _, ok := l.Value.(bool); !ok {
+				return nil, fmt.Errorf("expected a comparison for %s at position %d", l, start.pos)
+			}
+		}
+		return left, nil
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return &Binary{Op: op, Left: left, Right: right}, nil
+}
+
+func (p *parser) parseOperand() (Node, error) {
+	if _, ok := p.accept("("); ok {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return &Literal{Value: t.text}, nil
+	case tokenNumber:
+		value, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", t.text, t.pos)
+		}
+		return &Literal{Value: value}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return &Literal{Value: true}, nil
+		case "false":
+			return &Literal{Value: false}, nil
+		case "null":
+			return &Literal{Value: nil}, nil
+		case "contentType":
+			return &ContentType{}, nil
+		case "headers":
+			if err := p.expect("["); err != nil {
+				return nil, err
+			}
+			name := p.next()
+			if name.kind != tokenString {
+				return nil, p.unexpected(name)
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			return &Header{Name: name.text}, nil
+		case "payload":
+			path, err := p.parsePath([]string{})
+			if err != nil {
+				return nil, err
+			}
+			return &Payload{Path: path}, nil
+		}
+		return nil, fmt.Errorf("unknown reference %q at position %d, expected headers, payload or contentType", t.text, t.pos)
+	}
+	return nil, p.unexpected(t)
+}
+
+func (p *parser) parsePath(path []string) ([]string, error) {
+	for {
+		if _, ok := p.accept("."); ok {
+			t := p.next()
+			if t.kind != tokenIdent {
+				return nil, p.unexpected(t)
+			}
+			path = append(path, t.text)
+			continue
+		}
+		if _, ok := p.accept("["); ok {
+			t := p.next()
+			if t.kind != tokenString {
+				return nil, p.unexpected(t)
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			path = append(path, t.text)
+			continue
+		}
+		return path, nil
+	}
+}
+
+var puncts = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", "."}
+
+func lex(expr string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			text, n, err := lexString(expr[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%v at position %d", err, i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i})
+			i += n
+		case isDigit(c) || (c == '-' && i+1 < len(expr) && isDigit(expr[i+1])):
+			j := i + 1
+			for j < len(expr) && (isDigit(expr[j]) || expr[j] == '.' || expr[j] == 'e' || expr[j] == 'E') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: expr[i:j], pos: i})
+			i = j
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(expr) && isIdentPart(expr[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: expr[i:j], pos: i})
+			i = j
+		default:
+			matched := false
+			for _, punct := range puncts {
+				if strings.HasPrefix(expr[i:], punct) {
+					tokens = append(tokens, token{kind: tokenPunct, text: punct, pos: i})
+					i += len(punct)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected %q at position %d", c, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(expr)}), nil
+}
+
+// lexString reads a quoted string, returning the unquoted value and the
+// number of bytes consumed
+func lexString(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case quote:
+			return b.String(), i + 1, nil
+		case '\\':
+			i++
+			if i == len(s) {
+				break
+			}
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isIdent(s string) bool {
+	if s == "" || !isIdentStart(s[0]) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if !isIdentPart(s[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2019 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expression_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/projectriff/system/pkg/expression"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected string
+		wantErr  bool
+	}{
+		{name: "header comparison", expr: `headers["X-Tenant"] == 'acme'`, expected: `(headers["X-Tenant"] == "acme")`},
+		{name: "payload comparison", expr: "payload.order.total >= 100", expected: "(payload.order.total >= 100)"},
+		{name: "quoted payload field", expr: `payload["line-items"] != null`, expected: `(payload["line-items"] != null)`},
+		{name: "content type", expr: `contentType == "application/json"`, expected: `(contentType == "application/json")`},
+		{name: "reference", expr: "payload.enabled", expected: "payload.enabled"},
+		{name: "negative number", expr: "payload.delta < -1.5", expected: "(payload.delta < -1.5)"},
+		{name: "and binds tighter than or", expr: `payload.a == 1 || payload.b == 2 && !payload.c`, expected: "((payload.a == 1) || ((payload.b == 2) && !payload.c))"},
+		{name: "parentheses", expr: `(payload.a == 1 || payload.b == 2) && true`, expected: "(((payload.a == 1) || (payload.b == 2)) && true)"},
+		{name: "escaped quote", expr: `headers["x"] == "say \"hi\""`, expected: `(headers["x"] == "say \"hi\"")`},
+		{name: "empty", expr: "", wantErr: true},
+		{name: "unknown reference", expr: "body.a == 1", wantErr: true},
+		{name: "lone string", expr: `"acme"`, wantErr: true},
+		{name: "missing operand", expr: "payload.a ==", wantErr: true},
+		{name: "unbalanced parentheses", expr: "(payload.a == 1", wantErr: true},
+		{name: "unterminated string", expr: `headers["x] == 1`, wantErr: true},
+		{name: "header without name", expr: "headers == 1", wantErr: true},
+		{name: "unsupported operator", expr: "payload.a = 1", wantErr: true},
+		{name: "trailing tokens", expr: "payload.a == 1 payload.b", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node, err := expression.Parse(test.expr)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(test.expected, node.String()); diff != "" {
+				t.Errorf("Parse() (-expected, +actual) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "field", path: "id", expected: []string{"id"}},
+		{name: "nested field", path: "order.customer.id", expected: []string{"order", "customer", "id"}},
+		{name: "quoted field", path: `order["line-items"]`, expected: []string{"order", "line-items"}},
+		{name: "empty", path: "", wantErr: true},
+		{name: "leading dot", path: ".id", wantErr: true},
+		{name: "trailing dot", path: "order.", wantErr: true},
+		{name: "expression", path: "id == 1", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, err := expression.ParsePath(test.path)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParsePath() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if diff := cmp.Diff(test.expected, path); diff != "" {
+				t.Errorf("ParsePath() (-expected, +actual) = %s", diff)
+			}
+		})
+	}
+}